@@ -17,6 +17,16 @@ import (
 // @BasePath /
 // @schemes http
 
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description JWT в формате "Bearer <token>"
+
+// @securityDefinitions.apikey APIKeyAuth
+// @in header
+// @name X-API-Key
+// @description API-ключ сервиса, выпускается через POST /api_keys
+
 func main() {
 
 	docs.SwaggerInfo.Host = "localhost:8080"
@@ -0,0 +1,109 @@
+// Command rates loads exchange rates from a CSV file into the database.
+//
+// Each line is "from,to,date,rate", for example "USD,RUB,2026-10-01,81.5". A header line
+// starting with "from" is skipped. The file is loaded in one transaction.
+//
+//	rates -file rates.csv
+//	cat rates.csv | rates -file -
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/4udiwe/subscription-service/config"
+	"github.com/4udiwe/subscription-service/internal/entity"
+	exchange_rate_repo "github.com/4udiwe/subscription-service/internal/repository/exchange_rate"
+	"github.com/4udiwe/subscription-service/internal/service/exchange_rate"
+	"github.com/4udiwe/subscription-service/pkg/postgres"
+	log "github.com/sirupsen/logrus"
+)
+
+func main() {
+	configPath := flag.String("config", os.Getenv("CONFIG_PATH"), "path to the config file")
+	file := flag.String("file", "-", "CSV file with rates, - for stdin")
+	flag.Parse()
+
+	cfg, err := config.New(*configPath)
+	if err != nil {
+		log.Fatalf("rates - config.New: %v", err)
+	}
+
+	in := os.Stdin
+	if *file != "-" {
+		in, err = os.Open(*file)
+		if err != nil {
+			log.Fatalf("rates - os.Open: %v", err)
+		}
+		defer in.Close()
+	}
+
+	rates, err := readRates(in)
+	if err != nil {
+		log.Fatalf("rates - readRates: %v", err)
+	}
+
+	pg, err := postgres.New(cfg.Postgres.URL, postgres.ConnAttempts(5))
+	if err != nil {
+		log.Fatalf("rates - postgres.New: %v", err)
+	}
+	defer pg.Close()
+
+	service := exchange_rate.New(exchange_rate_repo.New(pg), pg)
+
+	loaded, err := service.LoadRates(context.Background(), rates)
+	if err != nil {
+		log.Fatalf("rates - LoadRates: %v", err)
+	}
+
+	fmt.Printf("loaded %d exchange rates\n", loaded)
+}
+
+func readRates(r io.Reader) ([]entity.ExchangeRate, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 4
+	reader.TrimLeadingSpace = true
+
+	var rates []entity.ExchangeRate
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if line == 1 && strings.EqualFold(record[0], "from") {
+			continue
+		}
+
+		date, err := time.Parse("2006-01-02", record[2])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid date %q", line, record[2])
+		}
+		rate, err := strconv.ParseFloat(record[3], 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid rate %q", line, record[3])
+		}
+
+		rates = append(rates, entity.ExchangeRate{
+			From: record[0],
+			To:   record[1],
+			Date: date,
+			Rate: rate,
+		})
+	}
+
+	if len(rates) == 0 {
+		return nil, errors.New("no rates in input")
+	}
+	return rates, nil
+}
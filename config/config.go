@@ -13,6 +13,16 @@ type (
 		HTTP     HTTP     `yaml:"http"`
 		Postgres Postgres `yaml:"postgres"`
 		Log      Log      `yaml:"logger"`
+		Auth     Auth     `yaml:"auth"`
+		Renewal  Renewal  `yaml:"renewal"`
+		Expiry   Expiry   `yaml:"expiry"`
+		Pricing  Pricing  `yaml:"pricing"`
+		Outbox   Outbox   `yaml:"outbox"`
+		Webhooks Webhooks `yaml:"webhooks"`
+
+		Idempotency Idempotency `yaml:"idempotency"`
+		Metrics     Metrics     `yaml:"metrics"`
+		Tracing     Tracing     `yaml:"tracing"`
 	}
 
 	App struct {
@@ -31,6 +41,79 @@ type (
 	Log struct {
 		Level string `env-required:"true" yaml:"level" env:"LOG_LEVEL"`
 	}
+
+	Auth struct {
+		Enabled     bool   `yaml:"enabled" env:"AUTH_ENABLED" env-default:"true"`
+		Issuer      string `yaml:"issuer" env:"AUTH_ISSUER"`
+		Audience    string `yaml:"audience" env:"AUTH_AUDIENCE"`
+		HS256Secret string `yaml:"hs256_secret" env:"AUTH_HS256_SECRET"`
+		JWKSFile    string `yaml:"jwks_file" env:"AUTH_JWKS_FILE"`
+		RolesClaim  string `yaml:"roles_claim" env:"AUTH_ROLES_CLAIM" env-default:"roles"`
+	}
+
+	Renewal struct {
+		Enabled      bool          `yaml:"enabled" env:"RENEWAL_ENABLED" env-default:"true"`
+		Interval     time.Duration `yaml:"interval" env:"RENEWAL_INTERVAL" env-default:"1h"`
+		Window       time.Duration `yaml:"window" env:"RENEWAL_WINDOW" env-default:"24h"`
+		NoticeWindow time.Duration `yaml:"notice_window" env:"RENEWAL_NOTICE_WINDOW" env-default:"72h"`
+		BatchSize    int           `yaml:"batch_size" env:"RENEWAL_BATCH_SIZE" env-default:"100"`
+	}
+
+	Expiry struct {
+		Enabled      bool          `yaml:"enabled" env:"EXPIRY_ENABLED" env-default:"true"`
+		Interval     time.Duration `yaml:"interval" env:"EXPIRY_INTERVAL" env-default:"1h"`
+		RenewalGrace time.Duration `yaml:"renewal_grace" env:"EXPIRY_RENEWAL_GRACE" env-default:"24h"`
+	}
+
+	Pricing struct {
+		Enabled   bool          `yaml:"enabled" env:"PRICING_ENABLED" env-default:"true"`
+		Interval  time.Duration `yaml:"interval" env:"PRICING_INTERVAL" env-default:"1h"`
+		BatchSize int           `yaml:"batch_size" env:"PRICING_BATCH_SIZE" env-default:"100"`
+	}
+
+	Outbox struct {
+		Enabled        bool          `yaml:"enabled" env:"OUTBOX_ENABLED" env-default:"true"`
+		Interval       time.Duration `yaml:"interval" env:"OUTBOX_INTERVAL" env-default:"5s"`
+		BatchSize      int           `yaml:"batch_size" env:"OUTBOX_BATCH_SIZE" env-default:"100"`
+		MaxAttempts    int           `yaml:"max_attempts" env:"OUTBOX_MAX_ATTEMPTS" env-default:"10"`
+		Backoff        time.Duration `yaml:"backoff" env:"OUTBOX_BACKOFF" env-default:"30s"`
+		MaxBackoff     time.Duration `yaml:"max_backoff" env:"OUTBOX_MAX_BACKOFF" env-default:"1h"`
+		Lease          time.Duration `yaml:"lease" env:"OUTBOX_LEASE" env-default:"5m"`
+		Publisher      string        `yaml:"publisher" env:"OUTBOX_PUBLISHER" env-default:"log"`
+		File           string        `yaml:"file" env:"OUTBOX_FILE"`
+		WebhookURL     string        `yaml:"webhook_url" env:"OUTBOX_WEBHOOK_URL"`
+		WebhookSecret  string        `yaml:"webhook_secret" env:"OUTBOX_WEBHOOK_SECRET"`
+		WebhookTimeout time.Duration `yaml:"webhook_timeout" env:"OUTBOX_WEBHOOK_TIMEOUT" env-default:"5s"`
+	}
+
+	Idempotency struct {
+		Enabled   bool          `yaml:"enabled" env:"IDEMPOTENCY_ENABLED" env-default:"true"`
+		TTL       time.Duration `yaml:"ttl" env:"IDEMPOTENCY_TTL" env-default:"24h"`
+		Interval  time.Duration `yaml:"interval" env:"IDEMPOTENCY_INTERVAL" env-default:"1h"`
+		BatchSize int           `yaml:"batch_size" env:"IDEMPOTENCY_BATCH_SIZE" env-default:"1000"`
+	}
+
+	Metrics struct {
+		Enabled  bool          `yaml:"enabled" env:"METRICS_ENABLED" env-default:"true"`
+		Interval time.Duration `yaml:"interval" env:"METRICS_INTERVAL" env-default:"30s"`
+	}
+
+	Tracing struct {
+		Exporter    string  `yaml:"exporter" env:"TRACING_EXPORTER" env-default:"none"`
+		Endpoint    string  `yaml:"endpoint" env:"TRACING_ENDPOINT"`
+		Insecure    bool    `yaml:"insecure" env:"TRACING_INSECURE" env-default:"true"`
+		SampleRatio float64 `yaml:"sample_ratio" env:"TRACING_SAMPLE_RATIO" env-default:"1"`
+	}
+
+	Webhooks struct {
+		Enabled     bool          `yaml:"enabled" env:"WEBHOOKS_ENABLED" env-default:"true"`
+		Interval    time.Duration `yaml:"interval" env:"WEBHOOKS_INTERVAL" env-default:"5s"`
+		BatchSize   int           `yaml:"batch_size" env:"WEBHOOKS_BATCH_SIZE" env-default:"100"`
+		Timeout     time.Duration `yaml:"timeout" env:"WEBHOOKS_TIMEOUT" env-default:"5s"`
+		MaxAttempts int           `yaml:"max_attempts" env:"WEBHOOKS_MAX_ATTEMPTS" env-default:"8"`
+		Backoff     time.Duration `yaml:"backoff" env:"WEBHOOKS_BACKOFF" env-default:"30s"`
+		MaxBackoff  time.Duration `yaml:"max_backoff" env:"WEBHOOKS_MAX_BACKOFF" env-default:"1h"`
+	}
 )
 
 func New(configPath string) (*Config, error) {
@@ -17,19 +17,24 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/offers": {
+        "/api_keys": {
             "get": {
-                "description": "Получение списка всех офферов",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
                 ],
+                "description": "Получение списка API-ключей, включая отозванные, новые первыми. Сами ключи не возвращаются, prefix - их первые символы. last_used_at - время последнего запроса с ключом с точностью до минуты.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "offers"
+                    "api_keys"
                 ],
-                "summary": "Получение всех офферов",
+                "summary": "Получение API-ключей",
                 "parameters": [
                     {
                         "type": "integer",
@@ -51,25 +56,45 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/internal_handler_get_offers.GetAllOffersResponse"
+                            "$ref": "#/definitions/internal_handler_get_api_keys.GetAPIKeysResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
                         }
                     }
                 }
             },
             "post": {
-                "description": "Создание нового предложения с указанными параметрами",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Выпуск ключа для сервиса, который вызывает API (биллинг, аналитика). Ключ передается в заголовке X-API-Key. scopes - права ключа: subscriptions:read, subscriptions:write, offers:read, offers:write, admin (admin включает все остальные и админские ручки). Ключ возвращается только в этом ответе, сервис хранит его хэш. Повтор запроса с тем же Idempotency-Key возвращает ответ без key. name - автор изменений, сделанных ключом, в журнале аудита (api_key:\u003cname\u003e), он уникален среди действующих ключей.",
                 "consumes": [
                     "application/json"
                 ],
@@ -77,59 +102,100 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "offers"
+                    "api_keys"
                 ],
-                "summary": "Создание нового предложения",
+                "summary": "Создание API-ключа",
                 "parameters": [
                     {
-                        "description": "Offer details",
-                        "name": "offer",
+                        "description": "api key",
+                        "name": "api_key",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/post_offer.PostOfferRequest"
+                            "$ref": "#/definitions/internal_handler_post_api_key.PostAPIKeyRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ключ идемпотентности: повтор запроса с тем же ключом возвращает сохраненный ответ",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/post_offer.PostOfferResponse"
+                            "$ref": "#/definitions/internal_handler_post_api_key.PostAPIKeyResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
                         }
                     },
                     "409": {
                         "description": "Conflict",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/api_keys/{id}": {
             "delete": {
-                "description": "Удаление предложения по ID. Если есть активные подписки на это предложение, оно не будет удалено.",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
                 ],
+                "description": "Отзыв API-ключа по ID: запросы с ним больше не принимаются. Ключ остается в списке с датой отзыва revoked_at.",
                 "tags": [
-                    "offers"
+                    "api_keys"
                 ],
-                "summary": "Удаление предложения",
+                "summary": "Отзыв API-ключа",
                 "parameters": [
                     {
-                        "description": "offer to delete",
-                        "name": "offer",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/delete_offer.DeleteOfferRequest"
-                        }
+                        "type": "string",
+                        "description": "API key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ключ идемпотентности: повтор запроса с тем же ключом возвращает сохраненный ответ",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -139,35 +205,98 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/subscriptions": {
+        "/audit": {
             "get": {
-                "description": "Получение списка всех подписок",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
                 ],
+                "description": "Получение журнала изменений подписок и предложений, новые первыми. Каждая запись содержит автора изменения (sub токена или api_key:\u003cname\u003e, anonymous при выключенной аутентификации, system для фоновых задач), действие и состояние сущности до и после изменения. Период from включительно, to не включительно.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "subscriptions"
+                    "audit"
                 ],
-                "summary": "Получение всех подписок",
+                "summary": "Получение журнала аудита",
                 "parameters": [
+                    {
+                        "enum": [
+                            "subscription",
+                            "offer"
+                        ],
+                        "type": "string",
+                        "description": "Тип сущности",
+                        "name": "entity_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID сущности",
+                        "name": "entity_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Автор изменения",
+                        "name": "actor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало периода (RFC3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец периода (RFC3339)",
+                        "name": "to",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
                         "default": 1,
@@ -188,106 +317,120 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/internal_handler_get_subs.GetAllSubscriptionsResponse"
+                            "$ref": "#/definitions/internal_handler_get_audit.GetAuditResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
-                }
-            },
-            "post": {
-                "description": "Создание новой подписки для пользователя с возможностью создания нового предложения, если оно не существует",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "subscriptions"
-                ],
-                "summary": "Создание новой подписки",
-                "parameters": [
-                    {
-                        "description": "subscription info",
-                        "name": "subscription",
-                        "in": "body",
-                        "required": true,
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/internal_handler_post_sub_by_name.PostSubscriptionByNameRequest"
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
                         }
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "Created",
+                    },
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/internal_handler_post_sub_by_name.PostSubscriptionByNameResponse"
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Удаление подписки по ID. Не удаляет предложение, на которое была оформлена подписка.",
-                "consumes": [
+            }
+        },
+        "/exchange_rates": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Получение загруженных курсов, новые даты первыми",
+                "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "subscriptions"
+                    "exchange_rates"
                 ],
-                "summary": "Удаление подписки",
+                "summary": "Получение курсов валют",
                 "parameters": [
                     {
-                        "description": "subscription to delete",
-                        "name": "subscription",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/internal_handler_delete_sub.DeleteSubscriptionRequest"
-                        }
+                        "type": "string",
+                        "description": "Исходная валюта (ISO 4217)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Целевая валюта (ISO 4217)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Размер страницы",
+                        "name": "page_size",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "202": {
-                        "description": "No Content",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/internal_handler_get_exchange_rates.GetExchangeRatesResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/subscriptions/by-offer": {
+            },
             "post": {
-                "description": "Создание новой подписки для пользователя по ID предложения, полученного из ендпоинта всех предложений",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Загрузка курсов на даты. rate - количество валюты to за единицу валюты from. Курс, уже сохраненный для той же пары и даты, заменяется. Пакет загружается целиком или не загружается вовсе.",
                 "consumes": [
                     "application/json"
                 ],
@@ -295,45 +438,77 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "subscriptions"
+                    "exchange_rates"
                 ],
-                "summary": "Создание новой подписки по ID предложения",
+                "summary": "Загрузка курсов валют",
                 "parameters": [
                     {
-                        "description": "subscription info",
-                        "name": "subscription",
+                        "description": "exchange rates",
+                        "name": "rates",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/internal_handler_post_sub_by_offer_id.PostSubscriptionByOfferIDRequest"
+                            "$ref": "#/definitions/internal_handler_post_exchange_rates.PostExchangeRatesRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ключ идемпотентности: повтор запроса с тем же ключом возвращает сохраненный ответ",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/internal_handler_post_sub_by_offer_id.PostSubscriptionByOfferIDResponse"
+                            "$ref": "#/definitions/internal_handler_post_exchange_rates.PostExchangeRatesResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/subscriptions/by-user": {
+        "/offers": {
             "get": {
-                "description": "Получение списка подписок для указанного пользователя",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Получение списка всех офферов. В режиме cursor страницы строятся по (created_at, id), следующая страница запрашивается по next_cursor; page, total_items и total_pages в этом режиме не заполняются.",
                 "consumes": [
                     "application/json"
                 ],
@@ -341,19 +516,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "subscriptions"
+                    "offers"
                 ],
-                "summary": "Получение подписок по ID пользователя",
+                "summary": "Получение всех офферов",
                 "parameters": [
-                    {
-                        "description": "user ID",
-                        "name": "user",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/internal_handler_get_subs_by_user.GetSubscriptionsByUserRequest"
-                        }
-                    },
                     {
                         "type": "integer",
                         "default": 1,
@@ -368,27 +534,218 @@ const docTemplate = `{
                         "description": "Размер страницы",
                         "name": "page_size",
                         "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    {
+                        "enum": [
+                            "offset",
+                            "cursor"
+                        ],
+                        "type": "string",
+                        "description": "Режим пагинации: offset (page/page_size) или cursor",
+                        "name": "pagination",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы (next_cursor из предыдущего ответа), включает режим cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/internal_handler_get_subs_by_user.GetSubscriptionsByUserResponse"
+                            "$ref": "#/definitions/internal_handler_get_offers.GetAllOffersResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Создание нового предложения с указанными параметрами. Валюта задается кодом ISO 4217, по умолчанию RUB. trial_days задает длительность бесплатного пробного периода в днях, 0 - без пробного периода. Предложение определяется именем и валютой: для смены цены существующего предложения используется PATCH или планирование изменения цены",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "offers"
+                ],
+                "summary": "Создание нового предложения",
+                "parameters": [
+                    {
+                        "description": "Offer details",
+                        "name": "offer",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_post_offer.PostOfferRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ключ идемпотентности: повтор запроса с тем же ключом возвращает сохраненный ответ",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_post_offer.PostOfferResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Удаление предложения по ID. Если есть активные подписки на это предложение, оно не будет удалено.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "offers"
+                ],
+                "summary": "Удаление предложения",
+                "parameters": [
+                    {
+                        "description": "offer to delete",
+                        "name": "offer",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_delete_offer.DeleteOfferRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ключ идемпотентности: повтор запроса с тем же ключом возвращает сохраненный ответ",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "No Content",
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/subscriptions/by-user-and-subname": {
+        "/offers/{id}": {
             "get": {
-                "description": "Получение списка подписок для указанного пользователя и названия подписки с возможностью фильтрации по дате начала и окончания",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Получение предложения по его ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -396,111 +753,3105 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "subscriptions"
+                    "offers"
                 ],
-                "summary": "Получение подписок по ID пользователя и названию подписки",
+                "summary": "Получение предложения по ID",
                 "parameters": [
                     {
-                        "description": "user ID and subscription name",
-                        "name": "user",
-                        "in": "body",
-                        "required": true,
+                        "type": "string",
+                        "description": "Offer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/internal_handler_get_subs_by_user_subname.GetSubsByUserAndServiceNameRequest"
+                            "$ref": "#/definitions/internal_handler_get_offer.GetOfferResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
                         }
                     },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
                     {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "Номер страницы",
-                        "name": "page",
-                        "in": "query"
+                        "BearerAuth": []
                     },
                     {
-                        "maximum": 100,
-                        "type": "integer",
-                        "default": 10,
-                        "description": "Размер страницы",
-                        "name": "page_size",
-                        "in": "query"
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Изменение имени, цены, валюты, длительности и/или пробного периода предложения. Переданы могут быть только изменяемые поля. Уже оформленные подписки сохраняют свои даты окончания и пробные периоды. Переименование, после которого подписки пользователя пересеклись бы или у него оказалось бы два пробных периода одного сервиса, возвращает 409 (offer_rename_overlaps_subscriptions, offer_rename_duplicates_trial).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "offers"
+                ],
+                "summary": "Изменение предложения",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Offer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "fields to update",
+                        "name": "offer",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_patch_offer.PatchOfferRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ключ идемпотентности: повтор запроса с тем же ключом возвращает сохраненный ответ",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/internal_handler_get_subs_by_user_subname.GetSubsByUserAndServiceNameResponse"
+                            "$ref": "#/definitions/internal_handler_patch_offer.PatchOfferResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "delete_offer.DeleteOfferRequest": {
+        },
+        "/offers/{id}/prices": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Получение всех версий цены предложения, последние первыми. Версия действует с даты effective_from до начала следующей. Подписка закрепляет версию, по которой была оформлена, поэтому изменение цены не меняет уже оформленные подписки и суммы трат. Статусы: pending - запланированное изменение, еще не примененное к предложению, applied - примененная версия, failed - изменение, которое не удалось применить (у другого предложения уже есть то же название и новая валюта), оно не действует. Фильтр status не возвращает версии failed.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "offers"
+                ],
+                "summary": "Получение истории цен предложения",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Offer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "pending",
+                            "applied"
+                        ],
+                        "type": "string",
+                        "description": "Статус версии",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_get_offer_prices.GetOfferPricesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Планирование новой цены предложения с будущей даты effective_from. Без currency сохраняется валюта предложения. Уже оформленные подписки сохраняют свою цену, новая цена применяется с первого продления, которое начинается не раньше effective_from. Всем активным подписчикам предложения отправляется событие subscription.price_change_upcoming. В дату effective_from фоновый воркер применяет цену к предложению. На одну дату можно запланировать одно изменение.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "offers"
+                ],
+                "summary": "Планирование изменения цены предложения",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Offer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "price change",
+                        "name": "price",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_post_offer_price.PostOfferPriceRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ключ идемпотентности: повтор запроса с тем же ключом возвращает сохраненный ответ",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_post_offer_price.PostOfferPriceResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/offers/{id}/prices/{price_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Отмена изменения цены предложения, которое еще не применено. Если по новой цене уже продлены подписки, изменение отменить нельзя. Всем активным подписчикам предложения отправляется событие subscription.price_change_cancelled.",
+                "tags": [
+                    "offers"
+                ],
+                "summary": "Отмена запланированного изменения цены",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Offer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Price ID",
+                        "name": "price_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ключ идемпотентности: повтор запроса с тем же ключом возвращает сохраненный ответ",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "No Content",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/promo_codes": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Получение списка промокодов с числом использований, новые первыми",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "promo_codes"
+                ],
+                "summary": "Получение промокодов",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Размер страницы",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_get_promo_codes.GetPromoCodesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Создание промокода со скидкой в процентах (percent, 1-100) или фиксированной суммой (fixed, в валюте currency). Можно ограничить число применений, период действия и список предложений offer_ids; пустой список - промокод действует на все предложения. Код не зависит от регистра.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "promo_codes"
+                ],
+                "summary": "Создание промокода",
+                "parameters": [
+                    {
+                        "description": "promo code",
+                        "name": "promo_code",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_post_promo_code.PostPromoCodeRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ключ идемпотентности: повтор запроса с тем же ключом возвращает сохраненный ответ",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_post_promo_code.PostPromoCodeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Получение списка всех подписок. В режиме cursor страницы строятся по (created_at, id), следующая страница запрашивается по next_cursor; page, total_items и total_pages в этом режиме не заполняются.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Получение всех подписок",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Размер страницы",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "active",
+                            "cancelled",
+                            "expired",
+                            "paused"
+                        ],
+                        "type": "string",
+                        "description": "Статус подписки",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "offset",
+                            "cursor"
+                        ],
+                        "type": "string",
+                        "description": "Режим пагинации: offset (page/page_size) или cursor",
+                        "name": "pagination",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы (next_cursor из предыдущего ответа), включает режим cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_get_subs.GetAllSubscriptionsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Создание новой подписки для пользователя с возможностью создания нового предложения, если оно не существует. Создать предложение может только администратор или API-ключ со scope offers:write, остальные получают 404 offer_not_found. Предложение ищется по имени и валюте (по умолчанию RUB), подписка закрепляется за текущей версией его цены; если price не совпадает с текущей ценой предложения, возвращается 409 offer_price_mismatch. Если у предложения есть пробный период, подписка начинается с него; пробный период дается пользователю один раз на сервис, skip_trial позволяет оформить подписку без него. Промокод promo_code применяется к цене предложения только на первый период (продления идут по цене предложения без скидки), в price возвращается цена с учетом скидки",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Создание новой подписки",
+                "parameters": [
+                    {
+                        "description": "subscription info",
+                        "name": "subscription",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_post_sub_by_name.PostSubscriptionByNameRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ключ идемпотентности: повтор запроса с тем же ключом возвращает сохраненный ответ",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_post_sub_by_name.PostSubscriptionByNameResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Удаление подписки по ID. Запись не удаляется: подписка сразу отменяется с причиной deleted (как POST /subscriptions/{id}/cancel без at_period_end), поэтому история и суммы трат сохраняются, отправляется событие subscription.cancelled. Удаление уже закончившейся подписки ничего не меняет. Не удаляет предложение, на которое была оформлена подписка.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Удаление подписки",
+                "parameters": [
+                    {
+                        "description": "subscription to delete",
+                        "name": "subscription",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_delete_sub.DeleteSubscriptionRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ключ идемпотентности: повтор запроса с тем же ключом возвращает сохраненный ответ",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "No Content",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/by-offer": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Создание новой подписки для пользователя по ID предложения, полученного из ендпоинта всех предложений. Если у предложения есть пробный период, подписка начинается с него, а оплачиваемый период идет после trial_end_date. Пробный период дается пользователю один раз на сервис, skip_trial позволяет оформить подписку без него. Промокод promo_code применяется к цене предложения только на первый период (продления идут по цене предложения без скидки), в price возвращается цена с учетом скидки",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Создание новой подписки по ID предложения",
+                "parameters": [
+                    {
+                        "description": "subscription info",
+                        "name": "subscription",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_post_sub_by_offer_id.PostSubscriptionByOfferIDRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ключ идемпотентности: повтор запроса с тем же ключом возвращает сохраненный ответ",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_post_sub_by_offer_id.PostSubscriptionByOfferIDResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/by-user": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Получение списка подписок для указанного пользователя. В режиме cursor страницы строятся по (created_at, id), следующая страница запрашивается по next_cursor; page, total_items и total_pages в этом режиме не заполняются.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Получение подписок по ID пользователя",
+                "parameters": [
+                    {
+                        "description": "user ID",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_get_subs_by_user.GetSubscriptionsByUserRequest"
+                        }
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Размер страницы",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "active",
+                            "cancelled",
+                            "expired",
+                            "paused"
+                        ],
+                        "type": "string",
+                        "description": "Статус подписки",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "offset",
+                            "cursor"
+                        ],
+                        "type": "string",
+                        "description": "Режим пагинации: offset (page/page_size) или cursor",
+                        "name": "pagination",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы (next_cursor из предыдущего ответа), включает режим cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_get_subs_by_user.GetSubscriptionsByUserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/by-user-and-subname": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Получение списка подписок для указанного пользователя и названия подписки с возможностью фильтрации по дате начала и окончания. В режиме cursor страницы строятся по (created_at, id), следующая страница запрашивается по next_cursor; page, total_items и total_pages в этом режиме не заполняются. total_price считается по всем подходящим подпискам и переводится в валюту currency (по умолчанию RUB) по курсу на дату начала каждой подписки.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Получение подписок по ID пользователя и названию подписки",
+                "parameters": [
+                    {
+                        "description": "user ID and subscription name",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_get_subs_by_user_subname.GetSubsByUserAndServiceNameRequest"
+                        }
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Размер страницы",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "active",
+                            "cancelled",
+                            "expired",
+                            "paused"
+                        ],
+                        "type": "string",
+                        "description": "Статус подписки",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "offset",
+                            "cursor"
+                        ],
+                        "type": "string",
+                        "description": "Режим пагинации: offset (page/page_size) или cursor",
+                        "name": "pagination",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы (next_cursor из предыдущего ответа), включает режим cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "RUB",
+                        "description": "Валюта total_price (ISO 4217)",
+                        "name": "currency",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_get_subs_by_user_subname.GetSubsByUserAndServiceNameResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/cost": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Стоимость подписок за период с разбивкой по календарным месяцам и сервисам. Цена подписки распределяется по дням оплаченного периода, учитываются только дни, попавшие в период отчёта. Период отчёта - не длиннее 12 месяцев. Дни на паузе не оплачиваются. Без user_id отчёт строится по всем пользователям (только для администраторов), без service_name - по всем сервисам. Суммы переводятся в валюту currency (по умолчанию RUB) по курсу на дату начала каждой подписки.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Отчёт о стоимости подписок",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало периода (YYYY-MM-DD, включительно)",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец периода (YYYY-MM-DD, включительно)",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Названия сервисов",
+                        "name": "service_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "RUB",
+                        "description": "Валюта отчёта (ISO 4217)",
+                        "name": "currency",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_get_cost_report.GetCostReportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Получение полной информации о подписке, включая название, цену и длительность предложения и историю пауз",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Получение подписки по ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_get_sub.GetSubscriptionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Изменение даты начала, даты окончания, предложения и/или флага автопродления подписки. Если дата окончания не передана, а дата начала или предложение изменились, она пересчитывается по длительности предложения. Пересечение с другими подписками пользователя на тот же сервис проверяется повторно. Владелец подписки может менять только auto_renew, остальные поля - только администратор или API-ключ.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Изменение подписки",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "fields to update",
+                        "name": "subscription",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_patch_sub.PatchSubscriptionRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ключ идемпотентности: повтор запроса с тем же ключом возвращает сохраненный ответ",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_patch_sub.PatchSubscriptionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/{id}/cancel": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Отмена подписки с сохранением истории. По умолчанию подписка отменяется сразу и дата окончания переносится на текущий день. При at_period_end=true подписка остается активной до конца оплаченного периода. Автопродление в обоих случаях отключается, а уже созданное продление на следующий период, если оно еще не началось, тоже отменяется (без оплаченных дней).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Отмена подписки",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "cancellation options",
+                        "name": "subscription",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_cancel_sub.CancelSubscriptionRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ключ идемпотентности: повтор запроса с тем же ключом возвращает сохраненный ответ",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_cancel_sub.CancelSubscriptionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/{id}/change_plan": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Перевод пользователя на другое предложение того же сервиса. Текущая подписка заканчивается в дату переключения (по умолчанию сегодня), новая начинается в ту же дату. В ответе возвращается кредит за неиспользованные дни старого тарифа.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Смена тарифа подписки",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "new offer and switch date",
+                        "name": "plan",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_change_plan.ChangePlanRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ключ идемпотентности: повтор запроса с тем же ключом возвращает сохраненный ответ",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_change_plan.ChangePlanResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/{id}/pause": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Приостановка действующей подписки с текущего дня. Оплаченное время не теряется: при возобновлении дата окончания сдвигается на длительность паузы. Подписку нельзя приостановить во время пробного периода, а также если за ней уже идет продление или другая подписка на тот же сервис (409 subscription_followed).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Приостановка подписки",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ключ идемпотентности: повтор запроса с тем же ключом возвращает сохраненный ответ",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_pause_sub.PauseSubscriptionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/{id}/resume": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Возобновление приостановленной подписки. Дата окончания сдвигается вперед на длительность паузы.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Возобновление подписки",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ключ идемпотентности: повтор запроса с тем же ключом возвращает сохраненный ответ",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_resume_sub.ResumeSubscriptionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Получение списка зарегистрированных вебхуков, новые первыми. Секреты не возвращаются.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Получение вебхуков",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Размер страницы",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_get_webhooks.GetWebhooksResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Регистрация URL, на который сервис отправляет события POST-запросом с JSON. event_types - список типов событий (subscription.created, subscription.cancelled, subscription.renewed, subscription.expiring_soon и др.), пустой список - все события. Каждый запрос подписан HMAC-SHA256 по secret: заголовок X-Webhook-Signature содержит sha256=\u003chex\u003e от \"\u003cX-Webhook-Timestamp\u003e.\u003cтело запроса\u003e\". Если secret не передан, он генерируется; secret возвращается только в этом ответе. Повтор запроса с тем же Idempotency-Key возвращает ответ без secret.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Регистрация вебхука",
+                "parameters": [
+                    {
+                        "description": "webhook endpoint",
+                        "name": "webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_post_webhook.PostWebhookRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ключ идемпотентности: повтор запроса с тем же ключом возвращает сохраненный ответ",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_post_webhook.PostWebhookResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/deliveries/{id}/replay": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Ставит доставку в очередь на повторную отправку с новым счетчиком попыток, в том числе уже доставленную или в статусе dead. Тело запроса не меняется, получатель может отбросить дубль по X-Event-ID.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Повторная отправка доставки вебхука",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Delivery ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ключ идемпотентности: повтор запроса с тем же ключом возвращает сохраненный ответ",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_replay_webhook_delivery.ReplayWebhookDeliveryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Удаление вебхука по ID вместе с историей его доставок. Неотправленные события на этот URL больше не отправляются.",
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Удаление вебхука",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ключ идемпотентности: повтор запроса с тем же ключом возвращает сохраненный ответ",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "No Content",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/deliveries": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Получение истории доставок событий на вебхук, новые первыми. Статусы: pending - ожидает отправки или повтора (next_attempt_at), delivered - доставлено, dead - все попытки исчерпаны. Неудачная доставка повторяется с экспоненциальной задержкой.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Получение доставок вебхука",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "pending",
+                            "delivered",
+                            "dead"
+                        ],
+                        "type": "string",
+                        "description": "Статус доставки",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Размер страницы",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler_get_webhook_deliveries.GetWebhookDeliveriesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "github_com_4udiwe_subscription-service_internal_entity.Offer": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "durationMonths": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "priceID": {
+                    "type": "string"
+                },
+                "trialDays": {
+                    "type": "integer"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_4udiwe_subscription-service_internal_handler.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "subscription_not_found"
+                },
+                "detail": {
+                    "type": "string",
+                    "example": "subscription not found"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_4udiwe_subscription-service_pkg_validator.FieldError"
+                    }
+                },
+                "instance": {
+                    "type": "string",
+                    "example": "/subscriptions/0b6f2a43-7e53-4c5e-9b0f-0c2b6f6c9d11"
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 404
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Not Found"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "about:blank"
+                }
+            }
+        },
+        "github_com_4udiwe_subscription-service_pkg_validator.FieldError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "rule": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler_cancel_sub.CancelSubscriptionRequest": {
+            "type": "object",
+            "properties": {
+                "at_period_end": {
+                    "type": "boolean"
+                },
+                "reason": {
+                    "type": "string",
+                    "maxLength": 500
+                }
+            }
+        },
+        "internal_handler_cancel_sub.CancelSubscriptionResponse": {
+            "type": "object",
+            "properties": {
+                "cancel_reason": {
+                    "type": "string"
+                },
+                "cancelled_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "offer_name": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "subscription_id": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler_change_plan.ChangePlanRequest": {
+            "type": "object",
+            "required": [
+                "offer_id"
+            ],
+            "properties": {
+                "offer_id": {
+                    "type": "string"
+                },
+                "switch_date": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler_change_plan.ChangePlanResponse": {
+            "type": "object",
+            "properties": {
+                "current": {
+                    "$ref": "#/definitions/internal_handler_change_plan.Subscription"
+                },
+                "previous": {
+                    "$ref": "#/definitions/internal_handler_change_plan.Subscription"
+                },
+                "proration_credit": {
+                    "type": "integer"
+                },
+                "unused_days": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_handler_change_plan.Subscription": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "offer_id": {
+                    "type": "string"
+                },
+                "offer_name": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "subscription_id": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler_delete_offer.DeleteOfferRequest": {
+            "type": "object",
+            "required": [
+                "offer_id"
+            ],
+            "properties": {
+                "offer_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler_delete_sub.DeleteSubscriptionRequest": {
+            "type": "object",
+            "required": [
+                "subscription_id"
+            ],
+            "properties": {
+                "subscription_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler_get_api_keys.APIKey": {
+            "type": "object",
+            "properties": {
+                "api_key_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "internal_handler_get_api_keys.GetAPIKeysResponse": {
+            "type": "object",
+            "properties": {
+                "api_keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handler_get_api_keys.APIKey"
+                    }
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "page_size": {
+                    "type": "integer"
+                },
+                "total_items": {
+                    "type": "integer"
+                },
+                "total_pages": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_handler_get_audit.Entry": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "actor": {
+                    "type": "string"
+                },
+                "after": {
+                    "type": "object"
+                },
+                "before": {
+                    "type": "object"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "entity_id": {
+                    "type": "string"
+                },
+                "entity_type": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_handler_get_audit.GetAuditResponse": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handler_get_audit.Entry"
+                    }
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "page_size": {
+                    "type": "integer"
+                },
+                "total_items": {
+                    "type": "integer"
+                },
+                "total_pages": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_handler_get_cost_report.GetCostReportResponse": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "months": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handler_get_cost_report.MonthlyCost"
+                    }
+                },
+                "services": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handler_get_cost_report.ServiceCost"
+                    }
+                },
+                "to": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_handler_get_cost_report.MonthlyCost": {
+            "type": "object",
+            "properties": {
+                "month": {
+                    "type": "string"
+                },
+                "services": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handler_get_cost_report.ServiceCost"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_handler_get_cost_report.ServiceCost": {
+            "type": "object",
+            "properties": {
+                "cost": {
+                    "type": "integer"
+                },
+                "service_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler_get_exchange_rates.ExchangeRate": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "rate": {
+                    "type": "number"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler_get_exchange_rates.GetExchangeRatesResponse": {
+            "type": "object",
+            "properties": {
+                "page": {
+                    "type": "integer"
+                },
+                "page_size": {
+                    "type": "integer"
+                },
+                "rates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handler_get_exchange_rates.ExchangeRate"
+                    }
+                },
+                "total_items": {
+                    "type": "integer"
+                },
+                "total_pages": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_handler_get_offer.GetOfferResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "duration_months": {
+                    "type": "integer"
+                },
+                "offer_id": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "service_name": {
+                    "type": "string"
+                },
+                "trial_days": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler_get_offer_prices.GetOfferPricesResponse": {
+            "type": "object",
+            "properties": {
+                "prices": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handler_get_offer_prices.Price"
+                    }
+                }
+            }
+        },
+        "internal_handler_get_offer_prices.Price": {
+            "type": "object",
+            "properties": {
+                "applied_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "effective_from": {
+                    "type": "string"
+                },
+                "failed_at": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "price_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler_get_offers.GetAllOffersResponse": {
+            "type": "object",
+            "properties": {
+                "next_cursor": {
+                    "type": "string"
+                },
+                "offers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_entity.Offer"
+                    }
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "page_size": {
+                    "type": "integer"
+                },
+                "total_items": {
+                    "type": "integer"
+                },
+                "total_pages": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_handler_get_promo_codes.GetPromoCodesResponse": {
+            "type": "object",
+            "properties": {
+                "page": {
+                    "type": "integer"
+                },
+                "page_size": {
+                    "type": "integer"
+                },
+                "promo_codes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handler_get_promo_codes.PromoCode"
+                    }
+                },
+                "total_items": {
+                    "type": "integer"
+                },
+                "total_pages": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_handler_get_promo_codes.PromoCode": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "discount_type": {
+                    "type": "string"
+                },
+                "discount_value": {
+                    "type": "integer"
+                },
+                "max_redemptions": {
+                    "type": "integer"
+                },
+                "offer_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "promo_code_id": {
+                    "type": "string"
+                },
+                "redemptions": {
+                    "type": "integer"
+                },
+                "valid_from": {
+                    "type": "string"
+                },
+                "valid_until": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler_get_sub.GetSubscriptionResponse": {
+            "type": "object",
+            "properties": {
+                "auto_renew": {
+                    "type": "boolean"
+                },
+                "cancel_reason": {
+                    "type": "string"
+                },
+                "cancelled_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "duration_months": {
+                    "type": "integer"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "list_price": {
+                    "type": "integer"
+                },
+                "offer_id": {
+                    "type": "string"
+                },
+                "offer_name": {
+                    "type": "string"
+                },
+                "pauses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handler_get_sub.Pause"
+                    }
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "renewed_from_id": {
+                    "type": "string"
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "subscription_id": {
+                    "type": "string"
+                },
+                "trial_end_date": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler_get_sub.Pause": {
+            "type": "object",
+            "properties": {
+                "paused_at": {
+                    "type": "string"
+                },
+                "resumed_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler_get_subs.GetAllSubscriptionsResponse": {
+            "type": "object",
+            "properties": {
+                "next_cursor": {
+                    "type": "string"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "page_size": {
+                    "type": "integer"
+                },
+                "subscriptions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handler_get_subs.Subscription"
+                    }
+                },
+                "total_items": {
+                    "type": "integer"
+                },
+                "total_pages": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_handler_get_subs.Subscription": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "list_price": {
+                    "type": "integer"
+                },
+                "offer_name": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "subscription_id": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler_get_subs_by_user.GetSubscriptionsByUserRequest": {
+            "type": "object",
+            "required": [
+                "user_id"
+            ],
+            "properties": {
+                "cursor": {
+                    "type": "string"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "pageSize": {
+                    "type": "integer"
+                },
+                "pagination": {
+                    "type": "string",
+                    "enum": [
+                        "offset",
+                        "cursor"
+                    ]
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "active",
+                        "cancelled",
+                        "expired",
+                        "paused"
+                    ]
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler_get_subs_by_user.GetSubscriptionsByUserResponse": {
+            "type": "object",
+            "properties": {
+                "next_cursor": {
+                    "type": "string"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "page_size": {
+                    "type": "integer"
+                },
+                "subscriptions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handler_get_subs_by_user.Subscription"
+                    }
+                },
+                "total_items": {
+                    "type": "integer"
+                },
+                "total_pages": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_handler_get_subs_by_user.Subscription": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "list_price": {
+                    "type": "integer"
+                },
+                "offer_name": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "subscription_id": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler_get_subs_by_user_subname.GetSubsByUserAndServiceNameRequest": {
             "type": "object",
             "required": [
-                "offer_id"
+                "offer_name",
+                "user_id"
             ],
             "properties": {
-                "offer_id": {
+                "currency": {
+                    "type": "string"
+                },
+                "cursor": {
+                    "type": "string"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "offer_name": {
+                    "type": "string"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "pageSize": {
+                    "type": "integer"
+                },
+                "pagination": {
+                    "type": "string",
+                    "enum": [
+                        "offset",
+                        "cursor"
+                    ]
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "active",
+                        "cancelled",
+                        "expired",
+                        "paused"
+                    ]
+                },
+                "user_id": {
                     "type": "string"
                 }
             }
         },
-        "github_com_4udiwe_subscription-service_internal_entity.Offer": {
+        "internal_handler_get_subs_by_user_subname.GetSubsByUserAndServiceNameResponse": {
             "type": "object",
             "properties": {
-                "createdAt": {
+                "currency": {
                     "type": "string"
                 },
-                "durationMonths": {
+                "next_cursor": {
+                    "type": "string"
+                },
+                "page": {
                     "type": "integer"
                 },
-                "id": {
+                "page_size": {
+                    "type": "integer"
+                },
+                "subscriptions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handler_get_subs_by_user_subname.Subscription"
+                    }
+                },
+                "total_items": {
+                    "type": "integer"
+                },
+                "total_pages": {
+                    "type": "integer"
+                },
+                "total_price": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_handler_get_subs_by_user_subname.Subscription": {
+            "type": "object",
+            "properties": {
+                "currency": {
                     "type": "string"
                 },
-                "name": {
+                "end_date": {
+                    "type": "string"
+                },
+                "list_price": {
+                    "type": "integer"
+                },
+                "offer_name": {
                     "type": "string"
                 },
                 "price": {
                     "type": "integer"
                 },
-                "updatedAt": {
+                "start_date": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "subscription_id": {
+                    "type": "string"
+                },
+                "user_id": {
                     "type": "string"
                 }
             }
         },
-        "internal_handler_delete_sub.DeleteSubscriptionRequest": {
+        "internal_handler_get_webhook_deliveries.Delivery": {
             "type": "object",
-            "required": [
-                "subscription_id"
-            ],
             "properties": {
-                "subscription_id": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "delivered_at": {
+                    "type": "string"
+                },
+                "delivery_id": {
+                    "type": "string"
+                },
+                "event_id": {
+                    "type": "integer"
+                },
+                "event_type": {
+                    "type": "string"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "last_status_code": {
+                    "type": "integer"
+                },
+                "next_attempt_at": {
+                    "type": "string"
+                },
+                "payload": {
+                    "type": "object"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "webhook_id": {
                     "type": "string"
                 }
             }
         },
-        "internal_handler_get_offers.GetAllOffersResponse": {
+        "internal_handler_get_webhook_deliveries.GetWebhookDeliveriesResponse": {
             "type": "object",
             "properties": {
-                "offers": {
+                "deliveries": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/github_com_4udiwe_subscription-service_internal_entity.Offer"
+                        "$ref": "#/definitions/internal_handler_get_webhook_deliveries.Delivery"
                     }
                 },
                 "page": {
@@ -517,7 +3868,7 @@ const docTemplate = `{
                 }
             }
         },
-        "internal_handler_get_subs.GetAllSubscriptionsResponse": {
+        "internal_handler_get_webhooks.GetWebhooksResponse": {
             "type": "object",
             "properties": {
                 "page": {
@@ -526,178 +3877,454 @@ const docTemplate = `{
                 "page_size": {
                     "type": "integer"
                 },
-                "subscriptions": {
+                "total_items": {
+                    "type": "integer"
+                },
+                "total_pages": {
+                    "type": "integer"
+                },
+                "webhooks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handler_get_webhooks.Webhook"
+                    }
+                }
+            }
+        },
+        "internal_handler_get_webhooks.Webhook": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "url": {
+                    "type": "string"
+                },
+                "webhook_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler_patch_offer.PatchOfferRequest": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string"
+                },
+                "duration_months": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "price": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "service_name": {
+                    "type": "string",
+                    "minLength": 1
+                },
+                "trial_days": {
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "internal_handler_patch_offer.PatchOfferResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "duration_months": {
+                    "type": "integer"
+                },
+                "offer_id": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "service_name": {
+                    "type": "string"
+                },
+                "trial_days": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler_patch_sub.PatchSubscriptionRequest": {
+            "type": "object",
+            "properties": {
+                "auto_renew": {
+                    "type": "boolean"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "offer_id": {
+                    "type": "string"
+                },
+                "start_date": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler_patch_sub.PatchSubscriptionResponse": {
+            "type": "object",
+            "properties": {
+                "auto_renew": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "offer_id": {
+                    "type": "string"
+                },
+                "offer_name": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "subscription_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler_pause_sub.PauseSubscriptionResponse": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "offer_name": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "subscription_id": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler_post_api_key.PostAPIKeyRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "scopes"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "scopes": {
                     "type": "array",
+                    "minItems": 1,
                     "items": {
-                        "$ref": "#/definitions/internal_handler_get_subs.Subscription"
+                        "type": "string"
                     }
-                },
-                "total_items": {
-                    "type": "integer"
-                },
-                "total_pages": {
-                    "type": "integer"
                 }
             }
         },
-        "internal_handler_get_subs.Subscription": {
+        "internal_handler_post_api_key.PostAPIKeyResponse": {
             "type": "object",
             "properties": {
-                "end_date": {
+                "api_key_id": {
                     "type": "string"
                 },
-                "offer_name": {
+                "created_at": {
                     "type": "string"
                 },
-                "price": {
-                    "type": "integer"
-                },
-                "start_date": {
+                "key": {
                     "type": "string"
                 },
-                "subscription_id": {
+                "name": {
                     "type": "string"
                 },
-                "user_id": {
+                "prefix": {
                     "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "internal_handler_get_subs_by_user.GetSubscriptionsByUserRequest": {
+        "internal_handler_post_exchange_rates.ExchangeRate": {
             "type": "object",
             "required": [
-                "user_id"
+                "date",
+                "from",
+                "rate",
+                "to"
             ],
             "properties": {
-                "page": {
-                    "type": "integer"
+                "date": {
+                    "type": "string"
                 },
-                "pageSize": {
-                    "type": "integer"
+                "from": {
+                    "type": "string"
                 },
-                "user_id": {
+                "rate": {
+                    "type": "number"
+                },
+                "to": {
                     "type": "string"
                 }
             }
         },
-        "internal_handler_get_subs_by_user.GetSubscriptionsByUserResponse": {
+        "internal_handler_post_exchange_rates.PostExchangeRatesRequest": {
             "type": "object",
+            "required": [
+                "rates"
+            ],
             "properties": {
-                "page": {
-                    "type": "integer"
-                },
-                "page_size": {
-                    "type": "integer"
-                },
-                "subscriptions": {
+                "rates": {
                     "type": "array",
+                    "maxItems": 1000,
+                    "minItems": 1,
                     "items": {
-                        "$ref": "#/definitions/internal_handler_get_subs_by_user.Subscription"
+                        "$ref": "#/definitions/internal_handler_post_exchange_rates.ExchangeRate"
                     }
-                },
-                "total_items": {
+                }
+            }
+        },
+        "internal_handler_post_exchange_rates.PostExchangeRatesResponse": {
+            "type": "object",
+            "properties": {
+                "loaded": {
                     "type": "integer"
+                }
+            }
+        },
+        "internal_handler_post_offer.PostOfferRequest": {
+            "type": "object",
+            "required": [
+                "duration_months",
+                "price",
+                "service_name"
+            ],
+            "properties": {
+                "currency": {
+                    "type": "string"
                 },
-                "total_pages": {
-                    "type": "integer"
+                "duration_months": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "price": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "service_name": {
+                    "type": "string"
+                },
+                "trial_days": {
+                    "type": "integer",
+                    "minimum": 0
                 }
             }
         },
-        "internal_handler_get_subs_by_user.Subscription": {
+        "internal_handler_post_offer.PostOfferResponse": {
             "type": "object",
             "properties": {
-                "end_date": {
+                "created_at": {
                     "type": "string"
                 },
-                "offer_name": {
+                "currency": {
                     "type": "string"
                 },
-                "price": {
+                "duration_months": {
                     "type": "integer"
                 },
-                "start_date": {
+                "offer_id": {
                     "type": "string"
                 },
-                "subscription_id": {
-                    "type": "string"
+                "price": {
+                    "type": "integer"
                 },
-                "user_id": {
+                "service_name": {
                     "type": "string"
+                },
+                "trial_days": {
+                    "type": "integer"
                 }
             }
         },
-        "internal_handler_get_subs_by_user_subname.GetSubsByUserAndServiceNameRequest": {
+        "internal_handler_post_offer_price.PostOfferPriceRequest": {
             "type": "object",
             "required": [
-                "offer_name",
-                "user_id"
+                "effective_from",
+                "price"
             ],
             "properties": {
-                "end_date": {
+                "currency": {
                     "type": "string"
                 },
-                "offer_name": {
+                "effective_from": {
                     "type": "string"
                 },
-                "page": {
-                    "type": "integer"
+                "price": {
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "internal_handler_post_offer_price.PostOfferPriceResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
                 },
-                "pageSize": {
+                "currency": {
+                    "type": "string"
+                },
+                "effective_from": {
+                    "type": "string"
+                },
+                "offer_id": {
+                    "type": "string"
+                },
+                "price": {
                     "type": "integer"
                 },
-                "start_date": {
+                "price_id": {
                     "type": "string"
                 },
-                "user_id": {
+                "status": {
                     "type": "string"
                 }
             }
         },
-        "internal_handler_get_subs_by_user_subname.GetSubsByUserAndServiceNameResponse": {
+        "internal_handler_post_promo_code.PostPromoCodeRequest": {
             "type": "object",
+            "required": [
+                "code",
+                "discount_type",
+                "discount_value"
+            ],
             "properties": {
-                "page": {
-                    "type": "integer"
+                "code": {
+                    "type": "string",
+                    "maxLength": 64,
+                    "minLength": 1
                 },
-                "page_size": {
-                    "type": "integer"
+                "currency": {
+                    "type": "string"
                 },
-                "subscriptions": {
+                "discount_type": {
+                    "type": "string",
+                    "enum": [
+                        "percent",
+                        "fixed"
+                    ]
+                },
+                "discount_value": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "max_redemptions": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "offer_ids": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/internal_handler_get_subs_by_user_subname.Subscription"
+                        "type": "string"
                     }
                 },
-                "total_items": {
-                    "type": "integer"
-                },
-                "total_pages": {
-                    "type": "integer"
+                "valid_from": {
+                    "type": "string"
                 },
-                "total_price": {
-                    "type": "integer"
+                "valid_until": {
+                    "type": "string"
                 }
             }
         },
-        "internal_handler_get_subs_by_user_subname.Subscription": {
+        "internal_handler_post_promo_code.PostPromoCodeResponse": {
             "type": "object",
             "properties": {
-                "end_date": {
+                "code": {
                     "type": "string"
                 },
-                "offer_name": {
+                "created_at": {
                     "type": "string"
                 },
-                "price": {
+                "currency": {
+                    "type": "string"
+                },
+                "discount_type": {
+                    "type": "string"
+                },
+                "discount_value": {
                     "type": "integer"
                 },
-                "start_date": {
+                "max_redemptions": {
+                    "type": "integer"
+                },
+                "offer_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "promo_code_id": {
                     "type": "string"
                 },
-                "subscription_id": {
+                "redemptions": {
+                    "type": "integer"
+                },
+                "valid_from": {
                     "type": "string"
                 },
-                "user_id": {
+                "valid_until": {
                     "type": "string"
                 }
             }
@@ -711,6 +4338,12 @@ const docTemplate = `{
                 "user_id"
             ],
             "properties": {
+                "auto_renew": {
+                    "type": "boolean"
+                },
+                "currency": {
+                    "type": "string"
+                },
                 "end_date": {
                     "type": "string"
                 },
@@ -718,9 +4351,16 @@ const docTemplate = `{
                     "type": "integer",
                     "minimum": 0
                 },
+                "promo_code": {
+                    "type": "string",
+                    "minLength": 1
+                },
                 "service_name": {
                     "type": "string"
                 },
+                "skip_trial": {
+                    "type": "boolean"
+                },
                 "start_date": {
                     "type": "string"
                 },
@@ -732,6 +4372,12 @@ const docTemplate = `{
         "internal_handler_post_sub_by_name.PostSubscriptionByNameResponse": {
             "type": "object",
             "properties": {
+                "auto_renew": {
+                    "type": "boolean"
+                },
+                "currency": {
+                    "type": "string"
+                },
                 "end_date": {
                     "type": "string"
                 },
@@ -747,6 +4393,9 @@ const docTemplate = `{
                 "subscription_id": {
                     "type": "string"
                 },
+                "trial_end_date": {
+                    "type": "string"
+                },
                 "user_id": {
                     "type": "string"
                 }
@@ -760,9 +4409,19 @@ const docTemplate = `{
                 "user_id"
             ],
             "properties": {
+                "auto_renew": {
+                    "type": "boolean"
+                },
                 "offer_id": {
                     "type": "string"
                 },
+                "promo_code": {
+                    "type": "string",
+                    "minLength": 1
+                },
+                "skip_trial": {
+                    "type": "boolean"
+                },
                 "start_date": {
                     "type": "string"
                 },
@@ -774,6 +4433,12 @@ const docTemplate = `{
         "internal_handler_post_sub_by_offer_id.PostSubscriptionByOfferIDResponse": {
             "type": "object",
             "properties": {
+                "auto_renew": {
+                    "type": "boolean"
+                },
+                "currency": {
+                    "type": "string"
+                },
                 "end_date": {
                     "type": "string"
                 },
@@ -789,52 +4454,135 @@ const docTemplate = `{
                 "subscription_id": {
                     "type": "string"
                 },
+                "trial_end_date": {
+                    "type": "string"
+                },
                 "user_id": {
                     "type": "string"
                 }
             }
         },
-        "post_offer.PostOfferRequest": {
+        "internal_handler_post_webhook.PostWebhookRequest": {
             "type": "object",
             "required": [
-                "duration_months",
-                "price",
-                "service_name"
+                "event_types",
+                "url"
             ],
             "properties": {
-                "duration_months": {
-                    "type": "integer",
-                    "minimum": 1
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "price": {
-                    "type": "integer",
-                    "minimum": 0
+                "secret": {
+                    "type": "string",
+                    "maxLength": 256,
+                    "minLength": 16
                 },
-                "service_name": {
+                "url": {
                     "type": "string"
                 }
             }
         },
-        "post_offer.PostOfferResponse": {
+        "internal_handler_post_webhook.PostWebhookResponse": {
             "type": "object",
             "properties": {
                 "created_at": {
                     "type": "string"
                 },
-                "duration_months": {
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "secret": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "webhook_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler_replay_webhook_delivery.ReplayWebhookDeliveryResponse": {
+            "type": "object",
+            "properties": {
+                "attempts": {
                     "type": "integer"
                 },
-                "offer_id": {
+                "created_at": {
+                    "type": "string"
+                },
+                "delivery_id": {
+                    "type": "string"
+                },
+                "event_id": {
+                    "type": "integer"
+                },
+                "event_type": {
+                    "type": "string"
+                },
+                "next_attempt_at": {
+                    "type": "string"
+                },
+                "payload": {
+                    "type": "object"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "webhook_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler_resume_sub.ResumeSubscriptionResponse": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "offer_name": {
                     "type": "string"
                 },
                 "price": {
                     "type": "integer"
                 },
-                "service_name": {
+                "start_date": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "subscription_id": {
+                    "type": "string"
+                },
+                "user_id": {
                     "type": "string"
                 }
             }
         }
+    },
+    "securityDefinitions": {
+        "APIKeyAuth": {
+            "description": "API-ключ сервиса, выпускается через POST /api_keys",
+            "type": "apiKey",
+            "name": "X-API-Key",
+            "in": "header"
+        },
+        "BearerAuth": {
+            "description": "JWT в формате \"Bearer \u003ctoken\u003e\"",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
+        }
     }
 }`
 
@@ -3,14 +3,39 @@ package app
 import (
 	"context"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/4udiwe/subscription-service/config"
 	"github.com/4udiwe/subscription-service/internal/database"
 	"github.com/4udiwe/subscription-service/internal/handler"
+	"github.com/4udiwe/subscription-service/internal/metrics"
+	api_key_repo "github.com/4udiwe/subscription-service/internal/repository/api_key"
+	audit_repo "github.com/4udiwe/subscription-service/internal/repository/audit"
+	exchange_rate_repo "github.com/4udiwe/subscription-service/internal/repository/exchange_rate"
+	idempotency_repo "github.com/4udiwe/subscription-service/internal/repository/idempotency"
 	offer_repo "github.com/4udiwe/subscription-service/internal/repository/offer"
+	outbox_repo "github.com/4udiwe/subscription-service/internal/repository/outbox"
+	promo_code_repo "github.com/4udiwe/subscription-service/internal/repository/promo_code"
 	subscription_repo "github.com/4udiwe/subscription-service/internal/repository/subscription"
+	webhook_repo "github.com/4udiwe/subscription-service/internal/repository/webhook"
+	"github.com/4udiwe/subscription-service/internal/service/api_key"
+	"github.com/4udiwe/subscription-service/internal/service/audit"
+	"github.com/4udiwe/subscription-service/internal/service/exchange_rate"
+	"github.com/4udiwe/subscription-service/internal/service/idempotency"
 	"github.com/4udiwe/subscription-service/internal/service/offer"
+	"github.com/4udiwe/subscription-service/internal/service/outbox"
+	"github.com/4udiwe/subscription-service/internal/service/promo_code"
 	"github.com/4udiwe/subscription-service/internal/service/subscription"
+	"github.com/4udiwe/subscription-service/internal/service/webhook"
+	"github.com/4udiwe/subscription-service/internal/worker/delivery"
+	"github.com/4udiwe/subscription-service/internal/worker/expiry"
+	"github.com/4udiwe/subscription-service/internal/worker/pricing"
+	"github.com/4udiwe/subscription-service/internal/worker/relay"
+	"github.com/4udiwe/subscription-service/internal/worker/renewal"
+	"github.com/4udiwe/subscription-service/internal/worker/stats"
+	"github.com/4udiwe/subscription-service/internal/worker/sweeper"
+	"github.com/4udiwe/subscription-service/pkg/auth"
 	"github.com/4udiwe/subscription-service/pkg/httpserver"
 	"github.com/4udiwe/subscription-service/pkg/postgres"
 	"github.com/labstack/echo/v4"
@@ -27,17 +52,54 @@ type App struct {
 	// Echo
 	echoHandler *echo.Echo
 
+	// Auth
+	authVerifier *auth.Verifier
+
 	// Repositories
-	offerRepo *offer_repo.Repository
-	subRepo   *subscription_repo.Repository
+	offerRepo   *offer_repo.Repository
+	subRepo     *subscription_repo.Repository
+	rateRepo    *exchange_rate_repo.Repository
+	promoRepo   *promo_code_repo.Repository
+	outboxRepo  *outbox_repo.Repository
+	webhookRepo *webhook_repo.Repository
+	auditRepo   *audit_repo.Repository
+	apiKeyRepo  *api_key_repo.Repository
+	idemRepo    *idempotency_repo.Repository
 
 	// Services
-	offerService *offer.OfferService
-	subService   *subscription.SubscriptionService
+	offerService   *offer.OfferService
+	subService     *subscription.SubscriptionService
+	rateService    *exchange_rate.ExchangeRateService
+	promoService   *promo_code.PromoCodeService
+	outboxService  *outbox.OutboxService
+	webhookService *webhook.WebhookService
+	auditService   *audit.AuditService
+	apiKeyService  *api_key.APIKeyService
+	idemService    *idempotency.IdempotencyService
+
+	// Publishers
+	outboxPublisher outbox.Publisher
+	outboxFile      *os.File
+
+	// Workers
+	renewalWorker  *renewal.Worker
+	expiryWorker   *expiry.Worker
+	relayWorker    *relay.Worker
+	pricingWorker  *pricing.Worker
+	deliveryWorker *delivery.Worker
+	sweeperWorker  *sweeper.Worker
+	statsWorker    *stats.Worker
 
 	// Handlers
 	deleteSubscriptionHandler handler.Handler
-
+	deleteOfferHandler        handler.Handler
+	cancelSubscriptionHandler handler.Handler
+
+	getOfferHandler                         handler.Handler
+	getOfferPricesHandler                   handler.Handler
+	postOfferPriceHandler                   handler.Handler
+	deleteOfferPriceHandler                 handler.Handler
+	getSubscriptionHandler                  handler.Handler
 	getOffersHandler                        handler.Handler
 	getSubscriptionsHandler                 handler.Handler
 	getSubscriptionsByUserHandler           handler.Handler
@@ -45,6 +107,33 @@ type App struct {
 
 	postSubciptionByNameHandler    handler.Handler
 	postSubciptionByOfferIDHandler handler.Handler
+	postOfferHandler               handler.Handler
+
+	patchOfferHandler        handler.Handler
+	patchSubscriptionHandler handler.Handler
+
+	pauseSubscriptionHandler  handler.Handler
+	resumeSubscriptionHandler handler.Handler
+	changePlanHandler         handler.Handler
+	getCostReportHandler      handler.Handler
+
+	getExchangeRatesHandler  handler.Handler
+	postExchangeRatesHandler handler.Handler
+
+	getPromoCodesHandler handler.Handler
+	postPromoCodeHandler handler.Handler
+
+	postWebhookHandler           handler.Handler
+	getWebhooksHandler           handler.Handler
+	deleteWebhookHandler         handler.Handler
+	getWebhookDeliveriesHandler  handler.Handler
+	replayWebhookDeliveryHandler handler.Handler
+
+	getAuditHandler handler.Handler
+
+	postAPIKeyHandler   handler.Handler
+	getAPIKeysHandler   handler.Handler
+	deleteAPIKeyHandler handler.Handler
 }
 
 func New(configPath string) *App {
@@ -55,16 +144,24 @@ func New(configPath string) *App {
 
 	initLogger(cfg.Log.Level)
 
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+
 	return &App{
-		cfg: cfg,
+		cfg:       cfg,
+		interrupt: interrupt,
 	}
 }
 
 func (app *App) Start() {
+	// Tracing
+	tracer := app.initTracing()
+	defer app.shutdownTracing(tracer)
+
 	// Postgres
 	log.Info("Connecting to PostgreSQL...")
 
-	postgres, err := postgres.New(app.cfg.Postgres.URL, postgres.ConnAttempts(5))
+	postgres, err := postgres.New(app.cfg.Postgres.URL, postgres.ConnAttempts(5), postgres.Tracer(postgres.NewQueryTracer()))
 
 	if err != nil {
 		log.Fatalf("app - Start - Postgres failed:%v", err)
@@ -73,11 +170,59 @@ func (app *App) Start() {
 
 	defer postgres.Close()
 
+	if app.cfg.Metrics.Enabled {
+		metrics.Registry.MustRegister(metrics.NewPoolCollector(postgres.Pool))
+	}
+
 	// Migrations
 	if err := database.RunMigrations(context.Background(), app.postgres.Pool); err != nil {
 		log.Errorf("app - Start - Migrations failed: %v", err)
 	}
 
+	// Workers
+	if app.cfg.Renewal.Enabled {
+		log.Info("Starting renewal worker...")
+		app.RenewalWorker().Start()
+		defer app.RenewalWorker().Stop()
+	}
+
+	if app.cfg.Expiry.Enabled {
+		log.Info("Starting expiry worker...")
+		app.ExpiryWorker().Start()
+		defer app.ExpiryWorker().Stop()
+	}
+
+	if app.cfg.Pricing.Enabled {
+		log.Info("Starting pricing worker...")
+		app.PricingWorker().Start()
+		defer app.PricingWorker().Stop()
+	}
+
+	if app.cfg.Outbox.Enabled {
+		log.Info("Starting outbox relay worker...")
+		app.RelayWorker().Start()
+		defer app.closeOutboxFile()
+		defer app.RelayWorker().Stop()
+	}
+
+	if app.cfg.Webhooks.Enabled {
+		log.Info("Starting webhook delivery worker...")
+		app.DeliveryWorker().Start()
+		defer app.DeliveryWorker().Stop()
+	}
+
+	if app.cfg.Idempotency.Enabled {
+		log.Info("Starting idempotency key sweeper...")
+		app.SweeperWorker().Start()
+		defer app.SweeperWorker().Stop()
+	}
+
+	if app.cfg.Metrics.Enabled {
+		log.Info("Starting metrics worker...")
+		app.StatsWorker().Start()
+		defer app.StatsWorker().Stop()
+	}
+
 	// App server
 	log.Info("Starting app server...")
 	httpServer := httpserver.New(app.EchoHandler(), httpserver.Port(app.cfg.HTTP.Port))
@@ -0,0 +1,67 @@
+package app
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/4udiwe/subscription-service/internal/handler/middleware"
+	"github.com/4udiwe/subscription-service/internal/service/subscription"
+	"github.com/4udiwe/subscription-service/pkg/auth"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+)
+
+// AuthVerifier returns the verifier of bearer tokens, nil when auth.enabled is off.
+func (app *App) AuthVerifier() *auth.Verifier {
+	if app.authVerifier != nil || !app.cfg.Auth.Enabled {
+		return app.authVerifier
+	}
+
+	verifier, err := auth.NewVerifier(
+		auth.HS256Secret(app.cfg.Auth.HS256Secret),
+		auth.JWKSFile(app.cfg.Auth.JWKSFile),
+		auth.Issuer(app.cfg.Auth.Issuer),
+		auth.Audience(app.cfg.Auth.Audience),
+		auth.RolesClaim(app.cfg.Auth.RolesClaim),
+	)
+	if err != nil {
+		log.Fatalf("app - AuthVerifier - auth.NewVerifier: %v", err)
+	}
+
+	app.authVerifier = verifier
+	return app.authVerifier
+}
+
+// apiMiddlewares returns the middlewares of the API route groups: authentication, then idempotency
+// keys, which belong to the authenticated caller.
+func (app *App) apiMiddlewares() []echo.MiddlewareFunc {
+	var middlewares []echo.MiddlewareFunc
+	if app.cfg.Auth.Enabled {
+		middlewares = append(middlewares, middleware.Authenticate(app.AuthVerifier(), app.APIKeyService()))
+	} else {
+		log.Warn("app - authentication is disabled, every endpoint is open")
+	}
+	if app.cfg.Idempotency.Enabled {
+		middlewares = append(middlewares, middleware.Idempotency(app.IdempotencyService()))
+	}
+	return middlewares
+}
+
+// subscriptionOwner returns the user that owns the subscription named in the id path parameter.
+func (app *App) subscriptionOwner(c echo.Context) (uuid.UUID, error) {
+	subID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return uuid.Nil, echo.NewHTTPError(http.StatusBadRequest, "invalid subscription id")
+	}
+
+	sub, err := app.SubscriptionService().GetSubscriptionByID(c.Request().Context(), subID)
+	if err != nil {
+		if errors.Is(err, subscription.ErrSubscriptionNotFound) {
+			return uuid.Nil, echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+		}
+		return uuid.Nil, echo.NewHTTPError(http.StatusInternalServerError, err.Error()).SetInternal(err)
+	}
+
+	return sub.UserID, nil
+}
@@ -1,8 +1,15 @@
 package app
 
 import (
+	api_key_repo "github.com/4udiwe/subscription-service/internal/repository/api_key"
+	audit_repo "github.com/4udiwe/subscription-service/internal/repository/audit"
+	exchange_rate_repo "github.com/4udiwe/subscription-service/internal/repository/exchange_rate"
+	idempotency_repo "github.com/4udiwe/subscription-service/internal/repository/idempotency"
 	offer_repo "github.com/4udiwe/subscription-service/internal/repository/offer"
+	outbox_repo "github.com/4udiwe/subscription-service/internal/repository/outbox"
+	promo_code_repo "github.com/4udiwe/subscription-service/internal/repository/promo_code"
 	subscription_repo "github.com/4udiwe/subscription-service/internal/repository/subscription"
+	webhook_repo "github.com/4udiwe/subscription-service/internal/repository/webhook"
 	"github.com/4udiwe/subscription-service/pkg/postgres"
 )
 
@@ -25,3 +32,59 @@ func (app *App) SubscriptionRepo() *subscription_repo.Repository {
 	app.subRepo = subscription_repo.New(app.Postgres())
 	return app.subRepo
 }
+
+func (app *App) ExchangeRateRepo() *exchange_rate_repo.Repository {
+	if app.rateRepo != nil {
+		return app.rateRepo
+	}
+	app.rateRepo = exchange_rate_repo.New(app.Postgres())
+	return app.rateRepo
+}
+
+func (app *App) PromoCodeRepo() *promo_code_repo.Repository {
+	if app.promoRepo != nil {
+		return app.promoRepo
+	}
+	app.promoRepo = promo_code_repo.New(app.Postgres())
+	return app.promoRepo
+}
+
+func (app *App) OutboxRepo() *outbox_repo.Repository {
+	if app.outboxRepo != nil {
+		return app.outboxRepo
+	}
+	app.outboxRepo = outbox_repo.New(app.Postgres())
+	return app.outboxRepo
+}
+
+func (app *App) WebhookRepo() *webhook_repo.Repository {
+	if app.webhookRepo != nil {
+		return app.webhookRepo
+	}
+	app.webhookRepo = webhook_repo.New(app.Postgres())
+	return app.webhookRepo
+}
+
+func (app *App) AuditRepo() *audit_repo.Repository {
+	if app.auditRepo != nil {
+		return app.auditRepo
+	}
+	app.auditRepo = audit_repo.New(app.Postgres())
+	return app.auditRepo
+}
+
+func (app *App) APIKeyRepo() *api_key_repo.Repository {
+	if app.apiKeyRepo != nil {
+		return app.apiKeyRepo
+	}
+	app.apiKeyRepo = api_key_repo.New(app.Postgres())
+	return app.apiKeyRepo
+}
+
+func (app *App) IdempotencyRepo() *idempotency_repo.Repository {
+	if app.idemRepo != nil {
+		return app.idemRepo
+	}
+	app.idemRepo = idempotency_repo.New(app.Postgres())
+	return app.idemRepo
+}
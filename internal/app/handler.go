@@ -2,13 +2,40 @@ package app
 
 import (
 	"github.com/4udiwe/subscription-service/internal/handler"
+	"github.com/4udiwe/subscription-service/internal/handler/cancel_sub"
+	"github.com/4udiwe/subscription-service/internal/handler/change_plan"
+	"github.com/4udiwe/subscription-service/internal/handler/delete_api_key"
+	"github.com/4udiwe/subscription-service/internal/handler/delete_offer"
+	"github.com/4udiwe/subscription-service/internal/handler/delete_offer_price"
 	"github.com/4udiwe/subscription-service/internal/handler/delete_sub"
+	"github.com/4udiwe/subscription-service/internal/handler/delete_webhook"
+	"github.com/4udiwe/subscription-service/internal/handler/get_api_keys"
+	"github.com/4udiwe/subscription-service/internal/handler/get_audit"
+	"github.com/4udiwe/subscription-service/internal/handler/get_cost_report"
+	"github.com/4udiwe/subscription-service/internal/handler/get_exchange_rates"
+	"github.com/4udiwe/subscription-service/internal/handler/get_offer"
+	"github.com/4udiwe/subscription-service/internal/handler/get_offer_prices"
 	"github.com/4udiwe/subscription-service/internal/handler/get_offers"
+	"github.com/4udiwe/subscription-service/internal/handler/get_promo_codes"
+	"github.com/4udiwe/subscription-service/internal/handler/get_sub"
 	"github.com/4udiwe/subscription-service/internal/handler/get_subs"
 	"github.com/4udiwe/subscription-service/internal/handler/get_subs_by_user"
 	"github.com/4udiwe/subscription-service/internal/handler/get_subs_by_user_subname"
+	"github.com/4udiwe/subscription-service/internal/handler/get_webhook_deliveries"
+	"github.com/4udiwe/subscription-service/internal/handler/get_webhooks"
+	"github.com/4udiwe/subscription-service/internal/handler/patch_offer"
+	"github.com/4udiwe/subscription-service/internal/handler/patch_sub"
+	"github.com/4udiwe/subscription-service/internal/handler/pause_sub"
+	"github.com/4udiwe/subscription-service/internal/handler/post_api_key"
+	"github.com/4udiwe/subscription-service/internal/handler/post_exchange_rates"
+	"github.com/4udiwe/subscription-service/internal/handler/post_offer"
+	"github.com/4udiwe/subscription-service/internal/handler/post_offer_price"
+	"github.com/4udiwe/subscription-service/internal/handler/post_promo_code"
 	"github.com/4udiwe/subscription-service/internal/handler/post_sub_by_name"
 	"github.com/4udiwe/subscription-service/internal/handler/post_sub_by_offer_id"
+	"github.com/4udiwe/subscription-service/internal/handler/post_webhook"
+	"github.com/4udiwe/subscription-service/internal/handler/replay_webhook_delivery"
+	"github.com/4udiwe/subscription-service/internal/handler/resume_sub"
 )
 
 func (app *App) DeleteProductHandler() handler.Handler {
@@ -19,6 +46,54 @@ func (app *App) DeleteProductHandler() handler.Handler {
 	return app.deleteSubscriptionHandler
 }
 
+func (app *App) CancelSubscriptionHandler() handler.Handler {
+	if app.cancelSubscriptionHandler != nil {
+		return app.cancelSubscriptionHandler
+	}
+	app.cancelSubscriptionHandler = cancel_sub.New(app.SubscriptionService())
+	return app.cancelSubscriptionHandler
+}
+
+func (app *App) DeleteOfferHandler() handler.Handler {
+	if app.deleteOfferHandler != nil {
+		return app.deleteOfferHandler
+	}
+	app.deleteOfferHandler = delete_offer.New(app.OfferService())
+	return app.deleteOfferHandler
+}
+
+func (app *App) GetOfferHandler() handler.Handler {
+	if app.getOfferHandler != nil {
+		return app.getOfferHandler
+	}
+	app.getOfferHandler = get_offer.New(app.OfferService())
+	return app.getOfferHandler
+}
+
+func (app *App) GetOfferPricesHandler() handler.Handler {
+	if app.getOfferPricesHandler != nil {
+		return app.getOfferPricesHandler
+	}
+	app.getOfferPricesHandler = get_offer_prices.New(app.OfferService())
+	return app.getOfferPricesHandler
+}
+
+func (app *App) PostOfferPriceHandler() handler.Handler {
+	if app.postOfferPriceHandler != nil {
+		return app.postOfferPriceHandler
+	}
+	app.postOfferPriceHandler = post_offer_price.New(app.OfferService())
+	return app.postOfferPriceHandler
+}
+
+func (app *App) DeleteOfferPriceHandler() handler.Handler {
+	if app.deleteOfferPriceHandler != nil {
+		return app.deleteOfferPriceHandler
+	}
+	app.deleteOfferPriceHandler = delete_offer_price.New(app.OfferService())
+	return app.deleteOfferPriceHandler
+}
+
 func (app *App) GetOffersHandler() handler.Handler {
 	if app.getOffersHandler != nil {
 		return app.getOffersHandler
@@ -27,6 +102,14 @@ func (app *App) GetOffersHandler() handler.Handler {
 	return app.getOffersHandler
 }
 
+func (app *App) GetSubscriptionHandler() handler.Handler {
+	if app.getSubscriptionHandler != nil {
+		return app.getSubscriptionHandler
+	}
+	app.getSubscriptionHandler = get_sub.New(app.SubscriptionService())
+	return app.getSubscriptionHandler
+}
+
 func (app *App) GetSubscriptionsHandler() handler.Handler {
 	if app.getSubscriptionsHandler != nil {
 		return app.getSubscriptionsHandler
@@ -66,3 +149,163 @@ func (app *App) PostSubciptionByOfferIDHandler() handler.Handler {
 	app.postSubciptionByOfferIDHandler = post_sub_by_offer_id.New(app.SubscriptionService())
 	return app.postSubciptionByOfferIDHandler
 }
+
+func (app *App) PostOfferHandler() handler.Handler {
+	if app.postOfferHandler != nil {
+		return app.postOfferHandler
+	}
+	app.postOfferHandler = post_offer.New(app.OfferService())
+	return app.postOfferHandler
+}
+
+func (app *App) PatchOfferHandler() handler.Handler {
+	if app.patchOfferHandler != nil {
+		return app.patchOfferHandler
+	}
+	app.patchOfferHandler = patch_offer.New(app.OfferService())
+	return app.patchOfferHandler
+}
+
+func (app *App) PatchSubscriptionHandler() handler.Handler {
+	if app.patchSubscriptionHandler != nil {
+		return app.patchSubscriptionHandler
+	}
+	app.patchSubscriptionHandler = patch_sub.New(app.SubscriptionService())
+	return app.patchSubscriptionHandler
+}
+
+func (app *App) PauseSubscriptionHandler() handler.Handler {
+	if app.pauseSubscriptionHandler != nil {
+		return app.pauseSubscriptionHandler
+	}
+	app.pauseSubscriptionHandler = pause_sub.New(app.SubscriptionService())
+	return app.pauseSubscriptionHandler
+}
+
+func (app *App) ResumeSubscriptionHandler() handler.Handler {
+	if app.resumeSubscriptionHandler != nil {
+		return app.resumeSubscriptionHandler
+	}
+	app.resumeSubscriptionHandler = resume_sub.New(app.SubscriptionService())
+	return app.resumeSubscriptionHandler
+}
+
+func (app *App) ChangePlanHandler() handler.Handler {
+	if app.changePlanHandler != nil {
+		return app.changePlanHandler
+	}
+	app.changePlanHandler = change_plan.New(app.SubscriptionService())
+	return app.changePlanHandler
+}
+
+func (app *App) GetCostReportHandler() handler.Handler {
+	if app.getCostReportHandler != nil {
+		return app.getCostReportHandler
+	}
+	app.getCostReportHandler = get_cost_report.New(app.SubscriptionService())
+	return app.getCostReportHandler
+}
+
+func (app *App) GetExchangeRatesHandler() handler.Handler {
+	if app.getExchangeRatesHandler != nil {
+		return app.getExchangeRatesHandler
+	}
+	app.getExchangeRatesHandler = get_exchange_rates.New(app.ExchangeRateService())
+	return app.getExchangeRatesHandler
+}
+
+func (app *App) PostExchangeRatesHandler() handler.Handler {
+	if app.postExchangeRatesHandler != nil {
+		return app.postExchangeRatesHandler
+	}
+	app.postExchangeRatesHandler = post_exchange_rates.New(app.ExchangeRateService())
+	return app.postExchangeRatesHandler
+}
+
+func (app *App) GetPromoCodesHandler() handler.Handler {
+	if app.getPromoCodesHandler != nil {
+		return app.getPromoCodesHandler
+	}
+	app.getPromoCodesHandler = get_promo_codes.New(app.PromoCodeService())
+	return app.getPromoCodesHandler
+}
+
+func (app *App) PostPromoCodeHandler() handler.Handler {
+	if app.postPromoCodeHandler != nil {
+		return app.postPromoCodeHandler
+	}
+	app.postPromoCodeHandler = post_promo_code.New(app.PromoCodeService())
+	return app.postPromoCodeHandler
+}
+
+func (app *App) PostWebhookHandler() handler.Handler {
+	if app.postWebhookHandler != nil {
+		return app.postWebhookHandler
+	}
+	app.postWebhookHandler = post_webhook.New(app.WebhookService())
+	return app.postWebhookHandler
+}
+
+func (app *App) GetWebhooksHandler() handler.Handler {
+	if app.getWebhooksHandler != nil {
+		return app.getWebhooksHandler
+	}
+	app.getWebhooksHandler = get_webhooks.New(app.WebhookService())
+	return app.getWebhooksHandler
+}
+
+func (app *App) DeleteWebhookHandler() handler.Handler {
+	if app.deleteWebhookHandler != nil {
+		return app.deleteWebhookHandler
+	}
+	app.deleteWebhookHandler = delete_webhook.New(app.WebhookService())
+	return app.deleteWebhookHandler
+}
+
+func (app *App) GetWebhookDeliveriesHandler() handler.Handler {
+	if app.getWebhookDeliveriesHandler != nil {
+		return app.getWebhookDeliveriesHandler
+	}
+	app.getWebhookDeliveriesHandler = get_webhook_deliveries.New(app.WebhookService())
+	return app.getWebhookDeliveriesHandler
+}
+
+func (app *App) ReplayWebhookDeliveryHandler() handler.Handler {
+	if app.replayWebhookDeliveryHandler != nil {
+		return app.replayWebhookDeliveryHandler
+	}
+	app.replayWebhookDeliveryHandler = replay_webhook_delivery.New(app.WebhookService())
+	return app.replayWebhookDeliveryHandler
+}
+
+func (app *App) GetAuditHandler() handler.Handler {
+	if app.getAuditHandler != nil {
+		return app.getAuditHandler
+	}
+	app.getAuditHandler = get_audit.New(app.AuditService())
+	return app.getAuditHandler
+}
+
+func (app *App) PostAPIKeyHandler() handler.Handler {
+	if app.postAPIKeyHandler != nil {
+		return app.postAPIKeyHandler
+	}
+	app.postAPIKeyHandler = post_api_key.New(app.APIKeyService())
+	return app.postAPIKeyHandler
+}
+
+func (app *App) GetAPIKeysHandler() handler.Handler {
+	if app.getAPIKeysHandler != nil {
+		return app.getAPIKeysHandler
+	}
+	app.getAPIKeysHandler = get_api_keys.New(app.APIKeyService())
+	return app.getAPIKeysHandler
+}
+
+func (app *App) DeleteAPIKeyHandler() handler.Handler {
+	if app.deleteAPIKeyHandler != nil {
+		return app.deleteAPIKeyHandler
+	}
+	app.deleteAPIKeyHandler = delete_api_key.New(app.APIKeyService())
+	return app.deleteAPIKeyHandler
+}
@@ -0,0 +1,70 @@
+package app
+
+import (
+	"os"
+
+	"github.com/4udiwe/subscription-service/internal/publisher"
+	log_publisher "github.com/4udiwe/subscription-service/internal/publisher/log"
+	webhook_publisher "github.com/4udiwe/subscription-service/internal/publisher/webhook"
+	"github.com/4udiwe/subscription-service/internal/service/outbox"
+	"github.com/labstack/gommon/log"
+)
+
+const (
+	publisherNone    = "none"
+	publisherLog     = "log"
+	publisherWebhook = "webhook"
+)
+
+// OutboxPublisher returns the publisher the relay passes events to. Events go to the webhooks
+// registered over the API when webhooks are enabled, and to the publisher selected by
+// outbox.publisher: "log" writes them to outbox.file (stdout if empty), "webhook" POSTs them
+// to outbox.webhook_url, "none" turns it off.
+func (app *App) OutboxPublisher() outbox.Publisher {
+	if app.outboxPublisher != nil {
+		return app.outboxPublisher
+	}
+
+	var publishers []publisher.Publisher
+	if app.cfg.Webhooks.Enabled {
+		publishers = append(publishers, app.WebhookService())
+	}
+
+	switch app.cfg.Outbox.Publisher {
+	case publisherNone:
+	case publisherLog:
+		w := os.Stdout
+		if app.cfg.Outbox.File != "" {
+			file, err := os.OpenFile(app.cfg.Outbox.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+			if err != nil {
+				log.Fatalf("app - OutboxPublisher - open file: %v", err)
+			}
+			app.outboxFile = file
+			w = file
+		}
+		publishers = append(publishers, log_publisher.New(w))
+	case publisherWebhook:
+		if app.cfg.Outbox.WebhookURL == "" {
+			log.Fatal("app - OutboxPublisher - outbox.webhook_url is required for the webhook publisher")
+		}
+		publishers = append(publishers, webhook_publisher.New(
+			app.cfg.Outbox.WebhookURL,
+			webhook_publisher.Timeout(app.cfg.Outbox.WebhookTimeout),
+			webhook_publisher.Secret(app.cfg.Outbox.WebhookSecret),
+		))
+	default:
+		log.Fatalf("app - OutboxPublisher - unknown publisher %q", app.cfg.Outbox.Publisher)
+	}
+
+	app.outboxPublisher = publisher.NewFanout(publishers...)
+	return app.outboxPublisher
+}
+
+func (app *App) closeOutboxFile() {
+	if app.outboxFile == nil {
+		return
+	}
+	if err := app.outboxFile.Close(); err != nil {
+		log.Errorf("app - closeOutboxFile: %v", err)
+	}
+}
@@ -3,10 +3,17 @@ package app
 import (
 	"fmt"
 	"net/http"
+	"slices"
 
+	"github.com/4udiwe/subscription-service/internal/handler/middleware"
+	"github.com/4udiwe/subscription-service/internal/handler/problem"
+	"github.com/4udiwe/subscription-service/internal/metrics"
+	"github.com/4udiwe/subscription-service/pkg/auth"
 	"github.com/4udiwe/subscription-service/pkg/validator"
 	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	echoSwagger "github.com/swaggo/echo-swagger"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/labstack/echo/otelecho"
 )
 
 func (app *App) EchoHandler() *echo.Echo {
@@ -16,6 +23,13 @@ func (app *App) EchoHandler() *echo.Echo {
 
 	handler := echo.New()
 	handler.Validator = validator.NewCustomValidator()
+	handler.HTTPErrorHandler = problem.HTTPErrorHandler
+	handler.Use(otelecho.Middleware(app.cfg.App.Name, otelecho.WithSkipper(skipTracing)))
+	handler.Use(middleware.RequestID())
+	if app.cfg.Metrics.Enabled {
+		handler.Use(middleware.Metrics())
+	}
+	handler.Use(middleware.Actor())
 
 	app.configureRouter(handler)
 
@@ -31,20 +45,78 @@ func (app *App) configureRouter(handler *echo.Echo) {
 
 	handler.GET("/swagger/*", echoSwagger.WrapHandler)
 
-	offersGroup := handler.Group("offers")
+	api := app.apiMiddlewares()
+	adminOnly := middleware.RequireRole(auth.RoleAdmin)
+	ownerOnly := middleware.RequireOwner(app.subscriptionOwner)
+	adminScope := middleware.RequireScope(auth.ScopeAdmin)
+	subsRead := middleware.RequireScope(auth.ScopeSubscriptionsRead)
+	subsWrite := middleware.RequireScope(auth.ScopeSubscriptionsWrite)
+	offersRead := middleware.RequireScope(auth.ScopeOffersRead)
+	offersWrite := middleware.RequireScope(auth.ScopeOffersWrite)
+	adminAPI := slices.Concat(api, []echo.MiddlewareFunc{adminOnly, adminScope})
+
+	offersGroup := handler.Group("offers", api...)
+	{
+		offersGroup.GET("", app.GetOffersHandler().Handle, offersRead)
+		offersGroup.GET("/:id", app.GetOfferHandler().Handle, offersRead)
+		offersGroup.GET("/:id/prices", app.GetOfferPricesHandler().Handle, offersRead)
+		offersGroup.POST("/:id/prices", app.PostOfferPriceHandler().Handle, adminOnly, offersWrite)
+		offersGroup.DELETE("/:id/prices/:price_id", app.DeleteOfferPriceHandler().Handle, adminOnly, offersWrite)
+		offersGroup.POST("", app.PostOfferHandler().Handle, adminOnly, offersWrite)
+		offersGroup.PATCH("/:id", app.PatchOfferHandler().Handle, adminOnly, offersWrite)
+		offersGroup.DELETE("", app.DeleteOfferHandler().Handle, adminOnly, offersWrite)
+	}
+
+	subsGroup := handler.Group("subscriptions", api...)
 	{
-		offersGroup.GET("", app.GetOffersHandler().Handle)
+		subsGroup.GET("", app.GetSubscriptionsHandler().Handle, adminOnly, subsRead)
+		subsGroup.GET("/by_user", app.GetSubscriptionsByUserHandler().Handle, subsRead)
+		subsGroup.GET("/by_user_service_name", app.GetSubscriptionsByUserAndSubNameHandler().Handle, subsRead)
+		subsGroup.GET("/cost", app.GetCostReportHandler().Handle, subsRead)
+		subsGroup.GET("/:id", app.GetSubscriptionHandler().Handle, subsRead, ownerOnly)
+		subsGroup.POST("/by_name", app.PostSubciptionByNameHandler().Handle, subsWrite)
+		subsGroup.POST("/by_offer_id", app.PostSubciptionByOfferIDHandler().Handle, subsWrite)
+		subsGroup.PATCH("/:id", app.PatchSubscriptionHandler().Handle, subsWrite, ownerOnly)
+		subsGroup.POST("/:id/cancel", app.CancelSubscriptionHandler().Handle, subsWrite, ownerOnly)
+		subsGroup.POST("/:id/pause", app.PauseSubscriptionHandler().Handle, subsWrite, ownerOnly)
+		subsGroup.POST("/:id/resume", app.ResumeSubscriptionHandler().Handle, subsWrite, ownerOnly)
+		subsGroup.POST("/:id/change_plan", app.ChangePlanHandler().Handle, subsWrite, ownerOnly)
+		subsGroup.DELETE("", app.DeleteProductHandler().Handle, subsWrite)
 	}
 
-	subsGroup := handler.Group("subscriptions")
+	ratesGroup := handler.Group("exchange_rates", api...)
 	{
-		subsGroup.GET("", app.GetSubscriptionsHandler().Handle)
-		subsGroup.GET("/by_user", app.GetSubscriptionsByUserHandler().Handle)
-		subsGroup.GET("/by_user_service_name", app.GetSubscriptionsByUserAndSubNameHandler().Handle)
-		subsGroup.POST("/by_name", app.PostSubciptionByNameHandler().Handle)
-		subsGroup.POST("/by_offer_id", app.PostSubciptionByOfferIDHandler().Handle)
-		subsGroup.DELETE("", app.DeleteProductHandler().Handle)
+		ratesGroup.GET("", app.GetExchangeRatesHandler().Handle)
+		ratesGroup.POST("", app.PostExchangeRatesHandler().Handle, adminOnly, adminScope)
 	}
 
+	promoGroup := handler.Group("promo_codes", adminAPI...)
+	{
+		promoGroup.GET("", app.GetPromoCodesHandler().Handle)
+		promoGroup.POST("", app.PostPromoCodeHandler().Handle)
+	}
+
+	webhooksGroup := handler.Group("webhooks", adminAPI...)
+	{
+		webhooksGroup.GET("", app.GetWebhooksHandler().Handle)
+		webhooksGroup.POST("", app.PostWebhookHandler().Handle, middleware.RedactIdempotentResponse("secret"))
+		webhooksGroup.DELETE("/:id", app.DeleteWebhookHandler().Handle)
+		webhooksGroup.GET("/:id/deliveries", app.GetWebhookDeliveriesHandler().Handle)
+		webhooksGroup.POST("/deliveries/:id/replay", app.ReplayWebhookDeliveryHandler().Handle)
+	}
+
+	apiKeysGroup := handler.Group("api_keys", adminAPI...)
+	{
+		apiKeysGroup.GET("", app.GetAPIKeysHandler().Handle)
+		apiKeysGroup.POST("", app.PostAPIKeyHandler().Handle, middleware.RedactIdempotentResponse("key"))
+		apiKeysGroup.DELETE("/:id", app.DeleteAPIKeyHandler().Handle)
+	}
+
+	handler.GET("/audit", app.GetAuditHandler().Handle, adminAPI...)
+
 	handler.GET("/health", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+
+	if app.cfg.Metrics.Enabled {
+		handler.GET("/metrics", echo.WrapHandler(promhttp.HandlerFor(metrics.Registry, promhttp.HandlerOpts{})))
+	}
 }
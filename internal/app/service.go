@@ -1,15 +1,23 @@
 package app
 
 import (
+	webhook_publisher "github.com/4udiwe/subscription-service/internal/publisher/webhook"
+	"github.com/4udiwe/subscription-service/internal/service/api_key"
+	"github.com/4udiwe/subscription-service/internal/service/audit"
+	"github.com/4udiwe/subscription-service/internal/service/exchange_rate"
+	"github.com/4udiwe/subscription-service/internal/service/idempotency"
 	"github.com/4udiwe/subscription-service/internal/service/offer"
+	"github.com/4udiwe/subscription-service/internal/service/outbox"
+	"github.com/4udiwe/subscription-service/internal/service/promo_code"
 	"github.com/4udiwe/subscription-service/internal/service/subscription"
+	"github.com/4udiwe/subscription-service/internal/service/webhook"
 )
 
 func (app *App) OfferService() *offer.OfferService {
 	if app.offerService != nil {
 		return app.offerService
 	}
-	app.offerService = offer.New(app.OfferRepo(), app.SubscriptionRepo(), app.Postgres())
+	app.offerService = offer.New(app.OfferRepo(), app.SubscriptionRepo(), app.OutboxRepo(), app.AuditRepo(), app.Postgres())
 	return app.offerService
 }
 
@@ -17,6 +25,78 @@ func (app *App) SubscriptionService() *subscription.SubscriptionService {
 	if app.subService != nil {
 		return app.subService
 	}
-	app.subService = subscription.New(app.SubscriptionRepo(), app.OfferRepo(), app.Postgres())
+	app.subService = subscription.New(app.SubscriptionRepo(), app.OfferRepo(), app.PromoCodeRepo(), app.OutboxRepo(), app.AuditRepo(), app.Postgres())
 	return app.subService
 }
+
+func (app *App) ExchangeRateService() *exchange_rate.ExchangeRateService {
+	if app.rateService != nil {
+		return app.rateService
+	}
+	app.rateService = exchange_rate.New(app.ExchangeRateRepo(), app.Postgres())
+	return app.rateService
+}
+
+func (app *App) PromoCodeService() *promo_code.PromoCodeService {
+	if app.promoService != nil {
+		return app.promoService
+	}
+	app.promoService = promo_code.New(app.PromoCodeRepo(), app.Postgres())
+	return app.promoService
+}
+
+func (app *App) OutboxService() *outbox.OutboxService {
+	if app.outboxService != nil {
+		return app.outboxService
+	}
+	app.outboxService = outbox.New(
+		app.OutboxRepo(),
+		app.OutboxPublisher(),
+		outbox.MaxAttempts(app.cfg.Outbox.MaxAttempts),
+		outbox.Backoff(app.cfg.Outbox.Backoff, app.cfg.Outbox.MaxBackoff),
+		outbox.Lease(app.cfg.Outbox.Lease),
+	)
+	return app.outboxService
+}
+
+func (app *App) WebhookService() *webhook.WebhookService {
+	if app.webhookService != nil {
+		return app.webhookService
+	}
+	app.webhookService = webhook.New(
+		app.WebhookRepo(),
+		webhook_publisher.NewSender(app.cfg.Webhooks.Timeout),
+		webhook.MaxAttempts(app.cfg.Webhooks.MaxAttempts),
+		webhook.Backoff(app.cfg.Webhooks.Backoff, app.cfg.Webhooks.MaxBackoff),
+		// a claimed delivery must outlive its request
+		webhook.Lease(2*app.cfg.Webhooks.Timeout),
+	)
+	return app.webhookService
+}
+
+func (app *App) AuditService() *audit.AuditService {
+	if app.auditService != nil {
+		return app.auditService
+	}
+	app.auditService = audit.New(app.AuditRepo())
+	return app.auditService
+}
+
+func (app *App) APIKeyService() *api_key.APIKeyService {
+	if app.apiKeyService != nil {
+		return app.apiKeyService
+	}
+	app.apiKeyService = api_key.New(app.APIKeyRepo())
+	return app.apiKeyService
+}
+
+func (app *App) IdempotencyService() *idempotency.IdempotencyService {
+	if app.idemService != nil {
+		return app.idemService
+	}
+	app.idemService = idempotency.New(
+		app.IdempotencyRepo(),
+		idempotency.TTL(app.cfg.Idempotency.TTL),
+	)
+	return app.idemService
+}
@@ -0,0 +1,46 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/4udiwe/subscription-service/pkg/tracing"
+	"github.com/labstack/echo/v4"
+	log "github.com/sirupsen/logrus"
+)
+
+const tracingShutdownTimeout = 5 * time.Second
+
+func (app *App) initTracing() *tracing.Provider {
+	provider, err := tracing.New(
+		context.Background(),
+		tracing.ServiceName(app.cfg.App.Name),
+		tracing.ServiceVersion(app.cfg.App.Version),
+		tracing.Exporter(app.cfg.Tracing.Exporter),
+		tracing.Endpoint(app.cfg.Tracing.Endpoint),
+		tracing.Insecure(app.cfg.Tracing.Insecure),
+		tracing.SampleRatio(app.cfg.Tracing.SampleRatio),
+	)
+	if err != nil {
+		log.Fatalf("app - initTracing - tracing.New: %v", err)
+	}
+
+	log.Infof("Tracing exporter: %s", app.cfg.Tracing.Exporter)
+	return provider
+}
+
+func (app *App) shutdownTracing(provider *tracing.Provider) {
+	ctx, cancel := context.WithTimeout(context.Background(), tracingShutdownTimeout)
+	defer cancel()
+
+	if err := provider.Shutdown(ctx); err != nil {
+		log.Errorf("app - shutdownTracing: %v", err)
+	}
+}
+
+// skipTracing leaves the requests of probes, scrapers and the docs out of the traces.
+func skipTracing(c echo.Context) bool {
+	path := c.Request().URL.Path
+	return path == "/health" || path == "/metrics" || strings.HasPrefix(path, "/swagger/")
+}
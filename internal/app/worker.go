@@ -0,0 +1,102 @@
+package app
+
+import (
+	"github.com/4udiwe/subscription-service/internal/worker/delivery"
+	"github.com/4udiwe/subscription-service/internal/worker/expiry"
+	"github.com/4udiwe/subscription-service/internal/worker/pricing"
+	"github.com/4udiwe/subscription-service/internal/worker/relay"
+	"github.com/4udiwe/subscription-service/internal/worker/renewal"
+	"github.com/4udiwe/subscription-service/internal/worker/stats"
+	"github.com/4udiwe/subscription-service/internal/worker/sweeper"
+)
+
+func (app *App) RenewalWorker() *renewal.Worker {
+	if app.renewalWorker != nil {
+		return app.renewalWorker
+	}
+	app.renewalWorker = renewal.New(
+		app.SubscriptionService(),
+		renewal.Interval(app.cfg.Renewal.Interval),
+		renewal.Window(app.cfg.Renewal.Window),
+		renewal.NoticeWindow(app.cfg.Renewal.NoticeWindow),
+		renewal.BatchSize(app.cfg.Renewal.BatchSize),
+	)
+	return app.renewalWorker
+}
+
+func (app *App) ExpiryWorker() *expiry.Worker {
+	if app.expiryWorker != nil {
+		return app.expiryWorker
+	}
+	// without the renewal worker nothing renews the subscriptions, there is nothing to wait for
+	grace := app.cfg.Expiry.RenewalGrace
+	if !app.cfg.Renewal.Enabled {
+		grace = 0
+	}
+	app.expiryWorker = expiry.New(
+		app.SubscriptionService(),
+		expiry.Interval(app.cfg.Expiry.Interval),
+		expiry.RenewalGrace(grace),
+	)
+	return app.expiryWorker
+}
+
+func (app *App) PricingWorker() *pricing.Worker {
+	if app.pricingWorker != nil {
+		return app.pricingWorker
+	}
+	app.pricingWorker = pricing.New(
+		app.OfferService(),
+		pricing.Interval(app.cfg.Pricing.Interval),
+		pricing.BatchSize(app.cfg.Pricing.BatchSize),
+	)
+	return app.pricingWorker
+}
+
+func (app *App) RelayWorker() *relay.Worker {
+	if app.relayWorker != nil {
+		return app.relayWorker
+	}
+	app.relayWorker = relay.New(
+		app.OutboxService(),
+		relay.Interval(app.cfg.Outbox.Interval),
+		relay.BatchSize(app.cfg.Outbox.BatchSize),
+	)
+	return app.relayWorker
+}
+
+func (app *App) DeliveryWorker() *delivery.Worker {
+	if app.deliveryWorker != nil {
+		return app.deliveryWorker
+	}
+	app.deliveryWorker = delivery.New(
+		app.WebhookService(),
+		delivery.Interval(app.cfg.Webhooks.Interval),
+		delivery.BatchSize(app.cfg.Webhooks.BatchSize),
+	)
+	return app.deliveryWorker
+}
+
+func (app *App) SweeperWorker() *sweeper.Worker {
+	if app.sweeperWorker != nil {
+		return app.sweeperWorker
+	}
+	app.sweeperWorker = sweeper.New(
+		app.IdempotencyService(),
+		sweeper.Interval(app.cfg.Idempotency.Interval),
+		sweeper.BatchSize(app.cfg.Idempotency.BatchSize),
+	)
+	return app.sweeperWorker
+}
+
+func (app *App) StatsWorker() *stats.Worker {
+	if app.statsWorker != nil {
+		return app.statsWorker
+	}
+	app.statsWorker = stats.New(
+		app.SubscriptionService(),
+		app.OfferService(),
+		stats.Interval(app.cfg.Metrics.Interval),
+	)
+	return app.statsWorker
+}
@@ -9,6 +9,9 @@ import (
 const (
 	CodeUniqueViolation     = "23505"
 	CodeForeignKeyViolation = "23503"
+	CodeExclusionViolation  = "23P01"
+	CodeCheckViolation      = "23514"
+	CodeNoDataFound         = "P0002"
 )
 
 func IsUniqueViolation(err error) bool {
@@ -19,6 +22,16 @@ func IsUniqueViolation(err error) bool {
 	return false
 }
 
+// ConstraintName returns the name of the constraint or index the error violates, or "" when the
+// error is not a constraint violation.
+func ConstraintName(err error) string {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.ConstraintName
+	}
+	return ""
+}
+
 func IsForeignKeyViolation(err error) bool {
 	var pgErr *pgconn.PgError
 	if errors.As(err, &pgErr) {
@@ -26,3 +39,28 @@ func IsForeignKeyViolation(err error) bool {
 	}
 	return false
 }
+
+func IsExclusionViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == CodeExclusionViolation
+	}
+	return false
+}
+
+func IsCheckViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == CodeCheckViolation
+	}
+	return false
+}
+
+// IsNoDataFound reports the error raised by convert_price when there is no exchange rate.
+func IsNoDataFound(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == CodeNoDataFound
+	}
+	return false
+}
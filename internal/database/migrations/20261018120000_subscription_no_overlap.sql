@@ -0,0 +1,79 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE EXTENSION IF NOT EXISTS "btree_gist";
+
+-- service name is denormalized from offer so the exclusion constraint can use it
+ALTER TABLE subscription ADD COLUMN IF NOT EXISTS service_name TEXT;
+
+UPDATE subscription s
+SET service_name = o.name
+FROM offer o
+WHERE s.offer_id = o.id;
+
+ALTER TABLE subscription ALTER COLUMN service_name SET NOT NULL;
+
+CREATE OR REPLACE FUNCTION subscription_set_service_name() RETURNS TRIGGER AS $$
+BEGIN
+    SELECT name INTO NEW.service_name FROM offer WHERE id = NEW.offer_id;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_subscription_set_service_name
+    BEFORE INSERT OR UPDATE OF offer_id ON subscription
+    FOR EACH ROW EXECUTE FUNCTION subscription_set_service_name();
+
+CREATE OR REPLACE FUNCTION offer_propagate_name() RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE subscription SET service_name = NEW.name WHERE offer_id = NEW.id;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_offer_propagate_name
+    AFTER UPDATE OF name ON offer
+    FOR EACH ROW WHEN (OLD.name IS DISTINCT FROM NEW.name)
+    EXECUTE FUNCTION offer_propagate_name();
+
+-- rows written before the constraint can overlap, they are not changed silently: the migration
+-- stops and lists them, they have to be fixed by hand before it is run again
+DO $$
+DECLARE
+    conflicts TEXT;
+    total INTEGER;
+BEGIN
+    SELECT count(*), string_agg(format('%s (%s - %s) and %s (%s - %s)', a.id, a.start_date, a.end_date, b.id, b.start_date, b.end_date), E'\n' ORDER BY a.user_id, a.start_date)
+    INTO total, conflicts
+    FROM subscription a
+    JOIN subscription b ON b.user_id = a.user_id
+        AND b.service_name = a.service_name
+        AND (b.start_date, b.id) > (a.start_date, a.id)
+        AND daterange(a.start_date, a.end_date, '[)') && daterange(b.start_date, b.end_date, '[)');
+
+    IF total > 0 THEN
+        RAISE EXCEPTION 'subscription_no_overlap: % pairs of subscriptions of the same user and service overlap:%', total, E'\n' || conflicts
+            USING HINT = 'shorten or delete one subscription of each pair and run the migration again';
+    END IF;
+END;
+$$;
+
+ALTER TABLE subscription ADD CONSTRAINT subscription_no_overlap
+    EXCLUDE USING gist (
+        user_id WITH =,
+        service_name WITH =,
+        daterange(start_date, end_date, '[)') WITH &&
+    );
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE subscription DROP CONSTRAINT IF EXISTS subscription_no_overlap;
+
+DROP TRIGGER IF EXISTS trg_offer_propagate_name ON offer;
+DROP FUNCTION IF EXISTS offer_propagate_name();
+
+DROP TRIGGER IF EXISTS trg_subscription_set_service_name ON subscription;
+DROP FUNCTION IF EXISTS subscription_set_service_name();
+
+ALTER TABLE subscription DROP COLUMN IF EXISTS service_name;
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE subscription ADD COLUMN IF NOT EXISTS auto_renew BOOLEAN NOT NULL DEFAULT false;
+
+-- a subscription can be renewed only once, this keeps the renewal worker idempotent
+ALTER TABLE subscription ADD COLUMN IF NOT EXISTS renewed_from_id UUID NULL REFERENCES subscription(id) ON DELETE SET NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_subscription_renewed_from_id ON subscription(renewed_from_id);
+
+CREATE INDEX IF NOT EXISTS idx_subscription_auto_renew_end_date ON subscription(end_date) WHERE auto_renew;
+
+-- the last failed renewal attempt, failed subscriptions are picked after the others
+ALTER TABLE subscription ADD COLUMN IF NOT EXISTS renewal_failed_at TIMESTAMPTZ NULL;
+ALTER TABLE subscription ADD COLUMN IF NOT EXISTS renewal_error TEXT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_subscription_auto_renew_end_date;
+DROP INDEX IF EXISTS idx_subscription_renewed_from_id;
+
+ALTER TABLE subscription DROP COLUMN IF EXISTS renewal_error;
+ALTER TABLE subscription DROP COLUMN IF EXISTS renewal_failed_at;
+
+ALTER TABLE subscription DROP COLUMN IF EXISTS renewed_from_id;
+ALTER TABLE subscription DROP COLUMN IF EXISTS auto_renew;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE subscription ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'active'
+    CHECK (status IN ('active', 'cancelled', 'expired', 'paused'));
+ALTER TABLE subscription ADD COLUMN IF NOT EXISTS cancelled_at TIMESTAMPTZ NULL;
+ALTER TABLE subscription ADD COLUMN IF NOT EXISTS cancel_reason TEXT NULL;
+
+UPDATE subscription SET status = 'expired' WHERE end_date IS NOT NULL AND end_date <= CURRENT_DATE;
+
+CREATE INDEX IF NOT EXISTS idx_subscription_status ON subscription(status);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_subscription_status;
+
+ALTER TABLE subscription DROP COLUMN IF EXISTS cancel_reason;
+ALTER TABLE subscription DROP COLUMN IF EXISTS cancelled_at;
+ALTER TABLE subscription DROP COLUMN IF EXISTS status;
+-- +goose StatementEnd
@@ -0,0 +1,40 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS subscription_pause (
+    id UUID DEFAULT gen_random_uuid() NOT NULL,
+    subscription_id UUID NOT NULL REFERENCES subscription(id) ON DELETE CASCADE,
+    paused_at DATE NOT NULL,
+    resumed_at DATE NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK (resumed_at IS NULL OR resumed_at >= paused_at),
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_subscription_pause_subscription_id ON subscription_pause(subscription_id);
+-- only one open pause per subscription
+CREATE UNIQUE INDEX IF NOT EXISTS idx_subscription_pause_open ON subscription_pause(subscription_id) WHERE resumed_at IS NULL;
+
+-- a paused subscription will be extended on resume, so it occupies the service until then
+ALTER TABLE subscription DROP CONSTRAINT IF EXISTS subscription_no_overlap;
+ALTER TABLE subscription ADD CONSTRAINT subscription_no_overlap
+    EXCLUDE USING gist (
+        user_id WITH =,
+        service_name WITH =,
+        daterange(start_date, CASE WHEN status = 'paused' THEN NULL ELSE end_date END, '[)') WITH &&
+    );
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE subscription DROP CONSTRAINT IF EXISTS subscription_no_overlap;
+ALTER TABLE subscription ADD CONSTRAINT subscription_no_overlap
+    EXCLUDE USING gist (
+        user_id WITH =,
+        service_name WITH =,
+        daterange(start_date, end_date, '[)') WITH &&
+    );
+
+DROP INDEX IF EXISTS idx_subscription_pause_open;
+DROP INDEX IF EXISTS idx_subscription_pause_subscription_id;
+DROP TABLE IF EXISTS subscription_pause;
+-- +goose StatementEnd
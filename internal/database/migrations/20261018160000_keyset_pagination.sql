@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+-- lists are ordered by (created_at, id) and paged with keyset cursors
+CREATE INDEX IF NOT EXISTS idx_offer_created_at_id ON offer(created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_subscription_created_at_id ON subscription(created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_subscription_user_id_created_at_id ON subscription(user_id, created_at DESC, id DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_subscription_user_id_created_at_id;
+DROP INDEX IF EXISTS idx_subscription_created_at_id;
+DROP INDEX IF EXISTS idx_offer_created_at_id;
+-- +goose StatementEnd
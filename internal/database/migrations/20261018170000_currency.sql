@@ -0,0 +1,61 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE offer ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'RUB' CHECK (currency ~ '^[A-Z]{3}$');
+
+-- the same price in another currency is another offer
+ALTER TABLE offer DROP CONSTRAINT IF EXISTS offer_name_price_key;
+ALTER TABLE offer ADD CONSTRAINT offer_name_price_currency_key UNIQUE (name, price, currency);
+
+-- rate is the amount of to_currency for one unit of from_currency on rate_date
+CREATE TABLE IF NOT EXISTS exchange_rate (
+    from_currency CHAR(3) NOT NULL CHECK (from_currency ~ '^[A-Z]{3}$'),
+    to_currency CHAR(3) NOT NULL CHECK (to_currency ~ '^[A-Z]{3}$'),
+    rate_date DATE NOT NULL,
+    rate NUMERIC(20, 10) NOT NULL CHECK (rate > 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK (from_currency <> to_currency),
+    PRIMARY KEY (from_currency, to_currency, rate_date)
+);
+
+-- convert_price converts amount at the latest rate known on on_date. A rate stored for the
+-- opposite direction is used inverted. Raises no_data_found (P0002) when there is no rate.
+CREATE OR REPLACE FUNCTION convert_price(amount NUMERIC, from_cur TEXT, to_cur TEXT, on_date DATE) RETURNS NUMERIC AS $$
+DECLARE
+    r NUMERIC;
+BEGIN
+    IF from_cur = to_cur THEN
+        RETURN amount;
+    END IF;
+
+    SELECT x.rate INTO r FROM (
+        SELECT rate, rate_date, 0 AS inverted
+        FROM exchange_rate
+        WHERE from_currency = from_cur AND to_currency = to_cur AND rate_date <= on_date
+        UNION ALL
+        SELECT 1 / rate, rate_date, 1 AS inverted
+        FROM exchange_rate
+        WHERE from_currency = to_cur AND to_currency = from_cur AND rate_date <= on_date
+    ) x
+    ORDER BY x.rate_date DESC, x.inverted
+    LIMIT 1;
+
+    IF r IS NULL THEN
+        RAISE EXCEPTION 'no exchange rate from % to % on %', from_cur, to_cur, on_date
+            USING ERRCODE = 'no_data_found';
+    END IF;
+
+    RETURN amount * r;
+END;
+$$ LANGUAGE plpgsql STABLE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP FUNCTION IF EXISTS convert_price(NUMERIC, TEXT, TEXT, DATE);
+DROP TABLE IF EXISTS exchange_rate;
+
+ALTER TABLE offer DROP CONSTRAINT IF EXISTS offer_name_price_currency_key;
+ALTER TABLE offer ADD CONSTRAINT offer_name_price_key UNIQUE (name, price);
+ALTER TABLE offer DROP COLUMN IF EXISTS currency;
+-- +goose StatementEnd
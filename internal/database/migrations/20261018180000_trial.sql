@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE offer ADD COLUMN IF NOT EXISTS trial_days INTEGER NOT NULL DEFAULT 0 CHECK (trial_days >= 0);
+
+-- the trial is [start_date, trial_end_date), the paid period starts at trial_end_date
+ALTER TABLE subscription ADD COLUMN IF NOT EXISTS trial_end_date DATE NULL CHECK (trial_end_date IS NULL OR trial_end_date >= start_date);
+
+-- a user gets at most one trial per service
+CREATE UNIQUE INDEX IF NOT EXISTS idx_subscription_one_trial ON subscription(user_id, service_name) WHERE trial_end_date IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_subscription_one_trial;
+
+ALTER TABLE subscription DROP COLUMN IF EXISTS trial_end_date;
+ALTER TABLE offer DROP COLUMN IF EXISTS trial_days;
+-- +goose StatementEnd
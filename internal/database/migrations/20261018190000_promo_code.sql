@@ -0,0 +1,50 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS promo_code (
+    id UUID DEFAULT gen_random_uuid() NOT NULL,
+    code TEXT NOT NULL UNIQUE,
+    discount_type TEXT NOT NULL CHECK (discount_type IN ('percent', 'fixed')),
+    discount_value INTEGER NOT NULL CHECK (discount_value > 0),
+    -- currency of a fixed discount, it applies only to offers in the same currency
+    currency CHAR(3) NULL,
+    max_redemptions INTEGER NULL CHECK (max_redemptions IS NULL OR max_redemptions > 0),
+    redemptions INTEGER NOT NULL DEFAULT 0,
+    valid_from DATE NULL,
+    valid_until DATE NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (id),
+    CHECK (discount_type <> 'percent' OR discount_value <= 100),
+    CHECK (discount_type <> 'fixed' OR currency IS NOT NULL),
+    CHECK (max_redemptions IS NULL OR redemptions <= max_redemptions),
+    CHECK (valid_from IS NULL OR valid_until IS NULL OR valid_until >= valid_from)
+);
+
+-- a promo code without rows here applies to every offer
+CREATE TABLE IF NOT EXISTS promo_code_offer (
+    promo_code_id UUID NOT NULL REFERENCES promo_code(id) ON DELETE CASCADE,
+    offer_id UUID NOT NULL REFERENCES offer(id) ON DELETE CASCADE,
+    PRIMARY KEY (promo_code_id, offer_id)
+);
+
+-- the price the user actually pays, the list price of the offer minus the discount
+ALTER TABLE subscription ADD COLUMN IF NOT EXISTS price INTEGER NULL CHECK (price >= 0);
+
+UPDATE subscription s
+SET price = o.price
+FROM offer o
+WHERE o.id = s.offer_id;
+
+ALTER TABLE subscription ALTER COLUMN price SET NOT NULL;
+
+ALTER TABLE subscription ADD COLUMN IF NOT EXISTS promo_code_id UUID NULL REFERENCES promo_code(id) ON DELETE SET NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE subscription DROP COLUMN IF EXISTS promo_code_id;
+ALTER TABLE subscription DROP COLUMN IF EXISTS price;
+
+DROP TABLE IF EXISTS promo_code_offer;
+DROP TABLE IF EXISTS promo_code;
+-- +goose StatementEnd
@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+-- domain events written in the same transaction as the change they describe,
+-- the relay publishes them in id order and marks each one as published or dead
+CREATE TABLE IF NOT EXISTS outbox (
+    id BIGSERIAL PRIMARY KEY,
+    aggregate_type TEXT NOT NULL,
+    aggregate_id UUID NOT NULL,
+    event_type TEXT NOT NULL,
+    payload JSONB NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    published_at TIMESTAMPTZ NULL,
+    -- a relay that claimed the event publishes it until then, other relays skip it
+    locked_until TIMESTAMPTZ NULL,
+    -- a failed event is retried not earlier than that, the events after it wait for it
+    next_attempt_at TIMESTAMPTZ NULL,
+    -- set after the last allowed attempt, the event is not published any more
+    dead_at TIMESTAMPTZ NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_pending ON outbox(id) WHERE published_at IS NULL AND dead_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_outbox_retry ON outbox(id) WHERE published_at IS NULL AND dead_at IS NULL AND next_attempt_at IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS outbox;
+-- +goose StatementEnd
@@ -0,0 +1,64 @@
+-- +goose Up
+-- +goose StatementBegin
+-- price versions of an offer, a version is never changed once written. The version in effect on
+-- a date is the latest one with effective_from not after it.
+CREATE TABLE IF NOT EXISTS offer_price (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    offer_id UUID NOT NULL REFERENCES offer(id) ON DELETE CASCADE,
+    price INTEGER NOT NULL CHECK (price >= 0),
+    currency CHAR(3) NOT NULL CHECK (currency ~ '^[A-Z]{3}$'),
+    effective_from DATE NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_offer_price_offer_effective ON offer_price(offer_id, effective_from DESC, created_at DESC);
+
+INSERT INTO offer_price (offer_id, price, currency, effective_from, created_at)
+SELECT id, price, currency, created_at::date, created_at
+FROM offer;
+
+-- the version in effect now, offer.price and offer.currency mirror it. The offer and its first
+-- version are inserted together, so the check waits for the end of the transaction.
+ALTER TABLE offer ADD COLUMN IF NOT EXISTS price_id UUID NULL
+    REFERENCES offer_price(id) DEFERRABLE INITIALLY DEFERRED;
+
+UPDATE offer o
+SET price_id = p.id
+FROM offer_price p
+WHERE p.offer_id = o.id;
+
+ALTER TABLE offer ALTER COLUMN price_id SET NOT NULL;
+
+-- the version the subscription was bought at. Earlier price changes were not recorded, so existing
+-- subscriptions are pinned to the current version
+ALTER TABLE subscription ADD COLUMN IF NOT EXISTS price_id UUID NULL REFERENCES offer_price(id);
+
+UPDATE subscription s
+SET price_id = o.price_id
+FROM offer o
+WHERE o.id = s.offer_id;
+
+ALTER TABLE subscription ALTER COLUMN price_id SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_subscription_price_id ON subscription(price_id);
+
+-- a price change is a new version of the same offer, so the price no longer identifies an offer.
+-- Offers that earlier price changes split off stay as they are, lookups by name and currency
+-- take the newest one. New duplicates are rejected by the application under an advisory lock.
+ALTER TABLE offer DROP CONSTRAINT IF EXISTS offer_name_price_currency_key;
+CREATE INDEX IF NOT EXISTS idx_offer_name_currency ON offer(name, currency, created_at DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- fails when offers were created with the same name, price and currency after the migration
+DROP INDEX IF EXISTS idx_offer_name_currency;
+ALTER TABLE offer ADD CONSTRAINT offer_name_price_currency_key UNIQUE (name, price, currency);
+
+DROP INDEX IF EXISTS idx_subscription_price_id;
+ALTER TABLE subscription DROP COLUMN IF EXISTS price_id;
+ALTER TABLE offer DROP COLUMN IF EXISTS price_id;
+
+DROP INDEX IF EXISTS idx_offer_price_offer_effective;
+DROP TABLE IF EXISTS offer_price;
+-- +goose StatementEnd
@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+-- a version scheduled for a future date stays pending until the pricing worker applies it to the offer
+ALTER TABLE offer_price ADD COLUMN IF NOT EXISTS applied_at TIMESTAMPTZ NULL;
+-- a pending version the worker could not apply to the offer, it is not retried
+ALTER TABLE offer_price ADD COLUMN IF NOT EXISTS failed_at TIMESTAMPTZ NULL;
+
+UPDATE offer_price SET applied_at = created_at;
+
+-- one pending change per offer and date
+CREATE UNIQUE INDEX IF NOT EXISTS offer_price_pending_key ON offer_price(offer_id, effective_from) WHERE applied_at IS NULL AND failed_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_offer_price_pending_effective ON offer_price(effective_from) WHERE applied_at IS NULL AND failed_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_offer_price_pending_effective;
+DROP INDEX IF EXISTS offer_price_pending_key;
+
+-- pending and failed versions were never in effect
+DELETE FROM offer_price p
+WHERE p.applied_at IS NULL
+  AND NOT EXISTS (SELECT 1 FROM subscription s WHERE s.price_id = p.id);
+
+ALTER TABLE offer_price DROP COLUMN IF EXISTS failed_at;
+ALTER TABLE offer_price DROP COLUMN IF EXISTS applied_at;
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS api_key (
+    id UUID DEFAULT gen_random_uuid() NOT NULL,
+    name TEXT NOT NULL,
+    -- first characters of the key, tell keys apart without revealing them
+    prefix TEXT NOT NULL,
+    -- SHA-256 of the key, the key itself is returned once when it is created
+    key_hash TEXT NOT NULL,
+    scopes TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    last_used_at TIMESTAMPTZ NULL,
+    revoked_at TIMESTAMPTZ NULL,
+    PRIMARY KEY (id),
+    UNIQUE (key_hash)
+);
+
+-- a name is the actor of the changes made with the key, it is unique among keys in use
+CREATE UNIQUE INDEX IF NOT EXISTS api_key_active_name_key ON api_key(name) WHERE revoked_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS api_key;
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS idempotency_key (
+    -- the caller that sent the key, keys of different callers do not collide
+    scope TEXT NOT NULL,
+    key TEXT NOT NULL,
+    -- SHA-256 of the method, URI and body of the first request with the key
+    request_hash TEXT NOT NULL,
+    -- NULL while the first request is being handled
+    status_code INTEGER NULL,
+    content_type TEXT NULL,
+    response_body BYTEA NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (scope, key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_key_expires_at ON idempotency_key(expires_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS idempotency_key;
+-- +goose StatementEnd
@@ -0,0 +1,103 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	AuditActionCreate     = "create"
+	AuditActionUpdate     = "update"
+	AuditActionDelete     = "delete"
+	AuditActionCancel     = "cancel"
+	AuditActionPause      = "pause"
+	AuditActionResume     = "resume"
+	AuditActionChangePlan = "change_plan"
+	AuditActionRenew      = "renew"
+	AuditActionExpire     = "expire"
+	// schedule_price and cancel_price record a pending price change of an offer,
+	// its snapshot is the price version.
+	AuditActionSchedulePrice = "schedule_price"
+	AuditActionCancelPrice   = "cancel_price"
+)
+
+// AuditEntry records who changed an entity and how. Before is empty for a created entity and
+// After is empty for a deleted one. EntityType is one of the aggregate types.
+type AuditEntry struct {
+	ID         int64           `db:"id"`
+	Actor      string          `db:"actor"`
+	Action     string          `db:"action"`
+	EntityType string          `db:"entity_type"`
+	EntityID   uuid.UUID       `db:"entity_id"`
+	Before     json.RawMessage `db:"before"`
+	After      json.RawMessage `db:"after"`
+	CreatedAt  time.Time       `db:"created_at"`
+}
+
+// NewSubscriptionAudit records a change of a subscription, nil before or after means the
+// subscription did not exist. The actor is filled in by the service.
+func NewSubscriptionAudit(action string, before, after *Subscription) AuditEntry {
+	entry := AuditEntry{Action: action, EntityType: AggregateSubscription}
+	if before != nil {
+		entry.EntityID = before.ID
+		entry.Before = snapshot(subscriptionEventPayload(*before))
+	}
+	if after != nil {
+		entry.EntityID = after.ID
+		entry.After = snapshot(subscriptionEventPayload(*after))
+	}
+	return entry
+}
+
+// NewOfferAudit records a change of an offer, nil before or after means the offer did not exist.
+func NewOfferAudit(action string, before, after *Offer) AuditEntry {
+	entry := AuditEntry{Action: action, EntityType: AggregateOffer}
+	if before != nil {
+		entry.EntityID = before.ID
+		entry.Before = snapshot(offerEventPayload(*before))
+	}
+	if after != nil {
+		entry.EntityID = after.ID
+		entry.After = snapshot(offerEventPayload(*after))
+	}
+	return entry
+}
+
+// NewOfferPriceAudit records a change of a pending price version of an offer.
+func NewOfferPriceAudit(action string, before, after *OfferPrice) AuditEntry {
+	entry := AuditEntry{Action: action, EntityType: AggregateOffer}
+	if before != nil {
+		entry.EntityID = before.OfferID
+		entry.Before = snapshot(offerPriceSnapshot(*before))
+	}
+	if after != nil {
+		entry.EntityID = after.OfferID
+		entry.After = snapshot(offerPriceSnapshot(*after))
+	}
+	return entry
+}
+
+// offerPriceState is the audit snapshot of a price version.
+type offerPriceState struct {
+	PriceID       uuid.UUID `json:"price_id"`
+	Price         int       `json:"price"`
+	Currency      string    `json:"currency"`
+	EffectiveFrom string    `json:"effective_from"`
+}
+
+func offerPriceSnapshot(price OfferPrice) offerPriceState {
+	return offerPriceState{
+		PriceID:       price.ID,
+		Price:         price.Price,
+		Currency:      price.Currency,
+		EffectiveFrom: price.EffectiveFrom.Format(time.DateOnly),
+	}
+}
+
+func snapshot(state any) json.RawMessage {
+	// the snapshots are plain structs, marshalling them cannot fail
+	data, _ := json.Marshal(state)
+	return data
+}
@@ -0,0 +1,24 @@
+package entity
+
+import "time"
+
+// CostReport is the subscription cost for a period, broken down per calendar month and per service.
+type CostReport struct {
+	From     time.Time
+	To       time.Time
+	Currency string
+	Total    int
+	Services []ServiceCost
+	Months   []MonthlyCost
+}
+
+type MonthlyCost struct {
+	Month    time.Time
+	Total    int
+	Services []ServiceCost
+}
+
+type ServiceCost struct {
+	ServiceName string
+	Cost        int
+}
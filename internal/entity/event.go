@@ -0,0 +1,129 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SubscriptionEventPayload is the state of a subscription sent with its events.
+type SubscriptionEventPayload struct {
+	ID            uuid.UUID  `json:"id"`
+	UserID        uuid.UUID  `json:"user_id"`
+	OfferID       uuid.UUID  `json:"offer_id"`
+	StartDate     string     `json:"start_date"`
+	EndDate       string     `json:"end_date"`
+	TrialEndDate  *string    `json:"trial_end_date,omitempty"`
+	Price         int        `json:"price"`
+	PriceID       uuid.UUID  `json:"price_id"`
+	PromoCodeID   *uuid.UUID `json:"promo_code_id,omitempty"`
+	AutoRenew     bool       `json:"auto_renew"`
+	RenewedFromID *uuid.UUID `json:"renewed_from_id,omitempty"`
+	Status        string     `json:"status"`
+	CancelledAt   *time.Time `json:"cancelled_at,omitempty"`
+	CancelReason  *string    `json:"cancel_reason,omitempty"`
+}
+
+// PlanChangeEventPayload is sent with subscription.plan_changed.
+type PlanChangeEventPayload struct {
+	Previous        SubscriptionEventPayload `json:"previous"`
+	Current         SubscriptionEventPayload `json:"current"`
+	UnusedDays      int                      `json:"unused_days"`
+	ProrationCredit int                      `json:"proration_credit"`
+}
+
+// PriceChangeEventPayload is sent with subscription.price_change_upcoming and
+// subscription.price_change_cancelled. The subscription keeps its price, an upcoming one applies
+// from the first renewal on or after EffectiveFrom.
+type PriceChangeEventPayload struct {
+	Subscription  SubscriptionEventPayload `json:"subscription"`
+	PriceID       uuid.UUID                `json:"price_id"`
+	Price         int                      `json:"price"`
+	Currency      string                   `json:"currency"`
+	EffectiveFrom string                   `json:"effective_from"`
+}
+
+// OfferEventPayload is the state of an offer sent with its events.
+type OfferEventPayload struct {
+	ID             uuid.UUID `json:"id"`
+	Name           string    `json:"name"`
+	Price          int       `json:"price"`
+	PriceID        uuid.UUID `json:"price_id"`
+	Currency       string    `json:"currency"`
+	DurationMonths int       `json:"duration_months"`
+	TrialDays      int       `json:"trial_days"`
+}
+
+func NewSubscriptionEvent(eventType string, sub Subscription) OutboxEvent {
+	return newEvent(AggregateSubscription, sub.ID, eventType, subscriptionEventPayload(sub))
+}
+
+func NewPlanChangeEvent(change PlanChange) OutboxEvent {
+	return newEvent(AggregateSubscription, change.Current.ID, EventSubscriptionPlanChanged, PlanChangeEventPayload{
+		Previous:        subscriptionEventPayload(change.Previous.Subscription),
+		Current:         subscriptionEventPayload(change.Current.Subscription),
+		UnusedDays:      change.UnusedDays,
+		ProrationCredit: change.ProrationCredit,
+	})
+}
+
+func NewPriceChangeEvent(eventType string, sub Subscription, price OfferPrice) OutboxEvent {
+	return newEvent(AggregateSubscription, sub.ID, eventType, PriceChangeEventPayload{
+		Subscription:  subscriptionEventPayload(sub),
+		PriceID:       price.ID,
+		Price:         price.Price,
+		Currency:      price.Currency,
+		EffectiveFrom: price.EffectiveFrom.Format(time.DateOnly),
+	})
+}
+
+func NewOfferEvent(eventType string, offer Offer) OutboxEvent {
+	return newEvent(AggregateOffer, offer.ID, eventType, offerEventPayload(offer))
+}
+
+func offerEventPayload(offer Offer) OfferEventPayload {
+	return OfferEventPayload{
+		ID:             offer.ID,
+		Name:           offer.Name,
+		Price:          offer.Price,
+		PriceID:        offer.PriceID,
+		Currency:       offer.Currency,
+		DurationMonths: offer.DurationMonths,
+		TrialDays:      offer.TrialDays,
+	}
+}
+
+func subscriptionEventPayload(sub Subscription) SubscriptionEventPayload {
+	payload := SubscriptionEventPayload{
+		ID:            sub.ID,
+		UserID:        sub.UserID,
+		OfferID:       sub.OfferID,
+		StartDate:     sub.StartDate.Format(time.DateOnly),
+		EndDate:       sub.EndDate.Format(time.DateOnly),
+		Price:         sub.Price,
+		PriceID:       sub.PriceID,
+		PromoCodeID:   sub.PromoCodeID,
+		AutoRenew:     sub.AutoRenew,
+		RenewedFromID: sub.RenewedFromID,
+		Status:        string(sub.Status),
+		CancelledAt:   sub.CancelledAt,
+		CancelReason:  sub.CancelReason,
+	}
+	if sub.TrialEndDate != nil {
+		trialEndDate := sub.TrialEndDate.Format(time.DateOnly)
+		payload.TrialEndDate = &trialEndDate
+	}
+	return payload
+}
+
+func newEvent(aggregateType string, aggregateID uuid.UUID, eventType string, payload any) OutboxEvent {
+	// the payloads are plain structs, marshalling them cannot fail
+	data, _ := json.Marshal(payload)
+	return OutboxEvent{
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		EventType:     eventType,
+		Payload:       data,
+	}
+}
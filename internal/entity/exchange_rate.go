@@ -0,0 +1,13 @@
+package entity
+
+import "time"
+
+// ExchangeRate is the amount of To for one unit of From on Date.
+type ExchangeRate struct {
+	From      string    `db:"from_currency"`
+	To        string    `db:"to_currency"`
+	Date      time.Time `db:"rate_date"`
+	Rate      float64   `db:"rate"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
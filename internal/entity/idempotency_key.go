@@ -0,0 +1,16 @@
+package entity
+
+import "time"
+
+// IdempotencyKey is a key a caller sent with a mutating request and the response to that request.
+// StatusCode is nil while the request is being handled.
+type IdempotencyKey struct {
+	Scope        string    `db:"scope"`
+	Key          string    `db:"key"`
+	RequestHash  string    `db:"request_hash"`
+	StatusCode   *int      `db:"status_code"`
+	ContentType  *string   `db:"content_type"`
+	ResponseBody []byte    `db:"response_body"`
+	CreatedAt    time.Time `db:"created_at"`
+	ExpiresAt    time.Time `db:"expires_at"`
+}
@@ -6,11 +6,34 @@ import (
 	"github.com/google/uuid"
 )
 
+// DefaultCurrency is used for offers and reports when no currency is given.
+const DefaultCurrency = "RUB"
+
+// Offer is a plan of a service. Price and Currency are those of the price version in effect,
+// PriceID refers to it.
 type Offer struct {
 	ID             uuid.UUID `db:"id"`
 	Name           string    `db:"name"`
 	Price          int       `db:"price"`
+	Currency       string    `db:"currency"`
+	PriceID        uuid.UUID `db:"price_id"`
 	DurationMonths int       `db:"duration_months"`
+	TrialDays      int       `db:"trial_days"`
 	CreatedAt      time.Time `db:"created_at"`
 	UpdatedAt      time.Time `db:"updated_at"`
 }
+
+// OfferPrice is a version of the offer price. Versions are never changed, the one in effect on a
+// date is the latest with EffectiveFrom not after it. A version scheduled for a future date is
+// pending until it is applied to the offer, AppliedAt is nil until then. A pending version that
+// cannot be applied to the offer gets FailedAt and is no longer in effect.
+type OfferPrice struct {
+	ID            uuid.UUID  `db:"id"`
+	OfferID       uuid.UUID  `db:"offer_id"`
+	Price         int        `db:"price"`
+	Currency      string     `db:"currency"`
+	EffectiveFrom time.Time  `db:"effective_from"`
+	AppliedAt     *time.Time `db:"applied_at"`
+	FailedAt      *time.Time `db:"failed_at"`
+	CreatedAt     time.Time  `db:"created_at"`
+}
@@ -0,0 +1,71 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	AggregateSubscription = "subscription"
+	AggregateOffer        = "offer"
+)
+
+const (
+	EventSubscriptionCreated     = "subscription.created"
+	EventSubscriptionUpdated     = "subscription.updated"
+	EventSubscriptionRenewed     = "subscription.renewed"
+	EventSubscriptionPlanChanged = "subscription.plan_changed"
+	EventSubscriptionCancelled   = "subscription.cancelled"
+	EventSubscriptionPaused      = "subscription.paused"
+	EventSubscriptionResumed     = "subscription.resumed"
+	EventSubscriptionExpired     = "subscription.expired"
+	// EventSubscriptionExpiringSoon is sent once per period for a subscription that will not be renewed.
+	EventSubscriptionExpiringSoon = "subscription.expiring_soon"
+	// EventSubscriptionPriceChangeUpcoming is sent to every active subscriber of an offer when a
+	// price change is scheduled for it.
+	EventSubscriptionPriceChangeUpcoming = "subscription.price_change_upcoming"
+	// EventSubscriptionPriceChangeCancelled is sent to the same subscribers when the scheduled
+	// change is cancelled or cannot be applied.
+	EventSubscriptionPriceChangeCancelled = "subscription.price_change_cancelled"
+
+	EventOfferCreated = "offer.created"
+	EventOfferUpdated = "offer.updated"
+	EventOfferDeleted = "offer.deleted"
+)
+
+// EventTypes lists every event type the service emits.
+var EventTypes = []string{
+	EventSubscriptionCreated,
+	EventSubscriptionUpdated,
+	EventSubscriptionRenewed,
+	EventSubscriptionPlanChanged,
+	EventSubscriptionCancelled,
+	EventSubscriptionPaused,
+	EventSubscriptionResumed,
+	EventSubscriptionExpired,
+	EventSubscriptionExpiringSoon,
+	EventSubscriptionPriceChangeUpcoming,
+	EventSubscriptionPriceChangeCancelled,
+	EventOfferCreated,
+	EventOfferUpdated,
+	EventOfferDeleted,
+}
+
+// OutboxEvent is a domain event stored in the outbox until the relay publishes it.
+// Payload is the JSON state of the aggregate after the change. A failed event is retried at
+// NextAttemptAt, one that failed too many times gets DeadAt and is not published.
+type OutboxEvent struct {
+	ID            int64           `db:"id"`
+	AggregateType string          `db:"aggregate_type"`
+	AggregateID   uuid.UUID       `db:"aggregate_id"`
+	EventType     string          `db:"event_type"`
+	Payload       json.RawMessage `db:"payload"`
+	Attempts      int             `db:"attempts"`
+	LastError     *string         `db:"last_error"`
+	CreatedAt     time.Time       `db:"created_at"`
+	PublishedAt   *time.Time      `db:"published_at"`
+	NextAttemptAt *time.Time      `db:"next_attempt_at"`
+	DeadAt        *time.Time      `db:"dead_at"`
+}
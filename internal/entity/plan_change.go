@@ -0,0 +1,9 @@
+package entity
+
+// PlanChange is the result of switching a user to another offer of the same service.
+type PlanChange struct {
+	Previous        SubscriptionFullInfo
+	Current         SubscriptionFullInfo
+	UnusedDays      int
+	ProrationCredit int
+}
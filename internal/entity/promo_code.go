@@ -0,0 +1,33 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type DiscountType string
+
+const (
+	DiscountTypePercent DiscountType = "percent"
+	DiscountTypeFixed   DiscountType = "fixed"
+)
+
+// PromoCode gives a discount on the price of a new subscription. A percent discount takes
+// DiscountValue percent off the price, a fixed one takes DiscountValue in Currency off it.
+// Empty OfferIDs means the code applies to every offer. The discount is given on the first
+// period of the subscription only, renewals are charged the price of the offer.
+type PromoCode struct {
+	ID             uuid.UUID    `db:"id"`
+	Code           string       `db:"code"`
+	DiscountType   DiscountType `db:"discount_type"`
+	DiscountValue  int          `db:"discount_value"`
+	Currency       *string      `db:"currency"`
+	MaxRedemptions *int         `db:"max_redemptions"`
+	Redemptions    int          `db:"redemptions"`
+	ValidFrom      *time.Time   `db:"valid_from"`
+	ValidUntil     *time.Time   `db:"valid_until"`
+	OfferIDs       []uuid.UUID  `db:"offer_ids"`
+	CreatedAt      time.Time    `db:"created_at"`
+	UpdatedAt      time.Time    `db:"updated_at"`
+}
@@ -6,18 +6,41 @@ import (
 	"github.com/google/uuid"
 )
 
+type SubscriptionStatus string
+
+const (
+	SubscriptionStatusActive    SubscriptionStatus = "active"
+	SubscriptionStatusCancelled SubscriptionStatus = "cancelled"
+	SubscriptionStatusExpired   SubscriptionStatus = "expired"
+	SubscriptionStatusPaused    SubscriptionStatus = "paused"
+)
+
 type Subscription struct {
-	ID        uuid.UUID `db:"id"`
-	UserID    uuid.UUID `db:"user_id"`
-	OfferID   uuid.UUID `db:"offer_id"`
-	StartDate time.Time `db:"start_date"`
-	EndDate   time.Time `db:"end_date"`
-	CreatedAt time.Time `db:"created_at"`
-	UpdatedAt time.Time `db:"updated_at"`
+	ID            uuid.UUID          `db:"id"`
+	UserID        uuid.UUID          `db:"user_id"`
+	OfferID       uuid.UUID          `db:"offer_id"`
+	StartDate     time.Time          `db:"start_date"`
+	EndDate       time.Time          `db:"end_date"`
+	Price         int                `db:"price"`
+	PriceID       uuid.UUID          `db:"price_id"`
+	PromoCodeID   *uuid.UUID         `db:"promo_code_id"`
+	AutoRenew     bool               `db:"auto_renew"`
+	RenewedFromID *uuid.UUID         `db:"renewed_from_id"`
+	Status        SubscriptionStatus `db:"status"`
+	CancelledAt   *time.Time         `db:"cancelled_at"`
+	CancelReason  *string            `db:"cancel_reason"`
+	TrialEndDate  *time.Time         `db:"trial_end_date"`
+	CreatedAt     time.Time          `db:"created_at"`
+	UpdatedAt     time.Time          `db:"updated_at"`
 }
 
+// SubscriptionFullInfo is the subscription with the data of its offer. Price comes from the
+// subscription and is what the user pays, it differs from the list price when a promo code was used.
+// ListPrice and Currency come from the offer price version the subscription was bought at.
 type SubscriptionFullInfo struct {
 	Subscription
-	OfferName string `db:"offer_name"`
-	Price     int    `db:"price"`
+	OfferName      string `db:"offer_name"`
+	ListPrice      int    `db:"list_price"`
+	Currency       string `db:"currency"`
+	DurationMonths int    `db:"duration_months"`
 }
@@ -0,0 +1,15 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SubscriptionPause struct {
+	ID             uuid.UUID  `db:"id"`
+	SubscriptionID uuid.UUID  `db:"subscription_id"`
+	PausedAt       time.Time  `db:"paused_at"`
+	ResumedAt      *time.Time `db:"resumed_at"`
+	CreatedAt      time.Time  `db:"created_at"`
+}
@@ -0,0 +1,50 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryStatusPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryStatusDelivered WebhookDeliveryStatus = "delivered"
+	WebhookDeliveryStatusDead      WebhookDeliveryStatus = "dead"
+)
+
+// WebhookEndpoint receives the events of the listed types, an empty list means all types.
+type WebhookEndpoint struct {
+	ID         uuid.UUID `db:"id"`
+	URL        string    `db:"url"`
+	Secret     string    `db:"secret"`
+	EventTypes []string  `db:"event_types"`
+	CreatedAt  time.Time `db:"created_at"`
+	UpdatedAt  time.Time `db:"updated_at"`
+}
+
+// WebhookDelivery is one event to be sent to one endpoint. Payload is the exact request body.
+type WebhookDelivery struct {
+	ID             uuid.UUID             `db:"id"`
+	EndpointID     uuid.UUID             `db:"endpoint_id"`
+	EventID        int64                 `db:"event_id"`
+	EventType      string                `db:"event_type"`
+	Payload        json.RawMessage       `db:"payload"`
+	Status         WebhookDeliveryStatus `db:"status"`
+	Attempts       int                   `db:"attempts"`
+	NextAttemptAt  time.Time             `db:"next_attempt_at"`
+	LastStatusCode *int                  `db:"last_status_code"`
+	LastError      *string               `db:"last_error"`
+	CreatedAt      time.Time             `db:"created_at"`
+	UpdatedAt      time.Time             `db:"updated_at"`
+	DeliveredAt    *time.Time            `db:"delivered_at"`
+}
+
+// WebhookDeliveryTarget is a delivery together with the endpoint it goes to.
+type WebhookDeliveryTarget struct {
+	WebhookDelivery
+	URL    string `db:"url"`
+	Secret string `db:"secret"`
+}
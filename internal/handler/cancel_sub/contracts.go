@@ -0,0 +1,17 @@
+package cancel_sub
+
+import (
+	"context"
+
+	"github.com/4udiwe/subscription-service/internal/entity"
+	"github.com/google/uuid"
+)
+
+type SubscriptionService interface {
+	CancelSubscription(
+		ctx context.Context,
+		subID uuid.UUID,
+		reason *string,
+		atPeriodEnd bool,
+	) (entity.SubscriptionFullInfo, error)
+}
@@ -0,0 +1,92 @@
+package cancel_sub
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	h "github.com/4udiwe/subscription-service/internal/handler"
+	"github.com/4udiwe/subscription-service/internal/handler/decorator"
+	"github.com/4udiwe/subscription-service/internal/service/subscription"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type handler struct {
+	s SubscriptionService
+}
+
+func New(s SubscriptionService) h.Handler {
+	return decorator.NewBindAndValidateDecorator(&handler{s: s})
+}
+
+type CancelSubscriptionRequest struct {
+	SubscriptionID uuid.UUID `param:"id" json:"-" validate:"required,uuid"`
+	Reason         *string   `json:"reason" validate:"omitempty,max=500"`
+	AtPeriodEnd    bool      `json:"at_period_end"`
+}
+
+type CancelSubscriptionResponse struct {
+	SubscriptionID uuid.UUID `json:"subscription_id"`
+	UserID         uuid.UUID `json:"user_id"`
+	OfferName      string    `json:"offer_name"`
+	Price          int       `json:"price"`
+	Currency       string    `json:"currency"`
+	StartDate      string    `json:"start_date"`
+	EndDate        string    `json:"end_date"`
+	Status         string    `json:"status"`
+	CancelledAt    string    `json:"cancelled_at"`
+	CancelReason   *string   `json:"cancel_reason,omitempty"`
+}
+
+// Cancel subscription
+// @Summary Отмена подписки
+// @Description Отмена подписки с сохранением истории. По умолчанию подписка отменяется сразу и дата окончания переносится на текущий день. При at_period_end=true подписка остается активной до конца оплаченного периода. Автопродление в обоих случаях отключается, а уже созданное продление на следующий период, если оно еще не началось, тоже отменяется (без оплаченных дней).
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Param subscription body CancelSubscriptionRequest false "cancellation options"
+// @Param Idempotency-Key header string false "Ключ идемпотентности: повтор запроса с тем же ключом возвращает сохраненный ответ"
+// @Success 200 {object} CancelSubscriptionResponse
+// @Failure 400 {object} h.ErrorResponse
+// @Failure 401 {object} h.ErrorResponse
+// @Failure 403 {object} h.ErrorResponse
+// @Failure 404 {object} h.ErrorResponse
+// @Failure 409 {object} h.ErrorResponse
+// @Failure 422 {object} h.ErrorResponse
+// @Failure 500 {object} h.ErrorResponse
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /subscriptions/{id}/cancel [post]
+func (h *handler) Handle(c echo.Context, in CancelSubscriptionRequest) error {
+	sub, err := h.s.CancelSubscription(c.Request().Context(), in.SubscriptionID, in.Reason, in.AtPeriodEnd)
+
+	if err != nil {
+		if errors.Is(err, subscription.ErrSubscriptionNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+		}
+		if errors.Is(err, subscription.ErrSubscriptionNotActive) {
+			return echo.NewHTTPError(http.StatusConflict, err.Error()).SetInternal(err)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error()).SetInternal(err)
+	}
+
+	var cancelledAt string
+	if sub.CancelledAt != nil {
+		cancelledAt = sub.CancelledAt.Format(time.RFC3339)
+	}
+
+	return c.JSON(http.StatusOK, CancelSubscriptionResponse{
+		SubscriptionID: sub.ID,
+		UserID:         sub.UserID,
+		OfferName:      sub.OfferName,
+		Price:          sub.Price,
+		Currency:       sub.Currency,
+		StartDate:      sub.StartDate.Format("2006-01-02"),
+		EndDate:        sub.EndDate.Format("2006-01-02"),
+		Status:         string(sub.Status),
+		CancelledAt:    cancelledAt,
+		CancelReason:   sub.CancelReason,
+	})
+}
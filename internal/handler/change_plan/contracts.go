@@ -0,0 +1,13 @@
+package change_plan
+
+import (
+	"context"
+	"time"
+
+	"github.com/4udiwe/subscription-service/internal/entity"
+	"github.com/google/uuid"
+)
+
+type SubscriptionService interface {
+	ChangePlan(ctx context.Context, subID uuid.UUID, newOfferID uuid.UUID, switchDate time.Time) (entity.PlanChange, error)
+}
@@ -0,0 +1,119 @@
+package change_plan
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/4udiwe/subscription-service/internal/entity"
+	h "github.com/4udiwe/subscription-service/internal/handler"
+	"github.com/4udiwe/subscription-service/internal/handler/decorator"
+	"github.com/4udiwe/subscription-service/internal/service/subscription"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type handler struct {
+	s SubscriptionService
+}
+
+func New(s SubscriptionService) h.Handler {
+	return decorator.NewBindAndValidateDecorator(&handler{s: s})
+}
+
+type ChangePlanRequest struct {
+	SubscriptionID uuid.UUID `param:"id" json:"-" validate:"required,uuid"`
+	OfferID        uuid.UUID `json:"offer_id" validate:"required,uuid"`
+	SwitchDate     *string   `json:"switch_date" validate:"omitempty,datetime=2006-01-02"`
+}
+
+type ChangePlanResponse struct {
+	Previous        Subscription `json:"previous"`
+	Current         Subscription `json:"current"`
+	UnusedDays      int          `json:"unused_days"`
+	ProrationCredit int          `json:"proration_credit"`
+}
+
+type Subscription struct {
+	SubscriptionID uuid.UUID `json:"subscription_id"`
+	UserID         uuid.UUID `json:"user_id"`
+	OfferID        uuid.UUID `json:"offer_id"`
+	OfferName      string    `json:"offer_name"`
+	Price          int       `json:"price"`
+	Currency       string    `json:"currency"`
+	StartDate      string    `json:"start_date"`
+	EndDate        string    `json:"end_date"`
+	Status         string    `json:"status"`
+}
+
+// Change subscription plan
+// @Summary Смена тарифа подписки
+// @Description Перевод пользователя на другое предложение того же сервиса. Текущая подписка заканчивается в дату переключения (по умолчанию сегодня), новая начинается в ту же дату. В ответе возвращается кредит за неиспользованные дни старого тарифа.
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Param plan body ChangePlanRequest true "new offer and switch date"
+// @Param Idempotency-Key header string false "Ключ идемпотентности: повтор запроса с тем же ключом возвращает сохраненный ответ"
+// @Success 200 {object} ChangePlanResponse
+// @Failure 400 {object} h.ErrorResponse
+// @Failure 401 {object} h.ErrorResponse
+// @Failure 403 {object} h.ErrorResponse
+// @Failure 404 {object} h.ErrorResponse
+// @Failure 409 {object} h.ErrorResponse
+// @Failure 422 {object} h.ErrorResponse
+// @Failure 500 {object} h.ErrorResponse
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /subscriptions/{id}/change_plan [post]
+func (h *handler) Handle(c echo.Context, in ChangePlanRequest) error {
+	now := time.Now().UTC()
+	switchDate := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if in.SwitchDate != nil {
+		parsedSwitchDate, err := time.Parse("2006-01-02", *in.SwitchDate)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid switch_date format")
+		}
+		switchDate = parsedSwitchDate
+	}
+
+	change, err := h.s.ChangePlan(c.Request().Context(), in.SubscriptionID, in.OfferID, switchDate)
+
+	if err != nil {
+		if errors.Is(err, subscription.ErrSubscriptionNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+		}
+		if errors.Is(err, subscription.ErrOfferNotFound) ||
+			errors.Is(err, subscription.ErrOfferOfAnotherService) ||
+			errors.Is(err, subscription.ErrSameOffer) ||
+			errors.Is(err, subscription.ErrInvalidSwitchDate) {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		}
+		if errors.Is(err, subscription.ErrSubscriptionNotActive) ||
+			errors.Is(err, subscription.ErrUserAlreadyHasActiveSubscription) {
+			return echo.NewHTTPError(http.StatusConflict, err.Error()).SetInternal(err)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error()).SetInternal(err)
+	}
+
+	return c.JSON(http.StatusOK, ChangePlanResponse{
+		Previous:        toSubscription(change.Previous),
+		Current:         toSubscription(change.Current),
+		UnusedDays:      change.UnusedDays,
+		ProrationCredit: change.ProrationCredit,
+	})
+}
+
+func toSubscription(s entity.SubscriptionFullInfo) Subscription {
+	return Subscription{
+		SubscriptionID: s.ID,
+		UserID:         s.UserID,
+		OfferID:        s.OfferID,
+		OfferName:      s.OfferName,
+		Price:          s.Price,
+		Currency:       s.Currency,
+		StartDate:      s.StartDate.Format("2006-01-02"),
+		EndDate:        s.EndDate.Format("2006-01-02"),
+		Status:         string(s.Status),
+	}
+}
@@ -5,8 +5,8 @@ import (
 	"net/http"
 
 	h "github.com/4udiwe/subscription-service/internal/handler"
+	"github.com/4udiwe/subscription-service/pkg/logger"
 	"github.com/labstack/echo/v4"
-	"github.com/sirupsen/logrus"
 )
 
 type handler[T any] interface {
@@ -22,18 +22,20 @@ func NewBindAndValidateDecorator[T any](inner handler[T]) h.Handler {
 }
 
 func (d *bindAndValidateDecorator[T]) Handle(c echo.Context) error {
-	logrus.Infof("HTTP %s %s from %s", c.Request().Method, c.Path(), c.Request().RemoteAddr)
+	log := logger.FromContext(c.Request().Context())
+	log.Infof("HTTP %s %s from %s", c.Request().Method, c.Path(), c.Request().RemoteAddr)
 
 	var in T
 
 	if err := c.Bind(&in); err != nil {
-		logrus.Errorf("Failed to bind request: %v", err)
+		log.Errorf("Failed to bind request: %v", err)
 		return d.handleError(err, err.Error())
 	}
 
 	if err := c.Validate(in); err != nil {
-		logrus.Errorf("Failed to validate request: %v", err)
-		return d.handleError(err, err.Error())
+		log.Errorf("Failed to validate request: %v", err)
+		// the error lists every invalid field of the request
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
 	}
 
 	return d.inner.Handle(c, in)
@@ -0,0 +1,11 @@
+package delete_api_key
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type APIKeyService interface {
+	RevokeAPIKey(ctx context.Context, id uuid.UUID) error
+}
@@ -0,0 +1,53 @@
+package delete_api_key
+
+import (
+	"errors"
+	"net/http"
+
+	h "github.com/4udiwe/subscription-service/internal/handler"
+	"github.com/4udiwe/subscription-service/internal/handler/decorator"
+	service "github.com/4udiwe/subscription-service/internal/service/api_key"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type handler struct {
+	s APIKeyService
+}
+
+func New(s APIKeyService) h.Handler {
+	return decorator.NewBindAndValidateDecorator(&handler{s: s})
+}
+
+type DeleteAPIKeyRequest struct {
+	APIKeyID uuid.UUID `param:"id" validate:"required,uuid"`
+}
+
+// Revoke API key
+// @Summary Отзыв API-ключа
+// @Description Отзыв API-ключа по ID: запросы с ним больше не принимаются. Ключ остается в списке с датой отзыва revoked_at.
+// @Tags api_keys
+// @Param id path string true "API key ID"
+// @Param Idempotency-Key header string false "Ключ идемпотентности: повтор запроса с тем же ключом возвращает сохраненный ответ"
+// @Success 202 {string} string "No Content"
+// @Failure 400 {object} h.ErrorResponse
+// @Failure 401 {object} h.ErrorResponse
+// @Failure 403 {object} h.ErrorResponse
+// @Failure 404 {object} h.ErrorResponse
+// @Failure 422 {object} h.ErrorResponse
+// @Failure 500 {object} h.ErrorResponse
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /api_keys/{id} [delete]
+func (h *handler) Handle(c echo.Context, in DeleteAPIKeyRequest) error {
+	err := h.s.RevokeAPIKey(c.Request().Context(), in.APIKeyID)
+
+	if err != nil {
+		if errors.Is(err, service.ErrAPIKeyNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error()).SetInternal(err)
+	}
+
+	return c.NoContent(http.StatusAccepted)
+}
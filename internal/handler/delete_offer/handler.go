@@ -6,7 +6,7 @@ import (
 
 	h "github.com/4udiwe/subscription-service/internal/handler"
 	"github.com/4udiwe/subscription-service/internal/handler/decorator"
-	"github.com/4udiwe/subscription-service/internal/service/subscription"
+	service "github.com/4udiwe/subscription-service/internal/service/offer"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 )
@@ -29,18 +29,28 @@ type DeleteOfferRequest struct {
 // @Tags offers
 // @Accept json
 // @Param offer body DeleteOfferRequest true "offer to delete"
+// @Param Idempotency-Key header string false "Ключ идемпотентности: повтор запроса с тем же ключом возвращает сохраненный ответ"
 // @Success 202 {string} string "No Content"
-// @Failure 404 {string} ErrorResponse
-// @Failure 500 {string} ErrorResponse
+// @Failure 401 {object} h.ErrorResponse
+// @Failure 403 {object} h.ErrorResponse
+// @Failure 404 {object} h.ErrorResponse
+// @Failure 409 {object} h.ErrorResponse
+// @Failure 422 {object} h.ErrorResponse
+// @Failure 500 {object} h.ErrorResponse
+// @Security BearerAuth
+// @Security APIKeyAuth
 // @Router /offers [delete]
 func (h *handler) Handle(c echo.Context, in DeleteOfferRequest) error {
 	err := h.s.DeleteOffer(c.Request().Context(), in.OfferID)
 
 	if err != nil {
-		if errors.Is(err, subscription.ErrOfferNotFound) {
-			return echo.NewHTTPError(http.StatusNotFound, err.Error())
+		if errors.Is(err, service.ErrOfferNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
 		}
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		if errors.Is(err, service.ErrActiveSubscriptionsExist) {
+			return echo.NewHTTPError(http.StatusConflict, err.Error()).SetInternal(err)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error()).SetInternal(err)
 	}
 
 	return c.NoContent(http.StatusAccepted)
@@ -0,0 +1,11 @@
+package delete_offer_price
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type OfferService interface {
+	CancelPriceChange(ctx context.Context, offerID, priceID uuid.UUID) error
+}
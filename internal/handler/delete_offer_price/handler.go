@@ -0,0 +1,59 @@
+package delete_offer_price
+
+import (
+	"errors"
+	"net/http"
+
+	h "github.com/4udiwe/subscription-service/internal/handler"
+	"github.com/4udiwe/subscription-service/internal/handler/decorator"
+	service "github.com/4udiwe/subscription-service/internal/service/offer"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type handler struct {
+	s OfferService
+}
+
+func New(s OfferService) h.Handler {
+	return decorator.NewBindAndValidateDecorator(&handler{s: s})
+}
+
+type DeleteOfferPriceRequest struct {
+	OfferID uuid.UUID `param:"id" validate:"required,uuid"`
+	PriceID uuid.UUID `param:"price_id" validate:"required,uuid"`
+}
+
+// Cancel offer price change
+// @Summary Отмена запланированного изменения цены
+// @Description Отмена изменения цены предложения, которое еще не применено. Если по новой цене уже продлены подписки, изменение отменить нельзя. Всем активным подписчикам предложения отправляется событие subscription.price_change_cancelled.
+// @Tags offers
+// @Param id path string true "Offer ID"
+// @Param price_id path string true "Price ID"
+// @Param Idempotency-Key header string false "Ключ идемпотентности: повтор запроса с тем же ключом возвращает сохраненный ответ"
+// @Success 202 {string} string "No Content"
+// @Failure 400 {object} h.ErrorResponse
+// @Failure 401 {object} h.ErrorResponse
+// @Failure 403 {object} h.ErrorResponse
+// @Failure 404 {object} h.ErrorResponse
+// @Failure 409 {object} h.ErrorResponse
+// @Failure 422 {object} h.ErrorResponse
+// @Failure 500 {object} h.ErrorResponse
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /offers/{id}/prices/{price_id} [delete]
+func (h *handler) Handle(c echo.Context, in DeleteOfferPriceRequest) error {
+	err := h.s.CancelPriceChange(c.Request().Context(), in.OfferID, in.PriceID)
+
+	if err != nil {
+		if errors.Is(err, service.ErrPriceChangeNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+		}
+		if errors.Is(err, service.ErrPriceChangeInUse) {
+			return echo.NewHTTPError(http.StatusConflict, err.Error()).SetInternal(err)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error()).SetInternal(err)
+	}
+
+	return c.NoContent(http.StatusAccepted)
+}
@@ -3,9 +3,11 @@ package delete_sub
 import (
 	"context"
 
+	"github.com/4udiwe/subscription-service/internal/entity"
 	"github.com/google/uuid"
 )
 
 type SubscriptionService interface {
+	GetSubscriptionByID(ctx context.Context, subID uuid.UUID) (entity.SubscriptionFullInfo, error)
 	DeleteSubscription(ctx context.Context, subID uuid.UUID) error
 }
@@ -7,6 +7,7 @@ import (
 	h "github.com/4udiwe/subscription-service/internal/handler"
 	"github.com/4udiwe/subscription-service/internal/handler/decorator"
 	"github.com/4udiwe/subscription-service/internal/service/subscription"
+	"github.com/4udiwe/subscription-service/pkg/auth"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 )
@@ -25,22 +26,41 @@ type DeleteSubscriptionRequest struct {
 
 // Delete subscription
 // @Summary Удаление подписки
-// @Description Удаление подписки по ID. Не удаляет предложение, на которое была оформлена подписка.
+// @Description Удаление подписки по ID. Запись не удаляется: подписка сразу отменяется с причиной deleted (как POST /subscriptions/{id}/cancel без at_period_end), поэтому история и суммы трат сохраняются, отправляется событие subscription.cancelled. Удаление уже закончившейся подписки ничего не меняет. Не удаляет предложение, на которое была оформлена подписка.
 // @Tags subscriptions
 // @Accept json
 // @Param subscription body DeleteSubscriptionRequest true "subscription to delete"
+// @Param Idempotency-Key header string false "Ключ идемпотентности: повтор запроса с тем же ключом возвращает сохраненный ответ"
 // @Success 202 {string} string "No Content"
-// @Failure 404 {string} ErrorResponse
-// @Failure 500 {string} ErrorResponse
+// @Failure 401 {object} h.ErrorResponse
+// @Failure 403 {object} h.ErrorResponse
+// @Failure 404 {object} h.ErrorResponse
+// @Failure 422 {object} h.ErrorResponse
+// @Failure 500 {object} h.ErrorResponse
+// @Security BearerAuth
+// @Security APIKeyAuth
 // @Router /subscriptions [delete]
 func (h *handler) Handle(c echo.Context, in DeleteSubscriptionRequest) error {
+	if !auth.HasFullAccess(c.Request().Context()) {
+		sub, err := h.s.GetSubscriptionByID(c.Request().Context(), in.SubscriptionID)
+		if err != nil {
+			if errors.Is(err, subscription.ErrSubscriptionNotFound) {
+				return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, err.Error()).SetInternal(err)
+		}
+		if !auth.CanAccessUser(c.Request().Context(), sub.UserID) {
+			return echo.NewHTTPError(http.StatusForbidden, "access denied")
+		}
+	}
+
 	err := h.s.DeleteSubscription(c.Request().Context(), in.SubscriptionID)
 
 	if err != nil {
 		if errors.Is(err, subscription.ErrSubscriptionNotFound) {
-			return echo.NewHTTPError(http.StatusNotFound, err.Error())
+			return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
 		}
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error()).SetInternal(err)
 	}
 
 	return c.NoContent(http.StatusAccepted)
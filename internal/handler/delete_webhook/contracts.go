@@ -0,0 +1,11 @@
+package delete_webhook
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type WebhookService interface {
+	DeleteEndpoint(ctx context.Context, id uuid.UUID) error
+}
@@ -0,0 +1,53 @@
+package delete_webhook
+
+import (
+	"errors"
+	"net/http"
+
+	h "github.com/4udiwe/subscription-service/internal/handler"
+	"github.com/4udiwe/subscription-service/internal/handler/decorator"
+	service "github.com/4udiwe/subscription-service/internal/service/webhook"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type handler struct {
+	s WebhookService
+}
+
+func New(s WebhookService) h.Handler {
+	return decorator.NewBindAndValidateDecorator(&handler{s: s})
+}
+
+type DeleteWebhookRequest struct {
+	WebhookID uuid.UUID `param:"id" validate:"required,uuid"`
+}
+
+// Delete webhook
+// @Summary Удаление вебхука
+// @Description Удаление вебхука по ID вместе с историей его доставок. Неотправленные события на этот URL больше не отправляются.
+// @Tags webhooks
+// @Param id path string true "Webhook ID"
+// @Param Idempotency-Key header string false "Ключ идемпотентности: повтор запроса с тем же ключом возвращает сохраненный ответ"
+// @Success 202 {string} string "No Content"
+// @Failure 400 {object} h.ErrorResponse
+// @Failure 401 {object} h.ErrorResponse
+// @Failure 403 {object} h.ErrorResponse
+// @Failure 404 {object} h.ErrorResponse
+// @Failure 422 {object} h.ErrorResponse
+// @Failure 500 {object} h.ErrorResponse
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /webhooks/{id} [delete]
+func (h *handler) Handle(c echo.Context, in DeleteWebhookRequest) error {
+	err := h.s.DeleteEndpoint(c.Request().Context(), in.WebhookID)
+
+	if err != nil {
+		if errors.Is(err, service.ErrEndpointNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error()).SetInternal(err)
+	}
+
+	return c.NoContent(http.StatusAccepted)
+}
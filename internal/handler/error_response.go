@@ -0,0 +1,16 @@
+package handler
+
+import "github.com/4udiwe/subscription-service/pkg/validator"
+
+// ErrorResponse is the body of every error response, an RFC 7807 problem details object served as
+// application/problem+json. Code is a stable machine-readable code of the problem, Errors lists the
+// invalid fields of a request that failed validation.
+type ErrorResponse struct {
+	Type     string                 `json:"type" example:"about:blank"`
+	Title    string                 `json:"title" example:"Not Found"`
+	Status   int                    `json:"status" example:"404"`
+	Detail   string                 `json:"detail,omitempty" example:"subscription not found"`
+	Instance string                 `json:"instance,omitempty" example:"/subscriptions/0b6f2a43-7e53-4c5e-9b0f-0c2b6f6c9d11"`
+	Code     string                 `json:"code" example:"subscription_not_found"`
+	Errors   []validator.FieldError `json:"errors,omitempty"`
+}
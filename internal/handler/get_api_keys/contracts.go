@@ -0,0 +1,11 @@
+package get_api_keys
+
+import (
+	"context"
+
+	"github.com/4udiwe/subscription-service/internal/entity"
+)
+
+type APIKeyService interface {
+	GetAPIKeys(ctx context.Context, page int, pageSize int) (keys []entity.APIKey, total int, err error)
+}
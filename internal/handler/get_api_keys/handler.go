@@ -0,0 +1,107 @@
+package get_api_keys
+
+import (
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/4udiwe/subscription-service/internal/entity"
+	h "github.com/4udiwe/subscription-service/internal/handler"
+	"github.com/4udiwe/subscription-service/internal/handler/decorator"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/samber/lo"
+)
+
+const PAGE_NUMBER = 1
+const PAGE_SIZE = 10
+
+type handler struct {
+	s APIKeyService
+}
+
+func New(s APIKeyService) h.Handler {
+	return decorator.NewBindAndValidateDecorator(&handler{s: s})
+}
+
+type GetAPIKeysRequest struct {
+	Page     int `query:"page"`
+	PageSize int `query:"page_size"`
+}
+
+type GetAPIKeysResponse struct {
+	APIKeys    []APIKey `json:"api_keys"`
+	Page       int      `json:"page"`
+	PageSize   int      `json:"page_size"`
+	TotalItems int      `json:"total_items"`
+	TotalPages int      `json:"total_pages"`
+}
+
+type APIKey struct {
+	APIKeyID   uuid.UUID `json:"api_key_id"`
+	Name       string    `json:"name"`
+	Prefix     string    `json:"prefix"`
+	Scopes     []string  `json:"scopes"`
+	CreatedAt  string    `json:"created_at"`
+	LastUsedAt *string   `json:"last_used_at,omitempty"`
+	RevokedAt  *string   `json:"revoked_at,omitempty"`
+}
+
+// Get API keys
+// @Summary Получение API-ключей
+// @Description Получение списка API-ключей, включая отозванные, новые первыми. Сами ключи не возвращаются, prefix - их первые символы. last_used_at - время последнего запроса с ключом с точностью до минуты.
+// @Tags api_keys
+// @Produce json
+// @Param page query int false "Номер страницы" default(1)
+// @Param page_size query int false "Размер страницы" default(10) maximum(100)
+// @Success 200 {object} GetAPIKeysResponse
+// @Failure 400 {object} h.ErrorResponse
+// @Failure 401 {object} h.ErrorResponse
+// @Failure 403 {object} h.ErrorResponse
+// @Failure 500 {object} h.ErrorResponse
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /api_keys [get]
+func (h *handler) Handle(c echo.Context, in GetAPIKeysRequest) error {
+	if in.Page == 0 {
+		in.Page = PAGE_NUMBER
+	}
+
+	if in.PageSize <= 0 {
+		in.PageSize = PAGE_SIZE
+	} else if in.PageSize > 100 {
+		in.PageSize = 100
+	}
+
+	keys, totalCount, err := h.s.GetAPIKeys(c.Request().Context(), in.Page, in.PageSize)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error()).SetInternal(err)
+	}
+
+	totalPages := int(math.Ceil(float64(totalCount) / float64(in.PageSize)))
+
+	return c.JSON(http.StatusOK, GetAPIKeysResponse{
+		APIKeys:    lo.Map(keys, toAPIKey),
+		Page:       in.Page,
+		PageSize:   in.PageSize,
+		TotalItems: totalCount,
+		TotalPages: totalPages,
+	})
+}
+
+func toAPIKey(k entity.APIKey, _ int) APIKey {
+	key := APIKey{
+		APIKeyID:  k.ID,
+		Name:      k.Name,
+		Prefix:    k.Prefix,
+		Scopes:    k.Scopes,
+		CreatedAt: k.CreatedAt.Format(time.RFC3339),
+	}
+	if k.LastUsedAt != nil {
+		key.LastUsedAt = lo.ToPtr(k.LastUsedAt.Format(time.RFC3339))
+	}
+	if k.RevokedAt != nil {
+		key.RevokedAt = lo.ToPtr(k.RevokedAt.Format(time.RFC3339))
+	}
+	return key
+}
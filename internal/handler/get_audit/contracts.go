@@ -0,0 +1,22 @@
+package get_audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/4udiwe/subscription-service/internal/entity"
+	"github.com/google/uuid"
+)
+
+type AuditService interface {
+	GetAuditLog(
+		ctx context.Context,
+		entityType *string,
+		entityID *uuid.UUID,
+		actor *string,
+		from *time.Time,
+		to *time.Time,
+		page int,
+		pageSize int,
+	) ([]entity.AuditEntry, int, error)
+}
@@ -0,0 +1,155 @@
+package get_audit
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/4udiwe/subscription-service/internal/entity"
+	h "github.com/4udiwe/subscription-service/internal/handler"
+	"github.com/4udiwe/subscription-service/internal/handler/decorator"
+	service "github.com/4udiwe/subscription-service/internal/service/audit"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/samber/lo"
+)
+
+const PAGE_NUMBER = 1
+const PAGE_SIZE = 10
+
+type handler struct {
+	s AuditService
+}
+
+func New(s AuditService) h.Handler {
+	return decorator.NewBindAndValidateDecorator(&handler{s: s})
+}
+
+type GetAuditRequest struct {
+	EntityType string `query:"entity_type" validate:"omitempty,oneof=subscription offer"`
+	EntityID   string `query:"entity_id" validate:"omitempty,uuid"`
+	Actor      string `query:"actor"`
+	From       string `query:"from" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	To         string `query:"to" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	Page       int    `query:"page"`
+	PageSize   int    `query:"page_size"`
+}
+
+type GetAuditResponse struct {
+	Entries    []Entry `json:"entries"`
+	Page       int     `json:"page"`
+	PageSize   int     `json:"page_size"`
+	TotalItems int     `json:"total_items"`
+	TotalPages int     `json:"total_pages"`
+}
+
+type Entry struct {
+	ID         int64           `json:"id"`
+	Actor      string          `json:"actor"`
+	Action     string          `json:"action"`
+	EntityType string          `json:"entity_type"`
+	EntityID   uuid.UUID       `json:"entity_id"`
+	Before     json.RawMessage `json:"before,omitempty" swaggertype:"object"`
+	After      json.RawMessage `json:"after,omitempty" swaggertype:"object"`
+	CreatedAt  string          `json:"created_at"`
+}
+
+// Get audit log
+// @Summary Получение журнала аудита
+// @Description Получение журнала изменений подписок и предложений, новые первыми. Каждая запись содержит автора изменения (sub токена или api_key:<name>, anonymous при выключенной аутентификации, system для фоновых задач), действие и состояние сущности до и после изменения. Период from включительно, to не включительно.
+// @Tags audit
+// @Produce json
+// @Param entity_type query string false "Тип сущности" Enums(subscription, offer)
+// @Param entity_id query string false "ID сущности"
+// @Param actor query string false "Автор изменения"
+// @Param from query string false "Начало периода (RFC3339)"
+// @Param to query string false "Конец периода (RFC3339)"
+// @Param page query int false "Номер страницы" default(1)
+// @Param page_size query int false "Размер страницы" default(10) maximum(100)
+// @Success 200 {object} GetAuditResponse
+// @Failure 400 {object} h.ErrorResponse
+// @Failure 401 {object} h.ErrorResponse
+// @Failure 403 {object} h.ErrorResponse
+// @Failure 500 {object} h.ErrorResponse
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /audit [get]
+func (h *handler) Handle(c echo.Context, in GetAuditRequest) error {
+	if in.Page == 0 {
+		in.Page = PAGE_NUMBER
+	}
+
+	if in.PageSize <= 0 {
+		in.PageSize = PAGE_SIZE
+	} else if in.PageSize > 100 {
+		in.PageSize = 100
+	}
+
+	var entityType *string
+	if in.EntityType != "" {
+		entityType = &in.EntityType
+	}
+
+	var entityID *uuid.UUID
+	if in.EntityID != "" {
+		parsedID, err := uuid.Parse(in.EntityID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid entity_id format")
+		}
+		entityID = &parsedID
+	}
+
+	var actor *string
+	if in.Actor != "" {
+		actor = &in.Actor
+	}
+
+	var from, to *time.Time
+	if in.From != "" {
+		parsedFrom, err := time.Parse(time.RFC3339, in.From)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid from format")
+		}
+		from = &parsedFrom
+	}
+	if in.To != "" {
+		parsedTo, err := time.Parse(time.RFC3339, in.To)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid to format")
+		}
+		to = &parsedTo
+	}
+
+	entries, totalCount, err := h.s.GetAuditLog(c.Request().Context(), entityType, entityID, actor, from, to, in.Page, in.PageSize)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidPeriod) {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error()).SetInternal(err)
+	}
+
+	totalPages := int(math.Ceil(float64(totalCount) / float64(in.PageSize)))
+
+	return c.JSON(http.StatusOK, GetAuditResponse{
+		Entries:    lo.Map(entries, toEntry),
+		Page:       in.Page,
+		PageSize:   in.PageSize,
+		TotalItems: totalCount,
+		TotalPages: totalPages,
+	})
+}
+
+func toEntry(e entity.AuditEntry, _ int) Entry {
+	return Entry{
+		ID:         e.ID,
+		Actor:      e.Actor,
+		Action:     e.Action,
+		EntityType: e.EntityType,
+		EntityID:   e.EntityID,
+		Before:     e.Before,
+		After:      e.After,
+		CreatedAt:  e.CreatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,20 @@
+package get_cost_report
+
+import (
+	"context"
+	"time"
+
+	"github.com/4udiwe/subscription-service/internal/entity"
+	"github.com/google/uuid"
+)
+
+type SubscriptionService interface {
+	GetCostReport(
+		ctx context.Context,
+		userID *uuid.UUID,
+		serviceNames []string,
+		from time.Time,
+		to time.Time,
+		currency string,
+	) (entity.CostReport, error)
+}
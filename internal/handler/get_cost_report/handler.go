@@ -0,0 +1,135 @@
+package get_cost_report
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/4udiwe/subscription-service/internal/entity"
+	h "github.com/4udiwe/subscription-service/internal/handler"
+	"github.com/4udiwe/subscription-service/internal/handler/decorator"
+	"github.com/4udiwe/subscription-service/internal/service/subscription"
+	"github.com/4udiwe/subscription-service/pkg/auth"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/samber/lo"
+)
+
+type handler struct {
+	s SubscriptionService
+}
+
+func New(s SubscriptionService) h.Handler {
+	return decorator.NewBindAndValidateDecorator(&handler{s: s})
+}
+
+type GetCostReportRequest struct {
+	UserID       string   `query:"user_id" validate:"omitempty,uuid"`
+	From         string   `query:"from" validate:"required,datetime=2006-01-02"`
+	To           string   `query:"to" validate:"required,datetime=2006-01-02"`
+	ServiceNames []string `query:"service_name"`
+	Currency     string   `query:"currency" validate:"omitempty,iso4217"`
+}
+
+type GetCostReportResponse struct {
+	From     string        `json:"from"`
+	To       string        `json:"to"`
+	Currency string        `json:"currency"`
+	Total    int           `json:"total"`
+	Services []ServiceCost `json:"services"`
+	Months   []MonthlyCost `json:"months"`
+}
+
+type MonthlyCost struct {
+	Month    string        `json:"month"`
+	Total    int           `json:"total"`
+	Services []ServiceCost `json:"services"`
+}
+
+type ServiceCost struct {
+	ServiceName string `json:"service_name"`
+	Cost        int    `json:"cost"`
+}
+
+// Get subscription cost report
+// @Summary Отчёт о стоимости подписок
+// @Description Стоимость подписок за период с разбивкой по календарным месяцам и сервисам. Цена подписки распределяется по дням оплаченного периода, учитываются только дни, попавшие в период отчёта. Период отчёта - не длиннее 12 месяцев. Дни на паузе не оплачиваются. Без user_id отчёт строится по всем пользователям (только для администраторов), без service_name - по всем сервисам. Суммы переводятся в валюту currency (по умолчанию RUB) по курсу на дату начала каждой подписки.
+// @Tags subscriptions
+// @Produce json
+// @Param user_id query string false "User ID"
+// @Param from query string true "Начало периода (YYYY-MM-DD, включительно)"
+// @Param to query string true "Конец периода (YYYY-MM-DD, включительно)"
+// @Param service_name query []string false "Названия сервисов" collectionFormat(multi)
+// @Param currency query string false "Валюта отчёта (ISO 4217)" default(RUB)
+// @Success 200 {object} GetCostReportResponse
+// @Failure 400 {object} h.ErrorResponse
+// @Failure 401 {object} h.ErrorResponse
+// @Failure 403 {object} h.ErrorResponse
+// @Failure 422 {object} h.ErrorResponse
+// @Failure 500 {object} h.ErrorResponse
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /subscriptions/cost [get]
+func (h *handler) Handle(c echo.Context, in GetCostReportRequest) error {
+	var userID *uuid.UUID
+	if in.UserID != "" {
+		parsedUserID, err := uuid.Parse(in.UserID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid user_id format")
+		}
+		userID = &parsedUserID
+	}
+	if userID == nil && !auth.HasFullAccess(c.Request().Context()) ||
+		userID != nil && !auth.CanAccessUser(c.Request().Context(), *userID) {
+		return echo.NewHTTPError(http.StatusForbidden, "access denied")
+	}
+
+	from, err := time.Parse("2006-01-02", in.From)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid from format")
+	}
+	to, err := time.Parse("2006-01-02", in.To)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid to format")
+	}
+
+	if in.Currency == "" {
+		in.Currency = entity.DefaultCurrency
+	}
+
+	report, err := h.s.GetCostReport(c.Request().Context(), userID, in.ServiceNames, from, to, in.Currency)
+
+	if err != nil {
+		if errors.Is(err, subscription.ErrInvalidReportPeriod) || errors.Is(err, subscription.ErrReportPeriodTooLong) {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		}
+		if errors.Is(err, subscription.ErrExchangeRateNotFound) {
+			return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error()).SetInternal(err)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error()).SetInternal(err)
+	}
+
+	return c.JSON(http.StatusOK, GetCostReportResponse{
+		From:     report.From.Format("2006-01-02"),
+		To:       report.To.Format("2006-01-02"),
+		Currency: report.Currency,
+		Total:    report.Total,
+		Services: toServiceCosts(report.Services),
+		Months: lo.Map(report.Months, func(m entity.MonthlyCost, _ int) MonthlyCost {
+			return MonthlyCost{
+				Month:    m.Month.Format("2006-01"),
+				Total:    m.Total,
+				Services: toServiceCosts(m.Services),
+			}
+		}),
+	})
+}
+
+func toServiceCosts(services []entity.ServiceCost) []ServiceCost {
+	return lo.Map(services, func(s entity.ServiceCost, _ int) ServiceCost {
+		return ServiceCost{
+			ServiceName: s.ServiceName,
+			Cost:        s.Cost,
+		}
+	})
+}
@@ -0,0 +1,17 @@
+package get_exchange_rates
+
+import (
+	"context"
+
+	"github.com/4udiwe/subscription-service/internal/entity"
+)
+
+type ExchangeRateService interface {
+	GetRates(
+		ctx context.Context,
+		from *string,
+		to *string,
+		page int,
+		pageSize int,
+	) (rates []entity.ExchangeRate, total int, err error)
+}
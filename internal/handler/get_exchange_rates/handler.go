@@ -0,0 +1,103 @@
+package get_exchange_rates
+
+import (
+	"math"
+	"net/http"
+
+	"github.com/4udiwe/subscription-service/internal/entity"
+	h "github.com/4udiwe/subscription-service/internal/handler"
+	"github.com/4udiwe/subscription-service/internal/handler/decorator"
+	"github.com/labstack/echo/v4"
+	"github.com/samber/lo"
+)
+
+const PAGE_NUMBER = 1
+const PAGE_SIZE = 10
+
+type handler struct {
+	s ExchangeRateService
+}
+
+func New(s ExchangeRateService) h.Handler {
+	return decorator.NewBindAndValidateDecorator(&handler{s: s})
+}
+
+type GetExchangeRatesRequest struct {
+	From     string `query:"from" validate:"omitempty,iso4217"`
+	To       string `query:"to" validate:"omitempty,iso4217"`
+	Page     int    `query:"page"`
+	PageSize int    `query:"page_size"`
+}
+
+type GetExchangeRatesResponse struct {
+	Rates      []ExchangeRate `json:"rates"`
+	Page       int            `json:"page"`
+	PageSize   int            `json:"page_size"`
+	TotalItems int            `json:"total_items"`
+	TotalPages int            `json:"total_pages"`
+}
+
+type ExchangeRate struct {
+	From string  `json:"from"`
+	To   string  `json:"to"`
+	Date string  `json:"date"`
+	Rate float64 `json:"rate"`
+}
+
+// Get exchange rates
+// @Summary Получение курсов валют
+// @Description Получение загруженных курсов, новые даты первыми
+// @Tags exchange_rates
+// @Produce json
+// @Param from query string false "Исходная валюта (ISO 4217)"
+// @Param to query string false "Целевая валюта (ISO 4217)"
+// @Param page query int false "Номер страницы" default(1)
+// @Param page_size query int false "Размер страницы" default(10) maximum(100)
+// @Success 200 {object} GetExchangeRatesResponse
+// @Failure 400 {object} h.ErrorResponse
+// @Failure 401 {object} h.ErrorResponse
+// @Failure 500 {object} h.ErrorResponse
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /exchange_rates [get]
+func (h *handler) Handle(c echo.Context, in GetExchangeRatesRequest) error {
+	if in.Page == 0 {
+		in.Page = PAGE_NUMBER
+	}
+
+	if in.PageSize <= 0 {
+		in.PageSize = PAGE_SIZE
+	} else if in.PageSize > 100 {
+		in.PageSize = 100
+	}
+
+	var from, to *string
+	if in.From != "" {
+		from = &in.From
+	}
+	if in.To != "" {
+		to = &in.To
+	}
+
+	rates, totalCount, err := h.s.GetRates(c.Request().Context(), from, to, in.Page, in.PageSize)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error()).SetInternal(err)
+	}
+
+	totalPages := int(math.Ceil(float64(totalCount) / float64(in.PageSize)))
+
+	return c.JSON(http.StatusOK, GetExchangeRatesResponse{
+		Rates: lo.Map(rates, func(r entity.ExchangeRate, _ int) ExchangeRate {
+			return ExchangeRate{
+				From: r.From,
+				To:   r.To,
+				Date: r.Date.Format("2006-01-02"),
+				Rate: r.Rate,
+			}
+		}),
+		Page:       in.Page,
+		PageSize:   in.PageSize,
+		TotalItems: totalCount,
+		TotalPages: totalPages,
+	})
+}
@@ -0,0 +1,12 @@
+package get_offer
+
+import (
+	"context"
+
+	"github.com/4udiwe/subscription-service/internal/entity"
+	"github.com/google/uuid"
+)
+
+type OfferService interface {
+	GetOfferByID(ctx context.Context, offerID uuid.UUID) (entity.Offer, error)
+}
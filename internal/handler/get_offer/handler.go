@@ -0,0 +1,71 @@
+package get_offer
+
+import (
+	"errors"
+	"net/http"
+
+	h "github.com/4udiwe/subscription-service/internal/handler"
+	"github.com/4udiwe/subscription-service/internal/handler/decorator"
+	service "github.com/4udiwe/subscription-service/internal/service/offer"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type handler struct {
+	s OfferService
+}
+
+func New(s OfferService) h.Handler {
+	return decorator.NewBindAndValidateDecorator(&handler{s: s})
+}
+
+type GetOfferRequest struct {
+	OfferID uuid.UUID `param:"id" validate:"required,uuid"`
+}
+
+type GetOfferResponse struct {
+	OfferID        uuid.UUID `json:"offer_id"`
+	ServiceName    string    `json:"service_name"`
+	Price          int       `json:"price"`
+	Currency       string    `json:"currency"`
+	DurationMonths int       `json:"duration_months"`
+	TrialDays      int       `json:"trial_days"`
+	CreatedAt      string    `json:"created_at"`
+	UpdatedAt      string    `json:"updated_at"`
+}
+
+// Get offer by ID
+// @Summary Получение предложения по ID
+// @Description Получение предложения по его ID
+// @Tags offers
+// @Accept json
+// @Produce json
+// @Param id path string true "Offer ID"
+// @Success 200 {object} GetOfferResponse
+// @Failure 400 {object} h.ErrorResponse
+// @Failure 401 {object} h.ErrorResponse
+// @Failure 404 {object} h.ErrorResponse
+// @Failure 500 {object} h.ErrorResponse
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /offers/{id} [get]
+func (h *handler) Handle(c echo.Context, in GetOfferRequest) error {
+	offer, err := h.s.GetOfferByID(c.Request().Context(), in.OfferID)
+	if err != nil {
+		if errors.Is(err, service.ErrOfferNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error()).SetInternal(err)
+	}
+
+	return c.JSON(http.StatusOK, GetOfferResponse{
+		OfferID:        offer.ID,
+		ServiceName:    offer.Name,
+		Price:          offer.Price,
+		Currency:       offer.Currency,
+		DurationMonths: offer.DurationMonths,
+		TrialDays:      offer.TrialDays,
+		CreatedAt:      offer.CreatedAt.Format("2006-01-02"),
+		UpdatedAt:      offer.UpdatedAt.Format("2006-01-02"),
+	})
+}
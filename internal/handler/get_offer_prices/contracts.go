@@ -0,0 +1,12 @@
+package get_offer_prices
+
+import (
+	"context"
+
+	"github.com/4udiwe/subscription-service/internal/entity"
+	"github.com/google/uuid"
+)
+
+type OfferService interface {
+	GetOfferPrices(ctx context.Context, offerID uuid.UUID, pending *bool) ([]entity.OfferPrice, error)
+}
@@ -0,0 +1,97 @@
+package get_offer_prices
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/4udiwe/subscription-service/internal/entity"
+	h "github.com/4udiwe/subscription-service/internal/handler"
+	"github.com/4udiwe/subscription-service/internal/handler/decorator"
+	service "github.com/4udiwe/subscription-service/internal/service/offer"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/samber/lo"
+)
+
+type handler struct {
+	s OfferService
+}
+
+func New(s OfferService) h.Handler {
+	return decorator.NewBindAndValidateDecorator(&handler{s: s})
+}
+
+type GetOfferPricesRequest struct {
+	OfferID uuid.UUID `param:"id" validate:"required,uuid"`
+	Status  string    `query:"status" validate:"omitempty,oneof=pending applied"`
+}
+
+type GetOfferPricesResponse struct {
+	Prices []Price `json:"prices"`
+}
+
+type Price struct {
+	PriceID       uuid.UUID `json:"price_id"`
+	Price         int       `json:"price"`
+	Currency      string    `json:"currency"`
+	EffectiveFrom string    `json:"effective_from"`
+	Status        string    `json:"status"`
+	AppliedAt     string    `json:"applied_at,omitempty"`
+	FailedAt      string    `json:"failed_at,omitempty"`
+	CreatedAt     string    `json:"created_at"`
+}
+
+// Get offer prices
+// @Summary Получение истории цен предложения
+// @Description Получение всех версий цены предложения, последние первыми. Версия действует с даты effective_from до начала следующей. Подписка закрепляет версию, по которой была оформлена, поэтому изменение цены не меняет уже оформленные подписки и суммы трат. Статусы: pending - запланированное изменение, еще не примененное к предложению, applied - примененная версия, failed - изменение, которое не удалось применить (у другого предложения уже есть то же название и новая валюта), оно не действует. Фильтр status не возвращает версии failed.
+// @Tags offers
+// @Produce json
+// @Param id path string true "Offer ID"
+// @Param status query string false "Статус версии" Enums(pending, applied)
+// @Success 200 {object} GetOfferPricesResponse
+// @Failure 400 {object} h.ErrorResponse
+// @Failure 401 {object} h.ErrorResponse
+// @Failure 404 {object} h.ErrorResponse
+// @Failure 500 {object} h.ErrorResponse
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /offers/{id}/prices [get]
+func (h *handler) Handle(c echo.Context, in GetOfferPricesRequest) error {
+	var pending *bool
+	if in.Status != "" {
+		pending = lo.ToPtr(in.Status == "pending")
+	}
+
+	prices, err := h.s.GetOfferPrices(c.Request().Context(), in.OfferID, pending)
+	if err != nil {
+		if errors.Is(err, service.ErrOfferNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error()).SetInternal(err)
+	}
+
+	return c.JSON(http.StatusOK, GetOfferPricesResponse{
+		Prices: lo.Map(prices, toPrice),
+	})
+}
+
+func toPrice(p entity.OfferPrice, _ int) Price {
+	price := Price{
+		PriceID:       p.ID,
+		Price:         p.Price,
+		Currency:      p.Currency,
+		EffectiveFrom: p.EffectiveFrom.Format("2006-01-02"),
+		Status:        "pending",
+		CreatedAt:     p.CreatedAt.Format(time.RFC3339),
+	}
+	if p.AppliedAt != nil {
+		price.Status = "applied"
+		price.AppliedAt = p.AppliedAt.Format(time.RFC3339)
+	}
+	if p.FailedAt != nil {
+		price.Status = "failed"
+		price.FailedAt = p.FailedAt.Format(time.RFC3339)
+	}
+	return price
+}
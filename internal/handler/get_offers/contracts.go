@@ -4,8 +4,10 @@ import (
 	"context"
 
 	"github.com/4udiwe/subscription-service/internal/entity"
+	"github.com/4udiwe/subscription-service/pkg/cursor"
 )
 
 type OfferService interface {
 	GetAllOffers(ctx context.Context, page int, pageSize int) (offers []entity.Offer, total int, err error)
+	GetAllOffersAfter(ctx context.Context, after *cursor.Cursor, pageSize int) (offers []entity.Offer, next *cursor.Cursor, err error)
 }
@@ -7,6 +7,7 @@ import (
 	"github.com/4udiwe/subscription-service/internal/entity"
 	h "github.com/4udiwe/subscription-service/internal/handler"
 	decorator "github.com/4udiwe/subscription-service/internal/handler/decorator"
+	"github.com/4udiwe/subscription-service/pkg/cursor"
 	"github.com/labstack/echo/v4"
 )
 
@@ -22,8 +23,10 @@ func New(s OfferService) h.Handler {
 }
 
 type GetAllOffersRequest struct {
-	Page     int `query:"page"`
-	PageSize int `query:"page_size"`
+	Page       int    `query:"page"`
+	PageSize   int    `query:"page_size"`
+	Pagination string `query:"pagination" validate:"omitempty,oneof=offset cursor"`
+	Cursor     string `query:"cursor"`
 }
 
 type GetAllOffersResponse struct {
@@ -32,19 +35,25 @@ type GetAllOffersResponse struct {
 	PageSize   int            `json:"page_size"`
 	TotalItems int            `json:"total_items"`
 	TotalPages int            `json:"total_pages"`
+	NextCursor string         `json:"next_cursor,omitempty"`
 }
 
 // Get all offers
 // @Summary Получение всех офферов
-// @Description Получение списка всех офферов
+// @Description Получение списка всех офферов. В режиме cursor страницы строятся по (created_at, id), следующая страница запрашивается по next_cursor; page, total_items и total_pages в этом режиме не заполняются.
 // @Tags offers
 // @Accept json
 // @Produce json
 // @Param page query int false "Номер страницы" default(1)
 // @Param page_size query int false "Размер страницы" default(10) maximum(100)
+// @Param pagination query string false "Режим пагинации: offset (page/page_size) или cursor" Enums(offset, cursor)
+// @Param cursor query string false "Курсор следующей страницы (next_cursor из предыдущего ответа), включает режим cursor"
 // @Success 200 {object} GetAllOffersResponse
-// @Failure 400 {string} ErrorResponse
-// @Failure 500 {string} ErrorResponse
+// @Failure 400 {object} h.ErrorResponse
+// @Failure 401 {object} h.ErrorResponse
+// @Failure 500 {object} h.ErrorResponse
+// @Security BearerAuth
+// @Security APIKeyAuth
 // @Router /offers [get]
 func (h *handler) Handle(c echo.Context, in GetAllOffersRequest) error {
 	if in.Page == 0 {
@@ -57,9 +66,34 @@ func (h *handler) Handle(c echo.Context, in GetAllOffersRequest) error {
 		in.PageSize = 100
 	}
 
+	var after *cursor.Cursor
+	if in.Cursor != "" {
+		decoded, err := cursor.Decode(in.Cursor)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		}
+		after = &decoded
+	}
+
+	if after != nil || in.Pagination == "cursor" {
+		offers, next, err := h.s.GetAllOffersAfter(c.Request().Context(), after, in.PageSize)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, err.Error()).SetInternal(err)
+		}
+
+		response := GetAllOffersResponse{
+			Offers:   offers,
+			PageSize: in.PageSize,
+		}
+		if next != nil {
+			response.NextCursor = next.Encode()
+		}
+		return c.JSON(http.StatusOK, response)
+	}
+
 	offers, totalCount, err := h.s.GetAllOffers(c.Request().Context(), in.Page, in.PageSize)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error()).SetInternal(err)
 	}
 
 	totalPages := int(math.Ceil(float64(totalCount) / float64(in.PageSize)))
@@ -0,0 +1,11 @@
+package get_promo_codes
+
+import (
+	"context"
+
+	"github.com/4udiwe/subscription-service/internal/entity"
+)
+
+type PromoCodeService interface {
+	GetPromoCodes(ctx context.Context, page int, pageSize int) (codes []entity.PromoCode, total int, err error)
+}
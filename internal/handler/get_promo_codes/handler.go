@@ -0,0 +1,114 @@
+package get_promo_codes
+
+import (
+	"math"
+	"net/http"
+
+	"github.com/4udiwe/subscription-service/internal/entity"
+	h "github.com/4udiwe/subscription-service/internal/handler"
+	"github.com/4udiwe/subscription-service/internal/handler/decorator"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/samber/lo"
+)
+
+const PAGE_NUMBER = 1
+const PAGE_SIZE = 10
+
+type handler struct {
+	s PromoCodeService
+}
+
+func New(s PromoCodeService) h.Handler {
+	return decorator.NewBindAndValidateDecorator(&handler{s: s})
+}
+
+type GetPromoCodesRequest struct {
+	Page     int `query:"page"`
+	PageSize int `query:"page_size"`
+}
+
+type GetPromoCodesResponse struct {
+	PromoCodes []PromoCode `json:"promo_codes"`
+	Page       int         `json:"page"`
+	PageSize   int         `json:"page_size"`
+	TotalItems int         `json:"total_items"`
+	TotalPages int         `json:"total_pages"`
+}
+
+type PromoCode struct {
+	PromoCodeID    uuid.UUID   `json:"promo_code_id"`
+	Code           string      `json:"code"`
+	DiscountType   string      `json:"discount_type"`
+	DiscountValue  int         `json:"discount_value"`
+	Currency       *string     `json:"currency,omitempty"`
+	MaxRedemptions *int        `json:"max_redemptions,omitempty"`
+	Redemptions    int         `json:"redemptions"`
+	ValidFrom      *string     `json:"valid_from,omitempty"`
+	ValidUntil     *string     `json:"valid_until,omitempty"`
+	OfferIDs       []uuid.UUID `json:"offer_ids,omitempty"`
+	CreatedAt      string      `json:"created_at"`
+}
+
+// Get promo codes
+// @Summary Получение промокодов
+// @Description Получение списка промокодов с числом использований, новые первыми
+// @Tags promo_codes
+// @Produce json
+// @Param page query int false "Номер страницы" default(1)
+// @Param page_size query int false "Размер страницы" default(10) maximum(100)
+// @Success 200 {object} GetPromoCodesResponse
+// @Failure 400 {object} h.ErrorResponse
+// @Failure 401 {object} h.ErrorResponse
+// @Failure 403 {object} h.ErrorResponse
+// @Failure 500 {object} h.ErrorResponse
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /promo_codes [get]
+func (h *handler) Handle(c echo.Context, in GetPromoCodesRequest) error {
+	if in.Page == 0 {
+		in.Page = PAGE_NUMBER
+	}
+
+	if in.PageSize <= 0 {
+		in.PageSize = PAGE_SIZE
+	} else if in.PageSize > 100 {
+		in.PageSize = 100
+	}
+
+	codes, totalCount, err := h.s.GetPromoCodes(c.Request().Context(), in.Page, in.PageSize)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error()).SetInternal(err)
+	}
+
+	totalPages := int(math.Ceil(float64(totalCount) / float64(in.PageSize)))
+
+	return c.JSON(http.StatusOK, GetPromoCodesResponse{
+		PromoCodes: lo.Map(codes, toPromoCode),
+		Page:       in.Page,
+		PageSize:   in.PageSize,
+		TotalItems: totalCount,
+		TotalPages: totalPages,
+	})
+}
+
+func toPromoCode(p entity.PromoCode, _ int) PromoCode {
+	code := PromoCode{
+		PromoCodeID:    p.ID,
+		Code:           p.Code,
+		DiscountType:   string(p.DiscountType),
+		DiscountValue:  p.DiscountValue,
+		Currency:       p.Currency,
+		MaxRedemptions: p.MaxRedemptions,
+		Redemptions:    p.Redemptions,
+		OfferIDs:       p.OfferIDs,
+		CreatedAt:      p.CreatedAt.Format("2006-01-02"),
+	}
+	if p.ValidFrom != nil {
+		code.ValidFrom = lo.ToPtr(p.ValidFrom.Format("2006-01-02"))
+	}
+	if p.ValidUntil != nil {
+		code.ValidUntil = lo.ToPtr(p.ValidUntil.Format("2006-01-02"))
+	}
+	return code
+}
@@ -0,0 +1,13 @@
+package get_sub
+
+import (
+	"context"
+
+	"github.com/4udiwe/subscription-service/internal/entity"
+	"github.com/google/uuid"
+)
+
+type SubscriptionService interface {
+	GetSubscriptionByID(ctx context.Context, subID uuid.UUID) (entity.SubscriptionFullInfo, error)
+	GetSubscriptionPauses(ctx context.Context, subID uuid.UUID) ([]entity.SubscriptionPause, error)
+}
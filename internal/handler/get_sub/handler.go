@@ -0,0 +1,121 @@
+package get_sub
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/4udiwe/subscription-service/internal/entity"
+	h "github.com/4udiwe/subscription-service/internal/handler"
+	"github.com/4udiwe/subscription-service/internal/handler/decorator"
+	"github.com/4udiwe/subscription-service/internal/service/subscription"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/samber/lo"
+)
+
+type handler struct {
+	s SubscriptionService
+}
+
+func New(s SubscriptionService) h.Handler {
+	return decorator.NewBindAndValidateDecorator(&handler{s: s})
+}
+
+type GetSubscriptionRequest struct {
+	SubscriptionID uuid.UUID `param:"id" validate:"required,uuid"`
+}
+
+type Pause struct {
+	PausedAt  string  `json:"paused_at"`
+	ResumedAt *string `json:"resumed_at"`
+}
+
+type GetSubscriptionResponse struct {
+	SubscriptionID uuid.UUID  `json:"subscription_id"`
+	UserID         uuid.UUID  `json:"user_id"`
+	OfferID        uuid.UUID  `json:"offer_id"`
+	OfferName      string     `json:"offer_name"`
+	Price          int        `json:"price"`
+	ListPrice      int        `json:"list_price"`
+	Currency       string     `json:"currency"`
+	DurationMonths int        `json:"duration_months"`
+	StartDate      string     `json:"start_date"`
+	EndDate        string     `json:"end_date"`
+	TrialEndDate   *string    `json:"trial_end_date,omitempty"`
+	AutoRenew      bool       `json:"auto_renew"`
+	RenewedFromID  *uuid.UUID `json:"renewed_from_id,omitempty"`
+	Status         string     `json:"status"`
+	CancelledAt    *time.Time `json:"cancelled_at,omitempty"`
+	CancelReason   *string    `json:"cancel_reason,omitempty"`
+	Pauses         []Pause    `json:"pauses"`
+	CreatedAt      string     `json:"created_at"`
+	UpdatedAt      string     `json:"updated_at"`
+}
+
+// Get subscription by ID
+// @Summary Получение подписки по ID
+// @Description Получение полной информации о подписке, включая название, цену и длительность предложения и историю пауз
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Success 200 {object} GetSubscriptionResponse
+// @Failure 400 {object} h.ErrorResponse
+// @Failure 401 {object} h.ErrorResponse
+// @Failure 403 {object} h.ErrorResponse
+// @Failure 404 {object} h.ErrorResponse
+// @Failure 500 {object} h.ErrorResponse
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /subscriptions/{id} [get]
+func (h *handler) Handle(c echo.Context, in GetSubscriptionRequest) error {
+	sub, err := h.s.GetSubscriptionByID(c.Request().Context(), in.SubscriptionID)
+	if err != nil {
+		if errors.Is(err, subscription.ErrSubscriptionNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error()).SetInternal(err)
+	}
+
+	pauses, err := h.s.GetSubscriptionPauses(c.Request().Context(), sub.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error()).SetInternal(err)
+	}
+
+	var trialEndDate *string
+	if sub.TrialEndDate != nil {
+		trialEndDate = lo.ToPtr(sub.TrialEndDate.Format("2006-01-02"))
+	}
+
+	return c.JSON(http.StatusOK, GetSubscriptionResponse{
+		SubscriptionID: sub.ID,
+		UserID:         sub.UserID,
+		OfferID:        sub.OfferID,
+		OfferName:      sub.OfferName,
+		Price:          sub.Price,
+		ListPrice:      sub.ListPrice,
+		Currency:       sub.Currency,
+		DurationMonths: sub.DurationMonths,
+		StartDate:      sub.StartDate.Format("2006-01-02"),
+		EndDate:        sub.EndDate.Format("2006-01-02"),
+		TrialEndDate:   trialEndDate,
+		AutoRenew:      sub.AutoRenew,
+		RenewedFromID:  sub.RenewedFromID,
+		Status:         string(sub.Status),
+		CancelledAt:    sub.CancelledAt,
+		CancelReason:   sub.CancelReason,
+		Pauses: lo.Map(pauses, func(p entity.SubscriptionPause, _ int) Pause {
+			var resumedAt *string
+			if p.ResumedAt != nil {
+				resumedAt = lo.ToPtr(p.ResumedAt.Format("2006-01-02"))
+			}
+			return Pause{
+				PausedAt:  p.PausedAt.Format("2006-01-02"),
+				ResumedAt: resumedAt,
+			}
+		}),
+		CreatedAt: sub.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: sub.UpdatedAt.Format(time.RFC3339),
+	})
+}
@@ -4,8 +4,20 @@ import (
 	"context"
 
 	"github.com/4udiwe/subscription-service/internal/entity"
+	"github.com/4udiwe/subscription-service/pkg/cursor"
 )
 
 type SubscriptionService interface {
-	GetAllSubscriptions(ctx context.Context, page int, pageSize int) ([]entity.SubscriptionFullInfo, int, error)
+	GetAllSubscriptions(
+		ctx context.Context,
+		status *entity.SubscriptionStatus,
+		page int,
+		pageSize int,
+	) ([]entity.SubscriptionFullInfo, int, error)
+	GetAllSubscriptionsAfter(
+		ctx context.Context,
+		status *entity.SubscriptionStatus,
+		after *cursor.Cursor,
+		pageSize int,
+	) ([]entity.SubscriptionFullInfo, *cursor.Cursor, error)
 }
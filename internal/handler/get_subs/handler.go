@@ -7,6 +7,7 @@ import (
 	"github.com/4udiwe/subscription-service/internal/entity"
 	h "github.com/4udiwe/subscription-service/internal/handler"
 	"github.com/4udiwe/subscription-service/internal/handler/decorator"
+	"github.com/4udiwe/subscription-service/pkg/cursor"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"github.com/samber/lo"
@@ -24,8 +25,11 @@ func New(s SubscriptionService) h.Handler {
 }
 
 type GetAllSubscriptionsRequest struct {
-	Page     int `query:"page"`
-	PageSize int `query:"page_size"`
+	Page       int    `query:"page"`
+	PageSize   int    `query:"page_size"`
+	Status     string `query:"status" validate:"omitempty,oneof=active cancelled expired paused"`
+	Pagination string `query:"pagination" validate:"omitempty,oneof=offset cursor"`
+	Cursor     string `query:"cursor"`
 }
 
 type GetAllSubscriptionsResponse struct {
@@ -34,6 +38,7 @@ type GetAllSubscriptionsResponse struct {
 	PageSize      int            `json:"page_size"`
 	TotalItems    int            `json:"total_items"`
 	TotalPages    int            `json:"total_pages"`
+	NextCursor    string         `json:"next_cursor,omitempty"`
 }
 
 type Subscription struct {
@@ -41,20 +46,31 @@ type Subscription struct {
 	UserID         uuid.UUID `json:"user_id"`
 	OfferName      string    `json:"offer_name"`
 	Price          int       `json:"price"`
+	ListPrice      int       `json:"list_price"`
+	Currency       string    `json:"currency"`
 	StartDate      string    `json:"start_date"`
 	EndDate        string    `json:"end_date"`
+	Status         string    `json:"status"`
 }
 
 // Get all subscriptions
 // @Summary Получение всех подписок
-// @Description Получение списка всех подписок
+// @Description Получение списка всех подписок. В режиме cursor страницы строятся по (created_at, id), следующая страница запрашивается по next_cursor; page, total_items и total_pages в этом режиме не заполняются.
 // @Tags subscriptions
 // @Accept json
 // @Produce json
 // @Param page query int false "Номер страницы" default(1)
 // @Param page_size query int false "Размер страницы" default(10) maximum(100)
+// @Param status query string false "Статус подписки" Enums(active, cancelled, expired, paused)
+// @Param pagination query string false "Режим пагинации: offset (page/page_size) или cursor" Enums(offset, cursor)
+// @Param cursor query string false "Курсор следующей страницы (next_cursor из предыдущего ответа), включает режим cursor"
 // @Success 200 {object} GetAllSubscriptionsResponse
-// @Failure 500 {string} ErrorResponse
+// @Failure 400 {object} h.ErrorResponse
+// @Failure 401 {object} h.ErrorResponse
+// @Failure 403 {object} h.ErrorResponse
+// @Failure 500 {object} h.ErrorResponse
+// @Security BearerAuth
+// @Security APIKeyAuth
 // @Router /subscriptions [get]
 func (h *handler) Handle(c echo.Context, in GetAllSubscriptionsRequest) error {
 	if in.Page == 0 {
@@ -67,28 +83,63 @@ func (h *handler) Handle(c echo.Context, in GetAllSubscriptionsRequest) error {
 		in.PageSize = 100
 	}
 
-	sub, totalCount, err := h.s.GetAllSubscriptions(c.Request().Context(), in.Page, in.PageSize)
+	var status *entity.SubscriptionStatus
+	if in.Status != "" {
+		status = lo.ToPtr(entity.SubscriptionStatus(in.Status))
+	}
+
+	var after *cursor.Cursor
+	if in.Cursor != "" {
+		decoded, err := cursor.Decode(in.Cursor)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		}
+		after = &decoded
+	}
+
+	if after != nil || in.Pagination == "cursor" {
+		subs, next, err := h.s.GetAllSubscriptionsAfter(c.Request().Context(), status, after, in.PageSize)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, err.Error()).SetInternal(err)
+		}
+
+		response := GetAllSubscriptionsResponse{
+			Subscriptions: lo.Map(subs, toSubscription),
+			PageSize:      in.PageSize,
+		}
+		if next != nil {
+			response.NextCursor = next.Encode()
+		}
+		return c.JSON(http.StatusOK, response)
+	}
+
+	sub, totalCount, err := h.s.GetAllSubscriptions(c.Request().Context(), status, in.Page, in.PageSize)
 
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error()).SetInternal(err)
 	}
 
 	totalPages := int(math.Ceil(float64(totalCount) / float64(in.PageSize)))
 
 	return c.JSON(http.StatusOK, GetAllSubscriptionsResponse{
-		Subscriptions: lo.Map(sub, func(s entity.SubscriptionFullInfo, _ int) Subscription {
-			return Subscription{
-				SubscriptionID: s.ID,
-				UserID:         s.UserID,
-				OfferName:      s.OfferName,
-				Price:          s.Price,
-				StartDate:      s.StartDate.Format("2006-01-02"),
-				EndDate:        s.EndDate.Format("2006-01-02"),
-			}
-		}),
-		Page:       in.Page,
-		PageSize:   in.PageSize,
-		TotalItems: totalCount,
-		TotalPages: totalPages,
+		Subscriptions: lo.Map(sub, toSubscription),
+		Page:          in.Page,
+		PageSize:      in.PageSize,
+		TotalItems:    totalCount,
+		TotalPages:    totalPages,
 	})
 }
+
+func toSubscription(s entity.SubscriptionFullInfo, _ int) Subscription {
+	return Subscription{
+		SubscriptionID: s.ID,
+		UserID:         s.UserID,
+		OfferName:      s.OfferName,
+		Price:          s.Price,
+		ListPrice:      s.ListPrice,
+		Currency:       s.Currency,
+		StartDate:      s.StartDate.Format("2006-01-02"),
+		EndDate:        s.EndDate.Format("2006-01-02"),
+		Status:         string(s.Status),
+	}
+}
@@ -4,9 +4,23 @@ import (
 	"context"
 
 	"github.com/4udiwe/subscription-service/internal/entity"
+	"github.com/4udiwe/subscription-service/pkg/cursor"
 	"github.com/google/uuid"
 )
 
 type SubscriptionService interface {
-	GetAllSubscriptionsByUserID(ctx context.Context, userID uuid.UUID, page int, pageSize int) ([]entity.SubscriptionFullInfo, int, error)
+	GetAllSubscriptionsByUserID(
+		ctx context.Context,
+		userID uuid.UUID,
+		status *entity.SubscriptionStatus,
+		page int,
+		pageSize int,
+	) ([]entity.SubscriptionFullInfo, int, error)
+	GetAllSubscriptionsByUserIDAfter(
+		ctx context.Context,
+		userID uuid.UUID,
+		status *entity.SubscriptionStatus,
+		after *cursor.Cursor,
+		pageSize int,
+	) ([]entity.SubscriptionFullInfo, *cursor.Cursor, error)
 }
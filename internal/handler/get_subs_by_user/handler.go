@@ -7,6 +7,8 @@ import (
 	"github.com/4udiwe/subscription-service/internal/entity"
 	h "github.com/4udiwe/subscription-service/internal/handler"
 	"github.com/4udiwe/subscription-service/internal/handler/decorator"
+	"github.com/4udiwe/subscription-service/pkg/auth"
+	"github.com/4udiwe/subscription-service/pkg/cursor"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"github.com/samber/lo"
@@ -24,9 +26,12 @@ func New(s SubscriptionService) h.Handler {
 }
 
 type GetSubscriptionsByUserRequest struct {
-	UserID   uuid.UUID `json:"user_id" validate:"required,uuid"`
-	Page     int       `query:"page"`
-	PageSize int       `query:"page_size"`
+	UserID     uuid.UUID `json:"user_id" validate:"required,uuid"`
+	Page       int       `query:"page"`
+	PageSize   int       `query:"page_size"`
+	Status     string    `query:"status" validate:"omitempty,oneof=active cancelled expired paused"`
+	Pagination string    `query:"pagination" validate:"omitempty,oneof=offset cursor"`
+	Cursor     string    `query:"cursor"`
 }
 
 type GetSubscriptionsByUserResponse struct {
@@ -35,6 +40,7 @@ type GetSubscriptionsByUserResponse struct {
 	PageSize      int            `json:"page_size"`
 	TotalItems    int            `json:"total_items"`
 	TotalPages    int            `json:"total_pages"`
+	NextCursor    string         `json:"next_cursor,omitempty"`
 }
 
 type Subscription struct {
@@ -42,23 +48,38 @@ type Subscription struct {
 	UserID         uuid.UUID `json:"user_id"`
 	OfferName      string    `json:"offer_name"`
 	Price          int       `json:"price"`
+	ListPrice      int       `json:"list_price"`
+	Currency       string    `json:"currency"`
 	StartDate      string    `json:"start_date"`
 	EndDate        string    `json:"end_date"`
+	Status         string    `json:"status"`
 }
 
 // Get all subscriptions by user ID
 // @Summary Получение подписок по ID пользователя
-// @Description Получение списка подписок для указанного пользователя
+// @Description Получение списка подписок для указанного пользователя. В режиме cursor страницы строятся по (created_at, id), следующая страница запрашивается по next_cursor; page, total_items и total_pages в этом режиме не заполняются.
 // @Tags subscriptions
 // @Accept json
 // @Produce json
 // @Param user body GetSubscriptionsByUserRequest true "user ID"
 // @Param page query int false "Номер страницы" default(1)
 // @Param page_size query int false "Размер страницы" default(10) maximum(100)
+// @Param status query string false "Статус подписки" Enums(active, cancelled, expired, paused)
+// @Param pagination query string false "Режим пагинации: offset (page/page_size) или cursor" Enums(offset, cursor)
+// @Param cursor query string false "Курсор следующей страницы (next_cursor из предыдущего ответа), включает режим cursor"
 // @Success 200 {object} GetSubscriptionsByUserResponse
-// @Failure 500 {string} ErrorResponse
+// @Failure 400 {object} h.ErrorResponse
+// @Failure 401 {object} h.ErrorResponse
+// @Failure 403 {object} h.ErrorResponse
+// @Failure 500 {object} h.ErrorResponse
+// @Security BearerAuth
+// @Security APIKeyAuth
 // @Router /subscriptions/by-user [get]
 func (h *handler) Handle(c echo.Context, in GetSubscriptionsByUserRequest) error {
+	if !auth.CanAccessUser(c.Request().Context(), in.UserID) {
+		return echo.NewHTTPError(http.StatusForbidden, "access denied")
+	}
+
 	if in.Page == 0 {
 		in.Page = PAGE_NUMBER
 	}
@@ -69,28 +90,63 @@ func (h *handler) Handle(c echo.Context, in GetSubscriptionsByUserRequest) error
 		in.PageSize = 100
 	}
 
-	sub, totalCount, err := h.s.GetAllSubscriptionsByUserID(c.Request().Context(), in.UserID, in.Page, in.PageSize)
+	var status *entity.SubscriptionStatus
+	if in.Status != "" {
+		status = lo.ToPtr(entity.SubscriptionStatus(in.Status))
+	}
+
+	var after *cursor.Cursor
+	if in.Cursor != "" {
+		decoded, err := cursor.Decode(in.Cursor)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		}
+		after = &decoded
+	}
+
+	if after != nil || in.Pagination == "cursor" {
+		subs, next, err := h.s.GetAllSubscriptionsByUserIDAfter(c.Request().Context(), in.UserID, status, after, in.PageSize)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, err.Error()).SetInternal(err)
+		}
+
+		response := GetSubscriptionsByUserResponse{
+			Subscriptions: lo.Map(subs, toSubscription),
+			PageSize:      in.PageSize,
+		}
+		if next != nil {
+			response.NextCursor = next.Encode()
+		}
+		return c.JSON(http.StatusOK, response)
+	}
+
+	sub, totalCount, err := h.s.GetAllSubscriptionsByUserID(c.Request().Context(), in.UserID, status, in.Page, in.PageSize)
 
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error()).SetInternal(err)
 	}
 
 	totalPages := int(math.Ceil(float64(totalCount) / float64(in.PageSize)))
 
 	return c.JSON(http.StatusOK, GetSubscriptionsByUserResponse{
-		Subscriptions: lo.Map(sub, func(s entity.SubscriptionFullInfo, _ int) Subscription {
-			return Subscription{
-				SubscriptionID: s.ID,
-				UserID:         s.UserID,
-				OfferName:      s.OfferName,
-				Price:          s.Price,
-				StartDate:      s.StartDate.Format("2006-01-02"),
-				EndDate:        s.EndDate.Format("2006-01-02"),
-			}
-		}),
-		Page:       in.Page,
-		PageSize:   in.PageSize,
-		TotalItems: totalCount,
-		TotalPages: totalPages,
+		Subscriptions: lo.Map(sub, toSubscription),
+		Page:          in.Page,
+		PageSize:      in.PageSize,
+		TotalItems:    totalCount,
+		TotalPages:    totalPages,
 	})
 }
+
+func toSubscription(s entity.SubscriptionFullInfo, _ int) Subscription {
+	return Subscription{
+		SubscriptionID: s.ID,
+		UserID:         s.UserID,
+		OfferName:      s.OfferName,
+		Price:          s.Price,
+		ListPrice:      s.ListPrice,
+		Currency:       s.Currency,
+		StartDate:      s.StartDate.Format("2006-01-02"),
+		EndDate:        s.EndDate.Format("2006-01-02"),
+		Status:         string(s.Status),
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/4udiwe/subscription-service/internal/entity"
+	"github.com/4udiwe/subscription-service/pkg/cursor"
 	"github.com/google/uuid"
 )
 
@@ -13,9 +14,22 @@ type SubscriptionService interface {
 		ctx context.Context,
 		userID uuid.UUID,
 		subscriptionName string,
+		status *entity.SubscriptionStatus,
 		startPeriod *time.Time,
 		endPeriod *time.Time,
+		currency string,
 		page int,
 		pageSize int,
 	) (subs []entity.SubscriptionFullInfo, price int, totalCount int, err error)
+	GetAllWithPriceByUserIDAndSubscriptionNameAfter(
+		ctx context.Context,
+		userID uuid.UUID,
+		subscriptionName string,
+		status *entity.SubscriptionStatus,
+		startPeriod *time.Time,
+		endPeriod *time.Time,
+		currency string,
+		after *cursor.Cursor,
+		pageSize int,
+	) (subs []entity.SubscriptionFullInfo, price int, next *cursor.Cursor, err error)
 }
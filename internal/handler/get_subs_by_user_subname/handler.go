@@ -1,6 +1,7 @@
 package get_subs_by_user_subname
 
 import (
+	"errors"
 	"math"
 	"net/http"
 	"time"
@@ -8,6 +9,9 @@ import (
 	"github.com/4udiwe/subscription-service/internal/entity"
 	h "github.com/4udiwe/subscription-service/internal/handler"
 	"github.com/4udiwe/subscription-service/internal/handler/decorator"
+	"github.com/4udiwe/subscription-service/internal/service/subscription"
+	"github.com/4udiwe/subscription-service/pkg/auth"
+	"github.com/4udiwe/subscription-service/pkg/cursor"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"github.com/samber/lo"
@@ -25,21 +29,27 @@ func New(s SubscriptionService) h.Handler {
 }
 
 type GetSubsByUserAndServiceNameRequest struct {
-	UserID    uuid.UUID `json:"user_id" validate:"required,uuid"`
-	OfferName string    `json:"offer_name" validate:"required"`
-	StartDate string    `json:"start_date" validate:"omitempty"`
-	EndDate   string    `json:"end_date" validate:"omitempty"`
-	Page      int       `query:"page"`
-	PageSize  int       `query:"page_size"`
+	UserID     uuid.UUID `json:"user_id" validate:"required,uuid"`
+	OfferName  string    `json:"offer_name" validate:"required"`
+	StartDate  string    `json:"start_date" validate:"omitempty"`
+	EndDate    string    `json:"end_date" validate:"omitempty"`
+	Page       int       `query:"page"`
+	PageSize   int       `query:"page_size"`
+	Status     string    `query:"status" validate:"omitempty,oneof=active cancelled expired paused"`
+	Pagination string    `query:"pagination" validate:"omitempty,oneof=offset cursor"`
+	Cursor     string    `query:"cursor"`
+	Currency   string    `query:"currency" validate:"omitempty,iso4217"`
 }
 
 type GetSubsByUserAndServiceNameResponse struct {
 	TotalPrice    int            `json:"total_price"`
+	Currency      string         `json:"currency"`
 	Subscriptions []Subscription `json:"subscriptions"`
 	Page          int            `json:"page"`
 	PageSize      int            `json:"page_size"`
 	TotalItems    int            `json:"total_items"`
 	TotalPages    int            `json:"total_pages"`
+	NextCursor    string         `json:"next_cursor,omitempty"`
 }
 
 type Subscription struct {
@@ -47,24 +57,40 @@ type Subscription struct {
 	UserID         uuid.UUID `json:"user_id"`
 	OfferName      string    `json:"offer_name"`
 	Price          int       `json:"price"`
+	ListPrice      int       `json:"list_price"`
+	Currency       string    `json:"currency"`
 	StartDate      string    `json:"start_date"`
 	EndDate        string    `json:"end_date"`
+	Status         string    `json:"status"`
 }
 
 // Get all subscriptions by user ID and subscription name
 // @Summary Получение подписок по ID пользователя и названию подписки
-// @Description Получение списка подписок для указанного пользователя и названия подписки с возможностью фильтрации по дате начала и окончания
+// @Description Получение списка подписок для указанного пользователя и названия подписки с возможностью фильтрации по дате начала и окончания. В режиме cursor страницы строятся по (created_at, id), следующая страница запрашивается по next_cursor; page, total_items и total_pages в этом режиме не заполняются. total_price считается по всем подходящим подпискам и переводится в валюту currency (по умолчанию RUB) по курсу на дату начала каждой подписки.
 // @Tags subscriptions
 // @Accept json
 // @Produce json
 // @Param user body GetSubsByUserAndServiceNameRequest true "user ID and subscription name"
 // @Param page query int false "Номер страницы" default(1)
 // @Param page_size query int false "Размер страницы" default(10) maximum(100)
+// @Param status query string false "Статус подписки" Enums(active, cancelled, expired, paused)
+// @Param pagination query string false "Режим пагинации: offset (page/page_size) или cursor" Enums(offset, cursor)
+// @Param cursor query string false "Курсор следующей страницы (next_cursor из предыдущего ответа), включает режим cursor"
+// @Param currency query string false "Валюта total_price (ISO 4217)" default(RUB)
 // @Success 200 {object} GetSubsByUserAndServiceNameResponse
-// @Failure 400 {string} ErrorResponse
-// @Failure 500 {string} ErrorResponse
+// @Failure 400 {object} h.ErrorResponse
+// @Failure 401 {object} h.ErrorResponse
+// @Failure 403 {object} h.ErrorResponse
+// @Failure 422 {object} h.ErrorResponse
+// @Failure 500 {object} h.ErrorResponse
+// @Security BearerAuth
+// @Security APIKeyAuth
 // @Router /subscriptions/by-user-and-subname [get]
 func (h *handler) Handle(c echo.Context, in GetSubsByUserAndServiceNameRequest) error {
+	if !auth.CanAccessUser(c.Request().Context(), in.UserID) {
+		return echo.NewHTTPError(http.StatusForbidden, "access denied")
+	}
+
 	if in.Page == 0 {
 		in.Page = PAGE_NUMBER
 	}
@@ -92,29 +118,77 @@ func (h *handler) Handle(c echo.Context, in GetSubsByUserAndServiceNameRequest)
 		endDate = &parsedEndDate
 	}
 
-	sub, totalPrice, totalCount, err := h.s.GetAllWithPriceByUserIDAndSubscriptionName(c.Request().Context(), in.UserID, in.OfferName, startDate, endDate, in.Page, in.PageSize)
+	var status *entity.SubscriptionStatus
+	if in.Status != "" {
+		status = lo.ToPtr(entity.SubscriptionStatus(in.Status))
+	}
+
+	if in.Currency == "" {
+		in.Currency = entity.DefaultCurrency
+	}
+
+	var after *cursor.Cursor
+	if in.Cursor != "" {
+		decoded, err := cursor.Decode(in.Cursor)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		}
+		after = &decoded
+	}
+
+	if after != nil || in.Pagination == "cursor" {
+		subs, totalPrice, next, err := h.s.GetAllWithPriceByUserIDAndSubscriptionNameAfter(c.Request().Context(), in.UserID, in.OfferName, status, startDate, endDate, in.Currency, after, in.PageSize)
+		if err != nil {
+			if errors.Is(err, subscription.ErrExchangeRateNotFound) {
+				return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error()).SetInternal(err)
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, err.Error()).SetInternal(err)
+		}
+
+		response := GetSubsByUserAndServiceNameResponse{
+			TotalPrice:    totalPrice,
+			Currency:      in.Currency,
+			Subscriptions: lo.Map(subs, toSubscription),
+			PageSize:      in.PageSize,
+		}
+		if next != nil {
+			response.NextCursor = next.Encode()
+		}
+		return c.JSON(http.StatusOK, response)
+	}
+
+	sub, totalPrice, totalCount, err := h.s.GetAllWithPriceByUserIDAndSubscriptionName(c.Request().Context(), in.UserID, in.OfferName, status, startDate, endDate, in.Currency, in.Page, in.PageSize)
 
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		if errors.Is(err, subscription.ErrExchangeRateNotFound) {
+			return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error()).SetInternal(err)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error()).SetInternal(err)
 	}
 
 	totalPages := int(math.Ceil(float64(totalCount) / float64(in.PageSize)))
 
 	return c.JSON(http.StatusOK, GetSubsByUserAndServiceNameResponse{
-		TotalPrice: totalPrice,
-		Subscriptions: lo.Map(sub, func(s entity.SubscriptionFullInfo, _ int) Subscription {
-			return Subscription{
-				SubscriptionID: s.ID,
-				UserID:         s.UserID,
-				OfferName:      s.OfferName,
-				Price:          s.Price,
-				StartDate:      s.StartDate.Format("2006-01-02"),
-				EndDate:        s.EndDate.Format("2006-01-02"),
-			}
-		}),
-		Page:       in.Page,
-		PageSize:   in.PageSize,
-		TotalItems: totalCount,
-		TotalPages: totalPages,
+		TotalPrice:    totalPrice,
+		Currency:      in.Currency,
+		Subscriptions: lo.Map(sub, toSubscription),
+		Page:          in.Page,
+		PageSize:      in.PageSize,
+		TotalItems:    totalCount,
+		TotalPages:    totalPages,
 	})
 }
+
+func toSubscription(s entity.SubscriptionFullInfo, _ int) Subscription {
+	return Subscription{
+		SubscriptionID: s.ID,
+		UserID:         s.UserID,
+		OfferName:      s.OfferName,
+		Price:          s.Price,
+		ListPrice:      s.ListPrice,
+		Currency:       s.Currency,
+		StartDate:      s.StartDate.Format("2006-01-02"),
+		EndDate:        s.EndDate.Format("2006-01-02"),
+		Status:         string(s.Status),
+	}
+}
@@ -0,0 +1,18 @@
+package get_webhook_deliveries
+
+import (
+	"context"
+
+	"github.com/4udiwe/subscription-service/internal/entity"
+	"github.com/google/uuid"
+)
+
+type WebhookService interface {
+	GetDeliveries(
+		ctx context.Context,
+		endpointID uuid.UUID,
+		status *entity.WebhookDeliveryStatus,
+		page int,
+		pageSize int,
+	) ([]entity.WebhookDelivery, int, error)
+}
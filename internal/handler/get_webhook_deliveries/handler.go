@@ -0,0 +1,133 @@
+package get_webhook_deliveries
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/4udiwe/subscription-service/internal/entity"
+	h "github.com/4udiwe/subscription-service/internal/handler"
+	"github.com/4udiwe/subscription-service/internal/handler/decorator"
+	service "github.com/4udiwe/subscription-service/internal/service/webhook"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/samber/lo"
+)
+
+const PAGE_NUMBER = 1
+const PAGE_SIZE = 10
+
+type handler struct {
+	s WebhookService
+}
+
+func New(s WebhookService) h.Handler {
+	return decorator.NewBindAndValidateDecorator(&handler{s: s})
+}
+
+type GetWebhookDeliveriesRequest struct {
+	WebhookID uuid.UUID `param:"id" validate:"required,uuid"`
+	Status    string    `query:"status" validate:"omitempty,oneof=pending delivered dead"`
+	Page      int       `query:"page"`
+	PageSize  int       `query:"page_size"`
+}
+
+type GetWebhookDeliveriesResponse struct {
+	Deliveries []Delivery `json:"deliveries"`
+	Page       int        `json:"page"`
+	PageSize   int        `json:"page_size"`
+	TotalItems int        `json:"total_items"`
+	TotalPages int        `json:"total_pages"`
+}
+
+type Delivery struct {
+	DeliveryID     uuid.UUID       `json:"delivery_id"`
+	WebhookID      uuid.UUID       `json:"webhook_id"`
+	EventID        int64           `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  string          `json:"next_attempt_at,omitempty"`
+	LastStatusCode *int            `json:"last_status_code,omitempty"`
+	LastError      *string         `json:"last_error,omitempty"`
+	Payload        json.RawMessage `json:"payload" swaggertype:"object"`
+	CreatedAt      string          `json:"created_at"`
+	DeliveredAt    string          `json:"delivered_at,omitempty"`
+}
+
+// Get webhook deliveries
+// @Summary Получение доставок вебхука
+// @Description Получение истории доставок событий на вебхук, новые первыми. Статусы: pending - ожидает отправки или повтора (next_attempt_at), delivered - доставлено, dead - все попытки исчерпаны. Неудачная доставка повторяется с экспоненциальной задержкой.
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Param status query string false "Статус доставки" Enums(pending, delivered, dead)
+// @Param page query int false "Номер страницы" default(1)
+// @Param page_size query int false "Размер страницы" default(10) maximum(100)
+// @Success 200 {object} GetWebhookDeliveriesResponse
+// @Failure 400 {object} h.ErrorResponse
+// @Failure 401 {object} h.ErrorResponse
+// @Failure 403 {object} h.ErrorResponse
+// @Failure 404 {object} h.ErrorResponse
+// @Failure 500 {object} h.ErrorResponse
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /webhooks/{id}/deliveries [get]
+func (h *handler) Handle(c echo.Context, in GetWebhookDeliveriesRequest) error {
+	if in.Page == 0 {
+		in.Page = PAGE_NUMBER
+	}
+
+	if in.PageSize <= 0 {
+		in.PageSize = PAGE_SIZE
+	} else if in.PageSize > 100 {
+		in.PageSize = 100
+	}
+
+	var status *entity.WebhookDeliveryStatus
+	if in.Status != "" {
+		status = lo.ToPtr(entity.WebhookDeliveryStatus(in.Status))
+	}
+
+	deliveries, totalCount, err := h.s.GetDeliveries(c.Request().Context(), in.WebhookID, status, in.Page, in.PageSize)
+	if err != nil {
+		if errors.Is(err, service.ErrEndpointNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error()).SetInternal(err)
+	}
+
+	totalPages := int(math.Ceil(float64(totalCount) / float64(in.PageSize)))
+
+	return c.JSON(http.StatusOK, GetWebhookDeliveriesResponse{
+		Deliveries: lo.Map(deliveries, toDelivery),
+		Page:       in.Page,
+		PageSize:   in.PageSize,
+		TotalItems: totalCount,
+		TotalPages: totalPages,
+	})
+}
+
+func toDelivery(d entity.WebhookDelivery, _ int) Delivery {
+	delivery := Delivery{
+		DeliveryID:     d.ID,
+		WebhookID:      d.EndpointID,
+		EventID:        d.EventID,
+		EventType:      d.EventType,
+		Status:         string(d.Status),
+		Attempts:       d.Attempts,
+		LastStatusCode: d.LastStatusCode,
+		LastError:      d.LastError,
+		Payload:        d.Payload,
+		CreatedAt:      d.CreatedAt.Format(time.RFC3339),
+	}
+	if d.Status == entity.WebhookDeliveryStatusPending {
+		delivery.NextAttemptAt = d.NextAttemptAt.Format(time.RFC3339)
+	}
+	if d.DeliveredAt != nil {
+		delivery.DeliveredAt = d.DeliveredAt.Format(time.RFC3339)
+	}
+	return delivery
+}
@@ -0,0 +1,11 @@
+package get_webhooks
+
+import (
+	"context"
+
+	"github.com/4udiwe/subscription-service/internal/entity"
+)
+
+type WebhookService interface {
+	GetEndpoints(ctx context.Context, page int, pageSize int) (endpoints []entity.WebhookEndpoint, total int, err error)
+}
@@ -0,0 +1,96 @@
+package get_webhooks
+
+import (
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/4udiwe/subscription-service/internal/entity"
+	h "github.com/4udiwe/subscription-service/internal/handler"
+	"github.com/4udiwe/subscription-service/internal/handler/decorator"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/samber/lo"
+)
+
+const PAGE_NUMBER = 1
+const PAGE_SIZE = 10
+
+type handler struct {
+	s WebhookService
+}
+
+func New(s WebhookService) h.Handler {
+	return decorator.NewBindAndValidateDecorator(&handler{s: s})
+}
+
+type GetWebhooksRequest struct {
+	Page     int `query:"page"`
+	PageSize int `query:"page_size"`
+}
+
+type GetWebhooksResponse struct {
+	Webhooks   []Webhook `json:"webhooks"`
+	Page       int       `json:"page"`
+	PageSize   int       `json:"page_size"`
+	TotalItems int       `json:"total_items"`
+	TotalPages int       `json:"total_pages"`
+}
+
+type Webhook struct {
+	WebhookID  uuid.UUID `json:"webhook_id"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"event_types"`
+	CreatedAt  string    `json:"created_at"`
+}
+
+// Get webhooks
+// @Summary Получение вебхуков
+// @Description Получение списка зарегистрированных вебхуков, новые первыми. Секреты не возвращаются.
+// @Tags webhooks
+// @Produce json
+// @Param page query int false "Номер страницы" default(1)
+// @Param page_size query int false "Размер страницы" default(10) maximum(100)
+// @Success 200 {object} GetWebhooksResponse
+// @Failure 400 {object} h.ErrorResponse
+// @Failure 401 {object} h.ErrorResponse
+// @Failure 403 {object} h.ErrorResponse
+// @Failure 500 {object} h.ErrorResponse
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /webhooks [get]
+func (h *handler) Handle(c echo.Context, in GetWebhooksRequest) error {
+	if in.Page == 0 {
+		in.Page = PAGE_NUMBER
+	}
+
+	if in.PageSize <= 0 {
+		in.PageSize = PAGE_SIZE
+	} else if in.PageSize > 100 {
+		in.PageSize = 100
+	}
+
+	endpoints, totalCount, err := h.s.GetEndpoints(c.Request().Context(), in.Page, in.PageSize)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error()).SetInternal(err)
+	}
+
+	totalPages := int(math.Ceil(float64(totalCount) / float64(in.PageSize)))
+
+	return c.JSON(http.StatusOK, GetWebhooksResponse{
+		Webhooks:   lo.Map(endpoints, toWebhook),
+		Page:       in.Page,
+		PageSize:   in.PageSize,
+		TotalItems: totalCount,
+		TotalPages: totalPages,
+	})
+}
+
+func toWebhook(e entity.WebhookEndpoint, _ int) Webhook {
+	return Webhook{
+		WebhookID:  e.ID,
+		URL:        e.URL,
+		EventTypes: e.EventTypes,
+		CreatedAt:  e.CreatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,18 @@
+package middleware
+
+import (
+	"github.com/4udiwe/subscription-service/pkg/actor"
+	"github.com/labstack/echo/v4"
+)
+
+// Actor makes actor.Anonymous the actor of the request. Authenticate replaces it with the
+// authenticated caller, the actor is never taken from the request itself, so it cannot be forged.
+func Actor() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			c.SetRequest(req.WithContext(actor.WithActor(req.Context(), actor.Anonymous)))
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/4udiwe/subscription-service/pkg/actor"
+	"github.com/4udiwe/subscription-service/pkg/auth"
+	"github.com/4udiwe/subscription-service/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	HeaderAPIKey = "X-API-Key"
+	bearerPrefix = "Bearer "
+)
+
+// APIKeyAuthenticator tells who holds an API key.
+type APIKeyAuthenticator interface {
+	Authenticate(ctx context.Context, key string) (auth.Identity, error)
+}
+
+// Authenticate rejects requests without a valid API key in the X-API-Key header or a valid bearer
+// token and puts the identity of the caller into the request context, the subject of the identity
+// becomes the actor of the request.
+func Authenticate(verifier *auth.Verifier, keys APIKeyAuthenticator) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			var identity auth.Identity
+			if key := strings.TrimSpace(c.Request().Header.Get(HeaderAPIKey)); key != "" {
+				var err error
+				identity, err = keys.Authenticate(c.Request().Context(), key)
+				if err != nil {
+					logger.FromContext(c.Request().Context()).Debugf("middleware.Authenticate - check api key: %v", err)
+					return echo.NewHTTPError(http.StatusUnauthorized, "invalid api key")
+				}
+			} else {
+				header := c.Request().Header.Get(echo.HeaderAuthorization)
+				if len(header) <= len(bearerPrefix) || !strings.EqualFold(header[:len(bearerPrefix)], bearerPrefix) {
+					return echo.NewHTTPError(http.StatusUnauthorized, "missing bearer token or api key")
+				}
+
+				var err error
+				identity, err = verifier.Verify(strings.TrimSpace(header[len(bearerPrefix):]))
+				if err != nil {
+					logger.FromContext(c.Request().Context()).Debugf("middleware.Authenticate - verify token: %v", err)
+					return echo.NewHTTPError(http.StatusUnauthorized, "invalid token")
+				}
+			}
+
+			req := c.Request()
+			ctx := auth.WithIdentity(req.Context(), identity)
+			ctx = logger.WithFields(ctx, logrus.Fields{"user_id": identity.Subject})
+			c.SetRequest(req.WithContext(actor.WithActor(ctx, identity.Subject)))
+			return next(c)
+		}
+	}
+}
+
+// RequireRole lets through the users granted the role, and every caller when authentication is off.
+// Callers with API keys are let through, RequireScope checks them.
+func RequireRole(role string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			identity, ok := auth.FromContext(c.Request().Context())
+			if ok && !identity.APIKey && !identity.HasRole(role) {
+				return echo.NewHTTPError(http.StatusForbidden, "access denied")
+			}
+			return next(c)
+		}
+	}
+}
+
+// RequireScope lets through the callers with API keys granted the scope, and every caller when
+// authentication is off. Users are let through, RequireRole and ownership checks restrict them.
+func RequireScope(scope string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			identity, ok := auth.FromContext(c.Request().Context())
+			if ok && identity.APIKey && !identity.HasScope(scope) {
+				return echo.NewHTTPError(http.StatusForbidden, "api key lacks scope "+scope)
+			}
+			return next(c)
+		}
+	}
+}
+
+// OwnerFunc returns the user that owns the resource the request is made to.
+type OwnerFunc func(c echo.Context) (uuid.UUID, error)
+
+// RequireOwner lets through admins and the owner of the resource, errors of owner are returned as is.
+func RequireOwner(owner OwnerFunc) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx := c.Request().Context()
+			if auth.HasFullAccess(ctx) {
+				return next(c)
+			}
+
+			userID, err := owner(c)
+			if err != nil {
+				return err
+			}
+			if !auth.CanAccessUser(ctx, userID) {
+				return echo.NewHTTPError(http.StatusForbidden, "access denied")
+			}
+			return next(c)
+		}
+	}
+}
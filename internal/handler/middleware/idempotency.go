@@ -0,0 +1,184 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/4udiwe/subscription-service/internal/entity"
+	"github.com/4udiwe/subscription-service/internal/service/idempotency"
+	"github.com/4udiwe/subscription-service/pkg/actor"
+	"github.com/4udiwe/subscription-service/pkg/logger"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	HeaderIdempotencyKey     = "Idempotency-Key"
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+
+	redactedFieldsKey = "idempotency_redacted_fields"
+)
+
+// IdempotencyStore keeps the responses to the requests made with idempotency keys.
+type IdempotencyStore interface {
+	Begin(ctx context.Context, scope, key, requestHash string) (*entity.IdempotencyKey, error)
+	Complete(ctx context.Context, scope, key string, statusCode int, contentType string, body []byte) error
+	Release(ctx context.Context, scope, key string) error
+}
+
+// Idempotency makes mutating requests with the Idempotency-Key header safe to retry: a repeated
+// request with the same key gets the stored status and body of the first one, a request with the
+// same key and a different method, URI or body gets 422. Keys belong to the actor of the request.
+// Server errors and rejected credentials are not stored, the request can be retried with its key.
+// Fields named with RedactIdempotentResponse are removed from the stored body.
+func Idempotency(store IdempotencyStore) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			key := strings.TrimSpace(c.Request().Header.Get(HeaderIdempotencyKey))
+			if key == "" || !isMutating(c.Request().Method) {
+				return next(c)
+			}
+			if len(key) > maxIdempotencyKeyLength {
+				return echo.NewHTTPError(http.StatusBadRequest, "idempotency key is longer than "+strconv.Itoa(maxIdempotencyKeyLength)+" characters")
+			}
+
+			req := c.Request()
+			body, err := io.ReadAll(req.Body)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, "cannot read request body")
+			}
+			req.Body = io.NopCloser(bytes.NewReader(body))
+
+			ctx := req.Context()
+			scope := actor.FromContext(ctx)
+
+			stored, err := store.Begin(ctx, scope, key, requestHash(req, body))
+			if err != nil {
+				switch {
+				case errors.Is(err, idempotency.ErrRequestMismatch):
+					return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error()).SetInternal(err)
+				case errors.Is(err, idempotency.ErrRequestInProgress):
+					return echo.NewHTTPError(http.StatusConflict, err.Error()).SetInternal(err)
+				default:
+					return echo.NewHTTPError(http.StatusInternalServerError, err.Error()).SetInternal(err)
+				}
+			}
+			if stored != nil {
+				return replay(c, *stored)
+			}
+
+			completed := false
+			defer func() {
+				if !completed {
+					_ = store.Release(context.WithoutCancel(ctx), scope, key)
+				}
+			}()
+
+			rec := &responseRecorder{ResponseWriter: c.Response().Writer}
+			c.Response().Writer = rec
+
+			// the error is written here, so the response can be stored
+			if err := next(c); err != nil {
+				c.Error(err)
+			}
+
+			status := c.Response().Status
+			if status >= http.StatusInternalServerError || status == http.StatusUnauthorized || status == http.StatusForbidden {
+				return nil
+			}
+
+			contentType := c.Response().Header().Get(echo.HeaderContentType)
+			response := rec.body.Bytes()
+			if fields, ok := c.Get(redactedFieldsKey).([]string); ok {
+				response = redact(response, fields)
+			}
+			if err := store.Complete(context.WithoutCancel(ctx), scope, key, status, contentType, response); err != nil {
+				logger.FromContext(ctx).Errorf("middleware.Idempotency - store response: %v", err)
+				return nil
+			}
+			completed = true
+			return nil
+		}
+	}
+}
+
+// RedactIdempotentResponse keeps the secret fields of the JSON response, such as a generated key,
+// out of the idempotency store. A replayed response has no such fields. It is a route middleware,
+// Idempotency reads the fields after the handler returns.
+func RedactIdempotentResponse(fields ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set(redactedFieldsKey, fields)
+			return next(c)
+		}
+	}
+}
+
+// redact removes fields from the JSON object in body. A body that is not a JSON object is not
+// stored at all, it could hold the secret in another shape.
+func redact(body []byte, fields []string) []byte {
+	if len(body) == 0 {
+		return body
+	}
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(body, &object); err != nil {
+		return nil
+	}
+	for _, field := range fields {
+		delete(object, field)
+	}
+	redacted, err := json.Marshal(object)
+	if err != nil {
+		return nil
+	}
+	return redacted
+}
+
+func isMutating(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+func requestHash(req *http.Request, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(req.Method + " " + req.URL.RequestURI() + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func replay(c echo.Context, stored entity.IdempotencyKey) error {
+	c.Response().Header().Set(HeaderIdempotentReplayed, "true")
+	if len(stored.ResponseBody) == 0 {
+		return c.NoContent(*stored.StatusCode)
+	}
+
+	contentType := echo.MIMEApplicationJSON
+	if stored.ContentType != nil && *stored.ContentType != "" {
+		contentType = *stored.ContentType
+	}
+	return c.Blob(*stored.StatusCode, contentType, stored.ResponseBody)
+}
+
+// responseRecorder copies the response body while it is written to the client.
+type responseRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/4udiwe/subscription-service/internal/metrics"
+	"github.com/labstack/echo/v4"
+)
+
+const unmatchedRoute = "unmatched"
+
+// Metrics counts the requests and observes their latency by route, method and status.
+// The route is the path template, so requests to /offers/1 and /offers/2 share a series.
+func Metrics() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+
+			// the error is written here, so its status is known
+			if err := next(c); err != nil {
+				c.Error(err)
+			}
+
+			route := c.Path()
+			if route == "" {
+				route = unmatchedRoute
+			}
+			status := strconv.Itoa(c.Response().Status)
+			method := c.Request().Method
+
+			metrics.HTTPRequests.WithLabelValues(route, method, status).Inc()
+			metrics.HTTPRequestDuration.WithLabelValues(route, method, status).Observe(time.Since(start).Seconds())
+			return nil
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"github.com/4udiwe/subscription-service/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+const maxRequestIDLength = 128
+
+// RequestID takes the ID of the request from the X-Request-ID header, or generates one, and sends it
+// back in the response. The request context gets a logger whose lines carry the ID and the route.
+func RequestID() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			id := req.Header.Get(echo.HeaderXRequestID)
+			if !validRequestID(id) {
+				id = uuid.NewString()
+			}
+			c.Response().Header().Set(echo.HeaderXRequestID, id)
+
+			ctx := logger.WithFields(req.Context(), logrus.Fields{
+				"request_id": id,
+				"route":      req.Method + " " + c.Path(),
+			})
+			c.SetRequest(req.WithContext(ctx))
+			return next(c)
+		}
+	}
+}
+
+// validRequestID accepts IDs of printable ASCII characters only, so a client cannot break log lines.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,20 @@
+package patch_offer
+
+import (
+	"context"
+
+	"github.com/4udiwe/subscription-service/internal/entity"
+	"github.com/google/uuid"
+)
+
+type OfferService interface {
+	UpdateOffer(
+		ctx context.Context,
+		offerID uuid.UUID,
+		name *string,
+		price *int,
+		currency *string,
+		durationMonths *int,
+		trialDays *int,
+	) (entity.Offer, error)
+}
@@ -0,0 +1,91 @@
+package patch_offer
+
+import (
+	"errors"
+	"net/http"
+
+	h "github.com/4udiwe/subscription-service/internal/handler"
+	"github.com/4udiwe/subscription-service/internal/handler/decorator"
+	service "github.com/4udiwe/subscription-service/internal/service/offer"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type handler struct {
+	s OfferService
+}
+
+func New(s OfferService) h.Handler {
+	return decorator.NewBindAndValidateDecorator(&handler{s: s})
+}
+
+type PatchOfferRequest struct {
+	OfferID        uuid.UUID `param:"id" json:"-" validate:"required,uuid"`
+	ServiceName    *string   `json:"service_name" validate:"omitempty,min=1"`
+	Price          *int      `json:"price" validate:"omitempty,min=0"`
+	Currency       *string   `json:"currency" validate:"omitempty,iso4217"`
+	DurationMonths *int      `json:"duration_months" validate:"omitempty,min=1"`
+	TrialDays      *int      `json:"trial_days" validate:"omitempty,min=0"`
+}
+
+type PatchOfferResponse struct {
+	OfferID        uuid.UUID `json:"offer_id"`
+	ServiceName    string    `json:"service_name"`
+	Price          int       `json:"price"`
+	Currency       string    `json:"currency"`
+	DurationMonths int       `json:"duration_months"`
+	TrialDays      int       `json:"trial_days"`
+	CreatedAt      string    `json:"created_at"`
+	UpdatedAt      string    `json:"updated_at"`
+}
+
+// Update offer
+// @Summary Изменение предложения
+// @Description Изменение имени, цены, валюты, длительности и/или пробного периода предложения. Переданы могут быть только изменяемые поля. Уже оформленные подписки сохраняют свои даты окончания и пробные периоды. Переименование, после которого подписки пользователя пересеклись бы или у него оказалось бы два пробных периода одного сервиса, возвращает 409 (offer_rename_overlaps_subscriptions, offer_rename_duplicates_trial).
+// @Tags offers
+// @Accept json
+// @Produce json
+// @Param id path string true "Offer ID"
+// @Param offer body PatchOfferRequest true "fields to update"
+// @Param Idempotency-Key header string false "Ключ идемпотентности: повтор запроса с тем же ключом возвращает сохраненный ответ"
+// @Success 200 {object} PatchOfferResponse
+// @Failure 400 {object} h.ErrorResponse
+// @Failure 401 {object} h.ErrorResponse
+// @Failure 403 {object} h.ErrorResponse
+// @Failure 404 {object} h.ErrorResponse
+// @Failure 409 {object} h.ErrorResponse
+// @Failure 422 {object} h.ErrorResponse
+// @Failure 500 {object} h.ErrorResponse
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /offers/{id} [patch]
+func (h *handler) Handle(c echo.Context, in PatchOfferRequest) error {
+	if in.ServiceName == nil && in.Price == nil && in.Currency == nil && in.DurationMonths == nil && in.TrialDays == nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "at least one of service_name, price, currency, duration_months, trial_days is required")
+	}
+
+	offer, err := h.s.UpdateOffer(c.Request().Context(), in.OfferID, in.ServiceName, in.Price, in.Currency, in.DurationMonths, in.TrialDays)
+	if err != nil {
+		if errors.Is(err, service.ErrOfferNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+		}
+		if errors.Is(err, service.ErrOfferAlreadyExists) {
+			return echo.NewHTTPError(http.StatusConflict, err.Error()).SetInternal(err)
+		}
+		if errors.Is(err, service.ErrOfferRenameOverlapsSubscriptions) || errors.Is(err, service.ErrOfferRenameDuplicatesTrial) {
+			return echo.NewHTTPError(http.StatusConflict, err.Error()).SetInternal(err)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error()).SetInternal(err)
+	}
+
+	return c.JSON(http.StatusOK, PatchOfferResponse{
+		OfferID:        offer.ID,
+		ServiceName:    offer.Name,
+		Price:          offer.Price,
+		Currency:       offer.Currency,
+		DurationMonths: offer.DurationMonths,
+		TrialDays:      offer.TrialDays,
+		CreatedAt:      offer.CreatedAt.Format("2006-01-02"),
+		UpdatedAt:      offer.UpdatedAt.Format("2006-01-02"),
+	})
+}
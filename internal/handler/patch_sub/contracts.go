@@ -0,0 +1,20 @@
+package patch_sub
+
+import (
+	"context"
+	"time"
+
+	"github.com/4udiwe/subscription-service/internal/entity"
+	"github.com/google/uuid"
+)
+
+type SubscriptionService interface {
+	UpdateSubscription(
+		ctx context.Context,
+		subID uuid.UUID,
+		startDate *time.Time,
+		endDate *time.Time,
+		offerID *uuid.UUID,
+		autoRenew *bool,
+	) (entity.SubscriptionFullInfo, error)
+}
@@ -0,0 +1,123 @@
+package patch_sub
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	h "github.com/4udiwe/subscription-service/internal/handler"
+	"github.com/4udiwe/subscription-service/internal/handler/decorator"
+	"github.com/4udiwe/subscription-service/internal/service/subscription"
+	"github.com/4udiwe/subscription-service/pkg/auth"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type handler struct {
+	s SubscriptionService
+}
+
+func New(s SubscriptionService) h.Handler {
+	return decorator.NewBindAndValidateDecorator(&handler{s: s})
+}
+
+type PatchSubscriptionRequest struct {
+	SubscriptionID uuid.UUID  `param:"id" json:"-" validate:"required,uuid"`
+	OfferID        *uuid.UUID `json:"offer_id" validate:"omitempty,uuid"`
+	StartDate      *string    `json:"start_date" validate:"omitempty,datetime=2006-01-02"`
+	EndDate        *string    `json:"end_date" validate:"omitempty,datetime=2006-01-02"`
+	AutoRenew      *bool      `json:"auto_renew"`
+}
+
+type PatchSubscriptionResponse struct {
+	SubscriptionID uuid.UUID `json:"subscription_id"`
+	UserID         uuid.UUID `json:"user_id"`
+	OfferID        uuid.UUID `json:"offer_id"`
+	OfferName      string    `json:"offer_name"`
+	Price          int       `json:"price"`
+	Currency       string    `json:"currency"`
+	StartDate      string    `json:"start_date"`
+	EndDate        string    `json:"end_date"`
+	AutoRenew      bool      `json:"auto_renew"`
+	Status         string    `json:"status"`
+	CreatedAt      string    `json:"created_at"`
+	UpdatedAt      string    `json:"updated_at"`
+}
+
+// Update subscription
+// @Summary Изменение подписки
+// @Description Изменение даты начала, даты окончания, предложения и/или флага автопродления подписки. Если дата окончания не передана, а дата начала или предложение изменились, она пересчитывается по длительности предложения. Пересечение с другими подписками пользователя на тот же сервис проверяется повторно. Владелец подписки может менять только auto_renew, остальные поля - только администратор или API-ключ.
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Param subscription body PatchSubscriptionRequest true "fields to update"
+// @Param Idempotency-Key header string false "Ключ идемпотентности: повтор запроса с тем же ключом возвращает сохраненный ответ"
+// @Success 200 {object} PatchSubscriptionResponse
+// @Failure 400 {object} h.ErrorResponse
+// @Failure 401 {object} h.ErrorResponse
+// @Failure 403 {object} h.ErrorResponse
+// @Failure 404 {object} h.ErrorResponse
+// @Failure 422 {object} h.ErrorResponse
+// @Failure 500 {object} h.ErrorResponse
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /subscriptions/{id} [patch]
+func (h *handler) Handle(c echo.Context, in PatchSubscriptionRequest) error {
+	if in.OfferID == nil && in.StartDate == nil && in.EndDate == nil && in.AutoRenew == nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "at least one of offer_id, start_date, end_date, auto_renew is required")
+	}
+	// the price stays the same, so only admins and backends may move the dates or the offer
+	if !auth.HasFullAccess(c.Request().Context()) && (in.OfferID != nil || in.StartDate != nil || in.EndDate != nil) {
+		return echo.NewHTTPError(http.StatusForbidden, "only auto_renew can be changed by the owner")
+	}
+
+	var startDate, endDate *time.Time
+	if in.StartDate != nil {
+		parsedStartDate, err := time.Parse("2006-01-02", *in.StartDate)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid start_date format")
+		}
+		startDate = &parsedStartDate
+	}
+	if in.EndDate != nil {
+		parsedEndDate, err := time.Parse("2006-01-02", *in.EndDate)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid end_date format")
+		}
+		endDate = &parsedEndDate
+	}
+
+	sub, err := h.s.UpdateSubscription(c.Request().Context(), in.SubscriptionID, startDate, endDate, in.OfferID, in.AutoRenew)
+
+	if err != nil {
+		if errors.Is(err, subscription.ErrSubscriptionNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+		}
+		if errors.Is(err, subscription.ErrOfferNotFound) {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		}
+		if errors.Is(err, subscription.ErrInvalidSubscriptionDates) {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		}
+		if errors.Is(err, subscription.ErrUserAlreadyHasActiveSubscription) {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error()).SetInternal(err)
+	}
+
+	return c.JSON(http.StatusOK, PatchSubscriptionResponse{
+		SubscriptionID: sub.ID,
+		UserID:         sub.UserID,
+		OfferID:        sub.OfferID,
+		OfferName:      sub.OfferName,
+		Price:          sub.Price,
+		Currency:       sub.Currency,
+		StartDate:      sub.StartDate.Format("2006-01-02"),
+		EndDate:        sub.EndDate.Format("2006-01-02"),
+		AutoRenew:      sub.AutoRenew,
+		Status:         string(sub.Status),
+		CreatedAt:      sub.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:      sub.UpdatedAt.Format(time.RFC3339),
+	})
+}
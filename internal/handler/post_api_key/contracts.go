@@ -0,0 +1,11 @@
+package post_api_key
+
+import (
+	"context"
+
+	"github.com/4udiwe/subscription-service/internal/entity"
+)
+
+type APIKeyService interface {
+	CreateAPIKey(ctx context.Context, name string, scopes []string) (entity.APIKey, string, error)
+}
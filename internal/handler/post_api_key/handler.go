@@ -0,0 +1,75 @@
+package post_api_key
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	h "github.com/4udiwe/subscription-service/internal/handler"
+	"github.com/4udiwe/subscription-service/internal/handler/decorator"
+	service "github.com/4udiwe/subscription-service/internal/service/api_key"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type handler struct {
+	s APIKeyService
+}
+
+func New(s APIKeyService) h.Handler {
+	return decorator.NewBindAndValidateDecorator(&handler{s: s})
+}
+
+type PostAPIKeyRequest struct {
+	Name   string   `json:"name" validate:"required,max=100"`
+	Scopes []string `json:"scopes" validate:"required,min=1,dive,required"`
+}
+
+type PostAPIKeyResponse struct {
+	APIKeyID  uuid.UUID `json:"api_key_id"`
+	Name      string    `json:"name"`
+	Key       string    `json:"key"`
+	Prefix    string    `json:"prefix"`
+	Scopes    []string  `json:"scopes"`
+	CreatedAt string    `json:"created_at"`
+}
+
+// Create an API key
+// @Summary Создание API-ключа
+// @Description Выпуск ключа для сервиса, который вызывает API (биллинг, аналитика). Ключ передается в заголовке X-API-Key. scopes - права ключа: subscriptions:read, subscriptions:write, offers:read, offers:write, admin (admin включает все остальные и админские ручки). Ключ возвращается только в этом ответе, сервис хранит его хэш. Повтор запроса с тем же Idempotency-Key возвращает ответ без key. name - автор изменений, сделанных ключом, в журнале аудита (api_key:<name>), он уникален среди действующих ключей.
+// @Tags api_keys
+// @Accept json
+// @Produce json
+// @Param api_key body PostAPIKeyRequest true "api key"
+// @Param Idempotency-Key header string false "Ключ идемпотентности: повтор запроса с тем же ключом возвращает сохраненный ответ"
+// @Success 201 {object} PostAPIKeyResponse
+// @Failure 400 {object} h.ErrorResponse
+// @Failure 401 {object} h.ErrorResponse
+// @Failure 403 {object} h.ErrorResponse
+// @Failure 409 {object} h.ErrorResponse
+// @Failure 422 {object} h.ErrorResponse
+// @Failure 500 {object} h.ErrorResponse
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /api_keys [post]
+func (h *handler) Handle(c echo.Context, in PostAPIKeyRequest) error {
+	apiKey, key, err := h.s.CreateAPIKey(c.Request().Context(), in.Name, in.Scopes)
+	if err != nil {
+		if errors.Is(err, service.ErrUnknownScope) {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		}
+		if errors.Is(err, service.ErrAPIKeyAlreadyExists) {
+			return echo.NewHTTPError(http.StatusConflict, err.Error()).SetInternal(err)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error()).SetInternal(err)
+	}
+
+	return c.JSON(http.StatusCreated, PostAPIKeyResponse{
+		APIKeyID:  apiKey.ID,
+		Name:      apiKey.Name,
+		Key:       key,
+		Prefix:    apiKey.Prefix,
+		Scopes:    apiKey.Scopes,
+		CreatedAt: apiKey.CreatedAt.Format(time.RFC3339),
+	})
+}
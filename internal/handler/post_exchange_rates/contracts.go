@@ -0,0 +1,11 @@
+package post_exchange_rates
+
+import (
+	"context"
+
+	"github.com/4udiwe/subscription-service/internal/entity"
+)
+
+type ExchangeRateService interface {
+	LoadRates(ctx context.Context, rates []entity.ExchangeRate) (int, error)
+}
@@ -0,0 +1,79 @@
+package post_exchange_rates
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/4udiwe/subscription-service/internal/entity"
+	h "github.com/4udiwe/subscription-service/internal/handler"
+	"github.com/4udiwe/subscription-service/internal/handler/decorator"
+	service "github.com/4udiwe/subscription-service/internal/service/exchange_rate"
+	"github.com/labstack/echo/v4"
+)
+
+type handler struct {
+	s ExchangeRateService
+}
+
+func New(s ExchangeRateService) h.Handler {
+	return decorator.NewBindAndValidateDecorator(&handler{s: s})
+}
+
+type PostExchangeRatesRequest struct {
+	Rates []ExchangeRate `json:"rates" validate:"required,min=1,max=1000,dive"`
+}
+
+type ExchangeRate struct {
+	From string  `json:"from" validate:"required,iso4217"`
+	To   string  `json:"to" validate:"required,iso4217,nefield=From"`
+	Date string  `json:"date" validate:"required,datetime=2006-01-02"`
+	Rate float64 `json:"rate" validate:"required,gt=0"`
+}
+
+type PostExchangeRatesResponse struct {
+	Loaded int `json:"loaded"`
+}
+
+// Load exchange rates
+// @Summary Загрузка курсов валют
+// @Description Загрузка курсов на даты. rate - количество валюты to за единицу валюты from. Курс, уже сохраненный для той же пары и даты, заменяется. Пакет загружается целиком или не загружается вовсе.
+// @Tags exchange_rates
+// @Accept json
+// @Produce json
+// @Param rates body PostExchangeRatesRequest true "exchange rates"
+// @Param Idempotency-Key header string false "Ключ идемпотентности: повтор запроса с тем же ключом возвращает сохраненный ответ"
+// @Success 200 {object} PostExchangeRatesResponse
+// @Failure 400 {object} h.ErrorResponse
+// @Failure 401 {object} h.ErrorResponse
+// @Failure 403 {object} h.ErrorResponse
+// @Failure 422 {object} h.ErrorResponse
+// @Failure 500 {object} h.ErrorResponse
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /exchange_rates [post]
+func (h *handler) Handle(c echo.Context, in PostExchangeRatesRequest) error {
+	rates := make([]entity.ExchangeRate, 0, len(in.Rates))
+	for _, rate := range in.Rates {
+		date, err := time.Parse("2006-01-02", rate.Date)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid date format")
+		}
+		rates = append(rates, entity.ExchangeRate{
+			From: rate.From,
+			To:   rate.To,
+			Date: date,
+			Rate: rate.Rate,
+		})
+	}
+
+	loaded, err := h.s.LoadRates(c.Request().Context(), rates)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidExchangeRate) {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error()).SetInternal(err)
+	}
+
+	return c.JSON(http.StatusOK, PostExchangeRatesResponse{Loaded: loaded})
+}
@@ -7,5 +7,5 @@ import (
 )
 
 type OfferService interface {
-	CreateOffer(ctx context.Context, name string, price int, durationMonths int) (entity.Offer, error)
+	CreateOffer(ctx context.Context, name string, price int, currency string, durationMonths int, trialDays int) (entity.Offer, error)
 }
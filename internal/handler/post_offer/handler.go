@@ -4,6 +4,7 @@ import (
 	"errors"
 	"net/http"
 
+	"github.com/4udiwe/subscription-service/internal/entity"
 	h "github.com/4udiwe/subscription-service/internal/handler"
 	"github.com/4udiwe/subscription-service/internal/handler/decorator"
 	service "github.com/4udiwe/subscription-service/internal/service/offer"
@@ -22,42 +23,58 @@ func New(s OfferService) h.Handler {
 type PostOfferRequest struct {
 	ServiceName    string `json:"service_name" validate:"required"`
 	Price          int    `json:"price" validate:"required,min=0"`
+	Currency       string `json:"currency" validate:"omitempty,iso4217"`
 	DurationMonths int    `json:"duration_months" validate:"required,min=1"`
+	TrialDays      int    `json:"trial_days" validate:"omitempty,min=0"`
 }
 
 type PostOfferResponse struct {
 	OfferID        uuid.UUID `json:"offer_id"`
 	ServiceName    string    `json:"service_name"`
 	Price          int       `json:"price"`
+	Currency       string    `json:"currency"`
 	DurationMonths int       `json:"duration_months"`
+	TrialDays      int       `json:"trial_days"`
 	CreatedAt      string    `json:"created_at"`
 }
 
 // Create a new offer
 // @Summary Создание нового предложения
-// @Description Создание нового предложения с указанными параметрами
+// @Description Создание нового предложения с указанными параметрами. Валюта задается кодом ISO 4217, по умолчанию RUB. trial_days задает длительность бесплатного пробного периода в днях, 0 - без пробного периода. Предложение определяется именем и валютой: для смены цены существующего предложения используется PATCH или планирование изменения цены
 // @Tags offers
 // @Accept json
 // @Produce json
 // @Param offer body PostOfferRequest true "Offer details"
+// @Param Idempotency-Key header string false "Ключ идемпотентности: повтор запроса с тем же ключом возвращает сохраненный ответ"
 // @Success 201 {object} PostOfferResponse
-// @Failure 409 {string} ErrorResponse
-// @Failure 500 {string} ErrorResponse
+// @Failure 401 {object} h.ErrorResponse
+// @Failure 403 {object} h.ErrorResponse
+// @Failure 409 {object} h.ErrorResponse
+// @Failure 422 {object} h.ErrorResponse
+// @Failure 500 {object} h.ErrorResponse
+// @Security BearerAuth
+// @Security APIKeyAuth
 // @Router /offers [post]
 func (h *handler) Handle(c echo.Context, in PostOfferRequest) error {
-	offer, err := h.s.CreateOffer(c.Request().Context(), in.ServiceName, in.Price, in.DurationMonths)
+	if in.Currency == "" {
+		in.Currency = entity.DefaultCurrency
+	}
+
+	offer, err := h.s.CreateOffer(c.Request().Context(), in.ServiceName, in.Price, in.Currency, in.DurationMonths, in.TrialDays)
 	if err != nil {
-		if errors.Is(err, service.ErrOfferWithNameAndPriceAlreadyExists) {
-			return echo.NewHTTPError(http.StatusConflict, err.Error())
+		if errors.Is(err, service.ErrOfferAlreadyExists) {
+			return echo.NewHTTPError(http.StatusConflict, err.Error()).SetInternal(err)
 		}
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error()).SetInternal(err)
 	}
 
 	return c.JSON(http.StatusCreated, PostOfferResponse{
 		OfferID:        offer.ID,
 		ServiceName:    offer.Name,
 		Price:          offer.Price,
+		Currency:       offer.Currency,
 		DurationMonths: offer.DurationMonths,
+		TrialDays:      offer.TrialDays,
 		CreatedAt:      offer.CreatedAt.Format("2006-01-02"),
 	})
 }
@@ -0,0 +1,19 @@
+package post_offer_price
+
+import (
+	"context"
+	"time"
+
+	"github.com/4udiwe/subscription-service/internal/entity"
+	"github.com/google/uuid"
+)
+
+type OfferService interface {
+	SchedulePriceChange(
+		ctx context.Context,
+		offerID uuid.UUID,
+		price int,
+		currency *string,
+		effectiveFrom time.Time,
+	) (entity.OfferPrice, error)
+}
@@ -0,0 +1,89 @@
+package post_offer_price
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	h "github.com/4udiwe/subscription-service/internal/handler"
+	"github.com/4udiwe/subscription-service/internal/handler/decorator"
+	service "github.com/4udiwe/subscription-service/internal/service/offer"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type handler struct {
+	s OfferService
+}
+
+func New(s OfferService) h.Handler {
+	return decorator.NewBindAndValidateDecorator(&handler{s: s})
+}
+
+type PostOfferPriceRequest struct {
+	OfferID       uuid.UUID `param:"id" json:"-" validate:"required,uuid"`
+	Price         *int      `json:"price" validate:"required,min=0"`
+	Currency      *string   `json:"currency" validate:"omitempty,iso4217"`
+	EffectiveFrom string    `json:"effective_from" validate:"required,datetime=2006-01-02"`
+}
+
+type PostOfferPriceResponse struct {
+	PriceID       uuid.UUID `json:"price_id"`
+	OfferID       uuid.UUID `json:"offer_id"`
+	Price         int       `json:"price"`
+	Currency      string    `json:"currency"`
+	EffectiveFrom string    `json:"effective_from"`
+	Status        string    `json:"status"`
+	CreatedAt     string    `json:"created_at"`
+}
+
+// Schedule offer price change
+// @Summary Планирование изменения цены предложения
+// @Description Планирование новой цены предложения с будущей даты effective_from. Без currency сохраняется валюта предложения. Уже оформленные подписки сохраняют свою цену, новая цена применяется с первого продления, которое начинается не раньше effective_from. Всем активным подписчикам предложения отправляется событие subscription.price_change_upcoming. В дату effective_from фоновый воркер применяет цену к предложению. На одну дату можно запланировать одно изменение.
+// @Tags offers
+// @Accept json
+// @Produce json
+// @Param id path string true "Offer ID"
+// @Param price body PostOfferPriceRequest true "price change"
+// @Param Idempotency-Key header string false "Ключ идемпотентности: повтор запроса с тем же ключом возвращает сохраненный ответ"
+// @Success 201 {object} PostOfferPriceResponse
+// @Failure 400 {object} h.ErrorResponse
+// @Failure 401 {object} h.ErrorResponse
+// @Failure 403 {object} h.ErrorResponse
+// @Failure 404 {object} h.ErrorResponse
+// @Failure 409 {object} h.ErrorResponse
+// @Failure 422 {object} h.ErrorResponse
+// @Failure 500 {object} h.ErrorResponse
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /offers/{id}/prices [post]
+func (h *handler) Handle(c echo.Context, in PostOfferPriceRequest) error {
+	effectiveFrom, err := time.Parse("2006-01-02", in.EffectiveFrom)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid effective_from format")
+	}
+
+	price, err := h.s.SchedulePriceChange(c.Request().Context(), in.OfferID, *in.Price, in.Currency, effectiveFrom)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidEffectiveDate) {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		}
+		if errors.Is(err, service.ErrOfferNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+		}
+		if errors.Is(err, service.ErrPriceChangeAlreadyScheduled) {
+			return echo.NewHTTPError(http.StatusConflict, err.Error()).SetInternal(err)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error()).SetInternal(err)
+	}
+
+	return c.JSON(http.StatusCreated, PostOfferPriceResponse{
+		PriceID:       price.ID,
+		OfferID:       price.OfferID,
+		Price:         price.Price,
+		Currency:      price.Currency,
+		EffectiveFrom: price.EffectiveFrom.Format("2006-01-02"),
+		Status:        "pending",
+		CreatedAt:     price.CreatedAt.Format(time.RFC3339),
+	})
+}
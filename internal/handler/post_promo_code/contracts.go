@@ -0,0 +1,11 @@
+package post_promo_code
+
+import (
+	"context"
+
+	"github.com/4udiwe/subscription-service/internal/entity"
+)
+
+type PromoCodeService interface {
+	CreatePromoCode(ctx context.Context, code entity.PromoCode) (entity.PromoCode, error)
+}
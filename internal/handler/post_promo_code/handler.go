@@ -0,0 +1,122 @@
+package post_promo_code
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/4udiwe/subscription-service/internal/entity"
+	h "github.com/4udiwe/subscription-service/internal/handler"
+	"github.com/4udiwe/subscription-service/internal/handler/decorator"
+	service "github.com/4udiwe/subscription-service/internal/service/promo_code"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/samber/lo"
+)
+
+type handler struct {
+	s PromoCodeService
+}
+
+func New(s PromoCodeService) h.Handler {
+	return decorator.NewBindAndValidateDecorator(&handler{s: s})
+}
+
+type PostPromoCodeRequest struct {
+	Code           string      `json:"code" validate:"required,min=1,max=64"`
+	DiscountType   string      `json:"discount_type" validate:"required,oneof=percent fixed"`
+	DiscountValue  int         `json:"discount_value" validate:"required,min=1"`
+	Currency       *string     `json:"currency" validate:"omitempty,iso4217"`
+	MaxRedemptions *int        `json:"max_redemptions" validate:"omitempty,min=1"`
+	ValidFrom      *string     `json:"valid_from" validate:"omitempty,datetime=2006-01-02"`
+	ValidUntil     *string     `json:"valid_until" validate:"omitempty,datetime=2006-01-02"`
+	OfferIDs       []uuid.UUID `json:"offer_ids" validate:"omitempty,dive,uuid"`
+}
+
+type PostPromoCodeResponse struct {
+	PromoCodeID    uuid.UUID   `json:"promo_code_id"`
+	Code           string      `json:"code"`
+	DiscountType   string      `json:"discount_type"`
+	DiscountValue  int         `json:"discount_value"`
+	Currency       *string     `json:"currency,omitempty"`
+	MaxRedemptions *int        `json:"max_redemptions,omitempty"`
+	Redemptions    int         `json:"redemptions"`
+	ValidFrom      *string     `json:"valid_from,omitempty"`
+	ValidUntil     *string     `json:"valid_until,omitempty"`
+	OfferIDs       []uuid.UUID `json:"offer_ids,omitempty"`
+	CreatedAt      string      `json:"created_at"`
+}
+
+// Create a promo code
+// @Summary Создание промокода
+// @Description Создание промокода со скидкой в процентах (percent, 1-100) или фиксированной суммой (fixed, в валюте currency). Можно ограничить число применений, период действия и список предложений offer_ids; пустой список - промокод действует на все предложения. Код не зависит от регистра.
+// @Tags promo_codes
+// @Accept json
+// @Produce json
+// @Param promo_code body PostPromoCodeRequest true "promo code"
+// @Param Idempotency-Key header string false "Ключ идемпотентности: повтор запроса с тем же ключом возвращает сохраненный ответ"
+// @Success 201 {object} PostPromoCodeResponse
+// @Failure 400 {object} h.ErrorResponse
+// @Failure 401 {object} h.ErrorResponse
+// @Failure 403 {object} h.ErrorResponse
+// @Failure 409 {object} h.ErrorResponse
+// @Failure 422 {object} h.ErrorResponse
+// @Failure 500 {object} h.ErrorResponse
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /promo_codes [post]
+func (h *handler) Handle(c echo.Context, in PostPromoCodeRequest) error {
+	code := entity.PromoCode{
+		Code:           in.Code,
+		DiscountType:   entity.DiscountType(in.DiscountType),
+		DiscountValue:  in.DiscountValue,
+		Currency:       in.Currency,
+		MaxRedemptions: in.MaxRedemptions,
+		OfferIDs:       in.OfferIDs,
+	}
+	if in.ValidFrom != nil {
+		validFrom, err := time.Parse("2006-01-02", *in.ValidFrom)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid valid_from format")
+		}
+		code.ValidFrom = &validFrom
+	}
+	if in.ValidUntil != nil {
+		validUntil, err := time.Parse("2006-01-02", *in.ValidUntil)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid valid_until format")
+		}
+		code.ValidUntil = &validUntil
+	}
+
+	created, err := h.s.CreatePromoCode(c.Request().Context(), code)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidPromoCode) || errors.Is(err, service.ErrOfferNotFound) {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		}
+		if errors.Is(err, service.ErrPromoCodeAlreadyExists) {
+			return echo.NewHTTPError(http.StatusConflict, err.Error()).SetInternal(err)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error()).SetInternal(err)
+	}
+
+	response := PostPromoCodeResponse{
+		PromoCodeID:    created.ID,
+		Code:           created.Code,
+		DiscountType:   string(created.DiscountType),
+		DiscountValue:  created.DiscountValue,
+		Currency:       created.Currency,
+		MaxRedemptions: created.MaxRedemptions,
+		Redemptions:    created.Redemptions,
+		OfferIDs:       created.OfferIDs,
+		CreatedAt:      created.CreatedAt.Format("2006-01-02"),
+	}
+	if created.ValidFrom != nil {
+		response.ValidFrom = lo.ToPtr(created.ValidFrom.Format("2006-01-02"))
+	}
+	if created.ValidUntil != nil {
+		response.ValidUntil = lo.ToPtr(created.ValidUntil.Format("2006-01-02"))
+	}
+
+	return c.JSON(http.StatusCreated, response)
+}
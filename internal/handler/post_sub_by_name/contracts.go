@@ -14,7 +14,12 @@ type SubscriptionService interface {
 		userID uuid.UUID,
 		serviceName string,
 		price int,
+		currency string,
 		startDate time.Time,
 		endDate *time.Time,
+		autoRenew bool,
+		skipTrial bool,
+		promoCode *string,
+		createOffer bool,
 	) (entity.SubscriptionFullInfo, error)
 }
@@ -5,11 +5,14 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/4udiwe/subscription-service/internal/entity"
 	h "github.com/4udiwe/subscription-service/internal/handler"
 	"github.com/4udiwe/subscription-service/internal/handler/decorator"
 	"github.com/4udiwe/subscription-service/internal/service/subscription"
+	"github.com/4udiwe/subscription-service/pkg/auth"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
+	"github.com/samber/lo"
 )
 
 type handler struct {
@@ -24,8 +27,12 @@ type PostSubscriptionByNameRequest struct {
 	UserID      uuid.UUID `json:"user_id" validate:"required,uuid"`
 	ServiceName string    `json:"service_name" validate:"required"`
 	Price       int       `json:"price" validate:"required,min=0"`
+	Currency    string    `json:"currency" validate:"omitempty,iso4217"`
 	StartDate   string    `json:"start_date" validate:"required,datetime=2006-01-02"`
 	EndDate     *string   `json:"end_date" validate:"omitempty,datetime=2006-01-02"`
+	AutoRenew   bool      `json:"auto_renew"`
+	SkipTrial   bool      `json:"skip_trial"`
+	PromoCode   *string   `json:"promo_code" validate:"omitempty,min=1"`
 }
 
 type PostSubscriptionByNameResponse struct {
@@ -33,22 +40,37 @@ type PostSubscriptionByNameResponse struct {
 	UserID         uuid.UUID `json:"user_id"`
 	OfferName      string    `json:"offer_name"`
 	Price          int       `json:"price"`
+	Currency       string    `json:"currency"`
 	StartDate      string    `json:"start_date"`
 	EndDate        string    `json:"end_date"`
+	TrialEndDate   *string   `json:"trial_end_date,omitempty"`
+	AutoRenew      bool      `json:"auto_renew"`
 }
 
 // Create a new subscription
 // @Summary Создание новой подписки
-// @Description Создание новой подписки для пользователя с возможностью создания нового предложения, если оно не существует
+// @Description Создание новой подписки для пользователя с возможностью создания нового предложения, если оно не существует. Создать предложение может только администратор или API-ключ со scope offers:write, остальные получают 404 offer_not_found. Предложение ищется по имени и валюте (по умолчанию RUB), подписка закрепляется за текущей версией его цены; если price не совпадает с текущей ценой предложения, возвращается 409 offer_price_mismatch. Если у предложения есть пробный период, подписка начинается с него; пробный период дается пользователю один раз на сервис, skip_trial позволяет оформить подписку без него. Промокод promo_code применяется к цене предложения только на первый период (продления идут по цене предложения без скидки), в price возвращается цена с учетом скидки
 // @Tags subscriptions
 // @Accept json
 // @Produce json
 // @Param subscription body PostSubscriptionByNameRequest true "subscription info"
+// @Param Idempotency-Key header string false "Ключ идемпотентности: повтор запроса с тем же ключом возвращает сохраненный ответ"
 // @Success 201 {object} PostSubscriptionByNameResponse
-// @Failure 400 {string} ErrorResponse
-// @Failure 500 {string} ErrorResponse
+// @Failure 400 {object} h.ErrorResponse
+// @Failure 401 {object} h.ErrorResponse
+// @Failure 403 {object} h.ErrorResponse
+// @Failure 404 {object} h.ErrorResponse
+// @Failure 409 {object} h.ErrorResponse
+// @Failure 422 {object} h.ErrorResponse
+// @Failure 500 {object} h.ErrorResponse
+// @Security BearerAuth
+// @Security APIKeyAuth
 // @Router /subscriptions [post]
 func (h *handler) Handle(c echo.Context, in PostSubscriptionByNameRequest) error {
+	if !auth.CanAccessUser(c.Request().Context(), in.UserID) {
+		return echo.NewHTTPError(http.StatusForbidden, "access denied")
+	}
+
 	startDate, err := time.Parse("2006-01-02", in.StartDate)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, "invalid start_date format")
@@ -61,13 +83,34 @@ func (h *handler) Handle(c echo.Context, in PostSubscriptionByNameRequest) error
 		}
 		endDate = &parsedEndDate
 	}
-	sub, err := h.s.CreateSubscription(c.Request().Context(), in.UserID, in.ServiceName, in.Price, startDate, endDate)
+	if in.Currency == "" {
+		in.Currency = entity.DefaultCurrency
+	}
+
+	sub, err := h.s.CreateSubscription(c.Request().Context(), in.UserID, in.ServiceName, in.Price, in.Currency, startDate, endDate, in.AutoRenew, in.SkipTrial, in.PromoCode, auth.CanManageOffers(c.Request().Context()))
 
 	if err != nil {
+		if errors.Is(err, subscription.ErrOfferNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+		}
 		if errors.Is(err, subscription.ErrUserAlreadyHasActiveSubscription) {
-			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
 		}
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		if errors.Is(err, subscription.ErrTrialAlreadyUsed) || errors.Is(err, subscription.ErrOfferPriceMismatch) {
+			return echo.NewHTTPError(http.StatusConflict, err.Error()).SetInternal(err)
+		}
+		if errors.Is(err, subscription.ErrPromoCodeNotFound) ||
+			errors.Is(err, subscription.ErrPromoCodeNotValid) ||
+			errors.Is(err, subscription.ErrPromoCodeNotApplicable) ||
+			errors.Is(err, subscription.ErrPromoCodeExhausted) {
+			return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error()).SetInternal(err)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error()).SetInternal(err)
+	}
+
+	var trialEndDate *string
+	if sub.TrialEndDate != nil {
+		trialEndDate = lo.ToPtr(sub.TrialEndDate.Format("2006-01-02"))
 	}
 
 	return c.JSON(http.StatusCreated, PostSubscriptionByNameResponse{
@@ -75,7 +118,10 @@ func (h *handler) Handle(c echo.Context, in PostSubscriptionByNameRequest) error
 		UserID:         sub.UserID,
 		OfferName:      sub.OfferName,
 		Price:          sub.Price,
+		Currency:       sub.Currency,
 		StartDate:      sub.StartDate.Format("2006-01-02"),
 		EndDate:        sub.EndDate.Format("2006-01-02"),
+		TrialEndDate:   trialEndDate,
+		AutoRenew:      sub.AutoRenew,
 	})
 }
@@ -9,5 +9,12 @@ import (
 )
 
 type SubscriptionService interface {
-	CreateSubscriptionByOfferID(ctx context.Context, userID, offerID uuid.UUID, startDate time.Time) (entity.SubscriptionFullInfo, error)
+	CreateSubscriptionByOfferID(
+		ctx context.Context,
+		userID, offerID uuid.UUID,
+		startDate time.Time,
+		autoRenew bool,
+		skipTrial bool,
+		promoCode *string,
+	) (entity.SubscriptionFullInfo, error)
 }
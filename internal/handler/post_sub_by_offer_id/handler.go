@@ -8,8 +8,10 @@ import (
 	h "github.com/4udiwe/subscription-service/internal/handler"
 	"github.com/4udiwe/subscription-service/internal/handler/decorator"
 	"github.com/4udiwe/subscription-service/internal/service/subscription"
+	"github.com/4udiwe/subscription-service/pkg/auth"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
+	"github.com/samber/lo"
 )
 
 type handler struct {
@@ -24,6 +26,9 @@ type PostSubscriptionByOfferIDRequest struct {
 	UserID    uuid.UUID `json:"user_id" validate:"required,uuid"`
 	OfferID   uuid.UUID `json:"offer_id" validate:"required,uuid"`
 	StartDate string    `json:"start_date" validate:"required,datetime=2006-01-02"`
+	AutoRenew bool      `json:"auto_renew"`
+	SkipTrial bool      `json:"skip_trial"`
+	PromoCode *string   `json:"promo_code" validate:"omitempty,min=1"`
 }
 
 type PostSubscriptionByOfferIDResponse struct {
@@ -31,37 +36,65 @@ type PostSubscriptionByOfferIDResponse struct {
 	UserID         uuid.UUID `json:"user_id"`
 	OfferName      string    `json:"offer_name"`
 	Price          int       `json:"price"`
+	Currency       string    `json:"currency"`
 	StartDate      string    `json:"start_date"`
 	EndDate        string    `json:"end_date"`
+	TrialEndDate   *string   `json:"trial_end_date,omitempty"`
+	AutoRenew      bool      `json:"auto_renew"`
 }
 
 // Create a new subscription by offer ID
 // @Summary Создание новой подписки по ID предложения
-// @Description Создание новой подписки для пользователя по ID предложения, полученного из ендпоинта всех предложений
+// @Description Создание новой подписки для пользователя по ID предложения, полученного из ендпоинта всех предложений. Если у предложения есть пробный период, подписка начинается с него, а оплачиваемый период идет после trial_end_date. Пробный период дается пользователю один раз на сервис, skip_trial позволяет оформить подписку без него. Промокод promo_code применяется к цене предложения только на первый период (продления идут по цене предложения без скидки), в price возвращается цена с учетом скидки
 // @Tags subscriptions
 // @Accept json
 // @Produce json
 // @Param subscription body PostSubscriptionByOfferIDRequest true "subscription info"
+// @Param Idempotency-Key header string false "Ключ идемпотентности: повтор запроса с тем же ключом возвращает сохраненный ответ"
 // @Success 201 {object} PostSubscriptionByOfferIDResponse
-// @Failure 400 {string} ErrorResponse
-// @Failure 500 {string} ErrorResponse
+// @Failure 400 {object} h.ErrorResponse
+// @Failure 401 {object} h.ErrorResponse
+// @Failure 403 {object} h.ErrorResponse
+// @Failure 409 {object} h.ErrorResponse
+// @Failure 422 {object} h.ErrorResponse
+// @Failure 500 {object} h.ErrorResponse
+// @Security BearerAuth
+// @Security APIKeyAuth
 // @Router /subscriptions/by-offer [post]
 func (h *handler) Handle(c echo.Context, in PostSubscriptionByOfferIDRequest) error {
+	if !auth.CanAccessUser(c.Request().Context(), in.UserID) {
+		return echo.NewHTTPError(http.StatusForbidden, "access denied")
+	}
+
 	startDate, err := time.Parse("2006-01-02", in.StartDate)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, "invalid start_date format")
 	}
 
-	sub, err := h.s.CreateSubscriptionByOfferID(c.Request().Context(), in.UserID, in.OfferID, startDate)
+	sub, err := h.s.CreateSubscriptionByOfferID(c.Request().Context(), in.UserID, in.OfferID, startDate, in.AutoRenew, in.SkipTrial, in.PromoCode)
 
 	if err != nil {
 		if errors.Is(err, subscription.ErrOfferNotFound) {
-			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
 		}
 		if errors.Is(err, subscription.ErrUserAlreadyHasActiveSubscription) {
-			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		}
+		if errors.Is(err, subscription.ErrTrialAlreadyUsed) {
+			return echo.NewHTTPError(http.StatusConflict, err.Error()).SetInternal(err)
 		}
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		if errors.Is(err, subscription.ErrPromoCodeNotFound) ||
+			errors.Is(err, subscription.ErrPromoCodeNotValid) ||
+			errors.Is(err, subscription.ErrPromoCodeNotApplicable) ||
+			errors.Is(err, subscription.ErrPromoCodeExhausted) {
+			return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error()).SetInternal(err)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error()).SetInternal(err)
+	}
+
+	var trialEndDate *string
+	if sub.TrialEndDate != nil {
+		trialEndDate = lo.ToPtr(sub.TrialEndDate.Format("2006-01-02"))
 	}
 
 	return c.JSON(http.StatusCreated, PostSubscriptionByOfferIDResponse{
@@ -69,7 +102,10 @@ func (h *handler) Handle(c echo.Context, in PostSubscriptionByOfferIDRequest) er
 		UserID:         sub.UserID,
 		OfferName:      sub.OfferName,
 		Price:          sub.Price,
+		Currency:       sub.Currency,
 		StartDate:      sub.StartDate.Format("2006-01-02"),
 		EndDate:        sub.EndDate.Format("2006-01-02"),
+		TrialEndDate:   trialEndDate,
+		AutoRenew:      sub.AutoRenew,
 	})
 }
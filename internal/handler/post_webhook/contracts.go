@@ -0,0 +1,11 @@
+package post_webhook
+
+import (
+	"context"
+
+	"github.com/4udiwe/subscription-service/internal/entity"
+)
+
+type WebhookService interface {
+	CreateEndpoint(ctx context.Context, url string, secret *string, eventTypes []string) (entity.WebhookEndpoint, error)
+}
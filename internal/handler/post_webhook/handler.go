@@ -0,0 +1,70 @@
+package post_webhook
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	h "github.com/4udiwe/subscription-service/internal/handler"
+	"github.com/4udiwe/subscription-service/internal/handler/decorator"
+	service "github.com/4udiwe/subscription-service/internal/service/webhook"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type handler struct {
+	s WebhookService
+}
+
+func New(s WebhookService) h.Handler {
+	return decorator.NewBindAndValidateDecorator(&handler{s: s})
+}
+
+type PostWebhookRequest struct {
+	URL        string   `json:"url" validate:"required,http_url"`
+	Secret     *string  `json:"secret" validate:"omitempty,min=16,max=256"`
+	EventTypes []string `json:"event_types" validate:"omitempty,dive,required"`
+}
+
+type PostWebhookResponse struct {
+	WebhookID  uuid.UUID `json:"webhook_id"`
+	URL        string    `json:"url"`
+	Secret     string    `json:"secret"`
+	EventTypes []string  `json:"event_types"`
+	CreatedAt  string    `json:"created_at"`
+}
+
+// Register a webhook endpoint
+// @Summary Регистрация вебхука
+// @Description Регистрация URL, на который сервис отправляет события POST-запросом с JSON. event_types - список типов событий (subscription.created, subscription.cancelled, subscription.renewed, subscription.expiring_soon и др.), пустой список - все события. Каждый запрос подписан HMAC-SHA256 по secret: заголовок X-Webhook-Signature содержит sha256=<hex> от "<X-Webhook-Timestamp>.<тело запроса>". Если secret не передан, он генерируется; secret возвращается только в этом ответе. Повтор запроса с тем же Idempotency-Key возвращает ответ без secret.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param webhook body PostWebhookRequest true "webhook endpoint"
+// @Param Idempotency-Key header string false "Ключ идемпотентности: повтор запроса с тем же ключом возвращает сохраненный ответ"
+// @Success 201 {object} PostWebhookResponse
+// @Failure 400 {object} h.ErrorResponse
+// @Failure 401 {object} h.ErrorResponse
+// @Failure 403 {object} h.ErrorResponse
+// @Failure 422 {object} h.ErrorResponse
+// @Failure 500 {object} h.ErrorResponse
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /webhooks [post]
+func (h *handler) Handle(c echo.Context, in PostWebhookRequest) error {
+	endpoint, err := h.s.CreateEndpoint(c.Request().Context(), in.URL, in.Secret, in.EventTypes)
+	if err != nil {
+		if errors.Is(err, service.ErrUnknownEventType) {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error()).SetInternal(err)
+	}
+
+	return c.JSON(http.StatusCreated, PostWebhookResponse{
+		WebhookID:  endpoint.ID,
+		URL:        endpoint.URL,
+		Secret:     endpoint.Secret,
+		EventTypes: endpoint.EventTypes,
+		CreatedAt:  endpoint.CreatedAt.Format(time.RFC3339),
+	})
+}
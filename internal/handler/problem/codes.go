@@ -0,0 +1,102 @@
+package problem
+
+import (
+	"github.com/4udiwe/subscription-service/internal/service/idempotency"
+	"github.com/4udiwe/subscription-service/internal/service/offer"
+	"github.com/4udiwe/subscription-service/internal/service/subscription"
+)
+
+// Codes of problems. They are part of the API: clients match on them, so they never change.
+const (
+	CodeValidationFailed = "validation_failed"
+
+	CodeOfferNotFound                   = "offer_not_found"
+	CodeOfferAlreadyExists              = "offer_already_exists"
+	CodeOfferRenameOverlapsSubscription = "offer_rename_overlaps_subscriptions"
+	CodeOfferRenameDuplicatesTrial      = "offer_rename_duplicates_trial"
+	CodeOfferHasActiveSubscriptions     = "offer_has_active_subscriptions"
+	CodeOfferOfAnotherService           = "offer_of_another_service"
+	CodeInvalidEffectiveDate            = "invalid_effective_date"
+	CodePriceChangeAlreadyScheduled     = "price_change_already_scheduled"
+	CodePriceChangeNotFound             = "price_change_not_found"
+	CodePriceChangeInUse                = "price_change_in_use"
+	CodeOfferPriceMismatch              = "offer_price_mismatch"
+
+	CodeSubscriptionNotFound     = "subscription_not_found"
+	CodeSubscriptionOverlap      = "subscription_overlap"
+	CodeSubscriptionNotActive    = "subscription_not_active"
+	CodeSubscriptionNotPaused    = "subscription_not_paused"
+	CodeSubscriptionInTrial      = "subscription_in_trial"
+	CodeSubscriptionFollowed     = "subscription_followed"
+	CodeInvalidSubscriptionDates = "invalid_subscription_dates"
+	CodeSameOffer                = "same_offer"
+	CodeInvalidSwitchDate        = "invalid_switch_date"
+	CodeInvalidReportPeriod      = "invalid_report_period"
+	CodeReportPeriodTooLong      = "report_period_too_long"
+	CodeExchangeRateNotFound     = "exchange_rate_not_found"
+	CodeTrialAlreadyUsed         = "trial_already_used"
+	CodePromoCodeNotFound        = "promo_code_not_found"
+	CodePromoCodeNotValid        = "promo_code_not_valid"
+	CodePromoCodeNotApplicable   = "promo_code_not_applicable"
+	CodePromoCodeExhausted       = "promo_code_exhausted"
+
+	CodeIdempotencyKeyReused         = "idempotency_key_reused"
+	CodeIdempotencyRequestInProgress = "idempotency_request_in_progress"
+)
+
+// codes maps the errors of the services to the codes of the problems they cause. Errors that are
+// not listed, such as failures to reach the database, get the code of their HTTP status.
+var codes = []struct {
+	err  error
+	code string
+}{
+	{offer.ErrOfferNotFound, CodeOfferNotFound},
+	{offer.ErrOfferAlreadyExists, CodeOfferAlreadyExists},
+	{offer.ErrOfferRenameOverlapsSubscriptions, CodeOfferRenameOverlapsSubscription},
+	{offer.ErrOfferRenameDuplicatesTrial, CodeOfferRenameDuplicatesTrial},
+	{offer.ErrActiveSubscriptionsExist, CodeOfferHasActiveSubscriptions},
+	{offer.ErrInvalidEffectiveDate, CodeInvalidEffectiveDate},
+	{offer.ErrPriceChangeAlreadyScheduled, CodePriceChangeAlreadyScheduled},
+	{offer.ErrPriceChangeNotFound, CodePriceChangeNotFound},
+	{offer.ErrPriceChangeInUse, CodePriceChangeInUse},
+
+	{subscription.ErrOfferNotFound, CodeOfferNotFound},
+	{subscription.ErrOfferPriceMismatch, CodeOfferPriceMismatch},
+	{subscription.ErrOfferOfAnotherService, CodeOfferOfAnotherService},
+	{subscription.ErrSubscriptionNotFound, CodeSubscriptionNotFound},
+	{subscription.ErrUserAlreadyHasActiveSubscription, CodeSubscriptionOverlap},
+	{subscription.ErrSubscriptionNotActive, CodeSubscriptionNotActive},
+	{subscription.ErrSubscriptionNotPaused, CodeSubscriptionNotPaused},
+	{subscription.ErrSubscriptionInTrial, CodeSubscriptionInTrial},
+	{subscription.ErrSubscriptionFollowed, CodeSubscriptionFollowed},
+	{subscription.ErrInvalidSubscriptionDates, CodeInvalidSubscriptionDates},
+	{subscription.ErrSameOffer, CodeSameOffer},
+	{subscription.ErrInvalidSwitchDate, CodeInvalidSwitchDate},
+	{subscription.ErrInvalidReportPeriod, CodeInvalidReportPeriod},
+	{subscription.ErrReportPeriodTooLong, CodeReportPeriodTooLong},
+	{subscription.ErrExchangeRateNotFound, CodeExchangeRateNotFound},
+	{subscription.ErrTrialAlreadyUsed, CodeTrialAlreadyUsed},
+	{subscription.ErrPromoCodeNotFound, CodePromoCodeNotFound},
+	{subscription.ErrPromoCodeNotValid, CodePromoCodeNotValid},
+	{subscription.ErrPromoCodeNotApplicable, CodePromoCodeNotApplicable},
+	{subscription.ErrPromoCodeExhausted, CodePromoCodeExhausted},
+
+	{idempotency.ErrRequestMismatch, CodeIdempotencyKeyReused},
+	{idempotency.ErrRequestInProgress, CodeIdempotencyRequestInProgress},
+}
+
+// statusCodes are the codes of problems caused by errors without a code of their own.
+var statusCodes = map[int]string{
+	400: "invalid_request",
+	401: "unauthorized",
+	403: "forbidden",
+	404: "not_found",
+	405: "method_not_allowed",
+	409: "conflict",
+	413: "request_too_large",
+	415: "unsupported_media_type",
+	422: "unprocessable_entity",
+	429: "too_many_requests",
+	500: "internal_error",
+	503: "service_unavailable",
+}
@@ -0,0 +1,104 @@
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	h "github.com/4udiwe/subscription-service/internal/handler"
+	"github.com/4udiwe/subscription-service/pkg/logger"
+	"github.com/4udiwe/subscription-service/pkg/validator"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	MIMEProblemJSON = "application/problem+json"
+
+	typeBlank = "about:blank"
+)
+
+// HTTPErrorHandler writes the errors returned by handlers and middlewares as application/problem+json.
+// The code is found by the error wrapped in echo.HTTPError, see HTTPError.SetInternal.
+func HTTPErrorHandler(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+
+	p := New(err)
+	p.Instance = c.Request().URL.Path
+
+	log := logger.FromContext(c.Request().Context())
+	if p.Status >= http.StatusInternalServerError {
+		log.Errorf("HTTP %s %s failed: %v", c.Request().Method, c.Request().URL.Path, err)
+	}
+
+	if c.Request().Method == http.MethodHead {
+		err = c.NoContent(p.Status)
+	} else {
+		err = write(c, p)
+	}
+	if err != nil {
+		log.Errorf("problem.HTTPErrorHandler - write response: %v", err)
+	}
+}
+
+// New describes the error as a problem.
+func New(err error) h.ErrorResponse {
+	status := http.StatusInternalServerError
+	detail := http.StatusText(status)
+
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) {
+		status = httpErr.Code
+		detail = message(httpErr.Message)
+	}
+
+	p := h.ErrorResponse{
+		Type:   typeBlank,
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Code:   code(err, status),
+	}
+
+	var validationErr *validator.ValidationError
+	if errors.As(err, &validationErr) {
+		p.Code = CodeValidationFailed
+		p.Errors = validationErr.Fields
+	}
+
+	return p
+}
+
+func code(err error, status int) string {
+	for _, c := range codes {
+		if errors.Is(err, c.err) {
+			return c.code
+		}
+	}
+	if code, ok := statusCodes[status]; ok {
+		return code
+	}
+	return strings.ReplaceAll(strings.ToLower(http.StatusText(status)), " ", "_")
+}
+
+func message(m any) string {
+	switch m := m.(type) {
+	case string:
+		return m
+	case error:
+		return m.Error()
+	default:
+		return fmt.Sprint(m)
+	}
+}
+
+func write(c echo.Context, p h.ErrorResponse) error {
+	body, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	return c.Blob(p.Status, MIMEProblemJSON, body)
+}
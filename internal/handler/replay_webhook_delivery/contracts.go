@@ -0,0 +1,12 @@
+package replay_webhook_delivery
+
+import (
+	"context"
+
+	"github.com/4udiwe/subscription-service/internal/entity"
+	"github.com/google/uuid"
+)
+
+type WebhookService interface {
+	ReplayDelivery(ctx context.Context, id uuid.UUID) (entity.WebhookDelivery, error)
+}
@@ -0,0 +1,77 @@
+package replay_webhook_delivery
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	h "github.com/4udiwe/subscription-service/internal/handler"
+	"github.com/4udiwe/subscription-service/internal/handler/decorator"
+	service "github.com/4udiwe/subscription-service/internal/service/webhook"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type handler struct {
+	s WebhookService
+}
+
+func New(s WebhookService) h.Handler {
+	return decorator.NewBindAndValidateDecorator(&handler{s: s})
+}
+
+type ReplayWebhookDeliveryRequest struct {
+	DeliveryID uuid.UUID `param:"id" validate:"required,uuid"`
+}
+
+type ReplayWebhookDeliveryResponse struct {
+	DeliveryID    uuid.UUID       `json:"delivery_id"`
+	WebhookID     uuid.UUID       `json:"webhook_id"`
+	EventID       int64           `json:"event_id"`
+	EventType     string          `json:"event_type"`
+	Status        string          `json:"status"`
+	Attempts      int             `json:"attempts"`
+	NextAttemptAt string          `json:"next_attempt_at"`
+	Payload       json.RawMessage `json:"payload" swaggertype:"object"`
+	CreatedAt     string          `json:"created_at"`
+}
+
+// Replay webhook delivery
+// @Summary Повторная отправка доставки вебхука
+// @Description Ставит доставку в очередь на повторную отправку с новым счетчиком попыток, в том числе уже доставленную или в статусе dead. Тело запроса не меняется, получатель может отбросить дубль по X-Event-ID.
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Delivery ID"
+// @Param Idempotency-Key header string false "Ключ идемпотентности: повтор запроса с тем же ключом возвращает сохраненный ответ"
+// @Success 200 {object} ReplayWebhookDeliveryResponse
+// @Failure 400 {object} h.ErrorResponse
+// @Failure 401 {object} h.ErrorResponse
+// @Failure 403 {object} h.ErrorResponse
+// @Failure 404 {object} h.ErrorResponse
+// @Failure 422 {object} h.ErrorResponse
+// @Failure 500 {object} h.ErrorResponse
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /webhooks/deliveries/{id}/replay [post]
+func (h *handler) Handle(c echo.Context, in ReplayWebhookDeliveryRequest) error {
+	delivery, err := h.s.ReplayDelivery(c.Request().Context(), in.DeliveryID)
+	if err != nil {
+		if errors.Is(err, service.ErrDeliveryNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error()).SetInternal(err)
+	}
+
+	return c.JSON(http.StatusOK, ReplayWebhookDeliveryResponse{
+		DeliveryID:    delivery.ID,
+		WebhookID:     delivery.EndpointID,
+		EventID:       delivery.EventID,
+		EventType:     delivery.EventType,
+		Status:        string(delivery.Status),
+		Attempts:      delivery.Attempts,
+		NextAttemptAt: delivery.NextAttemptAt.Format(time.RFC3339),
+		Payload:       delivery.Payload,
+		CreatedAt:     delivery.CreatedAt.Format(time.RFC3339),
+	})
+}
@@ -0,0 +1,12 @@
+package resume_sub
+
+import (
+	"context"
+
+	"github.com/4udiwe/subscription-service/internal/entity"
+	"github.com/google/uuid"
+)
+
+type SubscriptionService interface {
+	ResumeSubscription(ctx context.Context, subID uuid.UUID) (entity.SubscriptionFullInfo, error)
+}
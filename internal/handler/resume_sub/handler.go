@@ -0,0 +1,81 @@
+package resume_sub
+
+import (
+	"errors"
+	"net/http"
+
+	h "github.com/4udiwe/subscription-service/internal/handler"
+	"github.com/4udiwe/subscription-service/internal/handler/decorator"
+	"github.com/4udiwe/subscription-service/internal/service/subscription"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type handler struct {
+	s SubscriptionService
+}
+
+func New(s SubscriptionService) h.Handler {
+	return decorator.NewBindAndValidateDecorator(&handler{s: s})
+}
+
+type ResumeSubscriptionRequest struct {
+	SubscriptionID uuid.UUID `param:"id" validate:"required,uuid"`
+}
+
+type ResumeSubscriptionResponse struct {
+	SubscriptionID uuid.UUID `json:"subscription_id"`
+	UserID         uuid.UUID `json:"user_id"`
+	OfferName      string    `json:"offer_name"`
+	Price          int       `json:"price"`
+	Currency       string    `json:"currency"`
+	StartDate      string    `json:"start_date"`
+	EndDate        string    `json:"end_date"`
+	Status         string    `json:"status"`
+}
+
+// Resume subscription
+// @Summary Возобновление подписки
+// @Description Возобновление приостановленной подписки. Дата окончания сдвигается вперед на длительность паузы.
+// @Tags subscriptions
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Param Idempotency-Key header string false "Ключ идемпотентности: повтор запроса с тем же ключом возвращает сохраненный ответ"
+// @Success 200 {object} ResumeSubscriptionResponse
+// @Failure 400 {object} h.ErrorResponse
+// @Failure 401 {object} h.ErrorResponse
+// @Failure 403 {object} h.ErrorResponse
+// @Failure 404 {object} h.ErrorResponse
+// @Failure 409 {object} h.ErrorResponse
+// @Failure 422 {object} h.ErrorResponse
+// @Failure 500 {object} h.ErrorResponse
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /subscriptions/{id}/resume [post]
+func (h *handler) Handle(c echo.Context, in ResumeSubscriptionRequest) error {
+	sub, err := h.s.ResumeSubscription(c.Request().Context(), in.SubscriptionID)
+
+	if err != nil {
+		if errors.Is(err, subscription.ErrSubscriptionNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+		}
+		if errors.Is(err, subscription.ErrSubscriptionNotPaused) {
+			return echo.NewHTTPError(http.StatusConflict, err.Error()).SetInternal(err)
+		}
+		if errors.Is(err, subscription.ErrUserAlreadyHasActiveSubscription) {
+			return echo.NewHTTPError(http.StatusConflict, err.Error()).SetInternal(err)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error()).SetInternal(err)
+	}
+
+	return c.JSON(http.StatusOK, ResumeSubscriptionResponse{
+		SubscriptionID: sub.ID,
+		UserID:         sub.UserID,
+		OfferName:      sub.OfferName,
+		Price:          sub.Price,
+		Currency:       sub.Currency,
+		StartDate:      sub.StartDate.Format("2006-01-02"),
+		EndDate:        sub.EndDate.Format("2006-01-02"),
+		Status:         string(sub.Status),
+	})
+}
@@ -0,0 +1,94 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+// Registry holds the metrics of the service, it is exposed at /metrics.
+var Registry = prometheus.NewRegistry()
+
+var (
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "Number of HTTP requests by route, method and status.",
+	}, []string{"route", "method", "status"})
+
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "Latency of HTTP requests by route, method and status.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"route", "method", "status"})
+
+	QueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "db_query_duration_seconds",
+		Help:    "Latency of repository methods.",
+		Buckets: []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"method"})
+
+	ActiveSubscriptions = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "subscriptions_active",
+		Help: "Number of active subscriptions by service name.",
+	}, []string{"service"})
+
+	Offers = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "offers",
+		Help: "Number of offers.",
+	})
+
+	SubscriptionsCreated = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "subscriptions_created_total",
+		Help: "Number of subscriptions created.",
+	})
+
+	SubscriptionsDeleted = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "subscriptions_deleted_total",
+		Help: "Number of subscriptions deleted, a deleted subscription is cancelled right away.",
+	})
+
+	OffersCreated = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "offers_created_total",
+		Help: "Number of offers created.",
+	})
+
+	OffersDeleted = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "offers_deleted_total",
+		Help: "Number of offers deleted.",
+	})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequests,
+		HTTPRequestDuration,
+		QueryDuration,
+		ActiveSubscriptions,
+		Offers,
+		SubscriptionsCreated,
+		SubscriptionsDeleted,
+		OffersCreated,
+		OffersDeleted,
+	)
+}
+
+// ObserveQuery starts timing the repository method, the returned function records the latency:
+//
+//	defer metrics.ObserveQuery("OfferRepository.Create")()
+func ObserveQuery(method string) func() {
+	start := time.Now()
+	return func() {
+		QueryDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+	}
+}
+
+// SetActiveSubscriptions replaces the numbers of active subscriptions, so services without them are dropped.
+func SetActiveSubscriptions(counts map[string]int) {
+	ActiveSubscriptions.Reset()
+	for service, count := range counts {
+		ActiveSubscriptions.WithLabelValues(service).Set(float64(count))
+	}
+}
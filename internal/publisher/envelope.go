@@ -0,0 +1,31 @@
+package publisher
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/4udiwe/subscription-service/internal/entity"
+	"github.com/google/uuid"
+)
+
+// Envelope is the wire format of an event shared by all publishers. ID grows with every event
+// and can be used by consumers to drop duplicates.
+type Envelope struct {
+	ID            int64           `json:"id"`
+	Type          string          `json:"type"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   uuid.UUID       `json:"aggregate_id"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+	Payload       json.RawMessage `json:"payload"`
+}
+
+func Marshal(event entity.OutboxEvent) ([]byte, error) {
+	return json.Marshal(Envelope{
+		ID:            event.ID,
+		Type:          event.EventType,
+		AggregateType: event.AggregateType,
+		AggregateID:   event.AggregateID,
+		OccurredAt:    event.CreatedAt,
+		Payload:       event.Payload,
+	})
+}
@@ -0,0 +1,30 @@
+package publisher
+
+import (
+	"context"
+
+	"github.com/4udiwe/subscription-service/internal/entity"
+)
+
+type Publisher interface {
+	Publish(ctx context.Context, event entity.OutboxEvent) error
+}
+
+// Fanout passes every event to all publishers in order. The first error stops it and the
+// relay retries the event for all of them, so publishers must tolerate duplicates.
+type Fanout struct {
+	publishers []Publisher
+}
+
+func NewFanout(publishers ...Publisher) *Fanout {
+	return &Fanout{publishers: publishers}
+}
+
+func (f *Fanout) Publish(ctx context.Context, event entity.OutboxEvent) error {
+	for _, p := range f.publishers {
+		if err := p.Publish(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
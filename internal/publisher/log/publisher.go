@@ -0,0 +1,36 @@
+package log_publisher
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/4udiwe/subscription-service/internal/entity"
+	"github.com/4udiwe/subscription-service/internal/publisher"
+)
+
+// Publisher writes every event as a JSON line to w, e.g. stdout or a file.
+type Publisher struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func New(w io.Writer) *Publisher {
+	return &Publisher{w: w}
+}
+
+func (p *Publisher) Publish(_ context.Context, event entity.OutboxEvent) error {
+	data, err := publisher.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("LogPublisher.Publish - failed to marshal event: %w", err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, err := p.w.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("LogPublisher.Publish - failed to write event: %w", err)
+	}
+	return nil
+}
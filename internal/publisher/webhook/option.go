@@ -0,0 +1,19 @@
+package webhook_publisher
+
+import "time"
+
+type Option func(*Publisher)
+
+// Timeout sets the timeout of a single delivery request.
+func Timeout(timeout time.Duration) Option {
+	return func(p *Publisher) {
+		p.sender = NewSender(timeout)
+	}
+}
+
+// Secret makes the publisher sign every request, see Sign.
+func Secret(secret string) Option {
+	return func(p *Publisher) {
+		p.secret = secret
+	}
+}
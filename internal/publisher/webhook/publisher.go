@@ -0,0 +1,41 @@
+package webhook_publisher
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/4udiwe/subscription-service/internal/entity"
+	"github.com/4udiwe/subscription-service/internal/publisher"
+)
+
+// Publisher POSTs every event as JSON to a single URL configured at startup.
+type Publisher struct {
+	url    string
+	secret string
+	sender *Sender
+}
+
+func New(url string, opts ...Option) *Publisher {
+	p := &Publisher{
+		url:    url,
+		sender: NewSender(defaultTimeout),
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+func (p *Publisher) Publish(ctx context.Context, event entity.OutboxEvent) error {
+	data, err := publisher.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("WebhookPublisher.Publish - failed to marshal event: %w", err)
+	}
+
+	if _, err := p.sender.Send(ctx, p.url, p.secret, event.ID, event.EventType, data); err != nil {
+		return fmt.Errorf("WebhookPublisher.Publish - %w", err)
+	}
+	return nil
+}
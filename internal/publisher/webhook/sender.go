@@ -0,0 +1,55 @@
+package webhook_publisher
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const defaultTimeout = 5 * time.Second
+
+// Sender POSTs JSON bodies to webhook endpoints. Any response other than 2xx is a failed delivery.
+type Sender struct {
+	client *http.Client
+}
+
+func NewSender(timeout time.Duration) *Sender {
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	return &Sender{client: &http.Client{Timeout: timeout}}
+}
+
+// Send delivers the body to url and returns the response status, 0 if there was no response.
+// The request is signed when secret is not empty.
+func (s *Sender) Send(ctx context.Context, url, secret string, eventID int64, eventType string, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("WebhookSender.Send - failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEventID, strconv.FormatInt(eventID, 10))
+	req.Header.Set(HeaderEventType, eventType)
+
+	if secret != "" {
+		timestamp := time.Now().Unix()
+		req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+		req.Header.Set(HeaderSignature, Sign(secret, timestamp, body))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("WebhookSender.Send - request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("WebhookSender.Send - unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,8 @@
+package api_key_repo
+
+import "errors"
+
+var (
+	ErrAPIKeyNotFound      = errors.New("api key not found")
+	ErrAPIKeyAlreadyExists = errors.New("api key with this name already exists")
+)
@@ -0,0 +1,185 @@
+package api_key_repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/4udiwe/subscription-service/internal/database"
+	"github.com/4udiwe/subscription-service/internal/entity"
+	"github.com/4udiwe/subscription-service/internal/metrics"
+	"github.com/4udiwe/subscription-service/pkg/logger"
+	"github.com/4udiwe/subscription-service/pkg/postgres"
+	"github.com/4udiwe/subscription-service/pkg/tracing"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+var apiKeyColumns = []string{"id", "name", "prefix", "key_hash", "scopes", "created_at", "last_used_at", "revoked_at"}
+
+type Repository struct {
+	*postgres.Postgres
+}
+
+func New(postgres *postgres.Postgres) *Repository {
+	return &Repository{postgres}
+}
+
+func apiKeyFields(key *entity.APIKey) []any {
+	return []any{&key.ID, &key.Name, &key.Prefix, &key.KeyHash, &key.Scopes, &key.CreatedAt, &key.LastUsedAt, &key.RevokedAt}
+}
+
+func (r *Repository) Create(ctx context.Context, name, prefix, keyHash string, scopes []string) (_ entity.APIKey, err error) {
+	ctx, span := tracing.Start(ctx, "APIKeyRepository.Create")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("APIKeyRepository.Create")()
+	logger.FromContext(ctx).Infof("APIKeyRepository.Create called: name=%s, prefix=%s, scopes=%v", name, prefix, scopes)
+
+	query, args, _ := r.Builder.
+		Insert("api_key").
+		Columns("name", "prefix", "key_hash", "scopes").
+		Values(name, prefix, keyHash, scopes).
+		Suffix("RETURNING id, created_at").
+		ToSql()
+
+	key := entity.APIKey{
+		Name:    name,
+		Prefix:  prefix,
+		KeyHash: keyHash,
+		Scopes:  scopes,
+	}
+
+	err = r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(&key.ID, &key.CreatedAt)
+	if err != nil {
+		if database.IsUniqueViolation(err) {
+			return entity.APIKey{}, ErrAPIKeyAlreadyExists
+		}
+		logger.FromContext(ctx).Error("APIKeyRepository.Create error: ", err)
+		return entity.APIKey{}, fmt.Errorf("APIKeyRepository.Create - failed to create api key: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("APIKeyRepository.Create success: id=%s", key.ID)
+	return key, nil
+}
+
+func (r *Repository) GetAll(ctx context.Context, limit int, offset int) (keys []entity.APIKey, total int, err error) {
+	ctx, span := tracing.Start(ctx, "APIKeyRepository.GetAll")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("APIKeyRepository.GetAll")()
+	logger.FromContext(ctx).Info("APIKeyRepository.GetAll called")
+
+	query, args, _ := r.Builder.
+		Select(apiKeyColumns...).
+		From("api_key").
+		OrderBy("created_at DESC", "id DESC").
+		Limit(uint64(limit)).
+		Offset(uint64(offset)).
+		ToSql()
+
+	rows, err := r.GetTxManager(ctx).Query(ctx, query, args...)
+	if err != nil {
+		logger.FromContext(ctx).Error("APIKeyRepository.GetAll error: ", err)
+		return nil, 0, fmt.Errorf("APIKeyRepository.GetAll - failed to get api keys: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var key entity.APIKey
+		if err := rows.Scan(apiKeyFields(&key)...); err != nil {
+			logger.FromContext(ctx).Error("APIKeyRepository.GetAll scan error: ", err)
+			return nil, 0, fmt.Errorf("APIKeyRepository.GetAll - scan error: %w", err)
+		}
+		keys = append(keys, key)
+	}
+
+	countQuery, countArgs, _ := r.Builder.
+		Select("COUNT(*)").
+		From("api_key").
+		ToSql()
+
+	err = r.GetTxManager(ctx).QueryRow(ctx, countQuery, countArgs...).Scan(&total)
+	if err != nil {
+		logger.FromContext(ctx).Error("APIKeyRepository.GetAll count error: ", err)
+		return nil, 0, fmt.Errorf("APIKeyRepository.GetAll - failed to get total count: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("APIKeyRepository.GetAll success: count=%d", len(keys))
+	return keys, total, nil
+}
+
+// GetByHash returns the key in use with the hash, revoked keys are not found.
+func (r *Repository) GetByHash(ctx context.Context, keyHash string) (_ entity.APIKey, err error) {
+	ctx, span := tracing.Start(ctx, "APIKeyRepository.GetByHash")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("APIKeyRepository.GetByHash")()
+	query, args, _ := r.Builder.
+		Select(apiKeyColumns...).
+		From("api_key").
+		Where("key_hash = ?", keyHash).
+		Where("revoked_at IS NULL").
+		ToSql()
+
+	var key entity.APIKey
+	err = r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(apiKeyFields(&key)...)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.APIKey{}, ErrAPIKeyNotFound
+		}
+		logger.FromContext(ctx).Error("APIKeyRepository.GetByHash error: ", err)
+		return entity.APIKey{}, fmt.Errorf("APIKeyRepository.GetByHash - failed to get api key: %w", err)
+	}
+
+	return key, nil
+}
+
+// Revoke stops the key from authenticating requests, keys already revoked are not found.
+func (r *Repository) Revoke(ctx context.Context, id uuid.UUID) (_ entity.APIKey, err error) {
+	ctx, span := tracing.Start(ctx, "APIKeyRepository.Revoke")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("APIKeyRepository.Revoke")()
+	logger.FromContext(ctx).Infof("APIKeyRepository.Revoke called: id=%s", id)
+
+	query, args, _ := r.Builder.
+		Update("api_key").
+		Set("revoked_at", time.Now()).
+		Where("id = ?", id).
+		Where("revoked_at IS NULL").
+		Suffix("RETURNING " + strings.Join(apiKeyColumns, ", ")).
+		ToSql()
+
+	var key entity.APIKey
+	err = r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(apiKeyFields(&key)...)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.APIKey{}, ErrAPIKeyNotFound
+		}
+		logger.FromContext(ctx).Error("APIKeyRepository.Revoke error: ", err)
+		return entity.APIKey{}, fmt.Errorf("APIKeyRepository.Revoke - failed to revoke api key: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("APIKeyRepository.Revoke success: id=%s", id)
+	return key, nil
+}
+
+// TouchLastUsed sets last_used_at of the key to usedAt unless it was used after since,
+// so a busy key is written at most once in a while.
+func (r *Repository) TouchLastUsed(ctx context.Context, id uuid.UUID, usedAt time.Time, since time.Time) (err error) {
+	ctx, span := tracing.Start(ctx, "APIKeyRepository.TouchLastUsed")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("APIKeyRepository.TouchLastUsed")()
+	query, args, _ := r.Builder.
+		Update("api_key").
+		Set("last_used_at", usedAt).
+		Where("id = ?", id).
+		Where("(last_used_at IS NULL OR last_used_at < ?)", since).
+		ToSql()
+
+	if _, err := r.GetTxManager(ctx).Exec(ctx, query, args...); err != nil {
+		logger.FromContext(ctx).Error("APIKeyRepository.TouchLastUsed error: ", err)
+		return fmt.Errorf("APIKeyRepository.TouchLastUsed - failed to update last used time: %w", err)
+	}
+
+	return nil
+}
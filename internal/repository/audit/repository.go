@@ -0,0 +1,136 @@
+package audit_repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/4udiwe/subscription-service/internal/entity"
+	"github.com/4udiwe/subscription-service/internal/metrics"
+	"github.com/4udiwe/subscription-service/pkg/logger"
+	"github.com/4udiwe/subscription-service/pkg/postgres"
+	"github.com/4udiwe/subscription-service/pkg/tracing"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+)
+
+type Repository struct {
+	*postgres.Postgres
+}
+
+func New(postgres *postgres.Postgres) *Repository {
+	return &Repository{postgres}
+}
+
+// Add stores the entries. It should be called in the transaction of the change the entries describe.
+func (r *Repository) Add(ctx context.Context, entries ...entity.AuditEntry) (err error) {
+	ctx, span := tracing.Start(ctx, "AuditRepository.Add")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("AuditRepository.Add")()
+	if len(entries) == 0 {
+		return nil
+	}
+	logger.FromContext(ctx).Infof("AuditRepository.Add called: count=%d", len(entries))
+
+	builder := r.Builder.
+		Insert("audit_log").
+		Columns("actor", "action", "entity_type", "entity_id", "before", "after")
+
+	for _, entry := range entries {
+		builder = builder.Values(entry.Actor, entry.Action, entry.EntityType, entry.EntityID, nullJSON(entry.Before), nullJSON(entry.After))
+	}
+
+	query, args, _ := builder.ToSql()
+
+	if _, err := r.GetTxManager(ctx).Exec(ctx, query, args...); err != nil {
+		logger.FromContext(ctx).Error("AuditRepository.Add error: ", err)
+		return fmt.Errorf("AuditRepository.Add - failed to store audit entries: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("AuditRepository.Add success: count=%d", len(entries))
+	return nil
+}
+
+// GetAll returns the entries matching the filters, newest first. from is inclusive, to is exclusive.
+func (r *Repository) GetAll(
+	ctx context.Context,
+	entityType *string,
+	entityID *uuid.UUID,
+	actor *string,
+	from *time.Time,
+	to *time.Time,
+	limit int,
+	offset int,
+) (entries []entity.AuditEntry, total int, err error) {
+	ctx, span := tracing.Start(ctx, "AuditRepository.GetAll")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("AuditRepository.GetAll")()
+	logger.FromContext(ctx).Infof("AuditRepository.GetAll called: entityType=%v, entityID=%v, actor=%v, from=%v, to=%v", entityType, entityID, actor, from, to)
+
+	filter := squirrel.And{}
+	if entityType != nil {
+		filter = append(filter, squirrel.Eq{"entity_type": *entityType})
+	}
+	if entityID != nil {
+		filter = append(filter, squirrel.Eq{"entity_id": *entityID})
+	}
+	if actor != nil {
+		filter = append(filter, squirrel.Eq{"actor": *actor})
+	}
+	if from != nil {
+		filter = append(filter, squirrel.GtOrEq{"created_at": *from})
+	}
+	if to != nil {
+		filter = append(filter, squirrel.Lt{"created_at": *to})
+	}
+
+	query, args, _ := r.Builder.
+		Select("id", "actor", "action", "entity_type", "entity_id", "before", "after", "created_at").
+		From("audit_log").
+		Where(filter).
+		OrderBy("created_at DESC", "id DESC").
+		Limit(uint64(limit)).
+		Offset(uint64(offset)).
+		ToSql()
+
+	rows, err := r.GetTxManager(ctx).Query(ctx, query, args...)
+	if err != nil {
+		logger.FromContext(ctx).Error("AuditRepository.GetAll error: ", err)
+		return nil, 0, fmt.Errorf("AuditRepository.GetAll - failed to get audit entries: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry entity.AuditEntry
+		if err := rows.Scan(
+			&entry.ID, &entry.Actor, &entry.Action, &entry.EntityType, &entry.EntityID, &entry.Before, &entry.After, &entry.CreatedAt,
+		); err != nil {
+			logger.FromContext(ctx).Error("AuditRepository.GetAll scan error: ", err)
+			return nil, 0, fmt.Errorf("AuditRepository.GetAll - scan error: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	countQuery, countArgs, _ := r.Builder.
+		Select("COUNT(*)").
+		From("audit_log").
+		Where(filter).
+		ToSql()
+
+	err = r.GetTxManager(ctx).QueryRow(ctx, countQuery, countArgs...).Scan(&total)
+	if err != nil {
+		logger.FromContext(ctx).Error("AuditRepository.GetAll count error: ", err)
+		return nil, 0, fmt.Errorf("AuditRepository.GetAll - failed to get total count: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("AuditRepository.GetAll success: count=%d", len(entries))
+	return entries, total, nil
+}
+
+// nullJSON stores an empty snapshot as NULL rather than an invalid empty JSON document.
+func nullJSON(data []byte) any {
+	if len(data) == 0 {
+		return nil
+	}
+	return data
+}
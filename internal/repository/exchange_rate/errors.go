@@ -0,0 +1,7 @@
+package exchange_rate_repo
+
+import "errors"
+
+var (
+	ErrInvalidExchangeRate = errors.New("invalid exchange rate")
+)
@@ -0,0 +1,117 @@
+package exchange_rate_repo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/4udiwe/subscription-service/internal/database"
+	"github.com/4udiwe/subscription-service/internal/entity"
+	"github.com/4udiwe/subscription-service/internal/metrics"
+	"github.com/4udiwe/subscription-service/pkg/logger"
+	"github.com/4udiwe/subscription-service/pkg/postgres"
+	"github.com/4udiwe/subscription-service/pkg/tracing"
+)
+
+type Repository struct {
+	*postgres.Postgres
+}
+
+func New(postgres *postgres.Postgres) *Repository {
+	return &Repository{postgres}
+}
+
+// Upsert stores the rates, a rate already stored for the same pair and date is replaced.
+func (r *Repository) Upsert(ctx context.Context, rates []entity.ExchangeRate) (_ int64, err error) {
+	ctx, span := tracing.Start(ctx, "ExchangeRateRepository.Upsert")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("ExchangeRateRepository.Upsert")()
+	logger.FromContext(ctx).Infof("ExchangeRateRepository.Upsert called: count=%d", len(rates))
+	if len(rates) == 0 {
+		return 0, nil
+	}
+
+	builder := r.Builder.
+		Insert("exchange_rate").
+		Columns("from_currency", "to_currency", "rate_date", "rate").
+		Suffix("ON CONFLICT (from_currency, to_currency, rate_date) DO UPDATE SET rate = EXCLUDED.rate, updated_at = now()")
+
+	for _, rate := range rates {
+		builder = builder.Values(rate.From, rate.To, rate.Date, rate.Rate)
+	}
+
+	query, args, _ := builder.ToSql()
+
+	result, err := r.GetTxManager(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		logger.FromContext(ctx).Error("ExchangeRateRepository.Upsert error: ", err)
+		if database.IsCheckViolation(err) {
+			return 0, ErrInvalidExchangeRate
+		}
+		return 0, fmt.Errorf("ExchangeRateRepository.Upsert - failed to store rates: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("ExchangeRateRepository.Upsert success: count=%d", result.RowsAffected())
+	return result.RowsAffected(), nil
+}
+
+func (r *Repository) GetAll(
+	ctx context.Context,
+	from *string,
+	to *string,
+	limit int,
+	offset int,
+) (rates []entity.ExchangeRate, total int, err error) {
+	ctx, span := tracing.Start(ctx, "ExchangeRateRepository.GetAll")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("ExchangeRateRepository.GetAll")()
+	logger.FromContext(ctx).Infof("ExchangeRateRepository.GetAll called: from=%v, to=%v", from, to)
+
+	builder := r.Builder.
+		Select("from_currency", "to_currency", "rate_date", "rate::float8", "created_at", "updated_at").
+		From("exchange_rate").
+		OrderBy("rate_date DESC", "from_currency", "to_currency").
+		Limit(uint64(limit)).
+		Offset(uint64(offset))
+
+	countBuilder := r.Builder.
+		Select("COUNT(*)").
+		From("exchange_rate")
+
+	if from != nil {
+		builder = builder.Where("from_currency = ?", *from)
+		countBuilder = countBuilder.Where("from_currency = ?", *from)
+	}
+	if to != nil {
+		builder = builder.Where("to_currency = ?", *to)
+		countBuilder = countBuilder.Where("to_currency = ?", *to)
+	}
+
+	query, args, _ := builder.ToSql()
+
+	rows, err := r.GetTxManager(ctx).Query(ctx, query, args...)
+	if err != nil {
+		logger.FromContext(ctx).Error("ExchangeRateRepository.GetAll error: ", err)
+		return nil, 0, fmt.Errorf("ExchangeRateRepository.GetAll - failed to get rates: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var rate entity.ExchangeRate
+		if err := rows.Scan(&rate.From, &rate.To, &rate.Date, &rate.Rate, &rate.CreatedAt, &rate.UpdatedAt); err != nil {
+			logger.FromContext(ctx).Error("ExchangeRateRepository.GetAll scan error: ", err)
+			return nil, 0, fmt.Errorf("ExchangeRateRepository.GetAll - scan error: %w", err)
+		}
+		rates = append(rates, rate)
+	}
+
+	countQuery, countArgs, _ := countBuilder.ToSql()
+
+	err = r.GetTxManager(ctx).QueryRow(ctx, countQuery, countArgs...).Scan(&total)
+	if err != nil {
+		logger.FromContext(ctx).Error("ExchangeRateRepository.GetAll count error: ", err)
+		return nil, 0, fmt.Errorf("ExchangeRateRepository.GetAll - failed to get total count: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("ExchangeRateRepository.GetAll success: count=%d", len(rates))
+	return rates, total, nil
+}
@@ -0,0 +1,7 @@
+package idempotency_repo
+
+import "errors"
+
+var (
+	ErrKeyNotFound = errors.New("idempotency key not found")
+)
@@ -0,0 +1,166 @@
+package idempotency_repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/4udiwe/subscription-service/internal/entity"
+	"github.com/4udiwe/subscription-service/internal/metrics"
+	"github.com/4udiwe/subscription-service/pkg/logger"
+	"github.com/4udiwe/subscription-service/pkg/postgres"
+	"github.com/4udiwe/subscription-service/pkg/tracing"
+	"github.com/jackc/pgx/v5"
+)
+
+type Repository struct {
+	*postgres.Postgres
+}
+
+func New(postgres *postgres.Postgres) *Repository {
+	return &Repository{postgres}
+}
+
+// Acquire stores the key for the request unless a key that has not expired is already stored,
+// it reports whether the key was stored. An expired key is replaced.
+func (r *Repository) Acquire(ctx context.Context, scope, key, requestHash string, expiresAt time.Time) (_ bool, err error) {
+	ctx, span := tracing.Start(ctx, "IdempotencyRepository.Acquire")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("IdempotencyRepository.Acquire")()
+	logger.FromContext(ctx).Infof("IdempotencyRepository.Acquire called: scope=%s, key=%s", scope, key)
+
+	query, args, _ := r.Builder.
+		Insert("idempotency_key").
+		Columns("scope", "key", "request_hash", "expires_at").
+		Values(scope, key, requestHash, expiresAt).
+		Suffix(`ON CONFLICT (scope, key) DO UPDATE SET
+			request_hash = EXCLUDED.request_hash,
+			status_code = NULL,
+			content_type = NULL,
+			response_body = NULL,
+			created_at = now(),
+			expires_at = EXCLUDED.expires_at
+		WHERE idempotency_key.expires_at <= now()
+		RETURNING key`).
+		ToSql()
+
+	var stored string
+	err = r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(&stored)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		logger.FromContext(ctx).Error("IdempotencyRepository.Acquire error: ", err)
+		return false, fmt.Errorf("IdempotencyRepository.Acquire - failed to store key: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("IdempotencyRepository.Acquire success: scope=%s, key=%s", scope, key)
+	return true, nil
+}
+
+func (r *Repository) Get(ctx context.Context, scope, key string) (_ entity.IdempotencyKey, err error) {
+	ctx, span := tracing.Start(ctx, "IdempotencyRepository.Get")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("IdempotencyRepository.Get")()
+	logger.FromContext(ctx).Infof("IdempotencyRepository.Get called: scope=%s, key=%s", scope, key)
+
+	query, args, _ := r.Builder.
+		Select("scope", "key", "request_hash", "status_code", "content_type", "response_body", "created_at", "expires_at").
+		From("idempotency_key").
+		Where("scope = ?", scope).
+		Where("key = ?", key).
+		ToSql()
+
+	var stored entity.IdempotencyKey
+	err = r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(
+		&stored.Scope, &stored.Key, &stored.RequestHash, &stored.StatusCode,
+		&stored.ContentType, &stored.ResponseBody, &stored.CreatedAt, &stored.ExpiresAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.IdempotencyKey{}, ErrKeyNotFound
+		}
+		logger.FromContext(ctx).Error("IdempotencyRepository.Get error: ", err)
+		return entity.IdempotencyKey{}, fmt.Errorf("IdempotencyRepository.Get - failed to get key: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("IdempotencyRepository.Get success: scope=%s, key=%s", scope, key)
+	return stored, nil
+}
+
+// Complete stores the response to the request made with the key.
+func (r *Repository) Complete(ctx context.Context, scope, key string, statusCode int, contentType string, body []byte) (err error) {
+	ctx, span := tracing.Start(ctx, "IdempotencyRepository.Complete")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("IdempotencyRepository.Complete")()
+	logger.FromContext(ctx).Infof("IdempotencyRepository.Complete called: scope=%s, key=%s, statusCode=%d", scope, key, statusCode)
+
+	query, args, _ := r.Builder.
+		Update("idempotency_key").
+		Set("status_code", statusCode).
+		Set("content_type", contentType).
+		Set("response_body", body).
+		Where("scope = ?", scope).
+		Where("key = ?", key).
+		ToSql()
+
+	tag, err := r.GetTxManager(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		logger.FromContext(ctx).Error("IdempotencyRepository.Complete error: ", err)
+		return fmt.Errorf("IdempotencyRepository.Complete - failed to store response: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrKeyNotFound
+	}
+
+	logger.FromContext(ctx).Infof("IdempotencyRepository.Complete success: scope=%s, key=%s", scope, key)
+	return nil
+}
+
+// Release removes a key whose request has not been completed, so the request can be retried with it.
+func (r *Repository) Release(ctx context.Context, scope, key string) (err error) {
+	ctx, span := tracing.Start(ctx, "IdempotencyRepository.Release")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("IdempotencyRepository.Release")()
+	logger.FromContext(ctx).Infof("IdempotencyRepository.Release called: scope=%s, key=%s", scope, key)
+
+	query, args, _ := r.Builder.
+		Delete("idempotency_key").
+		Where("scope = ?", scope).
+		Where("key = ?", key).
+		Where("status_code IS NULL").
+		ToSql()
+
+	if _, err := r.GetTxManager(ctx).Exec(ctx, query, args...); err != nil {
+		logger.FromContext(ctx).Error("IdempotencyRepository.Release error: ", err)
+		return fmt.Errorf("IdempotencyRepository.Release - failed to remove key: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("IdempotencyRepository.Release success: scope=%s, key=%s", scope, key)
+	return nil
+}
+
+// DeleteExpired removes up to limit keys that expired before the time.
+func (r *Repository) DeleteExpired(ctx context.Context, before time.Time, limit int) (_ int64, err error) {
+	ctx, span := tracing.Start(ctx, "IdempotencyRepository.DeleteExpired")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("IdempotencyRepository.DeleteExpired")()
+	logger.FromContext(ctx).Infof("IdempotencyRepository.DeleteExpired called: before=%s, limit=%d", before, limit)
+
+	query, args, _ := r.Builder.
+		Delete("idempotency_key").
+		Where(`(scope, key) IN (
+			SELECT scope, key FROM idempotency_key WHERE expires_at <= ? LIMIT ?
+		)`, before, limit).
+		ToSql()
+
+	tag, err := r.GetTxManager(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		logger.FromContext(ctx).Error("IdempotencyRepository.DeleteExpired error: ", err)
+		return 0, fmt.Errorf("IdempotencyRepository.DeleteExpired - failed to delete keys: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("IdempotencyRepository.DeleteExpired success: deleted=%d", tag.RowsAffected())
+	return tag.RowsAffected(), nil
+}
@@ -3,6 +3,12 @@ package offer_repo
 import "errors"
 
 var (
-	ErrOfferNotFound                      = errors.New("offer not found")
-	ErrOfferWithNameAndPriceAlreadyExists = errors.New("offer with the same name and price already exists")
+	ErrOfferNotFound                    = errors.New("offer not found")
+	ErrOfferAlreadyExists               = errors.New("offer with the same name and currency already exists")
+	ErrOfferRenameOverlapsSubscriptions = errors.New("renaming offer makes subscriptions of a user overlap")
+	ErrOfferRenameDuplicatesTrial       = errors.New("renaming offer gives a user two trials of the service")
+	ErrOfferPriceNotFound               = errors.New("offer price not found")
+	ErrPendingPriceNotFound             = errors.New("pending price change not found")
+	ErrPriceChangeAlreadyScheduled      = errors.New("price change already scheduled for this date")
+	ErrOfferPriceInUse                  = errors.New("offer price is used by subscriptions")
 )
@@ -0,0 +1,337 @@
+package offer_repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/4udiwe/subscription-service/internal/database"
+	"github.com/4udiwe/subscription-service/internal/entity"
+	"github.com/4udiwe/subscription-service/internal/metrics"
+	"github.com/4udiwe/subscription-service/pkg/logger"
+	"github.com/4udiwe/subscription-service/pkg/tracing"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+var priceColumns = []string{"id", "offer_id", "price", "currency", "effective_from", "applied_at", "failed_at", "created_at"}
+
+func priceFields(p *entity.OfferPrice) []any {
+	return []any{&p.ID, &p.OfferID, &p.Price, &p.Currency, &p.EffectiveFrom, &p.AppliedAt, &p.FailedAt, &p.CreatedAt}
+}
+
+// AddPrice writes a new price version of the offer that is applied at once. It does not change
+// the offer itself.
+func (r *Repository) AddPrice(ctx context.Context, offerID uuid.UUID, price int, currency string, effectiveFrom time.Time) (_ entity.OfferPrice, err error) {
+	ctx, span := tracing.Start(ctx, "OfferRepository.AddPrice")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("OfferRepository.AddPrice")()
+	logger.FromContext(ctx).Infof("OfferRepository.AddPrice called: offerID=%s, price=%d, currency=%s, effectiveFrom=%v", offerID, price, currency, effectiveFrom)
+
+	query, args, _ := r.Builder.
+		Insert("offer_price").
+		Columns("offer_id", "price", "currency", "effective_from", "applied_at").
+		Values(offerID, price, currency, effectiveFrom, squirrel.Expr("now()")).
+		Suffix("RETURNING " + strings.Join(priceColumns, ", ")).
+		ToSql()
+
+	var offerPrice entity.OfferPrice
+	err = r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(priceFields(&offerPrice)...)
+	if err != nil {
+		logger.FromContext(ctx).Error("OfferRepository.AddPrice error: ", err)
+		return entity.OfferPrice{}, fmt.Errorf("OfferRepository.AddPrice - failed to add price: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("OfferRepository.AddPrice success: id=%s", offerPrice.ID)
+	return offerPrice, nil
+}
+
+// SchedulePrice writes a pending price version of the offer. There can be one pending version
+// per offer and date, a second one returns ErrPriceChangeAlreadyScheduled.
+func (r *Repository) SchedulePrice(ctx context.Context, offerID uuid.UUID, price int, currency string, effectiveFrom time.Time) (_ entity.OfferPrice, err error) {
+	ctx, span := tracing.Start(ctx, "OfferRepository.SchedulePrice")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("OfferRepository.SchedulePrice")()
+	logger.FromContext(ctx).Infof("OfferRepository.SchedulePrice called: offerID=%s, price=%d, currency=%s, effectiveFrom=%v", offerID, price, currency, effectiveFrom)
+
+	query, args, _ := r.Builder.
+		Insert("offer_price").
+		Columns("offer_id", "price", "currency", "effective_from").
+		Values(offerID, price, currency, effectiveFrom).
+		Suffix("RETURNING " + strings.Join(priceColumns, ", ")).
+		ToSql()
+
+	var offerPrice entity.OfferPrice
+	err = r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(priceFields(&offerPrice)...)
+	if err != nil {
+		logger.FromContext(ctx).Error("OfferRepository.SchedulePrice error: ", err)
+		if database.IsUniqueViolation(err) {
+			return entity.OfferPrice{}, ErrPriceChangeAlreadyScheduled
+		}
+		if database.IsForeignKeyViolation(err) {
+			return entity.OfferPrice{}, ErrOfferNotFound
+		}
+		return entity.OfferPrice{}, fmt.Errorf("OfferRepository.SchedulePrice - failed to schedule price: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("OfferRepository.SchedulePrice success: id=%s", offerPrice.ID)
+	return offerPrice, nil
+}
+
+// GetPrices returns the price versions of the offer, the latest effective first. A non-nil
+// pending returns only pending or only applied versions, failed versions are returned only without it.
+func (r *Repository) GetPrices(ctx context.Context, offerID uuid.UUID, pending *bool) (_ []entity.OfferPrice, err error) {
+	ctx, span := tracing.Start(ctx, "OfferRepository.GetPrices")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("OfferRepository.GetPrices")()
+	logger.FromContext(ctx).Infof("OfferRepository.GetPrices called: offerID=%s, pending=%v", offerID, pending)
+
+	builder := r.Builder.
+		Select(priceColumns...).
+		From("offer_price").
+		Where("offer_id = ?", offerID).
+		OrderBy("effective_from DESC", "created_at DESC")
+
+	if pending != nil {
+		if *pending {
+			builder = builder.Where("applied_at IS NULL AND failed_at IS NULL")
+		} else {
+			builder = builder.Where("applied_at IS NOT NULL")
+		}
+	}
+
+	query, args, _ := builder.ToSql()
+
+	rows, err := r.GetTxManager(ctx).Query(ctx, query, args...)
+	if err != nil {
+		logger.FromContext(ctx).Error("OfferRepository.GetPrices error: ", err)
+		return nil, fmt.Errorf("OfferRepository.GetPrices - failed to get prices: %w", err)
+	}
+	defer rows.Close()
+
+	var prices []entity.OfferPrice
+	for rows.Next() {
+		var p entity.OfferPrice
+		if err := rows.Scan(priceFields(&p)...); err != nil {
+			logger.FromContext(ctx).Error("OfferRepository.GetPrices scan error: ", err)
+			return nil, fmt.Errorf("OfferRepository.GetPrices - scan error: %w", err)
+		}
+		prices = append(prices, p)
+	}
+
+	logger.FromContext(ctx).Infof("OfferRepository.GetPrices success: count=%d", len(prices))
+	return prices, nil
+}
+
+func (r *Repository) GetPriceByID(ctx context.Context, id uuid.UUID) (_ entity.OfferPrice, err error) {
+	ctx, span := tracing.Start(ctx, "OfferRepository.GetPriceByID")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("OfferRepository.GetPriceByID")()
+	logger.FromContext(ctx).Infof("OfferRepository.GetPriceByID called: id=%s", id)
+
+	query, args, _ := r.Builder.
+		Select(priceColumns...).
+		From("offer_price").
+		Where("id = ?", id).
+		ToSql()
+
+	var p entity.OfferPrice
+	err = r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(priceFields(&p)...)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.OfferPrice{}, ErrOfferPriceNotFound
+		}
+		logger.FromContext(ctx).Error("OfferRepository.GetPriceByID error: ", err)
+		return entity.OfferPrice{}, fmt.Errorf("OfferRepository.GetPriceByID - failed to get price: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("OfferRepository.GetPriceByID success: id=%s", p.ID)
+	return p, nil
+}
+
+// GetPriceOn returns the price version of the offer in effect on date. Pending versions count,
+// so a period that starts after a scheduled change is charged the new price even if the change
+// has not been applied to the offer yet. Failed versions do not.
+func (r *Repository) GetPriceOn(ctx context.Context, offerID uuid.UUID, date time.Time) (_ entity.OfferPrice, err error) {
+	ctx, span := tracing.Start(ctx, "OfferRepository.GetPriceOn")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("OfferRepository.GetPriceOn")()
+	logger.FromContext(ctx).Infof("OfferRepository.GetPriceOn called: offerID=%s, date=%v", offerID, date)
+
+	query, args, _ := r.Builder.
+		Select(priceColumns...).
+		From("offer_price").
+		Where("offer_id = ?", offerID).
+		Where("effective_from <= ?", date).
+		Where("failed_at IS NULL").
+		OrderBy("effective_from DESC", "created_at DESC").
+		Limit(1).
+		ToSql()
+
+	var p entity.OfferPrice
+	err = r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(priceFields(&p)...)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.OfferPrice{}, ErrOfferPriceNotFound
+		}
+		logger.FromContext(ctx).Error("OfferRepository.GetPriceOn error: ", err)
+		return entity.OfferPrice{}, fmt.Errorf("OfferRepository.GetPriceOn - failed to get price: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("OfferRepository.GetPriceOn success: id=%s", p.ID)
+	return p, nil
+}
+
+// CancelPrice removes a pending price version of the offer. A version that is already applied
+// or belongs to another offer returns ErrPendingPriceNotFound, a version some subscription was
+// renewed at returns ErrOfferPriceInUse.
+func (r *Repository) CancelPrice(ctx context.Context, offerID, priceID uuid.UUID) (_ entity.OfferPrice, err error) {
+	ctx, span := tracing.Start(ctx, "OfferRepository.CancelPrice")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("OfferRepository.CancelPrice")()
+	logger.FromContext(ctx).Infof("OfferRepository.CancelPrice called: offerID=%s, priceID=%s", offerID, priceID)
+
+	query, args, _ := r.Builder.
+		Delete("offer_price").
+		Where("id = ?", priceID).
+		Where("offer_id = ?", offerID).
+		Where("applied_at IS NULL AND failed_at IS NULL").
+		Suffix("RETURNING " + strings.Join(priceColumns, ", ")).
+		ToSql()
+
+	var p entity.OfferPrice
+	err = r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(priceFields(&p)...)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.OfferPrice{}, ErrPendingPriceNotFound
+		}
+		logger.FromContext(ctx).Error("OfferRepository.CancelPrice error: ", err)
+		if database.IsForeignKeyViolation(err) {
+			return entity.OfferPrice{}, ErrOfferPriceInUse
+		}
+		return entity.OfferPrice{}, fmt.Errorf("OfferRepository.CancelPrice - failed to cancel price: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("OfferRepository.CancelPrice success: id=%s", p.ID)
+	return p, nil
+}
+
+// GetOfferIDsWithDuePrices returns up to limit offers that have pending price versions effective
+// not later than date.
+func (r *Repository) GetOfferIDsWithDuePrices(ctx context.Context, date time.Time, limit int) (_ []uuid.UUID, err error) {
+	ctx, span := tracing.Start(ctx, "OfferRepository.GetOfferIDsWithDuePrices")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("OfferRepository.GetOfferIDsWithDuePrices")()
+	logger.FromContext(ctx).Infof("OfferRepository.GetOfferIDsWithDuePrices called: date=%v, limit=%d", date, limit)
+
+	query, args, _ := r.Builder.
+		Select("offer_id").
+		From("offer_price").
+		Where("applied_at IS NULL AND failed_at IS NULL").
+		Where("effective_from <= ?", date).
+		GroupBy("offer_id").
+		OrderBy("MIN(effective_from)").
+		Limit(uint64(limit)).
+		ToSql()
+
+	rows, err := r.GetTxManager(ctx).Query(ctx, query, args...)
+	if err != nil {
+		logger.FromContext(ctx).Error("OfferRepository.GetOfferIDsWithDuePrices error: ", err)
+		return nil, fmt.Errorf("OfferRepository.GetOfferIDsWithDuePrices - failed to get offers: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			logger.FromContext(ctx).Error("OfferRepository.GetOfferIDsWithDuePrices scan error: ", err)
+			return nil, fmt.Errorf("OfferRepository.GetOfferIDsWithDuePrices - scan error: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	logger.FromContext(ctx).Infof("OfferRepository.GetOfferIDsWithDuePrices success: count=%d", len(ids))
+	return ids, nil
+}
+
+// MarkDuePricesApplied marks the pending price versions of the offer effective not later than
+// date as applied and returns them, the latest effective last. The rows stay locked until the end
+// of the transaction, so a concurrent call gets none of them.
+func (r *Repository) MarkDuePricesApplied(ctx context.Context, offerID uuid.UUID, date time.Time) (_ []entity.OfferPrice, err error) {
+	ctx, span := tracing.Start(ctx, "OfferRepository.MarkDuePricesApplied")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("OfferRepository.MarkDuePricesApplied")()
+	logger.FromContext(ctx).Infof("OfferRepository.MarkDuePricesApplied called: offerID=%s, date=%v", offerID, date)
+
+	prices, err := r.markDuePrices(ctx, "applied_at", offerID, date)
+	if err != nil {
+		logger.FromContext(ctx).Error("OfferRepository.MarkDuePricesApplied error: ", err)
+		return nil, fmt.Errorf("OfferRepository.MarkDuePricesApplied - failed to apply prices: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("OfferRepository.MarkDuePricesApplied success: count=%d", len(prices))
+	return prices, nil
+}
+
+// MarkDuePricesFailed marks the pending price versions of the offer effective not later than
+// date as failed and returns them, the latest effective last.
+func (r *Repository) MarkDuePricesFailed(ctx context.Context, offerID uuid.UUID, date time.Time) (_ []entity.OfferPrice, err error) {
+	ctx, span := tracing.Start(ctx, "OfferRepository.MarkDuePricesFailed")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("OfferRepository.MarkDuePricesFailed")()
+	logger.FromContext(ctx).Infof("OfferRepository.MarkDuePricesFailed called: offerID=%s, date=%v", offerID, date)
+
+	prices, err := r.markDuePrices(ctx, "failed_at", offerID, date)
+	if err != nil {
+		logger.FromContext(ctx).Error("OfferRepository.MarkDuePricesFailed error: ", err)
+		return nil, fmt.Errorf("OfferRepository.MarkDuePricesFailed - failed to mark prices: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("OfferRepository.MarkDuePricesFailed success: count=%d", len(prices))
+	return prices, nil
+}
+
+// markDuePrices sets column to now() on the pending price versions of the offer effective not
+// later than date.
+func (r *Repository) markDuePrices(ctx context.Context, column string, offerID uuid.UUID, date time.Time) ([]entity.OfferPrice, error) {
+	query, args, _ := r.Builder.
+		Update("offer_price").
+		Set(column, squirrel.Expr("now()")).
+		Where("offer_id = ?", offerID).
+		Where("applied_at IS NULL AND failed_at IS NULL").
+		Where("effective_from <= ?", date).
+		Suffix("RETURNING " + strings.Join(priceColumns, ", ")).
+		ToSql()
+
+	rows, err := r.GetTxManager(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var prices []entity.OfferPrice
+	for rows.Next() {
+		var p entity.OfferPrice
+		if err := rows.Scan(priceFields(&p)...); err != nil {
+			return nil, fmt.Errorf("scan error: %w", err)
+		}
+		prices = append(prices, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// UPDATE ... RETURNING has no order
+	slices.SortFunc(prices, func(a, b entity.OfferPrice) int {
+		if c := a.EffectiveFrom.Compare(b.EffectiveFrom); c != 0 {
+			return c
+		}
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	return prices, nil
+}
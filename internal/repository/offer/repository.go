@@ -7,10 +7,14 @@ import (
 
 	"github.com/4udiwe/subscription-service/internal/database"
 	"github.com/4udiwe/subscription-service/internal/entity"
+	"github.com/4udiwe/subscription-service/internal/metrics"
+	"github.com/4udiwe/subscription-service/pkg/cursor"
+	"github.com/4udiwe/subscription-service/pkg/logger"
 	"github.com/4udiwe/subscription-service/pkg/postgres"
+	"github.com/4udiwe/subscription-service/pkg/tracing"
+	"github.com/Masterminds/squirrel"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
-	"github.com/sirupsen/logrus"
 )
 
 type Repository struct {
@@ -21,60 +25,84 @@ func New(postgres *postgres.Postgres) *Repository {
 	return &Repository{postgres}
 }
 
-func (r *Repository) Create(ctx context.Context, name string, price int, durationMonths int) (entity.Offer, error) {
-	logrus.Infof("OfferRepository.Create called: name=%s, price=%d, durationMonths=%d", name, price, durationMonths)
+// Create inserts the offer together with its first price version, effective from today.
+// The offer refers to the version before it exists, so Create must run in a transaction.
+// An offer with the same name and currency is ErrOfferAlreadyExists.
+func (r *Repository) Create(ctx context.Context, name string, price int, currency string, durationMonths int, trialDays int) (_ entity.Offer, err error) {
+	ctx, span := tracing.Start(ctx, "OfferRepository.Create")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("OfferRepository.Create")()
+	logger.FromContext(ctx).Infof("OfferRepository.Create called: name=%s, price=%d, currency=%s, durationMonths=%d, trialDays=%d", name, price, currency, durationMonths, trialDays)
 
-	query, args, _ := r.Builder.
-		Insert("offer").
-		Columns("name", "price", "duration_months").
-		Values(name, price, durationMonths).
-		Suffix("RETURNING id, created_at").
-		ToSql()
+	if err := r.checkNameFree(ctx, uuid.Nil, name, currency); err != nil {
+		return entity.Offer{}, err
+	}
 
 	offer := entity.Offer{
 		Name:           name,
 		Price:          price,
+		Currency:       currency,
+		PriceID:        uuid.New(),
 		DurationMonths: durationMonths,
+		TrialDays:      trialDays,
 	}
 
-	err := r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(
-		&offer.ID, &offer.CreatedAt,
+	query, args, _ := r.Builder.
+		Insert("offer").
+		Columns("name", "price", "currency", "price_id", "duration_months", "trial_days").
+		Values(name, price, currency, offer.PriceID, durationMonths, trialDays).
+		Suffix("RETURNING id, created_at, updated_at").
+		ToSql()
+
+	err = r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(
+		&offer.ID, &offer.CreatedAt, &offer.UpdatedAt,
 	)
 	if err != nil {
-		logrus.Error("OfferRepository.Create error: ", err)
-		if database.IsUniqueViolation(err) {
-			return entity.Offer{}, ErrOfferWithNameAndPriceAlreadyExists
-		}
+		logger.FromContext(ctx).Error("OfferRepository.Create error: ", err)
 		return entity.Offer{}, fmt.Errorf("OfferRepository.Create - failed to create offer: %w", err)
 	}
 
-	logrus.Infof("OfferRepository.Create success: offer created with ID=%d", offer.ID)
+	priceQuery, priceArgs, _ := r.Builder.
+		Insert("offer_price").
+		Columns("id", "offer_id", "price", "currency", "effective_from", "applied_at").
+		Values(offer.PriceID, offer.ID, price, currency, squirrel.Expr("CURRENT_DATE"), squirrel.Expr("now()")).
+		ToSql()
+
+	if _, err := r.GetTxManager(ctx).Exec(ctx, priceQuery, priceArgs...); err != nil {
+		logger.FromContext(ctx).Error("OfferRepository.Create price error: ", err)
+		return entity.Offer{}, fmt.Errorf("OfferRepository.Create - failed to create offer price: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("OfferRepository.Create success: offer created with ID=%s", offer.ID)
 	return offer, nil
 }
 
 func (r *Repository) GetAll(ctx context.Context, limit int, offset int) (offers []entity.Offer, total int, err error) {
-	logrus.Info("OfferRepository.GetAll called")
+	ctx, span := tracing.Start(ctx, "OfferRepository.GetAll")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("OfferRepository.GetAll")()
+	logger.FromContext(ctx).Info("OfferRepository.GetAll called")
 
 	// base query
 	query, args, _ := r.Builder.
-		Select("id", "name", "price", "duration_months", "created_at, updated_at").
+		Select("id", "name", "price", "currency", "price_id", "duration_months", "trial_days", "created_at", "updated_at").
 		From("offer").
-		OrderBy("created_at DESC").
+		OrderBy("created_at DESC", "id DESC").
 		Limit(uint64(limit)).
 		Offset(uint64(offset)).
 		ToSql()
 
 	rows, err := r.GetTxManager(ctx).Query(ctx, query, args...)
 	if err != nil {
-		logrus.Error("OfferRepository.GetAll error: ", err)
+		logger.FromContext(ctx).Error("OfferRepository.GetAll error: ", err)
 		return nil, 0, fmt.Errorf("OfferRepository.GetAll - failed to get offers: %w", err)
 	}
 	defer rows.Close()
 
 	for rows.Next() {
 		var offer entity.Offer
-		if err := rows.Scan(&offer.ID, &offer.Name, &offer.Price, &offer.DurationMonths, &offer.CreatedAt, &offer.UpdatedAt); err != nil {
-			logrus.Error("OfferRepository.GetAll scan error: ", err)
+		if err := rows.Scan(&offer.ID, &offer.Name, &offer.Price, &offer.Currency, &offer.PriceID, &offer.DurationMonths, &offer.TrialDays, &offer.CreatedAt, &offer.UpdatedAt); err != nil {
+			logger.FromContext(ctx).Error("OfferRepository.GetAll scan error: ", err)
 			return nil, 0, fmt.Errorf("OfferRepository.GetAll - scan error: %w", err)
 		}
 		offers = append(offers, offer)
@@ -88,78 +116,253 @@ func (r *Repository) GetAll(ctx context.Context, limit int, offset int) (offers
 
 	err = r.GetTxManager(ctx).QueryRow(ctx, countQuery, countArgs...).Scan(&total)
 	if err != nil {
-		logrus.Error("OfferRepository.GetAll count query error: ", err)
+		logger.FromContext(ctx).Error("OfferRepository.GetAll count query error: ", err)
 		return nil, 0, fmt.Errorf("OfferRepository.GetAll - failed to get total count: %w", err)
 	}
 
-	logrus.Infof("OfferRepository.GetAll success: offers count=%d", len(offers))
+	logger.FromContext(ctx).Infof("OfferRepository.GetAll success: offers count=%d", len(offers))
 	return offers, total, nil
 }
 
-func (r *Repository) GetByID(ctx context.Context, id uuid.UUID) (entity.Offer, error) {
-	logrus.Infof("OfferRepository.GetById called: id=%d", id)
+// GetAllAfter returns up to limit offers that come after the cursor, newest first.
+// next is nil on the last page.
+func (r *Repository) GetAllAfter(ctx context.Context, after *cursor.Cursor, limit int) (offers []entity.Offer, next *cursor.Cursor, err error) {
+	ctx, span := tracing.Start(ctx, "OfferRepository.GetAllAfter")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("OfferRepository.GetAllAfter")()
+	logger.FromContext(ctx).Infof("OfferRepository.GetAllAfter called: after=%v, limit=%d", after, limit)
+
+	builder := r.Builder.
+		Select("id", "name", "price", "currency", "price_id", "duration_months", "trial_days", "created_at", "updated_at").
+		From("offer").
+		OrderBy("created_at DESC", "id DESC").
+		Limit(uint64(limit + 1))
+
+	if after != nil {
+		builder = builder.Where("(created_at, id) < (?, ?)", after.CreatedAt, after.ID)
+	}
+
+	query, args, _ := builder.ToSql()
+
+	rows, err := r.GetTxManager(ctx).Query(ctx, query, args...)
+	if err != nil {
+		logger.FromContext(ctx).Error("OfferRepository.GetAllAfter error: ", err)
+		return nil, nil, fmt.Errorf("OfferRepository.GetAllAfter - failed to get offers: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var offer entity.Offer
+		if err := rows.Scan(&offer.ID, &offer.Name, &offer.Price, &offer.Currency, &offer.PriceID, &offer.DurationMonths, &offer.TrialDays, &offer.CreatedAt, &offer.UpdatedAt); err != nil {
+			logger.FromContext(ctx).Error("OfferRepository.GetAllAfter scan error: ", err)
+			return nil, nil, fmt.Errorf("OfferRepository.GetAllAfter - scan error: %w", err)
+		}
+		offers = append(offers, offer)
+	}
+
+	// one extra row was fetched to find out whether there is a next page
+	if len(offers) > limit {
+		offers = offers[:limit]
+		last := offers[limit-1]
+		next = cursor.New(last.CreatedAt, last.ID)
+	}
+
+	logger.FromContext(ctx).Infof("OfferRepository.GetAllAfter success: offers count=%d", len(offers))
+	return offers, next, nil
+}
+
+func (r *Repository) GetByID(ctx context.Context, id uuid.UUID) (_ entity.Offer, err error) {
+	ctx, span := tracing.Start(ctx, "OfferRepository.GetByID")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("OfferRepository.GetByID")()
+	logger.FromContext(ctx).Infof("OfferRepository.GetById called: id=%s", id)
 	query, args, _ := r.Builder.
-		Select("id", "name", "price", "duration_months", "created_at").
+		Select("id", "name", "price", "currency", "price_id", "duration_months", "trial_days", "created_at", "updated_at").
 		From("offer").
 		Where("id = ?", id).
 		ToSql()
 
 	var offer entity.Offer
 
-	err := r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(
-		&offer.ID, &offer.Name, &offer.Price, &offer.DurationMonths, &offer.CreatedAt,
+	err = r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(
+		&offer.ID, &offer.Name, &offer.Price, &offer.Currency, &offer.PriceID, &offer.DurationMonths, &offer.TrialDays, &offer.CreatedAt, &offer.UpdatedAt,
 	)
 	if err != nil {
-		logrus.Error("OfferRepository.GetById error: ", err)
-		return entity.Offer{}, ErrOfferNotFound
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.Offer{}, ErrOfferNotFound
+		}
+		logger.FromContext(ctx).Error("OfferRepository.GetById error: ", err)
+		return entity.Offer{}, fmt.Errorf("OfferRepository.GetById - failed to get offer: %w", err)
 	}
 
-	logrus.Infof("OfferRepository.GetById success: id=%d", offer.ID)
+	logger.FromContext(ctx).Infof("OfferRepository.GetById success: id=%s", offer.ID)
 	return offer, nil
 }
 
-func (r *Repository) Delete(ctx context.Context, id uuid.UUID) error {
-	logrus.Infof("OfferRepository.Delete called: id=%d", id)
+// Update changes the offer, price and currency must be those of the version priceID.
+// Renaming the offer or changing its currency to those of another offer is ErrOfferAlreadyExists.
+// The new name is copied to the subscriptions of the offer, so a rename that makes them overlap
+// with or repeat the trial of subscriptions to the service of that name is
+// ErrOfferRenameOverlapsSubscriptions or ErrOfferRenameDuplicatesTrial. Update must run in a transaction.
+func (r *Repository) Update(
+	ctx context.Context,
+	id uuid.UUID,
+	name string,
+	price int,
+	currency string,
+	priceID uuid.UUID,
+	durationMonths int,
+	trialDays int,
+) (_ entity.Offer, err error) {
+	ctx, span := tracing.Start(ctx, "OfferRepository.Update")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("OfferRepository.Update")()
+	logger.FromContext(ctx).Infof("OfferRepository.Update called: id=%s, name=%s, price=%d, currency=%s, priceID=%s, durationMonths=%d, trialDays=%d", id, name, price, currency, priceID, durationMonths, trialDays)
+
+	if err := r.checkNameFree(ctx, id, name, currency); err != nil {
+		return entity.Offer{}, err
+	}
+
 	query, args, _ := r.Builder.
-		Delete("offer").
+		Update("offer").
+		Set("name", name).
+		Set("price", price).
+		Set("currency", currency).
+		Set("price_id", priceID).
+		Set("duration_months", durationMonths).
+		Set("trial_days", trialDays).
+		Set("updated_at", squirrel.Expr("now()")).
 		Where("id = ?", id).
+		Suffix("RETURNING id, name, price, currency, price_id, duration_months, trial_days, created_at, updated_at").
 		ToSql()
 
-	result, err := r.GetTxManager(ctx).Exec(ctx, query, args...)
+	var offer entity.Offer
+
+	err = r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(
+		&offer.ID, &offer.Name, &offer.Price, &offer.Currency, &offer.PriceID, &offer.DurationMonths, &offer.TrialDays, &offer.CreatedAt, &offer.UpdatedAt,
+	)
 	if err != nil {
-		logrus.Error("OfferRepository.Delete error: ", err)
-		return fmt.Errorf("OfferRepository.Delete - failed to delete offer: %w", err)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.Offer{}, ErrOfferNotFound
+		}
+		logger.FromContext(ctx).Error("OfferRepository.Update error: ", err)
+		if database.IsExclusionViolation(err) {
+			return entity.Offer{}, ErrOfferRenameOverlapsSubscriptions
+		}
+		if database.IsUniqueViolation(err) && database.ConstraintName(err) == "idx_subscription_one_trial" {
+			return entity.Offer{}, ErrOfferRenameDuplicatesTrial
+		}
+		return entity.Offer{}, fmt.Errorf("OfferRepository.Update - failed to update offer: %w", err)
 	}
 
-	rowsAffected := result.RowsAffected()
-	if rowsAffected == 0 {
-		logrus.Error("OfferRepository.Delete error: no offer found with id=", id)
-		return ErrOfferNotFound
+	logger.FromContext(ctx).Infof("OfferRepository.Update success: id=%s", offer.ID)
+	return offer, nil
+}
+
+// Delete removes the offer and returns its last state.
+func (r *Repository) Delete(ctx context.Context, id uuid.UUID) (_ entity.Offer, err error) {
+	ctx, span := tracing.Start(ctx, "OfferRepository.Delete")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("OfferRepository.Delete")()
+	logger.FromContext(ctx).Infof("OfferRepository.Delete called: id=%s", id)
+	query, args, _ := r.Builder.
+		Delete("offer").
+		Where("id = ?", id).
+		Suffix("RETURNING id, name, price, currency, price_id, duration_months, trial_days, created_at, updated_at").
+		ToSql()
+
+	var offer entity.Offer
+
+	err = r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(
+		&offer.ID, &offer.Name, &offer.Price, &offer.Currency, &offer.PriceID, &offer.DurationMonths, &offer.TrialDays, &offer.CreatedAt, &offer.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			logger.FromContext(ctx).Error("OfferRepository.Delete error: no offer found with id=", id)
+			return entity.Offer{}, ErrOfferNotFound
+		}
+		logger.FromContext(ctx).Error("OfferRepository.Delete error: ", err)
+		return entity.Offer{}, fmt.Errorf("OfferRepository.Delete - failed to delete offer: %w", err)
 	}
 
-	logrus.Infof("OfferRepository.Delete success: id=%d", id)
-	return nil
+	logger.FromContext(ctx).Infof("OfferRepository.Delete success: id=%s", id)
+	return offer, nil
 }
 
-func (r *Repository) GetByNameAndPrice(ctx context.Context, name string, price int) (entity.Offer, error) {
-	logrus.Infof("OfferRepository.GetByNameAndPrice called: name=%s, price=%d", name, price)
+// GetByNameAndCurrency returns the offer of the service in the currency. Offers that were split off
+// by price changes before prices were versioned share the name, the newest of them is returned.
+func (r *Repository) GetByNameAndCurrency(ctx context.Context, name string, currency string) (_ entity.Offer, err error) {
+	ctx, span := tracing.Start(ctx, "OfferRepository.GetByNameAndCurrency")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("OfferRepository.GetByNameAndCurrency")()
+	logger.FromContext(ctx).Infof("OfferRepository.GetByNameAndCurrency called: name=%s, currency=%s", name, currency)
 	query, args, _ := r.Builder.
-		Select("id", "name", "price", "duration_months", "created_at", "updated_at").
+		Select("id", "name", "price", "currency", "price_id", "duration_months", "trial_days", "created_at", "updated_at").
 		From("offer").
-		Where("name = ? AND price = ?", name, price).
+		Where("name = ? AND currency = ?", name, currency).
+		OrderBy("created_at DESC", "id DESC").
+		Limit(1).
 		ToSql()
 
 	var offer entity.Offer
 
-	err := r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(&offer.ID, &offer.Name, &offer.Price, &offer.DurationMonths, &offer.CreatedAt, &offer.UpdatedAt)
+	err = r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(&offer.ID, &offer.Name, &offer.Price, &offer.Currency, &offer.PriceID, &offer.DurationMonths, &offer.TrialDays, &offer.CreatedAt, &offer.UpdatedAt)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return entity.Offer{}, ErrOfferNotFound
 		}
-		logrus.Error("OfferRepository.GetByNameAndPrice error: ", err)
-		return entity.Offer{}, fmt.Errorf("OfferRepository.GetByNameAndPrice - failed to get offer: %w", err)
+		logger.FromContext(ctx).Error("OfferRepository.GetByNameAndCurrency error: ", err)
+		return entity.Offer{}, fmt.Errorf("OfferRepository.GetByNameAndCurrency - failed to get offer: %w", err)
 	}
 
-	logrus.Infof("OfferRepository.GetByNameAndPrice success: id=%s", offer.ID)
+	logger.FromContext(ctx).Infof("OfferRepository.GetByNameAndCurrency success: id=%s", offer.ID)
 	return offer, nil
 }
+
+// checkNameFree returns ErrOfferAlreadyExists when an offer other than id has the name and currency,
+// unless the offer id has them itself: offers split off by old price changes keep their names.
+// The check holds an advisory lock on the name until the end of the transaction, so concurrent
+// creations and renames to the same name are serialized.
+func (r *Repository) checkNameFree(ctx context.Context, id uuid.UUID, name string, currency string) error {
+	if _, err := r.GetTxManager(ctx).Exec(ctx, "SELECT pg_advisory_xact_lock(hashtextextended($1, 0))", "offer:"+currency+":"+name); err != nil {
+		logger.FromContext(ctx).Error("OfferRepository.checkNameFree lock error: ", err)
+		return fmt.Errorf("OfferRepository.checkNameFree - failed to lock offer name: %w", err)
+	}
+
+	query, args, _ := r.Builder.
+		Select().
+		Column(squirrel.Expr("COUNT(*) FILTER (WHERE id <> ?) > 0 AND COUNT(*) FILTER (WHERE id = ?) = 0", id, id)).
+		From("offer").
+		Where("name = ? AND currency = ?", name, currency).
+		ToSql()
+
+	var taken bool
+	if err := r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(&taken); err != nil {
+		logger.FromContext(ctx).Error("OfferRepository.checkNameFree error: ", err)
+		return fmt.Errorf("OfferRepository.checkNameFree - failed to check offer name: %w", err)
+	}
+	if taken {
+		return ErrOfferAlreadyExists
+	}
+	return nil
+}
+
+func (r *Repository) Count(ctx context.Context) (_ int, err error) {
+	ctx, span := tracing.Start(ctx, "OfferRepository.Count")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("OfferRepository.Count")()
+	logger.FromContext(ctx).Debug("OfferRepository.Count called")
+
+	query, args, _ := r.Builder.
+		Select("COUNT(*)").
+		From("offer").
+		ToSql()
+
+	var count int
+	if err := r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(&count); err != nil {
+		logger.FromContext(ctx).Error("OfferRepository.Count error: ", err)
+		return 0, fmt.Errorf("OfferRepository.Count - failed to count offers: %w", err)
+	}
+
+	return count, nil
+}
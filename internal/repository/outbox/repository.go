@@ -0,0 +1,188 @@
+package outbox_repo
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/4udiwe/subscription-service/internal/entity"
+	"github.com/4udiwe/subscription-service/internal/metrics"
+	"github.com/4udiwe/subscription-service/pkg/logger"
+	"github.com/4udiwe/subscription-service/pkg/postgres"
+	"github.com/4udiwe/subscription-service/pkg/tracing"
+	"github.com/Masterminds/squirrel"
+)
+
+type Repository struct {
+	*postgres.Postgres
+}
+
+func New(postgres *postgres.Postgres) *Repository {
+	return &Repository{postgres}
+}
+
+// Add stores the events. It should be called in the transaction of the change the events describe.
+func (r *Repository) Add(ctx context.Context, events ...entity.OutboxEvent) (err error) {
+	ctx, span := tracing.Start(ctx, "OutboxRepository.Add")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("OutboxRepository.Add")()
+	if len(events) == 0 {
+		return nil
+	}
+	logger.FromContext(ctx).Infof("OutboxRepository.Add called: count=%d", len(events))
+
+	builder := r.Builder.
+		Insert("outbox").
+		Columns("aggregate_type", "aggregate_id", "event_type", "payload")
+
+	for _, event := range events {
+		builder = builder.Values(event.AggregateType, event.AggregateID, event.EventType, event.Payload)
+	}
+
+	query, args, _ := builder.ToSql()
+
+	if _, err := r.GetTxManager(ctx).Exec(ctx, query, args...); err != nil {
+		logger.FromContext(ctx).Error("OutboxRepository.Add error: ", err)
+		return fmt.Errorf("OutboxRepository.Add - failed to store events: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("OutboxRepository.Add success: count=%d", len(events))
+	return nil
+}
+
+// ClaimPending takes up to limit unpublished events in id order and hides them from other
+// relays until leaseUntil. The claim is committed at once, so the events are published outside
+// of any transaction; if the relay dies before recording the result, they are retried after
+// the lease. A failed event waiting for its next attempt holds back the events after it, so
+// they are not published out of order.
+func (r *Repository) ClaimPending(ctx context.Context, limit int, leaseUntil time.Time) (_ []entity.OutboxEvent, err error) {
+	ctx, span := tracing.Start(ctx, "OutboxRepository.ClaimPending")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("OutboxRepository.ClaimPending")()
+	logger.FromContext(ctx).Debugf("OutboxRepository.ClaimPending called: limit=%d, leaseUntil=%v", limit, leaseUntil)
+
+	// the subquery keeps the default placeholders, the outer builder numbers them all
+	pending := squirrel.
+		Select("id").
+		From("outbox").
+		Where("published_at IS NULL AND dead_at IS NULL").
+		Where("(locked_until IS NULL OR locked_until <= now())").
+		Where(`NOT EXISTS (
+			SELECT 1 FROM outbox w
+			WHERE w.id <= outbox.id AND w.published_at IS NULL AND w.dead_at IS NULL AND w.next_attempt_at > now()
+		)`).
+		OrderBy("id").
+		Limit(uint64(limit)).
+		Suffix("FOR UPDATE SKIP LOCKED")
+
+	query, args, _ := r.Builder.
+		Update("outbox").
+		Set("locked_until", leaseUntil).
+		Where(squirrel.Expr("id IN (?)", pending)).
+		Suffix("RETURNING id, aggregate_type, aggregate_id, event_type, payload, attempts, last_error, created_at, published_at, next_attempt_at, dead_at").
+		ToSql()
+
+	rows, err := r.GetTxManager(ctx).Query(ctx, query, args...)
+	if err != nil {
+		logger.FromContext(ctx).Error("OutboxRepository.ClaimPending error: ", err)
+		return nil, fmt.Errorf("OutboxRepository.ClaimPending - failed to claim events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []entity.OutboxEvent
+	for rows.Next() {
+		var event entity.OutboxEvent
+		if err := rows.Scan(
+			&event.ID, &event.AggregateType, &event.AggregateID, &event.EventType, &event.Payload,
+			&event.Attempts, &event.LastError, &event.CreatedAt, &event.PublishedAt, &event.NextAttemptAt, &event.DeadAt,
+		); err != nil {
+			logger.FromContext(ctx).Error("OutboxRepository.ClaimPending scan error: ", err)
+			return nil, fmt.Errorf("OutboxRepository.ClaimPending - scan error: %w", err)
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		logger.FromContext(ctx).Error("OutboxRepository.ClaimPending error: ", err)
+		return nil, fmt.Errorf("OutboxRepository.ClaimPending - failed to claim events: %w", err)
+	}
+
+	// UPDATE ... RETURNING has no order
+	slices.SortFunc(events, func(a, b entity.OutboxEvent) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	logger.FromContext(ctx).Debugf("OutboxRepository.ClaimPending success: count=%d", len(events))
+	return events, nil
+}
+
+func (r *Repository) MarkPublished(ctx context.Context, id int64) (err error) {
+	ctx, span := tracing.Start(ctx, "OutboxRepository.MarkPublished")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("OutboxRepository.MarkPublished")()
+	query, args, _ := r.Builder.
+		Update("outbox").
+		Set("published_at", squirrel.Expr("now()")).
+		Set("attempts", squirrel.Expr("attempts + 1")).
+		Set("last_error", nil).
+		Set("locked_until", nil).
+		Set("next_attempt_at", nil).
+		Where("id = ?", id).
+		ToSql()
+
+	if _, err := r.GetTxManager(ctx).Exec(ctx, query, args...); err != nil {
+		logger.FromContext(ctx).Error("OutboxRepository.MarkPublished error: ", err)
+		return fmt.Errorf("OutboxRepository.MarkPublished - failed to update event: %w", err)
+	}
+	return nil
+}
+
+// MarkFailed records a failed delivery attempt. The event stays pending until nextAttemptAt, or
+// is not published any more when dead is true.
+func (r *Repository) MarkFailed(ctx context.Context, id int64, reason string, nextAttemptAt time.Time, dead bool) (err error) {
+	ctx, span := tracing.Start(ctx, "OutboxRepository.MarkFailed")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("OutboxRepository.MarkFailed")()
+	builder := r.Builder.
+		Update("outbox").
+		Set("attempts", squirrel.Expr("attempts + 1")).
+		Set("last_error", reason).
+		Set("locked_until", nil).
+		Set("next_attempt_at", nextAttemptAt).
+		Where("id = ?", id)
+
+	if dead {
+		builder = builder.Set("dead_at", squirrel.Expr("now()"))
+	}
+
+	query, args, _ := builder.ToSql()
+
+	if _, err := r.GetTxManager(ctx).Exec(ctx, query, args...); err != nil {
+		logger.FromContext(ctx).Error("OutboxRepository.MarkFailed error: ", err)
+		return fmt.Errorf("OutboxRepository.MarkFailed - failed to update event: %w", err)
+	}
+	return nil
+}
+
+// Release gives claimed events back before their lease ends, the next run takes them again.
+func (r *Repository) Release(ctx context.Context, ids []int64) (err error) {
+	ctx, span := tracing.Start(ctx, "OutboxRepository.Release")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("OutboxRepository.Release")()
+	if len(ids) == 0 {
+		return nil
+	}
+
+	query, args, _ := r.Builder.
+		Update("outbox").
+		Set("locked_until", nil).
+		Where(squirrel.Eq{"id": ids}).
+		ToSql()
+
+	if _, err := r.GetTxManager(ctx).Exec(ctx, query, args...); err != nil {
+		logger.FromContext(ctx).Error("OutboxRepository.Release error: ", err)
+		return fmt.Errorf("OutboxRepository.Release - failed to update events: %w", err)
+	}
+	return nil
+}
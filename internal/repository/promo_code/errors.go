@@ -0,0 +1,11 @@
+package promo_code_repo
+
+import "errors"
+
+var (
+	ErrPromoCodeNotFound      = errors.New("promo code not found")
+	ErrPromoCodeAlreadyExists = errors.New("promo code already exists")
+	ErrPromoCodeExhausted     = errors.New("promo code has no redemptions left")
+	ErrOfferNotFound          = errors.New("offer not found")
+	ErrInvalidPromoCode       = errors.New("invalid promo code")
+)
@@ -0,0 +1,200 @@
+package promo_code_repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/4udiwe/subscription-service/internal/database"
+	"github.com/4udiwe/subscription-service/internal/entity"
+	"github.com/4udiwe/subscription-service/internal/metrics"
+	"github.com/4udiwe/subscription-service/pkg/logger"
+	"github.com/4udiwe/subscription-service/pkg/postgres"
+	"github.com/4udiwe/subscription-service/pkg/tracing"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type Repository struct {
+	*postgres.Postgres
+}
+
+func New(postgres *postgres.Postgres) *Repository {
+	return &Repository{postgres}
+}
+
+// promoCodeColumns returns the promo code columns (table aliased as "p") in the order expected by promoCodeFields.
+func promoCodeColumns() []string {
+	return []string{
+		"p.id", "p.code", "p.discount_type", "p.discount_value", "p.currency",
+		"p.max_redemptions", "p.redemptions", "p.valid_from", "p.valid_until",
+		"COALESCE((SELECT array_agg(po.offer_id) FROM promo_code_offer po WHERE po.promo_code_id = p.id), '{}') AS offer_ids",
+		"p.created_at", "p.updated_at",
+	}
+}
+
+// promoCodeFields returns scan destinations matching promoCodeColumns.
+func promoCodeFields(code *entity.PromoCode) []any {
+	return []any{
+		&code.ID, &code.Code, &code.DiscountType, &code.DiscountValue, &code.Currency,
+		&code.MaxRedemptions, &code.Redemptions, &code.ValidFrom, &code.ValidUntil,
+		&code.OfferIDs, &code.CreatedAt, &code.UpdatedAt,
+	}
+}
+
+// Create inserts the promo code together with the offers it is restricted to.
+// It should be called within a transaction.
+func (r *Repository) Create(ctx context.Context, code entity.PromoCode) (_ entity.PromoCode, err error) {
+	ctx, span := tracing.Start(ctx, "PromoCodeRepository.Create")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("PromoCodeRepository.Create")()
+	logger.FromContext(ctx).Infof("PromoCodeRepository.Create called: code=%s, discountType=%s, discountValue=%d", code.Code, code.DiscountType, code.DiscountValue)
+
+	query, args, _ := r.Builder.
+		Insert("promo_code").
+		Columns("code", "discount_type", "discount_value", "currency", "max_redemptions", "valid_from", "valid_until").
+		Values(code.Code, code.DiscountType, code.DiscountValue, code.Currency, code.MaxRedemptions, code.ValidFrom, code.ValidUntil).
+		Suffix("RETURNING id, redemptions, created_at, updated_at").
+		ToSql()
+
+	err = r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(
+		&code.ID, &code.Redemptions, &code.CreatedAt, &code.UpdatedAt,
+	)
+	if err != nil {
+		logger.FromContext(ctx).Error("PromoCodeRepository.Create error: ", err)
+		if database.IsUniqueViolation(err) {
+			return entity.PromoCode{}, ErrPromoCodeAlreadyExists
+		}
+		if database.IsCheckViolation(err) {
+			return entity.PromoCode{}, ErrInvalidPromoCode
+		}
+		return entity.PromoCode{}, fmt.Errorf("PromoCodeRepository.Create - failed to create promo code: %w", err)
+	}
+
+	if len(code.OfferIDs) > 0 {
+		builder := r.Builder.
+			Insert("promo_code_offer").
+			Columns("promo_code_id", "offer_id").
+			Suffix("ON CONFLICT DO NOTHING")
+		for _, offerID := range code.OfferIDs {
+			builder = builder.Values(code.ID, offerID)
+		}
+
+		query, args, _ = builder.ToSql()
+
+		if _, err := r.GetTxManager(ctx).Exec(ctx, query, args...); err != nil {
+			logger.FromContext(ctx).Error("PromoCodeRepository.Create offers error: ", err)
+			if database.IsForeignKeyViolation(err) {
+				return entity.PromoCode{}, ErrOfferNotFound
+			}
+			return entity.PromoCode{}, fmt.Errorf("PromoCodeRepository.Create - failed to restrict promo code to offers: %w", err)
+		}
+	}
+
+	logger.FromContext(ctx).Infof("PromoCodeRepository.Create success: id=%s", code.ID)
+	return code, nil
+}
+
+func (r *Repository) GetAll(ctx context.Context, limit int, offset int) (codes []entity.PromoCode, total int, err error) {
+	ctx, span := tracing.Start(ctx, "PromoCodeRepository.GetAll")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("PromoCodeRepository.GetAll")()
+	logger.FromContext(ctx).Info("PromoCodeRepository.GetAll called")
+
+	query, args, _ := r.Builder.
+		Select(promoCodeColumns()...).
+		From("promo_code p").
+		OrderBy("p.created_at DESC", "p.id DESC").
+		Limit(uint64(limit)).
+		Offset(uint64(offset)).
+		ToSql()
+
+	rows, err := r.GetTxManager(ctx).Query(ctx, query, args...)
+	if err != nil {
+		logger.FromContext(ctx).Error("PromoCodeRepository.GetAll error: ", err)
+		return nil, 0, fmt.Errorf("PromoCodeRepository.GetAll - failed to get promo codes: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var code entity.PromoCode
+		if err := rows.Scan(promoCodeFields(&code)...); err != nil {
+			logger.FromContext(ctx).Error("PromoCodeRepository.GetAll scan error: ", err)
+			return nil, 0, fmt.Errorf("PromoCodeRepository.GetAll - scan error: %w", err)
+		}
+		codes = append(codes, code)
+	}
+
+	countQuery, countArgs, _ := r.Builder.
+		Select("COUNT(*)").
+		From("promo_code").
+		ToSql()
+
+	err = r.GetTxManager(ctx).QueryRow(ctx, countQuery, countArgs...).Scan(&total)
+	if err != nil {
+		logger.FromContext(ctx).Error("PromoCodeRepository.GetAll count error: ", err)
+		return nil, 0, fmt.Errorf("PromoCodeRepository.GetAll - failed to get total count: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("PromoCodeRepository.GetAll success: count=%d", len(codes))
+	return codes, total, nil
+}
+
+// GetByCodeForUpdate returns the promo code and locks its row until the end of the transaction,
+// so concurrent redemptions are checked against the up-to-date counter.
+func (r *Repository) GetByCodeForUpdate(ctx context.Context, code string) (_ entity.PromoCode, err error) {
+	ctx, span := tracing.Start(ctx, "PromoCodeRepository.GetByCodeForUpdate")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("PromoCodeRepository.GetByCodeForUpdate")()
+	logger.FromContext(ctx).Infof("PromoCodeRepository.GetByCodeForUpdate called: code=%s", code)
+
+	query, args, _ := r.Builder.
+		Select(promoCodeColumns()...).
+		From("promo_code p").
+		Where("p.code = ?", code).
+		Suffix("FOR UPDATE OF p").
+		ToSql()
+
+	var promo entity.PromoCode
+	err = r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(promoCodeFields(&promo)...)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.PromoCode{}, ErrPromoCodeNotFound
+		}
+		logger.FromContext(ctx).Error("PromoCodeRepository.GetByCodeForUpdate error: ", err)
+		return entity.PromoCode{}, fmt.Errorf("PromoCodeRepository.GetByCodeForUpdate - failed to get promo code: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("PromoCodeRepository.GetByCodeForUpdate success: id=%s", promo.ID)
+	return promo, nil
+}
+
+// Redeem counts one more use of the promo code. It returns ErrPromoCodeExhausted when the
+// redemption limit has been reached.
+func (r *Repository) Redeem(ctx context.Context, id uuid.UUID) (err error) {
+	ctx, span := tracing.Start(ctx, "PromoCodeRepository.Redeem")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("PromoCodeRepository.Redeem")()
+	logger.FromContext(ctx).Infof("PromoCodeRepository.Redeem called: id=%s", id)
+
+	query, args, _ := r.Builder.
+		Update("promo_code").
+		Set("redemptions", squirrel.Expr("redemptions + 1")).
+		Set("updated_at", squirrel.Expr("now()")).
+		Where("id = ?", id).
+		Where("(max_redemptions IS NULL OR redemptions < max_redemptions)").
+		ToSql()
+
+	result, err := r.GetTxManager(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		logger.FromContext(ctx).Error("PromoCodeRepository.Redeem error: ", err)
+		return fmt.Errorf("PromoCodeRepository.Redeem - failed to redeem promo code: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrPromoCodeExhausted
+	}
+
+	logger.FromContext(ctx).Infof("PromoCodeRepository.Redeem success: id=%s", id)
+	return nil
+}
@@ -0,0 +1,26 @@
+package subscription_repo
+
+import "github.com/4udiwe/subscription-service/internal/entity"
+
+// subscriptionColumns returns the subscription columns (table aliased as "s") in the order
+// expected by subscriptionFields, followed by extra columns.
+func subscriptionColumns(extra ...string) []string {
+	return append([]string{
+		"s.id", "s.user_id", "s.offer_id", "s.start_date", "s.end_date", "s.price", "s.price_id", "s.promo_code_id",
+		"s.auto_renew", "s.renewed_from_id", "s.status", "s.cancelled_at", "s.cancel_reason",
+		"s.trial_end_date", "s.created_at", "s.updated_at",
+	}, extra...)
+}
+
+// subscriptionFields returns scan destinations matching subscriptionColumns, followed by extra destinations.
+func subscriptionFields(sub *entity.Subscription, extra ...any) []any {
+	return append([]any{
+		&sub.ID, &sub.UserID, &sub.OfferID, &sub.StartDate, &sub.EndDate, &sub.Price, &sub.PriceID, &sub.PromoCodeID,
+		&sub.AutoRenew, &sub.RenewedFromID, &sub.Status, &sub.CancelledAt, &sub.CancelReason,
+		&sub.TrialEndDate, &sub.CreatedAt, &sub.UpdatedAt,
+	}, extra...)
+}
+
+// paidPrice is the price of a subscription for spend totals: a subscription without paid days,
+// such as one that ended within its free trial or a renewal cancelled before it started, costs nothing.
+const paidPrice = "CASE WHEN s.end_date <= COALESCE(s.trial_end_date, s.start_date) THEN 0 ELSE s.price END"
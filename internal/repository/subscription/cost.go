@@ -0,0 +1,115 @@
+package subscription_repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/4udiwe/subscription-service/internal/database"
+	"github.com/4udiwe/subscription-service/internal/entity"
+	"github.com/4udiwe/subscription-service/internal/metrics"
+	"github.com/4udiwe/subscription-service/pkg/logger"
+	"github.com/4udiwe/subscription-service/pkg/tracing"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+)
+
+// GetAllOverlappingPeriod returns subscriptions with their offers that cover at least one day
+// of [from, to). Prices are converted to currency at the rate for the start date of each
+// subscription. Empty userID and serviceNames mean no filtering.
+func (r *Repository) GetAllOverlappingPeriod(
+	ctx context.Context,
+	userID *uuid.UUID,
+	serviceNames []string,
+	from time.Time,
+	to time.Time,
+	currency string,
+) (_ []entity.SubscriptionFullInfo, err error) {
+	ctx, span := tracing.Start(ctx, "SubscriptionRepository.GetAllOverlappingPeriod")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("SubscriptionRepository.GetAllOverlappingPeriod")()
+	logger.FromContext(ctx).Infof("SubscriptionRepository.GetAllOverlappingPeriod called: userID=%v, serviceNames=%v, from=%s, to=%s, currency=%s", userID, serviceNames, from, to, currency)
+
+	builder := r.Builder.
+		Select(subscriptionColumns("o.name", "o.duration_months")...).
+		Column(squirrel.Expr("ROUND(convert_price(s.price, op.currency, ?, s.start_date))::int AS converted_price", currency)).
+		From("subscription s").
+		Join("offer o ON s.offer_id = o.id").
+		Join("offer_price op ON s.price_id = op.id").
+		Where("s.start_date < ?", to).
+		Where("s.end_date > ?", from).
+		OrderBy("s.start_date")
+
+	if userID != nil {
+		builder = builder.Where("s.user_id = ?", *userID)
+	}
+	if len(serviceNames) > 0 {
+		builder = builder.Where(squirrel.Eq{"o.name": serviceNames})
+	}
+
+	query, args, _ := builder.ToSql()
+
+	rows, err := r.GetTxManager(ctx).Query(ctx, query, args...)
+	if err != nil {
+		logger.FromContext(ctx).Error("SubscriptionRepository.GetAllOverlappingPeriod error: ", err)
+		return nil, fmt.Errorf("SubscriptionRepository.GetAllOverlappingPeriod - failed to get subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	var subs []entity.SubscriptionFullInfo
+	for rows.Next() {
+		sub := entity.SubscriptionFullInfo{Currency: currency}
+		var convertedPrice int
+		if err := rows.Scan(subscriptionFields(&sub.Subscription, &sub.OfferName, &sub.DurationMonths, &convertedPrice)...); err != nil {
+			logger.FromContext(ctx).Error("SubscriptionRepository.GetAllOverlappingPeriod scan error: ", err)
+			return nil, fmt.Errorf("SubscriptionRepository.GetAllOverlappingPeriod - scan error: %w", err)
+		}
+		sub.Price = convertedPrice
+		subs = append(subs, sub)
+	}
+	if err := rows.Err(); err != nil {
+		logger.FromContext(ctx).Error("SubscriptionRepository.GetAllOverlappingPeriod error: ", err)
+		if database.IsNoDataFound(err) {
+			return nil, ErrExchangeRateNotFound
+		}
+		return nil, fmt.Errorf("SubscriptionRepository.GetAllOverlappingPeriod - failed to get subscriptions: %w", err)
+	}
+	logger.FromContext(ctx).Infof("SubscriptionRepository.GetAllOverlappingPeriod success: count=%d", len(subs))
+	return subs, nil
+}
+
+func (r *Repository) GetPausesBySubscriptionIDs(ctx context.Context, subIDs []uuid.UUID) (_ []entity.SubscriptionPause, err error) {
+	ctx, span := tracing.Start(ctx, "SubscriptionRepository.GetPausesBySubscriptionIDs")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("SubscriptionRepository.GetPausesBySubscriptionIDs")()
+	logger.FromContext(ctx).Infof("SubscriptionRepository.GetPausesBySubscriptionIDs called: count=%d", len(subIDs))
+	if len(subIDs) == 0 {
+		return nil, nil
+	}
+
+	query, args, _ := r.Builder.
+		Select("id", "subscription_id", "paused_at", "resumed_at", "created_at").
+		From("subscription_pause").
+		Where(squirrel.Eq{"subscription_id": subIDs}).
+		OrderBy("subscription_id", "paused_at").
+		ToSql()
+
+	rows, err := r.GetTxManager(ctx).Query(ctx, query, args...)
+	if err != nil {
+		logger.FromContext(ctx).Error("SubscriptionRepository.GetPausesBySubscriptionIDs error: ", err)
+		return nil, fmt.Errorf("SubscriptionRepository.GetPausesBySubscriptionIDs - failed to get pauses: %w", err)
+	}
+	defer rows.Close()
+
+	var pauses []entity.SubscriptionPause
+	for rows.Next() {
+		var pause entity.SubscriptionPause
+		if err := rows.Scan(&pause.ID, &pause.SubscriptionID, &pause.PausedAt, &pause.ResumedAt, &pause.CreatedAt); err != nil {
+			logger.FromContext(ctx).Error("SubscriptionRepository.GetPausesBySubscriptionIDs scan error: ", err)
+			return nil, fmt.Errorf("SubscriptionRepository.GetPausesBySubscriptionIDs - scan error: %w", err)
+		}
+		pauses = append(pauses, pause)
+	}
+	logger.FromContext(ctx).Infof("SubscriptionRepository.GetPausesBySubscriptionIDs success: count=%d", len(pauses))
+	return pauses, nil
+}
@@ -3,5 +3,11 @@ package subscription_repo
 import "errors"
 
 var (
-	ErrSubscriptionNotFound = errors.New("subscription not found")
+	ErrSubscriptionNotFound             = errors.New("subscription not found")
+	ErrSubscriptionAlreadyPaused        = errors.New("subscription already paused")
+	ErrPauseNotFound                    = errors.New("open pause not found")
+	ErrSubscriptionAlreadyRenewed       = errors.New("subscription already renewed")
+	ErrUserAlreadyHasActiveSubscription = errors.New("subscription overlaps with another subscription of the user on the same service")
+	ErrExchangeRateNotFound             = errors.New("exchange rate not found")
+	ErrTrialAlreadyUsed                 = errors.New("user already had a trial on the service")
 )
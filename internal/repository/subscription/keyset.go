@@ -0,0 +1,204 @@
+package subscription_repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/4udiwe/subscription-service/internal/database"
+	"github.com/4udiwe/subscription-service/internal/entity"
+	"github.com/4udiwe/subscription-service/internal/metrics"
+	"github.com/4udiwe/subscription-service/pkg/cursor"
+	"github.com/4udiwe/subscription-service/pkg/logger"
+	"github.com/4udiwe/subscription-service/pkg/tracing"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+)
+
+// GetAllAfter returns up to limit subscriptions that come after the cursor, newest first.
+// next is nil on the last page.
+func (r *Repository) GetAllAfter(
+	ctx context.Context,
+	status *entity.SubscriptionStatus,
+	after *cursor.Cursor,
+	limit int,
+) (subs []entity.SubscriptionFullInfo, next *cursor.Cursor, err error) {
+	ctx, span := tracing.Start(ctx, "SubscriptionRepository.GetAllAfter")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("SubscriptionRepository.GetAllAfter")()
+	logger.FromContext(ctx).Infof("SubscriptionRepository.GetAllAfter called: status=%v, after=%v, limit=%d", status, after, limit)
+
+	builder := r.Builder.
+		Select(subscriptionColumns("o.name", "op.price", "op.currency")...).
+		From("subscription s").
+		Join("offer o ON s.offer_id = o.id").
+		Join("offer_price op ON s.price_id = op.id")
+
+	if status != nil {
+		builder = builder.Where("s.status = ?", *status)
+	}
+
+	subs, next, err = r.getPage(ctx, builder, after, limit)
+	if err != nil {
+		logger.FromContext(ctx).Error("SubscriptionRepository.GetAllAfter error: ", err)
+		return nil, nil, fmt.Errorf("SubscriptionRepository.GetAllAfter - failed to get subscriptions: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("SubscriptionRepository.GetAllAfter success: count=%d", len(subs))
+	return subs, next, nil
+}
+
+// GetAllByUserIDAfter returns up to limit subscriptions of the user that come after the cursor,
+// newest first. next is nil on the last page.
+func (r *Repository) GetAllByUserIDAfter(
+	ctx context.Context,
+	userID uuid.UUID,
+	status *entity.SubscriptionStatus,
+	after *cursor.Cursor,
+	limit int,
+) (subs []entity.SubscriptionFullInfo, next *cursor.Cursor, err error) {
+	ctx, span := tracing.Start(ctx, "SubscriptionRepository.GetAllByUserIDAfter")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("SubscriptionRepository.GetAllByUserIDAfter")()
+	logger.FromContext(ctx).Infof("SubscriptionRepository.GetAllByUserIDAfter called: userID=%s, status=%v, after=%v, limit=%d", userID, status, after, limit)
+
+	builder := r.Builder.
+		Select(subscriptionColumns("o.name", "op.price", "op.currency")...).
+		From("subscription s").
+		Join("offer o ON s.offer_id = o.id").
+		Join("offer_price op ON s.price_id = op.id").
+		Where("s.user_id = ?", userID)
+
+	if status != nil {
+		builder = builder.Where("s.status = ?", *status)
+	}
+
+	subs, next, err = r.getPage(ctx, builder, after, limit)
+	if err != nil {
+		logger.FromContext(ctx).Error("SubscriptionRepository.GetAllByUserIDAfter error: ", err)
+		return nil, nil, fmt.Errorf("SubscriptionRepository.GetAllByUserIDAfter - failed to get subscriptions: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("SubscriptionRepository.GetAllByUserIDAfter success: count=%d", len(subs))
+	return subs, next, nil
+}
+
+// GetAllByUserIDAndSubscriptionNameAfter is the keyset variant of GetAllByUserIDAndSubscriptionName.
+// The total price covers all matching subscriptions, not only the returned page, and is converted
+// to currency at the rate for the start date of each subscription. As in GetAllByUserIDAndSubscriptionName,
+// pauses do not reduce the total.
+func (r *Repository) GetAllByUserIDAndSubscriptionNameAfter(
+	ctx context.Context,
+	userID uuid.UUID,
+	subscriptionName string,
+	status *entity.SubscriptionStatus,
+	startPeriod *time.Time,
+	endPeriod *time.Time,
+	currency string,
+	after *cursor.Cursor,
+	limit int,
+) (subs []entity.SubscriptionFullInfo, totalPrice int, next *cursor.Cursor, err error) {
+	ctx, span := tracing.Start(ctx, "SubscriptionRepository.GetAllByUserIDAndSubscriptionNameAfter")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("SubscriptionRepository.GetAllByUserIDAndSubscriptionNameAfter")()
+	logger.FromContext(ctx).Infof("SubscriptionRepository.GetAllByUserIDAndSubscriptionNameAfter called: userID=%s, subscriptionName=%s, status=%v, startDate=%v, endDate=%v, currency=%s, after=%v, limit=%d", userID, subscriptionName, status, startPeriod, endPeriod, currency, after, limit)
+
+	filter := squirrel.And{
+		squirrel.Eq{"s.user_id": userID},
+		squirrel.Eq{"o.name": subscriptionName},
+	}
+	if status != nil {
+		filter = append(filter, squirrel.Eq{"s.status": *status})
+	}
+	if startPeriod != nil {
+		filter = append(filter, squirrel.GtOrEq{"s.start_date": *startPeriod})
+	}
+	if endPeriod != nil {
+		filter = append(filter, squirrel.LtOrEq{"s.start_date": *endPeriod})
+	}
+
+	builder := r.Builder.
+		Select(subscriptionColumns("o.name", "op.price", "op.currency")...).
+		From("subscription s").
+		Join("offer o ON s.offer_id = o.id").
+		Join("offer_price op ON s.price_id = op.id").
+		Where(filter)
+
+	subs, next, err = r.getPage(ctx, builder, after, limit)
+	if err != nil {
+		logger.FromContext(ctx).Error("SubscriptionRepository.GetAllByUserIDAndSubscriptionNameAfter error: ", err)
+		return nil, 0, nil, fmt.Errorf("SubscriptionRepository.GetAllByUserIDAndSubscriptionNameAfter - failed to get subscriptions: %w", err)
+	}
+
+	priceQuery, priceArgs, _ := r.Builder.
+		Select().
+		Column(squirrel.Expr("COALESCE(ROUND(SUM(convert_price("+paidPrice+", op.currency, ?, s.start_date))), 0)::int", currency)).
+		From("subscription s").
+		Join("offer o ON s.offer_id = o.id").
+		Join("offer_price op ON s.price_id = op.id").
+		Where(filter).
+		ToSql()
+
+	err = r.GetTxManager(ctx).QueryRow(ctx, priceQuery, priceArgs...).Scan(&totalPrice)
+	if err != nil {
+		logger.FromContext(ctx).Error("SubscriptionRepository.GetAllByUserIDAndSubscriptionNameAfter total price error: ", err)
+		if database.IsNoDataFound(err) {
+			return nil, 0, nil, ErrExchangeRateNotFound
+		}
+		return nil, 0, nil, fmt.Errorf("SubscriptionRepository.GetAllByUserIDAndSubscriptionNameAfter - failed to get total price: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("SubscriptionRepository.GetAllByUserIDAndSubscriptionNameAfter success: count=%d", len(subs))
+	return subs, totalPrice, next, nil
+}
+
+// getPage runs the query ordered by (created_at, id) descending, starting right after the cursor.
+// One extra row is fetched to find out whether there is a next page.
+func (r *Repository) getPage(
+	ctx context.Context,
+	builder squirrel.SelectBuilder,
+	after *cursor.Cursor,
+	limit int,
+) ([]entity.SubscriptionFullInfo, *cursor.Cursor, error) {
+	if after != nil {
+		builder = builder.Where("(s.created_at, s.id) < (?, ?)", after.CreatedAt, after.ID)
+	}
+
+	query, args, _ := builder.
+		OrderBy("s.created_at DESC", "s.id DESC").
+		Limit(uint64(limit + 1)).
+		ToSql()
+
+	rows, err := r.GetTxManager(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	var subs []entity.SubscriptionFullInfo
+	for rows.Next() {
+		var sub entity.SubscriptionFullInfo
+		if err := rows.Scan(subscriptionFields(&sub.Subscription, &sub.OfferName, &sub.ListPrice, &sub.Currency)...); err != nil {
+			return nil, nil, err
+		}
+		subs = append(subs, sub)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	subs, next := trimPage(subs, limit)
+	return subs, next, nil
+}
+
+// trimPage cuts the rows fetched with limit+1 down to the page. The extra row only shows that
+// there is a next page, the cursor points at the last row of this one.
+func trimPage(subs []entity.SubscriptionFullInfo, limit int) ([]entity.SubscriptionFullInfo, *cursor.Cursor) {
+	if len(subs) <= limit {
+		return subs, nil
+	}
+
+	subs = subs[:limit]
+	last := subs[limit-1]
+	return subs, cursor.New(last.CreatedAt, last.ID)
+}
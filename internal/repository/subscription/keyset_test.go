@@ -0,0 +1,58 @@
+package subscription_repo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/4udiwe/subscription-service/internal/entity"
+	"github.com/google/uuid"
+)
+
+func TestTrimPage(t *testing.T) {
+	// rows as getPage fetches them: created_at descending
+	rows := func(n int) []entity.SubscriptionFullInfo {
+		subs := make([]entity.SubscriptionFullInfo, n)
+		for i := range subs {
+			subs[i].ID = uuid.New()
+			subs[i].CreatedAt = time.Date(2025, 9, 21, 0, 0, 0, 0, time.UTC).Add(-time.Duration(i) * time.Hour)
+		}
+		return subs
+	}
+
+	tests := []struct {
+		name     string
+		fetched  int
+		limit    int
+		wantLen  int
+		wantNext bool
+	}{
+		{name: "no rows", fetched: 0, limit: 10, wantLen: 0, wantNext: false},
+		{name: "fewer than limit", fetched: 3, limit: 10, wantLen: 3, wantNext: false},
+		{name: "exactly limit", fetched: 10, limit: 10, wantLen: 10, wantNext: false},
+		{name: "one extra row", fetched: 11, limit: 10, wantLen: 10, wantNext: true},
+		{name: "limit of one", fetched: 2, limit: 1, wantLen: 1, wantNext: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fetched := rows(tt.fetched)
+
+			page, next := trimPage(fetched, tt.limit)
+			if len(page) != tt.wantLen {
+				t.Fatalf("page has %d rows, want %d", len(page), tt.wantLen)
+			}
+			if (next != nil) != tt.wantNext {
+				t.Fatalf("next cursor = %v, want one: %v", next, tt.wantNext)
+			}
+			if next == nil {
+				return
+			}
+
+			// the cursor points at the last row of the page, not at the extra one
+			last := page[len(page)-1]
+			if next.ID != last.ID || !next.CreatedAt.Equal(last.CreatedAt) {
+				t.Errorf("next cursor = %+v, want the last row of the page %s", *next, last.ID)
+			}
+		})
+	}
+}
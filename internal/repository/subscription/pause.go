@@ -0,0 +1,142 @@
+package subscription_repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/4udiwe/subscription-service/internal/database"
+	"github.com/4udiwe/subscription-service/internal/entity"
+	"github.com/4udiwe/subscription-service/internal/metrics"
+	"github.com/4udiwe/subscription-service/pkg/logger"
+	"github.com/4udiwe/subscription-service/pkg/tracing"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// SetStatusAndEndDate changes the status and the end date of the subscription.
+func (r *Repository) SetStatusAndEndDate(
+	ctx context.Context,
+	id uuid.UUID,
+	status entity.SubscriptionStatus,
+	endDate time.Time,
+) (_ entity.Subscription, err error) {
+	ctx, span := tracing.Start(ctx, "SubscriptionRepository.SetStatusAndEndDate")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("SubscriptionRepository.SetStatusAndEndDate")()
+	logger.FromContext(ctx).Infof("SubscriptionRepository.SetStatusAndEndDate called: id=%s, status=%s, endDate=%v", id, status, endDate)
+	query, args, _ := r.Builder.
+		Update("subscription s").
+		Set("status", status).
+		Set("end_date", endDate).
+		Set("updated_at", squirrel.Expr("now()")).
+		Where("s.id = ?", id).
+		Suffix("RETURNING " + strings.Join(subscriptionColumns(), ", ")).
+		ToSql()
+
+	var sub entity.Subscription
+	err = r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(subscriptionFields(&sub)...)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.Subscription{}, ErrSubscriptionNotFound
+		}
+		logger.FromContext(ctx).Error("SubscriptionRepository.SetStatusAndEndDate error: ", err)
+		if database.IsExclusionViolation(err) {
+			return entity.Subscription{}, ErrUserAlreadyHasActiveSubscription
+		}
+		return entity.Subscription{}, fmt.Errorf("SubscriptionRepository.SetStatusAndEndDate - failed to update subscription: %w", err)
+	}
+	logger.FromContext(ctx).Infof("SubscriptionRepository.SetStatusAndEndDate success: id=%s", sub.ID)
+	return sub, nil
+}
+
+func (r *Repository) CreatePause(ctx context.Context, subID uuid.UUID, pausedAt time.Time) (_ entity.SubscriptionPause, err error) {
+	ctx, span := tracing.Start(ctx, "SubscriptionRepository.CreatePause")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("SubscriptionRepository.CreatePause")()
+	logger.FromContext(ctx).Infof("SubscriptionRepository.CreatePause called: subID=%s, pausedAt=%v", subID, pausedAt)
+	query, args, _ := r.Builder.
+		Insert("subscription_pause").
+		Columns("subscription_id", "paused_at").
+		Values(subID, pausedAt).
+		Suffix("RETURNING id, created_at").
+		ToSql()
+
+	pause := entity.SubscriptionPause{
+		SubscriptionID: subID,
+		PausedAt:       pausedAt,
+	}
+	err = r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(&pause.ID, &pause.CreatedAt)
+	if err != nil {
+		logger.FromContext(ctx).Error("SubscriptionRepository.CreatePause error: ", err)
+		if database.IsUniqueViolation(err) {
+			return entity.SubscriptionPause{}, ErrSubscriptionAlreadyPaused
+		}
+		return entity.SubscriptionPause{}, fmt.Errorf("SubscriptionRepository.CreatePause - failed to create pause: %w", err)
+	}
+	logger.FromContext(ctx).Infof("SubscriptionRepository.CreatePause success: id=%s", pause.ID)
+	return pause, nil
+}
+
+// CloseOpenPause sets the resume date of the open pause of the subscription and returns it.
+func (r *Repository) CloseOpenPause(ctx context.Context, subID uuid.UUID, resumedAt time.Time) (_ entity.SubscriptionPause, err error) {
+	ctx, span := tracing.Start(ctx, "SubscriptionRepository.CloseOpenPause")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("SubscriptionRepository.CloseOpenPause")()
+	logger.FromContext(ctx).Infof("SubscriptionRepository.CloseOpenPause called: subID=%s, resumedAt=%v", subID, resumedAt)
+	query, args, _ := r.Builder.
+		Update("subscription_pause").
+		Set("resumed_at", resumedAt).
+		Where("subscription_id = ? AND resumed_at IS NULL", subID).
+		Suffix("RETURNING id, subscription_id, paused_at, resumed_at, created_at").
+		ToSql()
+
+	var pause entity.SubscriptionPause
+	err = r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(
+		&pause.ID, &pause.SubscriptionID, &pause.PausedAt, &pause.ResumedAt, &pause.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.SubscriptionPause{}, ErrPauseNotFound
+		}
+		logger.FromContext(ctx).Error("SubscriptionRepository.CloseOpenPause error: ", err)
+		return entity.SubscriptionPause{}, fmt.Errorf("SubscriptionRepository.CloseOpenPause - failed to close pause: %w", err)
+	}
+	logger.FromContext(ctx).Infof("SubscriptionRepository.CloseOpenPause success: id=%s", pause.ID)
+	return pause, nil
+}
+
+func (r *Repository) GetPauses(ctx context.Context, subID uuid.UUID) (_ []entity.SubscriptionPause, err error) {
+	ctx, span := tracing.Start(ctx, "SubscriptionRepository.GetPauses")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("SubscriptionRepository.GetPauses")()
+	logger.FromContext(ctx).Infof("SubscriptionRepository.GetPauses called: subID=%s", subID)
+	query, args, _ := r.Builder.
+		Select("id", "subscription_id", "paused_at", "resumed_at", "created_at").
+		From("subscription_pause").
+		Where("subscription_id = ?", subID).
+		OrderBy("paused_at").
+		ToSql()
+
+	rows, err := r.GetTxManager(ctx).Query(ctx, query, args...)
+	if err != nil {
+		logger.FromContext(ctx).Error("SubscriptionRepository.GetPauses error: ", err)
+		return nil, fmt.Errorf("SubscriptionRepository.GetPauses - failed to get pauses: %w", err)
+	}
+	defer rows.Close()
+
+	var pauses []entity.SubscriptionPause
+	for rows.Next() {
+		var pause entity.SubscriptionPause
+		if err := rows.Scan(&pause.ID, &pause.SubscriptionID, &pause.PausedAt, &pause.ResumedAt, &pause.CreatedAt); err != nil {
+			logger.FromContext(ctx).Error("SubscriptionRepository.GetPauses scan error: ", err)
+			return nil, fmt.Errorf("SubscriptionRepository.GetPauses - scan error: %w", err)
+		}
+		pauses = append(pauses, pause)
+	}
+	logger.FromContext(ctx).Infof("SubscriptionRepository.GetPauses success: count=%d", len(pauses))
+	return pauses, nil
+}
@@ -2,13 +2,20 @@ package subscription_repo
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/4udiwe/subscription-service/internal/database"
 	"github.com/4udiwe/subscription-service/internal/entity"
+	"github.com/4udiwe/subscription-service/internal/metrics"
+	"github.com/4udiwe/subscription-service/pkg/logger"
 	"github.com/4udiwe/subscription-service/pkg/postgres"
+	"github.com/4udiwe/subscription-service/pkg/tracing"
+	"github.com/Masterminds/squirrel"
 	"github.com/google/uuid"
-	"github.com/sirupsen/logrus"
+	"github.com/jackc/pgx/v5"
 )
 
 type Repository struct {
@@ -19,141 +26,526 @@ func New(postgres *postgres.Postgres) *Repository {
 	return &Repository{postgres}
 }
 
-func (r *Repository) Create(ctx context.Context, userID, offerID uuid.UUID, startDate, endDate time.Time) (entity.Subscription, error) {
-	logrus.Infof("SubscriptionRepository.Create called: userID=%s, offerID=%s", userID, offerID)
+// Create inserts a new subscription. A non-nil trialEndDate starts the subscription with a free
+// trial, the unique index allows one trial per user and service, a second one returns ErrTrialAlreadyUsed.
+// price is what the user pays, priceID is the offer price version it was bought at and promoCodeID
+// is the promo code it was discounted with, if any.
+func (r *Repository) Create(
+	ctx context.Context,
+	userID, offerID uuid.UUID,
+	startDate, endDate time.Time,
+	trialEndDate *time.Time,
+	price int,
+	priceID uuid.UUID,
+	promoCodeID *uuid.UUID,
+	autoRenew bool,
+) (_ entity.Subscription, err error) {
+	ctx, span := tracing.Start(ctx, "SubscriptionRepository.Create")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("SubscriptionRepository.Create")()
+	logger.FromContext(ctx).Infof("SubscriptionRepository.Create called: userID=%s, offerID=%s, trialEndDate=%v, price=%d, priceID=%s, promoCodeID=%v, autoRenew=%t", userID, offerID, trialEndDate, price, priceID, promoCodeID, autoRenew)
 	query, args, _ := r.Builder.
 		Insert("subscription").
-		Columns("user_id", "offer_id", "start_date", "end_date").
-		Values(userID, offerID, startDate, endDate).
-		Suffix("RETURNING id, created_at, updated_at").
+		Columns("user_id", "offer_id", "start_date", "end_date", "trial_end_date", "price", "price_id", "promo_code_id", "auto_renew").
+		Values(userID, offerID, startDate, endDate, trialEndDate, price, priceID, promoCodeID, autoRenew).
+		Suffix("RETURNING id, status, created_at, updated_at").
 		ToSql()
 
 	sub := entity.Subscription{
-		UserID:    userID,
-		OfferID:   offerID,
-		StartDate: startDate,
-		EndDate:   endDate,
+		UserID:       userID,
+		OfferID:      offerID,
+		StartDate:    startDate,
+		EndDate:      endDate,
+		Price:        price,
+		PriceID:      priceID,
+		PromoCodeID:  promoCodeID,
+		TrialEndDate: trialEndDate,
+		AutoRenew:    autoRenew,
 	}
-	err := r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(
-		&sub.ID, &sub.CreatedAt, &sub.UpdatedAt,
+	err = r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(
+		&sub.ID, &sub.Status, &sub.CreatedAt, &sub.UpdatedAt,
 	)
-	logrus.Debugf("Scanned values: ID=%s, CreatedAt=%s, UpdatedAt=%s", sub.ID.String(), sub.CreatedAt.String(), sub.UpdatedAt.String())
+	logger.FromContext(ctx).Debugf("Scanned values: ID=%s, CreatedAt=%s, UpdatedAt=%s", sub.ID.String(), sub.CreatedAt.String(), sub.UpdatedAt.String())
 	if err != nil {
-		logrus.Error("SubscriptionRepository.Create error: ", err)
+		logger.FromContext(ctx).Error("SubscriptionRepository.Create error: ", err)
+		if database.IsExclusionViolation(err) {
+			return entity.Subscription{}, ErrUserAlreadyHasActiveSubscription
+		}
+		if database.IsUniqueViolation(err) {
+			return entity.Subscription{}, ErrTrialAlreadyUsed
+		}
 		return entity.Subscription{}, fmt.Errorf("SubscriptionRepository.Create - failed to create subscription: %w", err)
 	}
-	logrus.Infof("SubscriptionRepository.Create success: id=%s", sub.ID.String())
+	logger.FromContext(ctx).Infof("SubscriptionRepository.Create success: id=%s", sub.ID.String())
 	return sub, nil
 }
 
-func (r *Repository) GetAll(ctx context.Context, limit int, offset int) (subs []entity.SubscriptionFullInfo, total int, err error) {
-	logrus.Info("SubscriptionRepository.GetAll called")
+// CreateRenewal inserts the next period of the given subscription at the given price version. Every
+// subscription can be renewed only once, a repeated call returns ErrSubscriptionAlreadyRenewed.
+func (r *Repository) CreateRenewal(ctx context.Context, prev entity.Subscription, startDate, endDate time.Time, price int, priceID uuid.UUID) (_ entity.Subscription, err error) {
+	ctx, span := tracing.Start(ctx, "SubscriptionRepository.CreateRenewal")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("SubscriptionRepository.CreateRenewal")()
+	logger.FromContext(ctx).Infof("SubscriptionRepository.CreateRenewal called: prevID=%s, startDate=%v, endDate=%v, price=%d, priceID=%s", prev.ID, startDate, endDate, price, priceID)
+	query, args, _ := r.Builder.
+		Insert("subscription").
+		Columns("user_id", "offer_id", "start_date", "end_date", "price", "price_id", "auto_renew", "renewed_from_id").
+		Values(prev.UserID, prev.OfferID, startDate, endDate, price, priceID, true, prev.ID).
+		Suffix("ON CONFLICT (renewed_from_id) DO NOTHING RETURNING id, status, created_at, updated_at").
+		ToSql()
 
-	// base query
+	sub := entity.Subscription{
+		UserID:        prev.UserID,
+		OfferID:       prev.OfferID,
+		StartDate:     startDate,
+		EndDate:       endDate,
+		Price:         price,
+		PriceID:       priceID,
+		AutoRenew:     true,
+		RenewedFromID: &prev.ID,
+	}
+	err = r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(
+		&sub.ID, &sub.Status, &sub.CreatedAt, &sub.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.Subscription{}, ErrSubscriptionAlreadyRenewed
+		}
+		logger.FromContext(ctx).Error("SubscriptionRepository.CreateRenewal error: ", err)
+		if database.IsExclusionViolation(err) {
+			return entity.Subscription{}, ErrUserAlreadyHasActiveSubscription
+		}
+		return entity.Subscription{}, fmt.Errorf("SubscriptionRepository.CreateRenewal - failed to create subscription: %w", err)
+	}
+	logger.FromContext(ctx).Infof("SubscriptionRepository.CreateRenewal success: id=%s", sub.ID)
+	return sub, nil
+}
+
+// GetRenewable returns auto-renewable subscriptions that end not later than until
+// and have not been renewed yet. Subscriptions whose renewal has failed come last, the longest
+// failed first, so they do not take the whole batch on every run.
+func (r *Repository) GetRenewable(ctx context.Context, until time.Time, limit int) (_ []entity.Subscription, err error) {
+	ctx, span := tracing.Start(ctx, "SubscriptionRepository.GetRenewable")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("SubscriptionRepository.GetRenewable")()
+	logger.FromContext(ctx).Infof("SubscriptionRepository.GetRenewable called: until=%v, limit=%d", until, limit)
 	query, args, _ := r.Builder.
-		Select("s.id", "s.user_id", "s.offer_id", "s.start_date", "s.end_date", "s.created_at", "s.updated_at", "o.name", "o.price").
+		Select(subscriptionColumns()...).
 		From("subscription s").
-		Join("offer o ON s.offer_id = o.id").
-		OrderBy("s.created_at DESC").
+		Where("s.auto_renew").
+		Where("s.status = ?", entity.SubscriptionStatusActive).
+		Where("s.end_date IS NOT NULL AND s.end_date <= ?", until).
+		Where("NOT EXISTS (SELECT 1 FROM subscription n WHERE n.renewed_from_id = s.id)").
+		OrderBy("s.renewal_failed_at NULLS FIRST", "s.end_date").
+		Limit(uint64(limit)).
+		ToSql()
+
+	rows, err := r.GetTxManager(ctx).Query(ctx, query, args...)
+	if err != nil {
+		logger.FromContext(ctx).Error("SubscriptionRepository.GetRenewable error: ", err)
+		return nil, fmt.Errorf("SubscriptionRepository.GetRenewable - failed to get subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	var subs []entity.Subscription
+	for rows.Next() {
+		var sub entity.Subscription
+		if err := rows.Scan(subscriptionFields(&sub)...); err != nil {
+			logger.FromContext(ctx).Error("SubscriptionRepository.GetRenewable scan error: ", err)
+			return nil, fmt.Errorf("SubscriptionRepository.GetRenewable - scan error: %w", err)
+		}
+		subs = append(subs, sub)
+	}
+	logger.FromContext(ctx).Infof("SubscriptionRepository.GetRenewable success: count=%d", len(subs))
+	return subs, nil
+}
+
+// MarkExpiringNotified picks up to limit active subscriptions without auto-renewal that end
+// after from and not later than until and have not been notified about this end date yet,
+// records the notice and returns them. Rows locked by another transaction are skipped.
+func (r *Repository) MarkExpiringNotified(ctx context.Context, from, until time.Time, limit int) (_ []entity.Subscription, err error) {
+	ctx, span := tracing.Start(ctx, "SubscriptionRepository.MarkExpiringNotified")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("SubscriptionRepository.MarkExpiringNotified")()
+	logger.FromContext(ctx).Infof("SubscriptionRepository.MarkExpiringNotified called: from=%v, until=%v, limit=%d", from, until, limit)
+
+	// the subquery keeps the default placeholders, the outer builder numbers them all
+	expiring := squirrel.
+		Select("id").
+		From("subscription").
+		Where("NOT auto_renew").
+		Where("status = ?", entity.SubscriptionStatusActive).
+		Where("end_date > ? AND end_date <= ?", from, until).
+		Where("expiring_notified_for IS DISTINCT FROM end_date").
+		OrderBy("end_date").
 		Limit(uint64(limit)).
-		Offset(uint64(offset)).
+		Suffix("FOR UPDATE SKIP LOCKED")
+
+	query, args, _ := r.Builder.
+		Update("subscription s").
+		Set("expiring_notified_for", squirrel.Expr("s.end_date")).
+		Where(squirrel.Expr("s.id IN (?)", expiring)).
+		Suffix("RETURNING " + strings.Join(subscriptionColumns(), ", ")).
+		ToSql()
+
+	rows, err := r.GetTxManager(ctx).Query(ctx, query, args...)
+	if err != nil {
+		logger.FromContext(ctx).Error("SubscriptionRepository.MarkExpiringNotified error: ", err)
+		return nil, fmt.Errorf("SubscriptionRepository.MarkExpiringNotified - failed to update subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	var subs []entity.Subscription
+	for rows.Next() {
+		var sub entity.Subscription
+		if err := rows.Scan(subscriptionFields(&sub)...); err != nil {
+			logger.FromContext(ctx).Error("SubscriptionRepository.MarkExpiringNotified scan error: ", err)
+			return nil, fmt.Errorf("SubscriptionRepository.MarkExpiringNotified - scan error: %w", err)
+		}
+		subs = append(subs, sub)
+	}
+	if err := rows.Err(); err != nil {
+		logger.FromContext(ctx).Error("SubscriptionRepository.MarkExpiringNotified error: ", err)
+		return nil, fmt.Errorf("SubscriptionRepository.MarkExpiringNotified - failed to update subscriptions: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("SubscriptionRepository.MarkExpiringNotified success: count=%d", len(subs))
+	return subs, nil
+}
+
+// MarkRenewalFailed records a failed renewal attempt of the subscription and its reason.
+func (r *Repository) MarkRenewalFailed(ctx context.Context, id uuid.UUID, reason string) (err error) {
+	ctx, span := tracing.Start(ctx, "SubscriptionRepository.MarkRenewalFailed")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("SubscriptionRepository.MarkRenewalFailed")()
+	logger.FromContext(ctx).Infof("SubscriptionRepository.MarkRenewalFailed called: id=%s", id)
+	query, args, _ := r.Builder.
+		Update("subscription").
+		Set("renewal_failed_at", squirrel.Expr("now()")).
+		Set("renewal_error", reason).
+		Where("id = ?", id).
+		ToSql()
+
+	result, err := r.GetTxManager(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		logger.FromContext(ctx).Error("SubscriptionRepository.MarkRenewalFailed error: ", err)
+		return fmt.Errorf("SubscriptionRepository.MarkRenewalFailed - failed to update subscription: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrSubscriptionNotFound
+	}
+	logger.FromContext(ctx).Infof("SubscriptionRepository.MarkRenewalFailed success: id=%s", id)
+	return nil
+}
+
+func (r *Repository) DisableAutoRenew(ctx context.Context, id uuid.UUID) (err error) {
+	ctx, span := tracing.Start(ctx, "SubscriptionRepository.DisableAutoRenew")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("SubscriptionRepository.DisableAutoRenew")()
+	logger.FromContext(ctx).Infof("SubscriptionRepository.DisableAutoRenew called: id=%s", id)
+	query, args, _ := r.Builder.
+		Update("subscription").
+		Set("auto_renew", false).
+		Set("updated_at", squirrel.Expr("now()")).
+		Where("id = ?", id).
 		ToSql()
 
+	result, err := r.GetTxManager(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		logger.FromContext(ctx).Error("SubscriptionRepository.DisableAutoRenew error: ", err)
+		return fmt.Errorf("SubscriptionRepository.DisableAutoRenew - failed to update subscription: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrSubscriptionNotFound
+	}
+	logger.FromContext(ctx).Infof("SubscriptionRepository.DisableAutoRenew success: id=%s", id)
+	return nil
+}
+
+func (r *Repository) GetAll(
+	ctx context.Context,
+	status *entity.SubscriptionStatus,
+	limit int,
+	offset int,
+) (subs []entity.SubscriptionFullInfo, total int, err error) {
+	ctx, span := tracing.Start(ctx, "SubscriptionRepository.GetAll")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("SubscriptionRepository.GetAll")()
+	logger.FromContext(ctx).Infof("SubscriptionRepository.GetAll called: status=%v", status)
+
+	// base query
+	builder := r.Builder.
+		Select(subscriptionColumns("o.name", "op.price", "op.currency")...).
+		From("subscription s").
+		Join("offer o ON s.offer_id = o.id").
+		Join("offer_price op ON s.price_id = op.id").
+		OrderBy("s.created_at DESC", "s.id DESC").
+		Limit(uint64(limit)).
+		Offset(uint64(offset))
+
+	if status != nil {
+		builder = builder.Where("s.status = ?", *status)
+	}
+
+	query, args, _ := builder.ToSql()
+
 	rows, err := r.GetTxManager(ctx).Query(ctx, query, args...)
 	if err != nil {
-		logrus.Error("SubscriptionRepository.GetAll error: ", err)
+		logger.FromContext(ctx).Error("SubscriptionRepository.GetAll error: ", err)
 		return nil, 0, fmt.Errorf("SubscriptionRepository.GetAll - failed to get subscriptions: %w", err)
 	}
 	defer rows.Close()
 
 	for rows.Next() {
 		var sub entity.SubscriptionFullInfo
-		if err := rows.Scan(&sub.ID, &sub.UserID, &sub.OfferID, &sub.StartDate, &sub.EndDate, &sub.CreatedAt, &sub.UpdatedAt, &sub.OfferName, &sub.Price); err != nil {
-			logrus.Error("SubscriptionRepository.GetAll scan error: ", err)
+		if err := rows.Scan(subscriptionFields(&sub.Subscription, &sub.OfferName, &sub.ListPrice, &sub.Currency)...); err != nil {
+			logger.FromContext(ctx).Error("SubscriptionRepository.GetAll scan error: ", err)
 			return nil, 0, fmt.Errorf("SubscriptionRepository.GetAll - scan error: %w", err)
 		}
 		subs = append(subs, sub)
 	}
 
 	// Get total count for pagination
-	countQuery, countArgs, _ := r.Builder.
+	countBuilder := r.Builder.
 		Select("COUNT(*)").
-		From("subscription").
-		ToSql()
+		From("subscription s")
+
+	if status != nil {
+		countBuilder = countBuilder.Where("s.status = ?", *status)
+	}
+
+	countQuery, countArgs, _ := countBuilder.ToSql()
 
 	err = r.GetTxManager(ctx).QueryRow(ctx, countQuery, countArgs...).Scan(&total)
 	if err != nil {
-		logrus.Error("SubscriptionRepository.GetAll - failed to get total count: ", err)
+		logger.FromContext(ctx).Error("SubscriptionRepository.GetAll - failed to get total count: ", err)
 		return nil, 0, fmt.Errorf("SubscriptionRepository.GetAll - failed to get total count: %w", err)
 	}
 
-	logrus.Infof("SubscriptionRepository.GetAll success: count=%d", len(subs))
+	logger.FromContext(ctx).Infof("SubscriptionRepository.GetAll success: count=%d", len(subs))
 	return subs, total, nil
 }
 
-func (r *Repository) GetById(ctx context.Context, id uuid.UUID) (entity.Subscription, error) {
-	logrus.Infof("SubscriptionRepository.GetById called: id=%s", id)
+func (r *Repository) GetById(ctx context.Context, id uuid.UUID) (_ entity.Subscription, err error) {
+	ctx, span := tracing.Start(ctx, "SubscriptionRepository.GetById")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("SubscriptionRepository.GetById")()
+	logger.FromContext(ctx).Infof("SubscriptionRepository.GetById called: id=%s", id)
 	query, args, _ := r.Builder.
-		Select("id", "user_id", "offer_id", "start_date", "end_date", "created_at", "updated_at").
-		From("subscription").
-		Where("id = ?", id).
+		Select(subscriptionColumns()...).
+		From("subscription s").
+		Where("s.id = ?", id).
 		ToSql()
 
 	var sub entity.Subscription
-	err := r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(
-		&sub.ID, &sub.UserID, &sub.OfferID, &sub.StartDate, &sub.EndDate, &sub.CreatedAt, &sub.UpdatedAt,
-	)
+	err = r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(subscriptionFields(&sub)...)
 	if err != nil {
-		logrus.Error("SubscriptionRepository.GetById error: ", err)
-		return entity.Subscription{}, ErrSubscriptionNotFound
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.Subscription{}, ErrSubscriptionNotFound
+		}
+		logger.FromContext(ctx).Error("SubscriptionRepository.GetById error: ", err)
+		return entity.Subscription{}, fmt.Errorf("SubscriptionRepository.GetById - failed to get subscription: %w", err)
 	}
-	logrus.Infof("SubscriptionRepository.GetById success: id=%s", sub.ID)
+	logger.FromContext(ctx).Infof("SubscriptionRepository.GetById success: id=%s", sub.ID)
 	return sub, nil
 }
 
-func (r *Repository) Delete(ctx context.Context, id uuid.UUID) error {
-	logrus.Infof("SubscriptionRepository.Delete called: id=%s", id)
+func (r *Repository) Update(
+	ctx context.Context,
+	id, offerID uuid.UUID,
+	startDate, endDate time.Time,
+	trialEndDate *time.Time,
+	price int,
+	priceID uuid.UUID,
+	autoRenew bool,
+) (_ entity.Subscription, err error) {
+	ctx, span := tracing.Start(ctx, "SubscriptionRepository.Update")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("SubscriptionRepository.Update")()
+	logger.FromContext(ctx).Infof("SubscriptionRepository.Update called: id=%s, offerID=%s, startDate=%v, endDate=%v, trialEndDate=%v, price=%d, priceID=%s, autoRenew=%t", id, offerID, startDate, endDate, trialEndDate, price, priceID, autoRenew)
 	query, args, _ := r.Builder.
-		Delete("subscription").
-		Where("id = ?", id).
+		Update("subscription s").
+		Set("offer_id", offerID).
+		Set("start_date", startDate).
+		Set("end_date", endDate).
+		Set("trial_end_date", trialEndDate).
+		Set("price", price).
+		Set("price_id", priceID).
+		Set("auto_renew", autoRenew).
+		Set("updated_at", squirrel.Expr("now()")).
+		Where("s.id = ?", id).
+		Suffix("RETURNING " + strings.Join(subscriptionColumns(), ", ")).
 		ToSql()
 
-	result, err := r.GetTxManager(ctx).Exec(ctx, query, args...)
+	var sub entity.Subscription
+	err = r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(subscriptionFields(&sub)...)
 	if err != nil {
-		logrus.Error("SubscriptionRepository.Delete error: ", err)
-		return fmt.Errorf("SubscriptionRepository.Delete - failed to delete subscription: %w", err)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.Subscription{}, ErrSubscriptionNotFound
+		}
+		logger.FromContext(ctx).Error("SubscriptionRepository.Update error: ", err)
+		if database.IsExclusionViolation(err) {
+			return entity.Subscription{}, ErrUserAlreadyHasActiveSubscription
+		}
+		return entity.Subscription{}, fmt.Errorf("SubscriptionRepository.Update - failed to update subscription: %w", err)
 	}
-	rowsAffected := result.RowsAffected()
-	if rowsAffected == 0 {
-		logrus.Error("SubscriptionRepository.Delete error: no subscription found with id=", id)
-		return ErrSubscriptionNotFound
+	logger.FromContext(ctx).Infof("SubscriptionRepository.Update success: id=%s", sub.ID)
+	return sub, nil
+}
+
+// Cancel sets the status and end date of the subscription, records the cancellation time and
+// reason and turns off auto-renewal.
+func (r *Repository) Cancel(
+	ctx context.Context,
+	id uuid.UUID,
+	status entity.SubscriptionStatus,
+	endDate time.Time,
+	reason *string,
+) (_ entity.Subscription, err error) {
+	ctx, span := tracing.Start(ctx, "SubscriptionRepository.Cancel")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("SubscriptionRepository.Cancel")()
+	logger.FromContext(ctx).Infof("SubscriptionRepository.Cancel called: id=%s, status=%s, endDate=%v", id, status, endDate)
+	query, args, _ := r.Builder.
+		Update("subscription s").
+		Set("status", status).
+		Set("end_date", endDate).
+		Set("cancelled_at", squirrel.Expr("now()")).
+		Set("cancel_reason", reason).
+		Set("auto_renew", false).
+		Set("updated_at", squirrel.Expr("now()")).
+		Where("s.id = ?", id).
+		Suffix("RETURNING " + strings.Join(subscriptionColumns(), ", ")).
+		ToSql()
+
+	var sub entity.Subscription
+	err = r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(subscriptionFields(&sub)...)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.Subscription{}, ErrSubscriptionNotFound
+		}
+		logger.FromContext(ctx).Error("SubscriptionRepository.Cancel error: ", err)
+		return entity.Subscription{}, fmt.Errorf("SubscriptionRepository.Cancel - failed to cancel subscription: %w", err)
 	}
-	logrus.Infof("SubscriptionRepository.Delete success: id=%s", id)
-	return nil
+	logger.FromContext(ctx).Infof("SubscriptionRepository.Cancel success: id=%s", sub.ID)
+	return sub, nil
+}
+
+// GetPendingRenewal returns the renewal of the subscription prevID that is active and starts
+// after date, locking it until the end of the transaction. ErrSubscriptionNotFound is returned
+// when the subscription has no such renewal.
+func (r *Repository) GetPendingRenewal(ctx context.Context, prevID uuid.UUID, date time.Time) (_ entity.Subscription, err error) {
+	ctx, span := tracing.Start(ctx, "SubscriptionRepository.GetPendingRenewal")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("SubscriptionRepository.GetPendingRenewal")()
+	logger.FromContext(ctx).Infof("SubscriptionRepository.GetPendingRenewal called: prevID=%s, date=%v", prevID, date)
+	query, args, _ := r.Builder.
+		Select(subscriptionColumns()...).
+		From("subscription s").
+		Where("s.renewed_from_id = ?", prevID).
+		Where("s.status = ?", entity.SubscriptionStatusActive).
+		Where("s.cancelled_at IS NULL").
+		Where("s.start_date > ?", date).
+		Suffix("FOR UPDATE").
+		ToSql()
+
+	var sub entity.Subscription
+	err = r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(subscriptionFields(&sub)...)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.Subscription{}, ErrSubscriptionNotFound
+		}
+		logger.FromContext(ctx).Error("SubscriptionRepository.GetPendingRenewal error: ", err)
+		return entity.Subscription{}, fmt.Errorf("SubscriptionRepository.GetPendingRenewal - failed to get renewal: %w", err)
+	}
+	logger.FromContext(ctx).Infof("SubscriptionRepository.GetPendingRenewal success: id=%s", sub.ID)
+	return sub, nil
+}
+
+// ExpireEnded moves active subscriptions that ended not later than date to their final status:
+// cancelled if the cancellation was scheduled for the end of the period, expired otherwise.
+// Auto-renewable subscriptions that have not been renewed and end after renewableAfter are
+// skipped. The updated subscriptions are returned.
+func (r *Repository) ExpireEnded(ctx context.Context, date, renewableAfter time.Time) (_ []entity.Subscription, err error) {
+	ctx, span := tracing.Start(ctx, "SubscriptionRepository.ExpireEnded")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("SubscriptionRepository.ExpireEnded")()
+	logger.FromContext(ctx).Infof("SubscriptionRepository.ExpireEnded called: date=%v, renewableAfter=%v", date, renewableAfter)
+	query, args, _ := r.Builder.
+		Update("subscription s").
+		Set("status", squirrel.Expr("CASE WHEN s.cancelled_at IS NOT NULL THEN ? ELSE ? END", entity.SubscriptionStatusCancelled, entity.SubscriptionStatusExpired)).
+		Set("updated_at", squirrel.Expr("now()")).
+		Where("s.status = ?", entity.SubscriptionStatusActive).
+		Where("s.end_date IS NOT NULL AND s.end_date <= ?", date).
+		Where(squirrel.Or{
+			squirrel.Expr("NOT s.auto_renew"),
+			squirrel.Expr("s.end_date <= ?", renewableAfter),
+			squirrel.Expr("EXISTS (SELECT 1 FROM subscription n WHERE n.renewed_from_id = s.id)"),
+		}).
+		Suffix("RETURNING " + strings.Join(subscriptionColumns(), ", ")).
+		ToSql()
+
+	rows, err := r.GetTxManager(ctx).Query(ctx, query, args...)
+	if err != nil {
+		logger.FromContext(ctx).Error("SubscriptionRepository.ExpireEnded error: ", err)
+		return nil, fmt.Errorf("SubscriptionRepository.ExpireEnded - failed to expire subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	var subs []entity.Subscription
+	for rows.Next() {
+		var sub entity.Subscription
+		if err := rows.Scan(subscriptionFields(&sub)...); err != nil {
+			logger.FromContext(ctx).Error("SubscriptionRepository.ExpireEnded scan error: ", err)
+			return nil, fmt.Errorf("SubscriptionRepository.ExpireEnded - scan error: %w", err)
+		}
+		subs = append(subs, sub)
+	}
+	if err := rows.Err(); err != nil {
+		logger.FromContext(ctx).Error("SubscriptionRepository.ExpireEnded error: ", err)
+		return nil, fmt.Errorf("SubscriptionRepository.ExpireEnded - failed to expire subscriptions: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("SubscriptionRepository.ExpireEnded success: count=%d", len(subs))
+	return subs, nil
 }
 
+// GetAllByUserIDAndSubscriptionName returns a page of the user's subscriptions on the service and
+// the total price of all matching subscriptions converted to currency. The total takes the full
+// price of every subscription: a pause moves the end date by the paused days, so the paid days
+// and the price do not change. Only the trial days are left out.
 func (r *Repository) GetAllByUserIDAndSubscriptionName(
 	ctx context.Context,
 	userID uuid.UUID,
 	subscriptionName string,
+	status *entity.SubscriptionStatus,
 	startPeriod *time.Time,
 	endPeriod *time.Time,
+	currency string,
 	limit int,
 	offset int,
 ) (subs []entity.SubscriptionFullInfo, totalPrice int, totalCount int, err error) {
-	logrus.Infof("SubscriptionRepository.GetByUserIDAndSubscriptionName called: userID=%s, subscriptionName=%s, startDate=%v, endDate=%v", userID, subscriptionName, startPeriod, endPeriod)
+	ctx, span := tracing.Start(ctx, "SubscriptionRepository.GetAllByUserIDAndSubscriptionName")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("SubscriptionRepository.GetAllByUserIDAndSubscriptionName")()
+	logger.FromContext(ctx).Infof("SubscriptionRepository.GetByUserIDAndSubscriptionName called: userID=%s, subscriptionName=%s, status=%v, startDate=%v, endDate=%v, currency=%s", userID, subscriptionName, status, startPeriod, endPeriod, currency)
 
-	// base query
+	// base query, the total price is converted to currency at the rate for the start date of each subscription
 	builder := r.Builder.
-		Select("s.id", "s.user_id", "s.offer_id", "s.start_date", "s.end_date", "s.created_at", "s.updated_at", "o.name", "o.price", "SUM(o.price) OVER() AS total_price").
+		Select(subscriptionColumns("o.name", "op.price", "op.currency")...).
+		Column(squirrel.Expr("ROUND(SUM(convert_price("+paidPrice+", op.currency, ?, s.start_date)) OVER())::int AS total_price", currency)).
 		From("subscription s").
 		Join("offer o ON s.offer_id = o.id").
+		Join("offer_price op ON s.price_id = op.id").
 		Where("s.user_id = ?", userID).
 		Where("o.name = ?", subscriptionName).
-		OrderBy("s.created_at DESC").
+		OrderBy("s.created_at DESC", "s.id DESC").
 		Limit(uint64(limit)).
 		Offset(uint64(offset))
 
+	if status != nil {
+		builder = builder.Where("s.status = ?", *status)
+	}
 	if startPeriod != nil {
 		builder = builder.Where("s.start_date >= ?", *startPeriod)
 	}
@@ -165,19 +557,26 @@ func (r *Repository) GetAllByUserIDAndSubscriptionName(
 
 	rows, err := r.GetTxManager(ctx).Query(ctx, query, args...)
 	if err != nil {
-		logrus.Error("SubscriptionRepository.GetByUserIDAndSubscriptionName error: ", err)
+		logger.FromContext(ctx).Error("SubscriptionRepository.GetByUserIDAndSubscriptionName error: ", err)
 		return nil, 0, 0, fmt.Errorf("SubscriptionRepository.GetByUserIDAndSubscriptionName - failed to get subscriptions: %w", err)
 	}
 	defer rows.Close()
 
 	for rows.Next() {
 		var sub entity.SubscriptionFullInfo
-		if err := rows.Scan(&sub.ID, &sub.UserID, &sub.OfferID, &sub.StartDate, &sub.EndDate, &sub.CreatedAt, &sub.UpdatedAt, &sub.OfferName, &sub.Price, &totalPrice); err != nil {
-			logrus.Error("SubscriptionRepository.GetByUserIDAndSubscriptionName scan error: ", err)
+		if err := rows.Scan(subscriptionFields(&sub.Subscription, &sub.OfferName, &sub.ListPrice, &sub.Currency, &totalPrice)...); err != nil {
+			logger.FromContext(ctx).Error("SubscriptionRepository.GetByUserIDAndSubscriptionName scan error: ", err)
 			return nil, 0, 0, fmt.Errorf("SubscriptionRepository.GetByUserIDAndSubscriptionName - scan error: %w", err)
 		}
 		subs = append(subs, sub)
 	}
+	if err := rows.Err(); err != nil {
+		logger.FromContext(ctx).Error("SubscriptionRepository.GetByUserIDAndSubscriptionName error: ", err)
+		if database.IsNoDataFound(err) {
+			return nil, 0, 0, ErrExchangeRateNotFound
+		}
+		return nil, 0, 0, fmt.Errorf("SubscriptionRepository.GetByUserIDAndSubscriptionName - failed to get subscriptions: %w", err)
+	}
 
 	// Get total count for pagination
 	countBuilder := r.Builder.
@@ -187,6 +586,9 @@ func (r *Repository) GetAllByUserIDAndSubscriptionName(
 		Where("s.user_id = ?", userID).
 		Where("o.name = ?", subscriptionName)
 
+	if status != nil {
+		countBuilder = countBuilder.Where("s.status = ?", *status)
+	}
 	if startPeriod != nil {
 		countBuilder = countBuilder.Where("s.start_date >= ?", *startPeriod)
 	}
@@ -197,26 +599,28 @@ func (r *Repository) GetAllByUserIDAndSubscriptionName(
 	countQuery, countArgs, _ := countBuilder.ToSql()
 	err = r.GetTxManager(ctx).QueryRow(ctx, countQuery, countArgs...).Scan(&totalCount)
 	if err != nil {
-		logrus.Error("SubscriptionRepository.GetByUserIDAndSubscriptionName count error: ", err)
+		logger.FromContext(ctx).Error("SubscriptionRepository.GetByUserIDAndSubscriptionName count error: ", err)
 		return nil, 0, 0, fmt.Errorf("SubscriptionRepository.GetByUserIDAndSubscriptionName - failed to count subscriptions: %w", err)
 	}
 
-	logrus.Infof("SubscriptionRepository.GetByUserIDAndSubscriptionName success: count=%d", len(subs))
+	logger.FromContext(ctx).Infof("SubscriptionRepository.GetByUserIDAndSubscriptionName success: count=%d", len(subs))
 	return subs, totalPrice, totalCount, nil
 }
 
-func (r *Repository) GetAllByOfferID(ctx context.Context, offerID uuid.UUID) ([]entity.Subscription, error) {
-	logrus.Infof("SubscriptionRepository.GetAllByOfferID called: offerID=%s", offerID)
+func (r *Repository) GetAllByOfferID(ctx context.Context, offerID uuid.UUID) (_ []entity.Subscription, err error) {
+	ctx, span := tracing.Start(ctx, "SubscriptionRepository.GetAllByOfferID")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("SubscriptionRepository.GetAllByOfferID")()
+	logger.FromContext(ctx).Infof("SubscriptionRepository.GetAllByOfferID called: offerID=%s", offerID)
 	query, args, _ := r.Builder.
-		Select("s.id", "s.user_id", "s.offer_id", "s.start_date", "s.end_date", "s.created_at", "s.updated_at").
+		Select(subscriptionColumns()...).
 		From("subscription s").
-		Join("offer o ON s.offer_id = o.id").
 		Where("s.offer_id = ?", offerID).
 		ToSql()
 
 	rows, err := r.GetTxManager(ctx).Query(ctx, query, args...)
 	if err != nil {
-		logrus.Error("SubscriptionRepository.GetAllByOfferID error: ", err)
+		logger.FromContext(ctx).Error("SubscriptionRepository.GetAllByOfferID error: ", err)
 		return nil, fmt.Errorf("SubscriptionRepository.GetAllByOfferID - failed to get subscriptions: %w", err)
 	}
 	defer rows.Close()
@@ -224,81 +628,224 @@ func (r *Repository) GetAllByOfferID(ctx context.Context, offerID uuid.UUID) ([]
 	var subs []entity.Subscription
 	for rows.Next() {
 		var sub entity.Subscription
-		if err := rows.Scan(&sub.ID, &sub.UserID, &sub.OfferID, &sub.StartDate, &sub.EndDate, &sub.CreatedAt, &sub.UpdatedAt); err != nil {
-			logrus.Error("SubscriptionRepository.GetAllByOfferID scan error: ", err)
+		if err := rows.Scan(subscriptionFields(&sub)...); err != nil {
+			logger.FromContext(ctx).Error("SubscriptionRepository.GetAllByOfferID scan error: ", err)
 			return nil, fmt.Errorf("SubscriptionRepository.GetAllByOfferID - scan error: %w", err)
 		}
 		subs = append(subs, sub)
 	}
-	logrus.Infof("SubscriptionRepository.GetAllByOfferID success: count=%d", len(subs))
+	logger.FromContext(ctx).Infof("SubscriptionRepository.GetAllByOfferID success: count=%d", len(subs))
 	return subs, nil
 }
 
-func (r *Repository) GetAllByUserID(ctx context.Context, userID uuid.UUID, limit int, offset int) (subs []entity.SubscriptionFullInfo, total int, err error) {
-	logrus.Infof("SubscriptionRepository.GetAllByUserID called: userID=%s", userID)
+// GetActiveByOfferID returns the active and paused subscriptions of the offer that are neither
+// cancelled nor renewed yet, the latest period of every subscriber.
+func (r *Repository) GetActiveByOfferID(ctx context.Context, offerID uuid.UUID) (_ []entity.Subscription, err error) {
+	ctx, span := tracing.Start(ctx, "SubscriptionRepository.GetActiveByOfferID")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("SubscriptionRepository.GetActiveByOfferID")()
+	logger.FromContext(ctx).Infof("SubscriptionRepository.GetActiveByOfferID called: offerID=%s", offerID)
+	query, args, _ := r.Builder.
+		Select(subscriptionColumns()...).
+		From("subscription s").
+		Where("s.offer_id = ?", offerID).
+		Where(squirrel.Eq{"s.status": []entity.SubscriptionStatus{entity.SubscriptionStatusActive, entity.SubscriptionStatusPaused}}).
+		Where("s.cancelled_at IS NULL").
+		Where("NOT EXISTS (SELECT 1 FROM subscription n WHERE n.renewed_from_id = s.id)").
+		OrderBy("s.created_at", "s.id").
+		ToSql()
+
+	rows, err := r.GetTxManager(ctx).Query(ctx, query, args...)
+	if err != nil {
+		logger.FromContext(ctx).Error("SubscriptionRepository.GetActiveByOfferID error: ", err)
+		return nil, fmt.Errorf("SubscriptionRepository.GetActiveByOfferID - failed to get subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	var subs []entity.Subscription
+	for rows.Next() {
+		var sub entity.Subscription
+		if err := rows.Scan(subscriptionFields(&sub)...); err != nil {
+			logger.FromContext(ctx).Error("SubscriptionRepository.GetActiveByOfferID scan error: ", err)
+			return nil, fmt.Errorf("SubscriptionRepository.GetActiveByOfferID - scan error: %w", err)
+		}
+		subs = append(subs, sub)
+	}
+	logger.FromContext(ctx).Infof("SubscriptionRepository.GetActiveByOfferID success: count=%d", len(subs))
+	return subs, nil
+}
+
+func (r *Repository) GetAllByUserID(
+	ctx context.Context,
+	userID uuid.UUID,
+	status *entity.SubscriptionStatus,
+	limit int,
+	offset int,
+) (subs []entity.SubscriptionFullInfo, total int, err error) {
+	ctx, span := tracing.Start(ctx, "SubscriptionRepository.GetAllByUserID")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("SubscriptionRepository.GetAllByUserID")()
+	logger.FromContext(ctx).Infof("SubscriptionRepository.GetAllByUserID called: userID=%s, status=%v", userID, status)
 
 	// base query
-	query, args, _ := r.Builder.
-		Select("s.id", "s.user_id", "s.offer_id", "s.start_date", "s.end_date", "s.created_at", "s.updated_at", "o.name", "o.price").
+	builder := r.Builder.
+		Select(subscriptionColumns("o.name", "op.price", "op.currency")...).
 		From("subscription s").
 		Join("offer o ON s.offer_id = o.id").
+		Join("offer_price op ON s.price_id = op.id").
 		Where("s.user_id = ?", userID).
-		OrderBy("s.created_at DESC").
+		OrderBy("s.created_at DESC", "s.id DESC").
 		Limit(uint64(limit)).
-		Offset(uint64(offset)).
-		ToSql()
+		Offset(uint64(offset))
+
+	if status != nil {
+		builder = builder.Where("s.status = ?", *status)
+	}
+
+	query, args, _ := builder.ToSql()
 
 	rows, err := r.GetTxManager(ctx).Query(ctx, query, args...)
 	if err != nil {
-		logrus.Error("SubscriptionRepository.GetAllByUserID error: ", err)
+		logger.FromContext(ctx).Error("SubscriptionRepository.GetAllByUserID error: ", err)
 		return nil, 0, fmt.Errorf("SubscriptionRepository.GetAllByUserID - failed to get subscriptions: %w", err)
 	}
 	defer rows.Close()
 
 	for rows.Next() {
 		var sub entity.SubscriptionFullInfo
-		if err := rows.Scan(&sub.ID, &sub.UserID, &sub.OfferID, &sub.StartDate, &sub.EndDate, &sub.CreatedAt, &sub.UpdatedAt, &sub.OfferName, &sub.Price); err != nil {
-			logrus.Error("SubscriptionRepository.GetAllByUserID scan error: ", err)
+		if err := rows.Scan(subscriptionFields(&sub.Subscription, &sub.OfferName, &sub.ListPrice, &sub.Currency)...); err != nil {
+			logger.FromContext(ctx).Error("SubscriptionRepository.GetAllByUserID scan error: ", err)
 			return nil, 0, fmt.Errorf("SubscriptionRepository.GetAllByUserID - scan error: %w", err)
 		}
 		subs = append(subs, sub)
 	}
 
 	// Get total count for pagination
-	countQuery, countArgs, _ := r.Builder.
+	countBuilder := r.Builder.
 		Select("COUNT(*)").
-		From("subscription").
-		Where("user_id = ?", userID).
-		ToSql()
+		From("subscription s").
+		Where("s.user_id = ?", userID)
+
+	if status != nil {
+		countBuilder = countBuilder.Where("s.status = ?", *status)
+	}
+
+	countQuery, countArgs, _ := countBuilder.ToSql()
 
 	err = r.GetTxManager(ctx).QueryRow(ctx, countQuery, countArgs...).Scan(&total)
 	if err != nil {
-		logrus.Error("SubscriptionRepository.GetAllByUserID count error: ", err)
+		logger.FromContext(ctx).Error("SubscriptionRepository.GetAllByUserID count error: ", err)
 		return nil, 0, fmt.Errorf("SubscriptionRepository.GetAllByUserID - failed to count subscriptions: %w", err)
 	}
 
-	logrus.Infof("SubscriptionRepository.GetAllByUserID success: count=%d", len(subs))
+	logger.FromContext(ctx).Infof("SubscriptionRepository.GetAllByUserID success: count=%d", len(subs))
 	return subs, total, nil
 }
 
-func (r *Repository) HasActiveSubscriptionOnServiceForDate(ctx context.Context, userID uuid.UUID, serviceName string, date time.Time) (bool, error) {
-	logrus.Infof("SubscriptionRepository.HasActiveSubscriptionOnServiceForDate called: userID=%s, serviceName=%s, onDate=%s", userID, serviceName, date)
+// HasActiveSubscriptionOnServiceForPeriod reports whether the user has a subscription on the
+// service that overlaps [start, end), the same check as the subscription_no_overlap constraint.
+// A paused subscription covers every date after its start, because it is extended on resume.
+// Subscriptions listed in exclude are not taken into account.
+func (r *Repository) HasActiveSubscriptionOnServiceForPeriod(
+	ctx context.Context,
+	userID uuid.UUID,
+	serviceName string,
+	start time.Time,
+	end time.Time,
+	exclude ...uuid.UUID,
+) (_ bool, err error) {
+	ctx, span := tracing.Start(ctx, "SubscriptionRepository.HasActiveSubscriptionOnServiceForPeriod")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("SubscriptionRepository.HasActiveSubscriptionOnServiceForPeriod")()
+	logger.FromContext(ctx).Infof("SubscriptionRepository.HasActiveSubscriptionOnServiceForPeriod called: userID=%s, serviceName=%s, start=%s, end=%s", userID, serviceName, start, end)
 
 	var count int
-	query, args, _ := r.Builder.
+	builder := r.Builder.
 		Select("COUNT(*)").
 		From("subscription s").
 		Join("offer o ON s.offer_id = o.id").
 		Where("s.user_id = ?", userID).
 		Where("o.name = ?", serviceName).
-		Where("s.start_date <= ? AND s.end_date > ?", date, date).
+		Where("daterange(s.start_date, CASE WHEN s.status = ? THEN NULL ELSE s.end_date END, '[)') && daterange(?::date, ?::date, '[)')",
+			entity.SubscriptionStatusPaused, start, end)
+
+	if len(exclude) > 0 {
+		builder = builder.Where(squirrel.NotEq{"s.id": exclude})
+	}
+
+	query, args, _ := builder.ToSql()
+
+	err = r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(&count)
+	if err != nil {
+		logger.FromContext(ctx).Error("SubscriptionRepository.HasActiveSubscriptionOnServiceForPeriod error: ", err)
+		return false, fmt.Errorf("SubscriptionRepository.HasActiveSubscriptionOnServiceForPeriod - failed to check active subscription: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// HasUsedTrial reports whether the user has ever had a trial on the service.
+func (r *Repository) HasUsedTrial(ctx context.Context, userID uuid.UUID, serviceName string) (_ bool, err error) {
+	ctx, span := tracing.Start(ctx, "SubscriptionRepository.HasUsedTrial")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("SubscriptionRepository.HasUsedTrial")()
+	logger.FromContext(ctx).Infof("SubscriptionRepository.HasUsedTrial called: userID=%s, serviceName=%s", userID, serviceName)
+
+	var count int
+	query, args, _ := r.Builder.
+		Select("COUNT(*)").
+		From("subscription s").
+		Where("s.user_id = ?", userID).
+		Where("s.service_name = ?", serviceName).
+		Where("s.trial_end_date IS NOT NULL").
 		ToSql()
 
-	err := r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(&count)
+	err = r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(&count)
 	if err != nil {
-		logrus.Error("SubscriptionRepository.HasActiveSubscriptionOnServiceForDate error: ", err)
-		return false, fmt.Errorf("SubscriptionRepository.HasActiveSubscriptionOnServiceForDate - failed to check active subscription: %w", err)
+		logger.FromContext(ctx).Error("SubscriptionRepository.HasUsedTrial error: ", err)
+		return false, fmt.Errorf("SubscriptionRepository.HasUsedTrial - failed to check trial: %w", err)
 	}
 
 	return count > 0, nil
 }
+
+// CountActiveByService returns the number of active subscriptions of every service that has them.
+func (r *Repository) CountActiveByService(ctx context.Context) (_ map[string]int, err error) {
+	ctx, span := tracing.Start(ctx, "SubscriptionRepository.CountActiveByService")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("SubscriptionRepository.CountActiveByService")()
+	logger.FromContext(ctx).Debug("SubscriptionRepository.CountActiveByService called")
+
+	query, args, _ := r.Builder.
+		Select("o.name", "COUNT(*)").
+		From("subscription s").
+		Join("offer o ON s.offer_id = o.id").
+		Where(squirrel.Eq{"s.status": entity.SubscriptionStatusActive}).
+		GroupBy("o.name").
+		ToSql()
+
+	rows, err := r.GetTxManager(ctx).Query(ctx, query, args...)
+	if err != nil {
+		logger.FromContext(ctx).Error("SubscriptionRepository.CountActiveByService error: ", err)
+		return nil, fmt.Errorf("SubscriptionRepository.CountActiveByService - failed to count subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var (
+			serviceName string
+			count       int
+		)
+		if err := rows.Scan(&serviceName, &count); err != nil {
+			logger.FromContext(ctx).Error("SubscriptionRepository.CountActiveByService scan error: ", err)
+			return nil, fmt.Errorf("SubscriptionRepository.CountActiveByService - scan error: %w", err)
+		}
+		counts[serviceName] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("SubscriptionRepository.CountActiveByService - rows error: %w", err)
+	}
+
+	return counts, nil
+}
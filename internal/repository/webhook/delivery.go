@@ -0,0 +1,259 @@
+package webhook_repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/4udiwe/subscription-service/internal/entity"
+	"github.com/4udiwe/subscription-service/internal/metrics"
+	"github.com/4udiwe/subscription-service/pkg/logger"
+	"github.com/4udiwe/subscription-service/pkg/tracing"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// deliveryColumns returns the delivery columns (table aliased as "d") in the order expected by
+// deliveryFields, followed by extra columns.
+func deliveryColumns(extra ...string) []string {
+	return append([]string{
+		"d.id", "d.endpoint_id", "d.event_id", "d.event_type", "d.payload", "d.status", "d.attempts",
+		"d.next_attempt_at", "d.last_status_code", "d.last_error", "d.created_at", "d.updated_at", "d.delivered_at",
+	}, extra...)
+}
+
+// deliveryFields returns scan destinations matching deliveryColumns, followed by extra destinations.
+func deliveryFields(d *entity.WebhookDelivery, extra ...any) []any {
+	return append([]any{
+		&d.ID, &d.EndpointID, &d.EventID, &d.EventType, &d.Payload, &d.Status, &d.Attempts,
+		&d.NextAttemptAt, &d.LastStatusCode, &d.LastError, &d.CreatedAt, &d.UpdatedAt, &d.DeliveredAt,
+	}, extra...)
+}
+
+// CreateDeliveries schedules the event for every endpoint subscribed to its type. An event that
+// has already been scheduled for an endpoint is skipped, so the call can be repeated.
+func (r *Repository) CreateDeliveries(ctx context.Context, eventID int64, eventType string, payload []byte) (_ int64, err error) {
+	ctx, span := tracing.Start(ctx, "WebhookRepository.CreateDeliveries")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("WebhookRepository.CreateDeliveries")()
+	logger.FromContext(ctx).Debugf("WebhookRepository.CreateDeliveries called: eventID=%d, eventType=%s", eventID, eventType)
+
+	query, args, _ := r.Builder.
+		Insert("webhook_delivery").
+		Columns("endpoint_id", "event_id", "event_type", "payload").
+		Select(r.Builder.
+			Select("e.id").
+			Column("?::BIGINT", eventID).
+			Column("?::TEXT", eventType).
+			Column("?::JSONB", string(payload)).
+			From("webhook_endpoint e").
+			Where("(cardinality(e.event_types) = 0 OR ? = ANY(e.event_types))", eventType)).
+		Suffix("ON CONFLICT (endpoint_id, event_id) DO NOTHING").
+		ToSql()
+
+	result, err := r.GetTxManager(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		logger.FromContext(ctx).Error("WebhookRepository.CreateDeliveries error: ", err)
+		return 0, fmt.Errorf("WebhookRepository.CreateDeliveries - failed to schedule deliveries: %w", err)
+	}
+
+	logger.FromContext(ctx).Debugf("WebhookRepository.CreateDeliveries success: count=%d", result.RowsAffected())
+	return result.RowsAffected(), nil
+}
+
+// ClaimDue takes up to limit pending deliveries whose time has come, oldest first, and moves
+// their next attempt to leaseUntil. Until then no other worker picks them up; if the worker
+// dies before recording the result, the delivery is retried after the lease.
+func (r *Repository) ClaimDue(ctx context.Context, limit int, leaseUntil time.Time) (_ []entity.WebhookDeliveryTarget, err error) {
+	ctx, span := tracing.Start(ctx, "WebhookRepository.ClaimDue")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("WebhookRepository.ClaimDue")()
+	logger.FromContext(ctx).Debugf("WebhookRepository.ClaimDue called: limit=%d, leaseUntil=%v", limit, leaseUntil)
+
+	// the subquery keeps the default placeholders, the outer builder numbers them all
+	due := squirrel.
+		Select("id").
+		From("webhook_delivery").
+		Where("status = ?", entity.WebhookDeliveryStatusPending).
+		Where("next_attempt_at <= now()").
+		OrderBy("next_attempt_at").
+		Limit(uint64(limit)).
+		Suffix("FOR UPDATE SKIP LOCKED")
+
+	query, args, _ := r.Builder.
+		Update("webhook_delivery d").
+		Set("next_attempt_at", leaseUntil).
+		From("webhook_endpoint e").
+		Where("e.id = d.endpoint_id").
+		Where(squirrel.Expr("d.id IN (?)", due)).
+		Suffix("RETURNING " + strings.Join(deliveryColumns("e.url", "e.secret"), ", ")).
+		ToSql()
+
+	rows, err := r.GetTxManager(ctx).Query(ctx, query, args...)
+	if err != nil {
+		logger.FromContext(ctx).Error("WebhookRepository.ClaimDue error: ", err)
+		return nil, fmt.Errorf("WebhookRepository.ClaimDue - failed to claim deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	var targets []entity.WebhookDeliveryTarget
+	for rows.Next() {
+		var target entity.WebhookDeliveryTarget
+		if err := rows.Scan(deliveryFields(&target.WebhookDelivery, &target.URL, &target.Secret)...); err != nil {
+			logger.FromContext(ctx).Error("WebhookRepository.ClaimDue scan error: ", err)
+			return nil, fmt.Errorf("WebhookRepository.ClaimDue - scan error: %w", err)
+		}
+		targets = append(targets, target)
+	}
+	if err := rows.Err(); err != nil {
+		logger.FromContext(ctx).Error("WebhookRepository.ClaimDue error: ", err)
+		return nil, fmt.Errorf("WebhookRepository.ClaimDue - failed to claim deliveries: %w", err)
+	}
+
+	logger.FromContext(ctx).Debugf("WebhookRepository.ClaimDue success: count=%d", len(targets))
+	return targets, nil
+}
+
+func (r *Repository) MarkDelivered(ctx context.Context, id uuid.UUID, statusCode int) (err error) {
+	ctx, span := tracing.Start(ctx, "WebhookRepository.MarkDelivered")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("WebhookRepository.MarkDelivered")()
+	query, args, _ := r.Builder.
+		Update("webhook_delivery").
+		Set("status", entity.WebhookDeliveryStatusDelivered).
+		Set("attempts", squirrel.Expr("attempts + 1")).
+		Set("last_status_code", statusCode).
+		Set("last_error", nil).
+		Set("delivered_at", squirrel.Expr("now()")).
+		Set("updated_at", squirrel.Expr("now()")).
+		Where("id = ?", id).
+		ToSql()
+
+	if _, err := r.GetTxManager(ctx).Exec(ctx, query, args...); err != nil {
+		logger.FromContext(ctx).Error("WebhookRepository.MarkDelivered error: ", err)
+		return fmt.Errorf("WebhookRepository.MarkDelivered - failed to update delivery: %w", err)
+	}
+	return nil
+}
+
+// MarkFailed records a failed attempt. The delivery is retried at nextAttemptAt, or moved to the
+// dead status when dead is true.
+func (r *Repository) MarkFailed(ctx context.Context, id uuid.UUID, statusCode *int, reason string, nextAttemptAt time.Time, dead bool) (err error) {
+	ctx, span := tracing.Start(ctx, "WebhookRepository.MarkFailed")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("WebhookRepository.MarkFailed")()
+	status := entity.WebhookDeliveryStatusPending
+	if dead {
+		status = entity.WebhookDeliveryStatusDead
+	}
+
+	query, args, _ := r.Builder.
+		Update("webhook_delivery").
+		Set("status", status).
+		Set("attempts", squirrel.Expr("attempts + 1")).
+		Set("next_attempt_at", nextAttemptAt).
+		Set("last_status_code", statusCode).
+		Set("last_error", reason).
+		Set("updated_at", squirrel.Expr("now()")).
+		Where("id = ?", id).
+		ToSql()
+
+	if _, err := r.GetTxManager(ctx).Exec(ctx, query, args...); err != nil {
+		logger.FromContext(ctx).Error("WebhookRepository.MarkFailed error: ", err)
+		return fmt.Errorf("WebhookRepository.MarkFailed - failed to update delivery: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) GetDeliveries(
+	ctx context.Context,
+	endpointID uuid.UUID,
+	status *entity.WebhookDeliveryStatus,
+	limit int,
+	offset int,
+) (deliveries []entity.WebhookDelivery, total int, err error) {
+	ctx, span := tracing.Start(ctx, "WebhookRepository.GetDeliveries")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("WebhookRepository.GetDeliveries")()
+	logger.FromContext(ctx).Infof("WebhookRepository.GetDeliveries called: endpointID=%s, status=%v", endpointID, status)
+
+	filter := squirrel.And{squirrel.Eq{"d.endpoint_id": endpointID}}
+	if status != nil {
+		filter = append(filter, squirrel.Eq{"d.status": *status})
+	}
+
+	query, args, _ := r.Builder.
+		Select(deliveryColumns()...).
+		From("webhook_delivery d").
+		Where(filter).
+		OrderBy("d.created_at DESC", "d.id DESC").
+		Limit(uint64(limit)).
+		Offset(uint64(offset)).
+		ToSql()
+
+	rows, err := r.GetTxManager(ctx).Query(ctx, query, args...)
+	if err != nil {
+		logger.FromContext(ctx).Error("WebhookRepository.GetDeliveries error: ", err)
+		return nil, 0, fmt.Errorf("WebhookRepository.GetDeliveries - failed to get deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var delivery entity.WebhookDelivery
+		if err := rows.Scan(deliveryFields(&delivery)...); err != nil {
+			logger.FromContext(ctx).Error("WebhookRepository.GetDeliveries scan error: ", err)
+			return nil, 0, fmt.Errorf("WebhookRepository.GetDeliveries - scan error: %w", err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	countQuery, countArgs, _ := r.Builder.
+		Select("COUNT(*)").
+		From("webhook_delivery d").
+		Where(filter).
+		ToSql()
+
+	err = r.GetTxManager(ctx).QueryRow(ctx, countQuery, countArgs...).Scan(&total)
+	if err != nil {
+		logger.FromContext(ctx).Error("WebhookRepository.GetDeliveries count error: ", err)
+		return nil, 0, fmt.Errorf("WebhookRepository.GetDeliveries - failed to get total count: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("WebhookRepository.GetDeliveries success: count=%d", len(deliveries))
+	return deliveries, total, nil
+}
+
+// Replay puts the delivery back to the queue with a fresh attempt budget, whatever its status.
+func (r *Repository) Replay(ctx context.Context, id uuid.UUID) (_ entity.WebhookDelivery, err error) {
+	ctx, span := tracing.Start(ctx, "WebhookRepository.Replay")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("WebhookRepository.Replay")()
+	logger.FromContext(ctx).Infof("WebhookRepository.Replay called: id=%s", id)
+
+	query, args, _ := r.Builder.
+		Update("webhook_delivery d").
+		Set("status", entity.WebhookDeliveryStatusPending).
+		Set("attempts", 0).
+		Set("next_attempt_at", squirrel.Expr("now()")).
+		Set("delivered_at", nil).
+		Set("updated_at", squirrel.Expr("now()")).
+		Where("d.id = ?", id).
+		Suffix("RETURNING " + strings.Join(deliveryColumns(), ", ")).
+		ToSql()
+
+	var delivery entity.WebhookDelivery
+	err = r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(deliveryFields(&delivery)...)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.WebhookDelivery{}, ErrDeliveryNotFound
+		}
+		logger.FromContext(ctx).Error("WebhookRepository.Replay error: ", err)
+		return entity.WebhookDelivery{}, fmt.Errorf("WebhookRepository.Replay - failed to replay delivery: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("WebhookRepository.Replay success: id=%s", delivery.ID)
+	return delivery, nil
+}
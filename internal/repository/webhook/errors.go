@@ -0,0 +1,8 @@
+package webhook_repo
+
+import "errors"
+
+var (
+	ErrEndpointNotFound = errors.New("webhook endpoint not found")
+	ErrDeliveryNotFound = errors.New("webhook delivery not found")
+)
@@ -0,0 +1,154 @@
+package webhook_repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/4udiwe/subscription-service/internal/entity"
+	"github.com/4udiwe/subscription-service/internal/metrics"
+	"github.com/4udiwe/subscription-service/pkg/logger"
+	"github.com/4udiwe/subscription-service/pkg/postgres"
+	"github.com/4udiwe/subscription-service/pkg/tracing"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type Repository struct {
+	*postgres.Postgres
+}
+
+func New(postgres *postgres.Postgres) *Repository {
+	return &Repository{postgres}
+}
+
+func (r *Repository) CreateEndpoint(ctx context.Context, url, secret string, eventTypes []string) (_ entity.WebhookEndpoint, err error) {
+	ctx, span := tracing.Start(ctx, "WebhookRepository.CreateEndpoint")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("WebhookRepository.CreateEndpoint")()
+	logger.FromContext(ctx).Infof("WebhookRepository.CreateEndpoint called: url=%s, eventTypes=%v", url, eventTypes)
+
+	if eventTypes == nil {
+		eventTypes = []string{}
+	}
+
+	query, args, _ := r.Builder.
+		Insert("webhook_endpoint").
+		Columns("url", "secret", "event_types").
+		Values(url, secret, eventTypes).
+		Suffix("RETURNING id, created_at, updated_at").
+		ToSql()
+
+	endpoint := entity.WebhookEndpoint{
+		URL:        url,
+		Secret:     secret,
+		EventTypes: eventTypes,
+	}
+
+	err = r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(&endpoint.ID, &endpoint.CreatedAt, &endpoint.UpdatedAt)
+	if err != nil {
+		logger.FromContext(ctx).Error("WebhookRepository.CreateEndpoint error: ", err)
+		return entity.WebhookEndpoint{}, fmt.Errorf("WebhookRepository.CreateEndpoint - failed to create endpoint: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("WebhookRepository.CreateEndpoint success: id=%s", endpoint.ID)
+	return endpoint, nil
+}
+
+func (r *Repository) GetEndpoints(ctx context.Context, limit int, offset int) (endpoints []entity.WebhookEndpoint, total int, err error) {
+	ctx, span := tracing.Start(ctx, "WebhookRepository.GetEndpoints")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("WebhookRepository.GetEndpoints")()
+	logger.FromContext(ctx).Info("WebhookRepository.GetEndpoints called")
+
+	query, args, _ := r.Builder.
+		Select("id", "url", "secret", "event_types", "created_at", "updated_at").
+		From("webhook_endpoint").
+		OrderBy("created_at DESC", "id DESC").
+		Limit(uint64(limit)).
+		Offset(uint64(offset)).
+		ToSql()
+
+	rows, err := r.GetTxManager(ctx).Query(ctx, query, args...)
+	if err != nil {
+		logger.FromContext(ctx).Error("WebhookRepository.GetEndpoints error: ", err)
+		return nil, 0, fmt.Errorf("WebhookRepository.GetEndpoints - failed to get endpoints: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var endpoint entity.WebhookEndpoint
+		if err := rows.Scan(&endpoint.ID, &endpoint.URL, &endpoint.Secret, &endpoint.EventTypes, &endpoint.CreatedAt, &endpoint.UpdatedAt); err != nil {
+			logger.FromContext(ctx).Error("WebhookRepository.GetEndpoints scan error: ", err)
+			return nil, 0, fmt.Errorf("WebhookRepository.GetEndpoints - scan error: %w", err)
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+
+	countQuery, countArgs, _ := r.Builder.
+		Select("COUNT(*)").
+		From("webhook_endpoint").
+		ToSql()
+
+	err = r.GetTxManager(ctx).QueryRow(ctx, countQuery, countArgs...).Scan(&total)
+	if err != nil {
+		logger.FromContext(ctx).Error("WebhookRepository.GetEndpoints count error: ", err)
+		return nil, 0, fmt.Errorf("WebhookRepository.GetEndpoints - failed to get total count: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("WebhookRepository.GetEndpoints success: count=%d", len(endpoints))
+	return endpoints, total, nil
+}
+
+func (r *Repository) GetEndpointByID(ctx context.Context, id uuid.UUID) (_ entity.WebhookEndpoint, err error) {
+	ctx, span := tracing.Start(ctx, "WebhookRepository.GetEndpointByID")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("WebhookRepository.GetEndpointByID")()
+	logger.FromContext(ctx).Infof("WebhookRepository.GetEndpointByID called: id=%s", id)
+
+	query, args, _ := r.Builder.
+		Select("id", "url", "secret", "event_types", "created_at", "updated_at").
+		From("webhook_endpoint").
+		Where("id = ?", id).
+		ToSql()
+
+	var endpoint entity.WebhookEndpoint
+	err = r.GetTxManager(ctx).QueryRow(ctx, query, args...).Scan(
+		&endpoint.ID, &endpoint.URL, &endpoint.Secret, &endpoint.EventTypes, &endpoint.CreatedAt, &endpoint.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.WebhookEndpoint{}, ErrEndpointNotFound
+		}
+		logger.FromContext(ctx).Error("WebhookRepository.GetEndpointByID error: ", err)
+		return entity.WebhookEndpoint{}, fmt.Errorf("WebhookRepository.GetEndpointByID - failed to get endpoint: %w", err)
+	}
+
+	logger.FromContext(ctx).Infof("WebhookRepository.GetEndpointByID success: id=%s", endpoint.ID)
+	return endpoint, nil
+}
+
+// DeleteEndpoint removes the endpoint together with its deliveries.
+func (r *Repository) DeleteEndpoint(ctx context.Context, id uuid.UUID) (err error) {
+	ctx, span := tracing.Start(ctx, "WebhookRepository.DeleteEndpoint")
+	defer tracing.End(span, &err)
+	defer metrics.ObserveQuery("WebhookRepository.DeleteEndpoint")()
+	logger.FromContext(ctx).Infof("WebhookRepository.DeleteEndpoint called: id=%s", id)
+
+	query, args, _ := r.Builder.
+		Delete("webhook_endpoint").
+		Where("id = ?", id).
+		ToSql()
+
+	result, err := r.GetTxManager(ctx).Exec(ctx, query, args...)
+	if err != nil {
+		logger.FromContext(ctx).Error("WebhookRepository.DeleteEndpoint error: ", err)
+		return fmt.Errorf("WebhookRepository.DeleteEndpoint - failed to delete endpoint: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrEndpointNotFound
+	}
+
+	logger.FromContext(ctx).Infof("WebhookRepository.DeleteEndpoint success: id=%s", id)
+	return nil
+}
@@ -0,0 +1,17 @@
+package api_key
+
+import (
+	"context"
+	"time"
+
+	"github.com/4udiwe/subscription-service/internal/entity"
+	"github.com/google/uuid"
+)
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, name, prefix, keyHash string, scopes []string) (entity.APIKey, error)
+	GetAll(ctx context.Context, limit int, offset int) (keys []entity.APIKey, total int, err error)
+	GetByHash(ctx context.Context, keyHash string) (entity.APIKey, error)
+	Revoke(ctx context.Context, id uuid.UUID) (entity.APIKey, error)
+	TouchLastUsed(ctx context.Context, id uuid.UUID, usedAt time.Time, since time.Time) error
+}
@@ -0,0 +1,16 @@
+package api_key
+
+import "errors"
+
+var (
+	ErrUnknownScope        = errors.New("unknown api key scope")
+	ErrAPIKeyNotFound      = errors.New("api key not found")
+	ErrAPIKeyAlreadyExists = errors.New("api key with this name already exists")
+	ErrInvalidAPIKey       = errors.New("invalid api key")
+
+	ErrCannotCreateAPIKey   = errors.New("cannot create api key")
+	ErrCannotFetchAPIKeys   = errors.New("cannot fetch api keys")
+	ErrCannotRevokeAPIKey   = errors.New("cannot revoke api key")
+	ErrCannotGenerateAPIKey = errors.New("cannot generate api key")
+	ErrCannotCheckAPIKey    = errors.New("cannot check api key")
+)
@@ -0,0 +1,145 @@
+package api_key
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/4udiwe/subscription-service/internal/entity"
+	api_key_repo "github.com/4udiwe/subscription-service/internal/repository/api_key"
+	"github.com/4udiwe/subscription-service/pkg/auth"
+	"github.com/4udiwe/subscription-service/pkg/logger"
+	"github.com/4udiwe/subscription-service/pkg/tracing"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+const (
+	keyPrefix  = "sk_"
+	keyBytes   = 32
+	prefixSize = len(keyPrefix) + 8
+
+	// last_used_at is written at most once per lastUsedPrecision for every key
+	lastUsedPrecision = time.Minute
+
+	// SubjectPrefix starts the subject of the identities authenticated with API keys.
+	SubjectPrefix = "api_key:"
+)
+
+type APIKeyService struct {
+	apiKeyRepository APIKeyRepository
+}
+
+func New(apiKeyRepository APIKeyRepository) *APIKeyService {
+	return &APIKeyService{
+		apiKeyRepository: apiKeyRepository,
+	}
+}
+
+// CreateAPIKey issues a key with the scopes. The key is returned only here, the service keeps its hash.
+func (s *APIKeyService) CreateAPIKey(ctx context.Context, name string, scopes []string) (_ entity.APIKey, _ string, err error) {
+	ctx, span := tracing.Start(ctx, "APIKeyService.CreateAPIKey")
+	defer tracing.End(span, &err)
+	logger.FromContext(ctx).Infof("APIKeyService.CreateAPIKey called: name=%s, scopes=%v", name, scopes)
+
+	for _, scope := range scopes {
+		if !lo.Contains(auth.Scopes, scope) {
+			return entity.APIKey{}, "", fmt.Errorf("%w: %s", ErrUnknownScope, scope)
+		}
+	}
+
+	key, err := generateKey()
+	if err != nil {
+		logger.FromContext(ctx).Errorf("APIKeyService.CreateAPIKey error generating key: %v", err)
+		return entity.APIKey{}, "", ErrCannotGenerateAPIKey
+	}
+
+	apiKey, err := s.apiKeyRepository.Create(ctx, name, key[:prefixSize], hashKey(key), lo.Uniq(scopes))
+	if err != nil {
+		if errors.Is(err, api_key_repo.ErrAPIKeyAlreadyExists) {
+			return entity.APIKey{}, "", ErrAPIKeyAlreadyExists
+		}
+		logger.FromContext(ctx).Errorf("APIKeyService.CreateAPIKey error: %v", err)
+		return entity.APIKey{}, "", ErrCannotCreateAPIKey
+	}
+
+	logger.FromContext(ctx).Infof("APIKeyService.CreateAPIKey success: id=%s", apiKey.ID)
+	return apiKey, key, nil
+}
+
+func (s *APIKeyService) GetAPIKeys(ctx context.Context, page int, pageSize int) (keys []entity.APIKey, total int, err error) {
+	ctx, span := tracing.Start(ctx, "APIKeyService.GetAPIKeys")
+	defer tracing.End(span, &err)
+	logger.FromContext(ctx).Info("APIKeyService.GetAPIKeys called")
+
+	limit := pageSize
+	offset := (page - 1) * pageSize
+
+	keys, total, err = s.apiKeyRepository.GetAll(ctx, limit, offset)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("APIKeyService.GetAPIKeys error: %v", err)
+		return nil, 0, ErrCannotFetchAPIKeys
+	}
+
+	logger.FromContext(ctx).Infof("APIKeyService.GetAPIKeys success: count=%d", len(keys))
+	return keys, total, nil
+}
+
+func (s *APIKeyService) RevokeAPIKey(ctx context.Context, id uuid.UUID) (err error) {
+	ctx, span := tracing.Start(ctx, "APIKeyService.RevokeAPIKey")
+	defer tracing.End(span, &err)
+	logger.FromContext(ctx).Infof("APIKeyService.RevokeAPIKey called: id=%s", id)
+
+	if _, err := s.apiKeyRepository.Revoke(ctx, id); err != nil {
+		if errors.Is(err, api_key_repo.ErrAPIKeyNotFound) {
+			return ErrAPIKeyNotFound
+		}
+		logger.FromContext(ctx).Errorf("APIKeyService.RevokeAPIKey error: %v", err)
+		return ErrCannotRevokeAPIKey
+	}
+
+	logger.FromContext(ctx).Infof("APIKeyService.RevokeAPIKey success: id=%s", id)
+	return nil
+}
+
+// Authenticate returns the identity of the caller holding the key and records that the key was used.
+func (s *APIKeyService) Authenticate(ctx context.Context, key string) (_ auth.Identity, err error) {
+	ctx, span := tracing.Start(ctx, "APIKeyService.Authenticate")
+	defer tracing.End(span, &err)
+	apiKey, err := s.apiKeyRepository.GetByHash(ctx, hashKey(key))
+	if err != nil {
+		if errors.Is(err, api_key_repo.ErrAPIKeyNotFound) {
+			return auth.Identity{}, ErrInvalidAPIKey
+		}
+		logger.FromContext(ctx).Errorf("APIKeyService.Authenticate error: %v", err)
+		return auth.Identity{}, ErrCannotCheckAPIKey
+	}
+
+	now := time.Now()
+	if err := s.apiKeyRepository.TouchLastUsed(ctx, apiKey.ID, now, now.Add(-lastUsedPrecision)); err != nil {
+		logger.FromContext(ctx).Warnf("APIKeyService.Authenticate error updating last used time: %v", err)
+	}
+
+	return auth.Identity{
+		Subject: SubjectPrefix + apiKey.Name,
+		Scopes:  apiKey.Scopes,
+		APIKey:  true,
+	}, nil
+}
+
+func generateKey() (string, error) {
+	buf := make([]byte, keyBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return keyPrefix + hex.EncodeToString(buf), nil
+}
+
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,22 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/4udiwe/subscription-service/internal/entity"
+	"github.com/google/uuid"
+)
+
+type AuditRepository interface {
+	GetAll(
+		ctx context.Context,
+		entityType *string,
+		entityID *uuid.UUID,
+		actor *string,
+		from *time.Time,
+		to *time.Time,
+		limit int,
+		offset int,
+	) (entries []entity.AuditEntry, total int, err error)
+}
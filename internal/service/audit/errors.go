@@ -0,0 +1,8 @@
+package audit
+
+import "errors"
+
+var (
+	ErrInvalidPeriod       = errors.New("to must be after from")
+	ErrCannotFetchAuditLog = errors.New("cannot fetch audit log")
+)
@@ -0,0 +1,52 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/4udiwe/subscription-service/internal/entity"
+	"github.com/4udiwe/subscription-service/pkg/logger"
+	"github.com/4udiwe/subscription-service/pkg/tracing"
+	"github.com/google/uuid"
+)
+
+type AuditService struct {
+	auditRepository AuditRepository
+}
+
+func New(auditRepository AuditRepository) *AuditService {
+	return &AuditService{auditRepository: auditRepository}
+}
+
+// GetAuditLog returns the audit entries matching the filters, newest first.
+// from is inclusive, to is exclusive.
+func (s *AuditService) GetAuditLog(
+	ctx context.Context,
+	entityType *string,
+	entityID *uuid.UUID,
+	actor *string,
+	from *time.Time,
+	to *time.Time,
+	page int,
+	pageSize int,
+) (entries []entity.AuditEntry, total int, err error) {
+	ctx, span := tracing.Start(ctx, "AuditService.GetAuditLog")
+	defer tracing.End(span, &err)
+	logger.FromContext(ctx).Info("AuditService.GetAuditLog called")
+
+	if from != nil && to != nil && !to.After(*from) {
+		return nil, 0, ErrInvalidPeriod
+	}
+
+	limit := pageSize
+	offset := (page - 1) * pageSize
+
+	entries, total, err = s.auditRepository.GetAll(ctx, entityType, entityID, actor, from, to, limit, offset)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("AuditService.GetAuditLog error: %v", err)
+		return nil, 0, ErrCannotFetchAuditLog
+	}
+
+	logger.FromContext(ctx).Infof("AuditService.GetAuditLog success: count=%d", len(entries))
+	return entries, total, nil
+}
@@ -0,0 +1,18 @@
+package exchange_rate
+
+import (
+	"context"
+
+	"github.com/4udiwe/subscription-service/internal/entity"
+)
+
+type ExchangeRateRepository interface {
+	Upsert(ctx context.Context, rates []entity.ExchangeRate) (int64, error)
+	GetAll(
+		ctx context.Context,
+		from *string,
+		to *string,
+		limit int,
+		offset int,
+	) (rates []entity.ExchangeRate, total int, err error)
+}
@@ -0,0 +1,9 @@
+package exchange_rate
+
+import "errors"
+
+var (
+	ErrInvalidExchangeRate = errors.New("rate must be positive and currencies must be different three-letter codes")
+	ErrCannotLoadRates     = errors.New("cannot load exchange rates")
+	ErrCannotFetchRates    = errors.New("cannot fetch exchange rates")
+)
@@ -0,0 +1,105 @@
+package exchange_rate
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/4udiwe/subscription-service/internal/entity"
+	exchange_rate_repo "github.com/4udiwe/subscription-service/internal/repository/exchange_rate"
+	"github.com/4udiwe/subscription-service/pkg/logger"
+	"github.com/4udiwe/subscription-service/pkg/tracing"
+	"github.com/4udiwe/subscription-service/pkg/transactor"
+)
+
+var currencyCode = regexp.MustCompile(`^[A-Z]{3}$`)
+
+type ExchangeRateService struct {
+	rateRepository ExchangeRateRepository
+	txManager      transactor.Transactor
+}
+
+func New(rateRepository ExchangeRateRepository, txManager transactor.Transactor) *ExchangeRateService {
+	return &ExchangeRateService{
+		rateRepository: rateRepository,
+		txManager:      txManager,
+	}
+}
+
+// LoadRates stores the rates in one transaction, so a batch is loaded either fully or not at all.
+// Rates already stored for the same pair and date are replaced.
+func (s *ExchangeRateService) LoadRates(ctx context.Context, rates []entity.ExchangeRate) (_ int, err error) {
+	ctx, span := tracing.Start(ctx, "ExchangeRateService.LoadRates")
+	defer tracing.End(span, &err)
+	logger.FromContext(ctx).Infof("ExchangeRateService.LoadRates called: count=%d", len(rates))
+
+	// the same pair and date can be given only once in a single upsert, the last one wins
+	unique := make([]entity.ExchangeRate, 0, len(rates))
+	seen := make(map[string]int)
+	for _, rate := range rates {
+		rate.From = strings.ToUpper(rate.From)
+		rate.To = strings.ToUpper(rate.To)
+		rate.Date = time.Date(rate.Date.Year(), rate.Date.Month(), rate.Date.Day(), 0, 0, 0, 0, time.UTC)
+
+		if !currencyCode.MatchString(rate.From) || !currencyCode.MatchString(rate.To) ||
+			rate.From == rate.To || rate.Rate <= 0 {
+			logger.FromContext(ctx).Errorf("ExchangeRateService.LoadRates error: invalid rate %+v", rate)
+			return 0, ErrInvalidExchangeRate
+		}
+
+		key := rate.From + rate.To + rate.Date.Format(time.DateOnly)
+		if i, ok := seen[key]; ok {
+			unique[i] = rate
+			continue
+		}
+		seen[key] = len(unique)
+		unique = append(unique, rate)
+	}
+
+	var loaded int64
+	err = s.txManager.WithinTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+		loaded, err = s.rateRepository.Upsert(txCtx, unique)
+		if err != nil {
+			if errors.Is(err, exchange_rate_repo.ErrInvalidExchangeRate) {
+				return ErrInvalidExchangeRate
+			}
+			logger.FromContext(ctx).Errorf("ExchangeRateService.LoadRates error: %v", err)
+			return ErrCannotLoadRates
+		}
+		return nil
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	logger.FromContext(ctx).Infof("ExchangeRateService.LoadRates success: count=%d", loaded)
+	return int(loaded), nil
+}
+
+func (s *ExchangeRateService) GetRates(
+	ctx context.Context,
+	from *string,
+	to *string,
+	page int,
+	pageSize int,
+) (rates []entity.ExchangeRate, total int, err error) {
+	ctx, span := tracing.Start(ctx, "ExchangeRateService.GetRates")
+	defer tracing.End(span, &err)
+	logger.FromContext(ctx).Infof("ExchangeRateService.GetRates called: from=%v, to=%v", from, to)
+
+	limit := pageSize
+	offset := (page - 1) * pageSize
+
+	rates, total, err = s.rateRepository.GetAll(ctx, from, to, limit, offset)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("ExchangeRateService.GetRates error: %v", err)
+		return nil, 0, ErrCannotFetchRates
+	}
+
+	logger.FromContext(ctx).Infof("ExchangeRateService.GetRates success: count=%d", len(rates))
+	return rates, total, nil
+}
@@ -0,0 +1,16 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+
+	"github.com/4udiwe/subscription-service/internal/entity"
+)
+
+type IdempotencyRepository interface {
+	Acquire(ctx context.Context, scope, key, requestHash string, expiresAt time.Time) (bool, error)
+	Get(ctx context.Context, scope, key string) (entity.IdempotencyKey, error)
+	Complete(ctx context.Context, scope, key string, statusCode int, contentType string, body []byte) error
+	Release(ctx context.Context, scope, key string) error
+	DeleteExpired(ctx context.Context, before time.Time, limit int) (int64, error)
+}
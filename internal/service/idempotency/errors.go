@@ -0,0 +1,13 @@
+package idempotency
+
+import "errors"
+
+var (
+	ErrRequestMismatch   = errors.New("idempotency key was used with a different request")
+	ErrRequestInProgress = errors.New("request with this idempotency key is in progress")
+
+	ErrCannotCheckKey      = errors.New("cannot check idempotency key")
+	ErrCannotStoreResponse = errors.New("cannot store idempotent response")
+	ErrCannotReleaseKey    = errors.New("cannot release idempotency key")
+	ErrCannotSweepKeys     = errors.New("cannot delete expired idempotency keys")
+)
@@ -0,0 +1,12 @@
+package idempotency
+
+import "time"
+
+type Option func(*IdempotencyService)
+
+// TTL sets how long a key and the response to its request are kept.
+func TTL(ttl time.Duration) Option {
+	return func(s *IdempotencyService) {
+		s.ttl = ttl
+	}
+}
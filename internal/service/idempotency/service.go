@@ -0,0 +1,129 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/4udiwe/subscription-service/internal/entity"
+	idempotency_repo "github.com/4udiwe/subscription-service/internal/repository/idempotency"
+	"github.com/4udiwe/subscription-service/pkg/logger"
+	"github.com/4udiwe/subscription-service/pkg/tracing"
+)
+
+const defaultTTL = 24 * time.Hour
+
+type IdempotencyService struct {
+	idempotencyRepository IdempotencyRepository
+
+	ttl time.Duration
+}
+
+func New(idempotencyRepository IdempotencyRepository, opts ...Option) *IdempotencyService {
+	s := &IdempotencyService{
+		idempotencyRepository: idempotencyRepository,
+		ttl:                   defaultTTL,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Begin claims the key of the caller for the request. It returns nil when the request is to be
+// handled, and the stored key with the response when the same request was already handled.
+func (s *IdempotencyService) Begin(ctx context.Context, scope, key, requestHash string) (_ *entity.IdempotencyKey, err error) {
+	ctx, span := tracing.Start(ctx, "IdempotencyService.Begin")
+	defer tracing.End(span, &err)
+	logger.FromContext(ctx).Infof("IdempotencyService.Begin called: scope=%s, key=%s", scope, key)
+
+	// the stored key may expire and be swept between Acquire and Get, then it is claimed again
+	for attempt := 0; attempt < 2; attempt++ {
+		acquired, err := s.idempotencyRepository.Acquire(ctx, scope, key, requestHash, time.Now().Add(s.ttl))
+		if err != nil {
+			logger.FromContext(ctx).Errorf("IdempotencyService.Begin error acquiring key: %v", err)
+			return nil, ErrCannotCheckKey
+		}
+		if acquired {
+			logger.FromContext(ctx).Infof("IdempotencyService.Begin success: new key, scope=%s, key=%s", scope, key)
+			return nil, nil
+		}
+
+		stored, err := s.idempotencyRepository.Get(ctx, scope, key)
+		if err != nil {
+			if errors.Is(err, idempotency_repo.ErrKeyNotFound) {
+				continue
+			}
+			logger.FromContext(ctx).Errorf("IdempotencyService.Begin error getting key: %v", err)
+			return nil, ErrCannotCheckKey
+		}
+
+		if stored.RequestHash != requestHash {
+			return nil, ErrRequestMismatch
+		}
+		if stored.StatusCode == nil {
+			return nil, ErrRequestInProgress
+		}
+
+		logger.FromContext(ctx).Infof("IdempotencyService.Begin success: replay, scope=%s, key=%s", scope, key)
+		return &stored, nil
+	}
+
+	return nil, ErrRequestInProgress
+}
+
+// Complete stores the response to the request made with the key, later requests with it get the response.
+func (s *IdempotencyService) Complete(ctx context.Context, scope, key string, statusCode int, contentType string, body []byte) (err error) {
+	ctx, span := tracing.Start(ctx, "IdempotencyService.Complete")
+	defer tracing.End(span, &err)
+	logger.FromContext(ctx).Infof("IdempotencyService.Complete called: scope=%s, key=%s, statusCode=%d", scope, key, statusCode)
+
+	if err := s.idempotencyRepository.Complete(ctx, scope, key, statusCode, contentType, body); err != nil {
+		logger.FromContext(ctx).Errorf("IdempotencyService.Complete error: %v", err)
+		return ErrCannotStoreResponse
+	}
+
+	logger.FromContext(ctx).Infof("IdempotencyService.Complete success: scope=%s, key=%s", scope, key)
+	return nil
+}
+
+// Release forgets the key of a request that was not completed, so the request can be retried with it.
+func (s *IdempotencyService) Release(ctx context.Context, scope, key string) (err error) {
+	ctx, span := tracing.Start(ctx, "IdempotencyService.Release")
+	defer tracing.End(span, &err)
+	logger.FromContext(ctx).Infof("IdempotencyService.Release called: scope=%s, key=%s", scope, key)
+
+	if err := s.idempotencyRepository.Release(ctx, scope, key); err != nil {
+		logger.FromContext(ctx).Errorf("IdempotencyService.Release error: %v", err)
+		return ErrCannotReleaseKey
+	}
+
+	logger.FromContext(ctx).Infof("IdempotencyService.Release success: scope=%s, key=%s", scope, key)
+	return nil
+}
+
+// SweepExpired deletes the keys that expired before now in batches of batchSize and returns how many were deleted.
+func (s *IdempotencyService) SweepExpired(ctx context.Context, now time.Time, batchSize int) (_ int64, err error) {
+	ctx, span := tracing.Start(ctx, "IdempotencyService.SweepExpired")
+	defer tracing.End(span, &err)
+	logger.FromContext(ctx).Infof("IdempotencyService.SweepExpired called: now=%s, batchSize=%d", now, batchSize)
+
+	var total int64
+	for {
+		deleted, err := s.idempotencyRepository.DeleteExpired(ctx, now, batchSize)
+		if err != nil {
+			logger.FromContext(ctx).Errorf("IdempotencyService.SweepExpired error: %v", err)
+			return total, ErrCannotSweepKeys
+		}
+		total += deleted
+
+		if deleted < int64(batchSize) || ctx.Err() != nil {
+			break
+		}
+	}
+
+	logger.FromContext(ctx).Infof("IdempotencyService.SweepExpired success: deleted=%d", total)
+	return total, nil
+}
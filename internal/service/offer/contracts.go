@@ -2,17 +2,49 @@ package offer
 
 import (
 	"context"
+	"time"
 
 	"github.com/4udiwe/subscription-service/internal/entity"
+	"github.com/4udiwe/subscription-service/pkg/cursor"
 	"github.com/google/uuid"
 )
 
 type OfferRepository interface {
-	Create(ctx context.Context, name string, price int, durationMonths int) (entity.Offer, error)
+	Create(ctx context.Context, name string, price int, currency string, durationMonths int, trialDays int) (entity.Offer, error)
 	GetAll(ctx context.Context, limit int, offset int) (offers []entity.Offer, total int, err error)
-	Delete(ctx context.Context, id uuid.UUID) error
+	GetAllAfter(ctx context.Context, after *cursor.Cursor, limit int) (offers []entity.Offer, next *cursor.Cursor, err error)
+	GetByID(ctx context.Context, id uuid.UUID) (entity.Offer, error)
+	Update(
+		ctx context.Context,
+		id uuid.UUID,
+		name string,
+		price int,
+		currency string,
+		priceID uuid.UUID,
+		durationMonths int,
+		trialDays int,
+	) (entity.Offer, error)
+	Delete(ctx context.Context, id uuid.UUID) (entity.Offer, error)
+	AddPrice(ctx context.Context, offerID uuid.UUID, price int, currency string, effectiveFrom time.Time) (entity.OfferPrice, error)
+	SchedulePrice(ctx context.Context, offerID uuid.UUID, price int, currency string, effectiveFrom time.Time) (entity.OfferPrice, error)
+	GetPrices(ctx context.Context, offerID uuid.UUID, pending *bool) ([]entity.OfferPrice, error)
+	GetPriceByID(ctx context.Context, id uuid.UUID) (entity.OfferPrice, error)
+	CancelPrice(ctx context.Context, offerID, priceID uuid.UUID) (entity.OfferPrice, error)
+	GetOfferIDsWithDuePrices(ctx context.Context, date time.Time, limit int) ([]uuid.UUID, error)
+	MarkDuePricesApplied(ctx context.Context, offerID uuid.UUID, date time.Time) ([]entity.OfferPrice, error)
+	MarkDuePricesFailed(ctx context.Context, offerID uuid.UUID, date time.Time) ([]entity.OfferPrice, error)
+	Count(ctx context.Context) (int, error)
 }
 
 type SubscriptionRepository interface {
 	GetAllByOfferID(ctx context.Context, offerID uuid.UUID) ([]entity.Subscription, error)
+	GetActiveByOfferID(ctx context.Context, offerID uuid.UUID) ([]entity.Subscription, error)
+}
+
+type OutboxRepository interface {
+	Add(ctx context.Context, events ...entity.OutboxEvent) error
+}
+
+type AuditRepository interface {
+	Add(ctx context.Context, entries ...entity.AuditEntry) error
 }
@@ -5,12 +5,26 @@ import "errors"
 var (
 	ErrOfferNotFound = errors.New("offer not found")
 
-	ErrCannotCreateOffer = errors.New("cannot create offer")
-	ErrCannotFindOffer   = errors.New("cannot find offer")
-	ErrCannotDeleteOffer = errors.New("cannot delete offer")
-	ErrCannotFetchOffers = errors.New("cannot fetch offers")
+	ErrCannotCreateOffer      = errors.New("cannot create offer")
+	ErrCannotFindOffer        = errors.New("cannot find offer")
+	ErrCannotDeleteOffer      = errors.New("cannot delete offer")
+	ErrCannotUpdateOffer      = errors.New("cannot update offer")
+	ErrCannotFetchOffers      = errors.New("cannot fetch offers")
+	ErrCannotCountOffers      = errors.New("cannot count offers")
+	ErrCannotFetchOfferPrices = errors.New("cannot fetch offer prices")
+	ErrCannotWriteEvents      = errors.New("cannot write events")
+	ErrCannotWriteAuditLog    = errors.New("cannot write audit log")
 
-	ErrCannotCheckActiveSubscriptions     = errors.New("cannot check active subscriptions for offer")
-	ErrOfferWithNameAndPriceAlreadyExists = errors.New("offer with given name and price already exists")
-	ErrActiveSubscriptionsExist           = errors.New("active subscriptions exist for given offer, could not delete")
+	ErrCannotCheckActiveSubscriptions   = errors.New("cannot check active subscriptions for offer")
+	ErrOfferAlreadyExists               = errors.New("offer with given name and currency already exists, schedule a price change instead")
+	ErrOfferRenameOverlapsSubscriptions = errors.New("renaming offer would make subscriptions of the same user overlap")
+	ErrOfferRenameDuplicatesTrial       = errors.New("renaming offer would give a user a second trial of the service")
+	ErrActiveSubscriptionsExist         = errors.New("active subscriptions exist for given offer, could not delete")
+
+	ErrInvalidEffectiveDate        = errors.New("effective_from must be after today")
+	ErrPriceChangeAlreadyScheduled = errors.New("price change already scheduled for this date")
+	ErrPriceChangeNotFound         = errors.New("pending price change not found")
+	ErrPriceChangeInUse            = errors.New("subscriptions are already renewed at this price, could not cancel")
+	ErrCannotSchedulePriceChange   = errors.New("cannot schedule price change")
+	ErrCannotCancelPriceChange     = errors.New("cannot cancel price change")
 )
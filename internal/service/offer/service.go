@@ -3,94 +3,533 @@ package offer
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/4udiwe/subscription-service/internal/entity"
+	"github.com/4udiwe/subscription-service/internal/metrics"
 	offer_repo "github.com/4udiwe/subscription-service/internal/repository/offer"
+	"github.com/4udiwe/subscription-service/pkg/actor"
+	"github.com/4udiwe/subscription-service/pkg/cursor"
+	"github.com/4udiwe/subscription-service/pkg/logger"
+	"github.com/4udiwe/subscription-service/pkg/tracing"
 	"github.com/4udiwe/subscription-service/pkg/transactor"
 	"github.com/google/uuid"
-	"github.com/sirupsen/logrus"
+	"github.com/samber/lo"
 )
 
 type OfferService struct {
-	offerRepository OfferRepository
-	subRepository   SubscriptionRepository
-	txManager       transactor.Transactor
+	offerRepository  OfferRepository
+	subRepository    SubscriptionRepository
+	outboxRepository OutboxRepository
+	auditRepository  AuditRepository
+	txManager        transactor.Transactor
 }
 
-func New(offerRepository OfferRepository, subRepository SubscriptionRepository, txManager transactor.Transactor) *OfferService {
+func New(
+	offerRepository OfferRepository,
+	subRepository SubscriptionRepository,
+	outboxRepository OutboxRepository,
+	auditRepository AuditRepository,
+	txManager transactor.Transactor,
+) *OfferService {
 	return &OfferService{
-		offerRepository: offerRepository,
-		subRepository:   subRepository,
-		txManager:       txManager,
+		offerRepository:  offerRepository,
+		subRepository:    subRepository,
+		outboxRepository: outboxRepository,
+		auditRepository:  auditRepository,
+		txManager:        txManager,
 	}
 }
 
-func (s *OfferService) CreateOffer(ctx context.Context, name string, price int, durationMonths int) (entity.Offer, error) {
-	logrus.Infof("OfferService.CreateOffer called: name=%s, price=%d, durationMonths=%d", name, price, durationMonths)
+func (s *OfferService) CreateOffer(ctx context.Context, name string, price int, currency string, durationMonths int, trialDays int) (_ entity.Offer, err error) {
+	ctx, span := tracing.Start(ctx, "OfferService.CreateOffer")
+	defer tracing.End(span, &err)
+	logger.FromContext(ctx).Infof("OfferService.CreateOffer called: name=%s, price=%d, currency=%s, durationMonths=%d, trialDays=%d", name, price, currency, durationMonths, trialDays)
 
-	offer, err := s.offerRepository.Create(ctx, name, price, durationMonths)
-	if err != nil {
-		if errors.Is(err, offer_repo.ErrOfferWithNameAndPriceAlreadyExists) {
-			return entity.Offer{}, ErrOfferWithNameAndPriceAlreadyExists
+	var offer entity.Offer
+
+	err = s.txManager.WithinTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+		offer, err = s.offerRepository.Create(txCtx, name, price, currency, durationMonths, trialDays)
+		if err != nil {
+			if errors.Is(err, offer_repo.ErrOfferAlreadyExists) {
+				return ErrOfferAlreadyExists
+			}
+			logger.FromContext(ctx).Errorf("OfferService.CreateOffer error: %v", err)
+			return ErrCannotCreateOffer
+		}
+
+		if err := s.addAudit(txCtx, entity.NewOfferAudit(entity.AuditActionCreate, nil, &offer)); err != nil {
+			return err
 		}
-		logrus.Errorf("OfferService.CreateOffer error: %v", err)
-		return entity.Offer{}, ErrCannotCreateOffer
+		return s.addEvents(txCtx, entity.NewOfferEvent(entity.EventOfferCreated, offer))
+	})
+
+	if err != nil {
+		return entity.Offer{}, err
 	}
 
-	logrus.Infof("OfferService.CreateOffer success: offer created with ID=%d", offer.ID)
+	metrics.OffersCreated.Inc()
+	logger.FromContext(ctx).Infof("OfferService.CreateOffer success: offer created with ID=%s", offer.ID)
 	return offer, nil
 }
 
 func (s *OfferService) GetAllOffers(ctx context.Context, page int, pageSize int) (offers []entity.Offer, total int, err error) {
-	logrus.Info("OfferService.GetAllOffers called")
+	ctx, span := tracing.Start(ctx, "OfferService.GetAllOffers")
+	defer tracing.End(span, &err)
+	logger.FromContext(ctx).Info("OfferService.GetAllOffers called")
 
 	limit := pageSize
 	offset := (page - 1) * pageSize
 
 	offers, total, err = s.offerRepository.GetAll(ctx, limit, offset)
 	if err != nil {
-		logrus.Errorf("OfferService.GetAllOffers error: %v", err)
+		logger.FromContext(ctx).Errorf("OfferService.GetAllOffers error: %v", err)
 		return nil, 0, ErrCannotFetchOffers
 	}
 
-	logrus.Info("OfferService.GetAllOffers success")
+	logger.FromContext(ctx).Info("OfferService.GetAllOffers success")
 	return offers, total, nil
 }
 
-func (s *OfferService) DeleteOffer(ctx context.Context, offerID uuid.UUID) error {
-	logrus.Infof("OfferService.DeleteOffer called: id=%s", offerID)
+// GetAllOffersAfter returns a page of offers that come after the cursor, nil cursor means the first page.
+func (s *OfferService) GetAllOffersAfter(ctx context.Context, after *cursor.Cursor, pageSize int) (offers []entity.Offer, next *cursor.Cursor, err error) {
+	ctx, span := tracing.Start(ctx, "OfferService.GetAllOffersAfter")
+	defer tracing.End(span, &err)
+	logger.FromContext(ctx).Infof("OfferService.GetAllOffersAfter called: after=%v", after)
+
+	offers, next, err = s.offerRepository.GetAllAfter(ctx, after, pageSize)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("OfferService.GetAllOffersAfter error: %v", err)
+		return nil, nil, ErrCannotFetchOffers
+	}
+
+	logger.FromContext(ctx).Info("OfferService.GetAllOffersAfter success")
+	return offers, next, nil
+}
+
+func (s *OfferService) CountOffers(ctx context.Context) (_ int, err error) {
+	ctx, span := tracing.Start(ctx, "OfferService.CountOffers")
+	defer tracing.End(span, &err)
+	count, err := s.offerRepository.Count(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("OfferService.CountOffers error: %v", err)
+		return 0, ErrCannotCountOffers
+	}
+	return count, nil
+}
+
+func (s *OfferService) GetOfferByID(ctx context.Context, offerID uuid.UUID) (_ entity.Offer, err error) {
+	ctx, span := tracing.Start(ctx, "OfferService.GetOfferByID")
+	defer tracing.End(span, &err)
+	logger.FromContext(ctx).Infof("OfferService.GetOfferByID called: id=%s", offerID)
+
+	offer, err := s.offerRepository.GetByID(ctx, offerID)
+	if err != nil {
+		if errors.Is(err, offer_repo.ErrOfferNotFound) {
+			return entity.Offer{}, ErrOfferNotFound
+		}
+		logger.FromContext(ctx).Errorf("OfferService.GetOfferByID error: %v", err)
+		return entity.Offer{}, ErrCannotFindOffer
+	}
+
+	logger.FromContext(ctx).Infof("OfferService.GetOfferByID success: id=%s", offer.ID)
+	return offer, nil
+}
+
+// UpdateOffer changes only the fields that are not nil. Existing subscriptions keep
+// their stored end dates, a new duration or trial length applies to subscriptions created afterwards.
+// A new price or currency is written as a new price version effective from today, existing
+// subscriptions keep the version they were bought at.
+func (s *OfferService) UpdateOffer(
+	ctx context.Context,
+	offerID uuid.UUID,
+	name *string,
+	price *int,
+	currency *string,
+	durationMonths *int,
+	trialDays *int,
+) (_ entity.Offer, err error) {
+	ctx, span := tracing.Start(ctx, "OfferService.UpdateOffer")
+	defer tracing.End(span, &err)
+	logger.FromContext(ctx).Infof("OfferService.UpdateOffer called: id=%s", offerID)
+	var offer entity.Offer
+
+	err = s.txManager.WithinTransaction(ctx, func(txCtx context.Context) error {
+		current, err := s.offerRepository.GetByID(txCtx, offerID)
+		if err != nil {
+			if errors.Is(err, offer_repo.ErrOfferNotFound) {
+				return ErrOfferNotFound
+			}
+			logger.FromContext(ctx).Errorf("OfferService.UpdateOffer error getting offer: %v", err)
+			return ErrCannotFindOffer
+		}
+
+		before := current
+
+		if name != nil {
+			current.Name = *name
+		}
+		if price != nil {
+			current.Price = *price
+		}
+		if currency != nil {
+			current.Currency = *currency
+		}
+		if durationMonths != nil {
+			current.DurationMonths = *durationMonths
+		}
+		if trialDays != nil {
+			current.TrialDays = *trialDays
+		}
+
+		if current.Price != before.Price || current.Currency != before.Currency {
+			offerPrice, err := s.offerRepository.AddPrice(txCtx, offerID, current.Price, current.Currency, truncateToDate(time.Now()))
+			if err != nil {
+				logger.FromContext(ctx).Errorf("OfferService.UpdateOffer error adding price: %v", err)
+				return ErrCannotUpdateOffer
+			}
+			current.PriceID = offerPrice.ID
+		}
 
-	err := s.txManager.WithinTransaction(ctx, func(txCtx context.Context) error {
+		offer, err = s.offerRepository.Update(txCtx, offerID, current.Name, current.Price, current.Currency, current.PriceID, current.DurationMonths, current.TrialDays)
+		if err != nil {
+			if errors.Is(err, offer_repo.ErrOfferNotFound) {
+				return ErrOfferNotFound
+			}
+			if errors.Is(err, offer_repo.ErrOfferAlreadyExists) {
+				return ErrOfferAlreadyExists
+			}
+			if errors.Is(err, offer_repo.ErrOfferRenameOverlapsSubscriptions) {
+				return ErrOfferRenameOverlapsSubscriptions
+			}
+			if errors.Is(err, offer_repo.ErrOfferRenameDuplicatesTrial) {
+				return ErrOfferRenameDuplicatesTrial
+			}
+			logger.FromContext(ctx).Errorf("OfferService.UpdateOffer error updating offer: %v", err)
+			return ErrCannotUpdateOffer
+		}
+
+		if err := s.addAudit(txCtx, entity.NewOfferAudit(entity.AuditActionUpdate, &before, &offer)); err != nil {
+			return err
+		}
+		return s.addEvents(txCtx, entity.NewOfferEvent(entity.EventOfferUpdated, offer))
+	})
+
+	if err != nil {
+		return entity.Offer{}, err
+	}
+
+	logger.FromContext(ctx).Infof("OfferService.UpdateOffer success: id=%s", offer.ID)
+	return offer, nil
+}
+
+// GetOfferPrices returns the price history of the offer, the latest effective version first.
+// A non-nil pending returns only the scheduled changes that are not applied yet or only the rest.
+func (s *OfferService) GetOfferPrices(ctx context.Context, offerID uuid.UUID, pending *bool) (_ []entity.OfferPrice, err error) {
+	ctx, span := tracing.Start(ctx, "OfferService.GetOfferPrices")
+	defer tracing.End(span, &err)
+	logger.FromContext(ctx).Infof("OfferService.GetOfferPrices called: id=%s, pending=%v", offerID, pending)
+
+	if _, err := s.offerRepository.GetByID(ctx, offerID); err != nil {
+		if errors.Is(err, offer_repo.ErrOfferNotFound) {
+			return nil, ErrOfferNotFound
+		}
+		logger.FromContext(ctx).Errorf("OfferService.GetOfferPrices error getting offer: %v", err)
+		return nil, ErrCannotFindOffer
+	}
+
+	prices, err := s.offerRepository.GetPrices(ctx, offerID, pending)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("OfferService.GetOfferPrices error: %v", err)
+		return nil, ErrCannotFetchOfferPrices
+	}
+
+	logger.FromContext(ctx).Infof("OfferService.GetOfferPrices success: count=%d", len(prices))
+	return prices, nil
+}
+
+// SchedulePriceChange writes a pending price version of the offer effective from a future date,
+// nil currency keeps the offer currency. Every active subscriber of the offer gets a
+// subscription.price_change_upcoming event, subscriptions keep their price until renewal.
+func (s *OfferService) SchedulePriceChange(
+	ctx context.Context,
+	offerID uuid.UUID,
+	price int,
+	currency *string,
+	effectiveFrom time.Time,
+) (_ entity.OfferPrice, err error) {
+	ctx, span := tracing.Start(ctx, "OfferService.SchedulePriceChange")
+	defer tracing.End(span, &err)
+	logger.FromContext(ctx).Infof("OfferService.SchedulePriceChange called: id=%s, price=%d, currency=%v, effectiveFrom=%v", offerID, price, currency, effectiveFrom)
+
+	effectiveFrom = truncateToDate(effectiveFrom)
+	if !effectiveFrom.After(truncateToDate(time.Now())) {
+		return entity.OfferPrice{}, ErrInvalidEffectiveDate
+	}
+
+	var offerPrice entity.OfferPrice
+
+	err = s.txManager.WithinTransaction(ctx, func(txCtx context.Context) error {
+		offer, err := s.offerRepository.GetByID(txCtx, offerID)
+		if err != nil {
+			if errors.Is(err, offer_repo.ErrOfferNotFound) {
+				return ErrOfferNotFound
+			}
+			logger.FromContext(ctx).Errorf("OfferService.SchedulePriceChange error getting offer: %v", err)
+			return ErrCannotFindOffer
+		}
+
+		newCurrency := offer.Currency
+		if currency != nil {
+			newCurrency = *currency
+		}
+
+		offerPrice, err = s.offerRepository.SchedulePrice(txCtx, offerID, price, newCurrency, effectiveFrom)
+		if err != nil {
+			if errors.Is(err, offer_repo.ErrPriceChangeAlreadyScheduled) {
+				return ErrPriceChangeAlreadyScheduled
+			}
+			if errors.Is(err, offer_repo.ErrOfferNotFound) {
+				return ErrOfferNotFound
+			}
+			logger.FromContext(ctx).Errorf("OfferService.SchedulePriceChange error: %v", err)
+			return ErrCannotSchedulePriceChange
+		}
+
+		subs, err := s.subRepository.GetActiveByOfferID(txCtx, offerID)
+		if err != nil {
+			logger.FromContext(ctx).Errorf("OfferService.SchedulePriceChange error fetching subscriptions: %v", err)
+			return ErrCannotCheckActiveSubscriptions
+		}
+
+		if err := s.addAudit(txCtx, entity.NewOfferPriceAudit(entity.AuditActionSchedulePrice, nil, &offerPrice)); err != nil {
+			return err
+		}
+
+		events := lo.Map(subs, func(sub entity.Subscription, _ int) entity.OutboxEvent {
+			return entity.NewPriceChangeEvent(entity.EventSubscriptionPriceChangeUpcoming, sub, offerPrice)
+		})
+		return s.addEvents(txCtx, events...)
+	})
+
+	if err != nil {
+		return entity.OfferPrice{}, err
+	}
+
+	logger.FromContext(ctx).Infof("OfferService.SchedulePriceChange success: id=%s", offerPrice.ID)
+	return offerPrice, nil
+}
+
+// CancelPriceChange removes a price change of the offer that is not applied yet and sends
+// subscription.price_change_cancelled to every active subscriber of the offer. A change some
+// subscription has already been renewed at cannot be cancelled.
+func (s *OfferService) CancelPriceChange(ctx context.Context, offerID, priceID uuid.UUID) (err error) {
+	ctx, span := tracing.Start(ctx, "OfferService.CancelPriceChange")
+	defer tracing.End(span, &err)
+	logger.FromContext(ctx).Infof("OfferService.CancelPriceChange called: id=%s, priceID=%s", offerID, priceID)
+
+	err = s.txManager.WithinTransaction(ctx, func(txCtx context.Context) error {
+		offerPrice, err := s.offerRepository.CancelPrice(txCtx, offerID, priceID)
+		if err != nil {
+			if errors.Is(err, offer_repo.ErrPendingPriceNotFound) {
+				return ErrPriceChangeNotFound
+			}
+			if errors.Is(err, offer_repo.ErrOfferPriceInUse) {
+				return ErrPriceChangeInUse
+			}
+			logger.FromContext(ctx).Errorf("OfferService.CancelPriceChange error: %v", err)
+			return ErrCannotCancelPriceChange
+		}
+
+		subs, err := s.subRepository.GetActiveByOfferID(txCtx, offerID)
+		if err != nil {
+			logger.FromContext(ctx).Errorf("OfferService.CancelPriceChange error fetching subscriptions: %v", err)
+			return ErrCannotCheckActiveSubscriptions
+		}
+
+		if err := s.addAudit(txCtx, entity.NewOfferPriceAudit(entity.AuditActionCancelPrice, &offerPrice, nil)); err != nil {
+			return err
+		}
+		return s.addEvents(txCtx, priceChangeCancelledEvents(subs, offerPrice)...)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	logger.FromContext(ctx).Infof("OfferService.CancelPriceChange success: priceID=%s", priceID)
+	return nil
+}
+
+// ApplyDuePriceChanges applies the pending price changes effective not later than date to their
+// offers and returns the number of offers changed. Every offer is updated in its own transaction
+// and gets the latest due change. A change that is older than the price the offer already has is
+// only marked as applied. Changes that cannot be applied because another offer already has the
+// name and the new currency are marked as failed, so they are not retried, and their subscribers
+// get subscription.price_change_cancelled.
+func (s *OfferService) ApplyDuePriceChanges(ctx context.Context, date time.Time, batchSize int) (_ int, err error) {
+	ctx, span := tracing.Start(ctx, "OfferService.ApplyDuePriceChanges")
+	defer tracing.End(span, &err)
+	logger.FromContext(ctx).Infof("OfferService.ApplyDuePriceChanges called: date=%v, batchSize=%d", date, batchSize)
+
+	offerIDs, err := s.offerRepository.GetOfferIDsWithDuePrices(ctx, date, batchSize)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("OfferService.ApplyDuePriceChanges error fetching offers: %v", err)
+		return 0, ErrCannotFetchOfferPrices
+	}
+
+	applied := 0
+	for _, offerID := range offerIDs {
+		changed := false
+		err := s.txManager.WithinTransaction(ctx, func(txCtx context.Context) error {
+			prices, err := s.offerRepository.MarkDuePricesApplied(txCtx, offerID, date)
+			if err != nil {
+				return err
+			}
+			if len(prices) == 0 {
+				// applied by a concurrent run
+				return nil
+			}
+			latest := prices[len(prices)-1]
+
+			current, err := s.offerRepository.GetByID(txCtx, offerID)
+			if err != nil {
+				return err
+			}
+			currentPrice, err := s.offerRepository.GetPriceByID(txCtx, current.PriceID)
+			if err != nil {
+				return err
+			}
+			if latest.EffectiveFrom.Before(currentPrice.EffectiveFrom) {
+				return nil
+			}
+
+			offer, err := s.offerRepository.Update(txCtx, offerID, current.Name, latest.Price, latest.Currency, latest.ID, current.DurationMonths, current.TrialDays)
+			if err != nil {
+				return err
+			}
+			changed = true
+
+			if err := s.addAudit(txCtx, entity.NewOfferAudit(entity.AuditActionUpdate, &current, &offer)); err != nil {
+				return err
+			}
+			return s.addEvents(txCtx, entity.NewOfferEvent(entity.EventOfferUpdated, offer))
+		})
+
+		switch {
+		case err == nil:
+			if changed {
+				applied++
+			}
+		case errors.Is(err, offer_repo.ErrOfferAlreadyExists):
+			logger.FromContext(ctx).Errorf("OfferService.ApplyDuePriceChanges: offer %s cannot take the new currency, another offer has the name and currency", offerID)
+			if err := s.failDuePriceChanges(ctx, offerID, date); err != nil {
+				logger.FromContext(ctx).Errorf("OfferService.ApplyDuePriceChanges error failing prices of %s: %v", offerID, err)
+			}
+		default:
+			logger.FromContext(ctx).Errorf("OfferService.ApplyDuePriceChanges error applying prices of %s: %v", offerID, err)
+		}
+	}
+
+	logger.FromContext(ctx).Infof("OfferService.ApplyDuePriceChanges success: applied=%d", applied)
+	return applied, nil
+}
+
+// failDuePriceChanges marks the due price changes of the offer as failed in a transaction of its
+// own, the one that tried to apply them is rolled back.
+func (s *OfferService) failDuePriceChanges(ctx context.Context, offerID uuid.UUID, date time.Time) error {
+	return s.txManager.WithinTransaction(ctx, func(txCtx context.Context) error {
+		prices, err := s.offerRepository.MarkDuePricesFailed(txCtx, offerID, date)
+		if err != nil || len(prices) == 0 {
+			return err
+		}
+
+		subs, err := s.subRepository.GetActiveByOfferID(txCtx, offerID)
+		if err != nil {
+			return err
+		}
+
+		var events []entity.OutboxEvent
+		for _, price := range prices {
+			events = append(events, priceChangeCancelledEvents(subs, price)...)
+		}
+		return s.addEvents(txCtx, events...)
+	})
+}
+
+func priceChangeCancelledEvents(subs []entity.Subscription, price entity.OfferPrice) []entity.OutboxEvent {
+	return lo.Map(subs, func(sub entity.Subscription, _ int) entity.OutboxEvent {
+		return entity.NewPriceChangeEvent(entity.EventSubscriptionPriceChangeCancelled, sub, price)
+	})
+}
+
+func (s *OfferService) DeleteOffer(ctx context.Context, offerID uuid.UUID) (err error) {
+	ctx, span := tracing.Start(ctx, "OfferService.DeleteOffer")
+	defer tracing.End(span, &err)
+	logger.FromContext(ctx).Infof("OfferService.DeleteOffer called: id=%s", offerID)
+
+	err = s.txManager.WithinTransaction(ctx, func(txCtx context.Context) error {
 		// check if offer have referring subscriptions
 		subs, err := s.subRepository.GetAllByOfferID(txCtx, offerID)
 		if err != nil {
-			logrus.Errorf("OfferService.DeleteOffer error fetching subscriptions: %v", err)
+			logger.FromContext(ctx).Errorf("OfferService.DeleteOffer error fetching subscriptions: %v", err)
 			return ErrCannotCheckActiveSubscriptions
 		}
 
 		// checking amount of subs
 		if len(subs) > 0 {
-			logrus.Errorf("Offer.DeleteOffer error: active subscriptions exist for this offer (ID=%v), could not delete", offerID)
+			logger.FromContext(ctx).Errorf("Offer.DeleteOffer error: active subscriptions exist for this offer (ID=%v), could not delete", offerID)
 			return ErrActiveSubscriptionsExist
 		}
 
 		// if its zero -> delete
-		err = s.offerRepository.Delete(txCtx, offerID)
+		offer, err := s.offerRepository.Delete(txCtx, offerID)
 		if err != nil {
 			if errors.Is(err, offer_repo.ErrOfferNotFound) {
 				return ErrOfferNotFound
 			}
-			logrus.Errorf("OfferService.DeleteOffer error deleting offer: %v", err)
+			logger.FromContext(ctx).Errorf("OfferService.DeleteOffer error deleting offer: %v", err)
 			return ErrCannotDeleteOffer
 		}
 
-		return nil
+		if err := s.addAudit(txCtx, entity.NewOfferAudit(entity.AuditActionDelete, &offer, nil)); err != nil {
+			return err
+		}
+		return s.addEvents(txCtx, entity.NewOfferEvent(entity.EventOfferDeleted, offer))
 	})
 
 	if err != nil {
 		return err
 	}
 
-	logrus.Infof("OfferService.DeleteOffer success: offer with ID=%s deleted", offerID)
+	metrics.OffersDeleted.Inc()
+	logger.FromContext(ctx).Infof("OfferService.DeleteOffer success: offer with ID=%s deleted", offerID)
 	return nil
 }
+
+// addEvents writes domain events to the outbox in the transaction of ctx.
+func (s *OfferService) addEvents(ctx context.Context, events ...entity.OutboxEvent) error {
+	if err := s.outboxRepository.Add(ctx, events...); err != nil {
+		logger.FromContext(ctx).Errorf("OfferService.addEvents error: %v", err)
+		return ErrCannotWriteEvents
+	}
+	return nil
+}
+
+// addAudit writes audit entries made by the actor of ctx in the transaction of ctx.
+func (s *OfferService) addAudit(ctx context.Context, entries ...entity.AuditEntry) error {
+	for i := range entries {
+		entries[i].Actor = actor.FromContext(ctx)
+	}
+	if err := s.auditRepository.Add(ctx, entries...); err != nil {
+		logger.FromContext(ctx).Errorf("OfferService.addAudit error: %v", err)
+		return ErrCannotWriteAuditLog
+	}
+	return nil
+}
+
+// truncateToDate drops the time part, price versions take effect from a DATE.
+func truncateToDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
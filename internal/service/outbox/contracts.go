@@ -0,0 +1,21 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/4udiwe/subscription-service/internal/entity"
+)
+
+type OutboxRepository interface {
+	ClaimPending(ctx context.Context, limit int, leaseUntil time.Time) ([]entity.OutboxEvent, error)
+	MarkPublished(ctx context.Context, id int64) error
+	MarkFailed(ctx context.Context, id int64, reason string, nextAttemptAt time.Time, dead bool) error
+	Release(ctx context.Context, ids []int64) error
+}
+
+// Publisher delivers an event to the outside world. A nil error means the event was accepted
+// and will not be sent again.
+type Publisher interface {
+	Publish(ctx context.Context, event entity.OutboxEvent) error
+}
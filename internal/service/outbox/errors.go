@@ -0,0 +1,7 @@
+package outbox
+
+import "errors"
+
+var (
+	ErrCannotRelayEvents = errors.New("cannot relay outbox events")
+)
@@ -0,0 +1,29 @@
+package outbox
+
+import "time"
+
+type Option func(*OutboxService)
+
+// MaxAttempts sets after how many failed attempts an event is marked dead and skipped.
+func MaxAttempts(attempts int) Option {
+	return func(s *OutboxService) {
+		s.maxAttempts = attempts
+	}
+}
+
+// Backoff sets the delay before the first retry of a failed event, it doubles with every failed
+// attempt up to maxDelay.
+func Backoff(delay, maxDelay time.Duration) Option {
+	return func(s *OutboxService) {
+		s.backoff = delay
+		s.maxBackoff = maxDelay
+	}
+}
+
+// Lease sets how long a claimed batch is hidden from other relays, it must be longer than
+// publishing a batch takes.
+func Lease(lease time.Duration) Option {
+	return func(s *OutboxService) {
+		s.lease = lease
+	}
+}
@@ -0,0 +1,111 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/4udiwe/subscription-service/internal/entity"
+	"github.com/4udiwe/subscription-service/pkg/logger"
+	"github.com/4udiwe/subscription-service/pkg/tracing"
+	"github.com/samber/lo"
+)
+
+const (
+	defaultMaxAttempts = 10
+	defaultBackoff     = 30 * time.Second
+	defaultMaxBackoff  = time.Hour
+	defaultLease       = 5 * time.Minute
+)
+
+type OutboxService struct {
+	outboxRepository OutboxRepository
+	publisher        Publisher
+
+	maxAttempts int
+	backoff     time.Duration
+	maxBackoff  time.Duration
+	lease       time.Duration
+}
+
+func New(outboxRepository OutboxRepository, publisher Publisher, opts ...Option) *OutboxService {
+	s := &OutboxService{
+		outboxRepository: outboxRepository,
+		publisher:        publisher,
+		maxAttempts:      defaultMaxAttempts,
+		backoff:          defaultBackoff,
+		maxBackoff:       defaultMaxBackoff,
+		lease:            defaultLease,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Relay publishes up to batchSize pending events in the order they were written and marks
+// them as published. The batch is claimed for the lease, so several relays can run at once,
+// and no transaction is held while the publisher sends it. Delivery is at-least-once: an event
+// is marked only after the publisher accepted it, so a crash in between sends it again. The
+// first failed event stops the batch to keep the order, it is retried with exponential backoff
+// and the events after it wait for it. An event that fails maxAttempts times is marked dead and
+// no longer holds back the ones after it.
+func (s *OutboxService) Relay(ctx context.Context, batchSize int) (_ int, err error) {
+	ctx, span := tracing.Start(ctx, "OutboxService.Relay")
+	defer tracing.End(span, &err)
+	logger.FromContext(ctx).Debugf("OutboxService.Relay called: batchSize=%d", batchSize)
+
+	events, err := s.outboxRepository.ClaimPending(ctx, batchSize, time.Now().Add(s.lease))
+	if err != nil {
+		logger.FromContext(ctx).Errorf("OutboxService.Relay error getting events: %v", err)
+		return 0, ErrCannotRelayEvents
+	}
+
+	published := 0
+	for i, event := range events {
+		if err := s.publisher.Publish(ctx, event); err != nil {
+			attempts := event.Attempts + 1
+			dead := attempts >= s.maxAttempts
+			if dead {
+				logger.FromContext(ctx).Errorf("OutboxService.Relay: event %d (%s) is dead after %d attempts: %v", event.ID, event.EventType, attempts, err)
+			} else {
+				logger.FromContext(ctx).Warnf("OutboxService.Relay: event %d (%s) not delivered, attempt %d: %v", event.ID, event.EventType, attempts, err)
+			}
+
+			if err := s.outboxRepository.MarkFailed(ctx, event.ID, err.Error(), time.Now().Add(s.retryDelay(attempts)), dead); err != nil {
+				logger.FromContext(ctx).Errorf("OutboxService.Relay error marking event %d failed: %v", event.ID, err)
+				return published, ErrCannotRelayEvents
+			}
+			if dead {
+				continue
+			}
+
+			rest := lo.Map(events[i+1:], func(event entity.OutboxEvent, _ int) int64 { return event.ID })
+			if err := s.outboxRepository.Release(ctx, rest); err != nil {
+				logger.FromContext(ctx).Errorf("OutboxService.Relay error releasing events: %v", err)
+				return published, ErrCannotRelayEvents
+			}
+			break
+		}
+
+		if err := s.outboxRepository.MarkPublished(ctx, event.ID); err != nil {
+			logger.FromContext(ctx).Errorf("OutboxService.Relay error marking event %d published: %v", event.ID, err)
+			return published, ErrCannotRelayEvents
+		}
+		published++
+	}
+
+	logger.FromContext(ctx).Debugf("OutboxService.Relay success: published=%d", published)
+	return published, nil
+}
+
+// retryDelay returns the delay after the given number of failed attempts: backoff, 2*backoff,
+// 4*backoff and so on, capped at maxBackoff.
+func (s *OutboxService) retryDelay(attempts int) time.Duration {
+	delay := s.backoff
+	for i := 1; i < attempts && delay < s.maxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, s.maxBackoff)
+}
@@ -0,0 +1,236 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/4udiwe/subscription-service/internal/entity"
+)
+
+// fakeRepository keeps the outbox in memory. ClaimPending follows the rules of the real query at
+// now, the test moves now forward instead of sleeping.
+type fakeRepository struct {
+	mu     sync.Mutex
+	events []*entity.OutboxEvent
+	now    time.Time
+}
+
+func newFakeRepository(count int) *fakeRepository {
+	r := &fakeRepository{now: time.Now()}
+	for i := 1; i <= count; i++ {
+		r.events = append(r.events, &entity.OutboxEvent{ID: int64(i), EventType: entity.EventSubscriptionCreated})
+	}
+	return r
+}
+
+func (r *fakeRepository) get(id int64) entity.OutboxEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return *r.find(id)
+}
+
+func (r *fakeRepository) find(id int64) *entity.OutboxEvent {
+	for _, event := range r.events {
+		if event.ID == id {
+			return event
+		}
+	}
+	return nil
+}
+
+// advance moves the clock past the next attempt of every event.
+func (r *fakeRepository) advance() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, event := range r.events {
+		if event.NextAttemptAt != nil && event.NextAttemptAt.After(r.now) {
+			r.now = *event.NextAttemptAt
+		}
+	}
+}
+
+func (r *fakeRepository) ClaimPending(_ context.Context, limit int, _ time.Time) ([]entity.OutboxEvent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var claimed []entity.OutboxEvent
+	for _, event := range r.events {
+		if event.PublishedAt != nil || event.DeadAt != nil {
+			continue
+		}
+		// a waiting event holds back the ones after it
+		if event.NextAttemptAt != nil && event.NextAttemptAt.After(r.now) {
+			break
+		}
+		if len(claimed) == limit {
+			break
+		}
+		claimed = append(claimed, *event)
+	}
+	return claimed, nil
+}
+
+func (r *fakeRepository) MarkPublished(_ context.Context, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	event := r.find(id)
+	event.Attempts++
+	event.PublishedAt = &r.now
+	event.NextAttemptAt = nil
+	return nil
+}
+
+func (r *fakeRepository) MarkFailed(_ context.Context, id int64, reason string, nextAttemptAt time.Time, dead bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	event := r.find(id)
+	event.Attempts++
+	event.LastError = &reason
+	event.NextAttemptAt = &nextAttemptAt
+	if dead {
+		event.DeadAt = &r.now
+	}
+	return nil
+}
+
+func (r *fakeRepository) Release(context.Context, []int64) error {
+	return nil
+}
+
+// fakePublisher fails the events in failing and records the order of the published ones.
+type fakePublisher struct {
+	mu        sync.Mutex
+	failing   map[int64]bool
+	calls     int
+	published []int64
+}
+
+func (p *fakePublisher) Publish(_ context.Context, event entity.OutboxEvent) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.calls++
+	if p.failing[event.ID] {
+		return errors.New("receiver is down")
+	}
+	p.published = append(p.published, event.ID)
+	return nil
+}
+
+func (p *fakePublisher) setFailing(id int64, failing bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.failing[id] = failing
+}
+
+func TestRetryDelay(t *testing.T) {
+	s := New(nil, nil, Backoff(time.Second, 10*time.Second))
+
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 1, want: time.Second},
+		{attempts: 2, want: 2 * time.Second},
+		{attempts: 3, want: 4 * time.Second},
+		{attempts: 4, want: 8 * time.Second},
+		{attempts: 5, want: 10 * time.Second},
+		{attempts: 50, want: 10 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.attempts), func(t *testing.T) {
+			if got := s.retryDelay(tt.attempts); got != tt.want {
+				t.Errorf("retryDelay(%d) = %v, want %v", tt.attempts, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRelayRetriesUntilDead(t *testing.T) {
+	const maxAttempts = 4
+	repo := newFakeRepository(2)
+	pub := &fakePublisher{failing: map[int64]bool{1: true}}
+	s := New(repo, pub, MaxAttempts(maxAttempts), Backoff(time.Minute, time.Hour))
+
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		before := time.Now()
+		if _, err := s.Relay(context.Background(), 10); err != nil {
+			t.Fatalf("Relay: %v", err)
+		}
+
+		event := repo.get(1)
+		if event.Attempts != attempt {
+			t.Fatalf("attempts = %d, want %d", event.Attempts, attempt)
+		}
+		if attempt < maxAttempts {
+			if event.DeadAt != nil {
+				t.Fatalf("event is dead after attempt %d", attempt)
+			}
+			// the next attempt waits for the backoff of this one
+			if wait := event.NextAttemptAt.Sub(before); wait < s.retryDelay(attempt) {
+				t.Errorf("next attempt in %v, want at least %v", wait, s.retryDelay(attempt))
+			}
+			if len(pub.published) != 0 {
+				t.Fatalf("published %v while the first event is retried, want the order kept", pub.published)
+			}
+		}
+
+		// an event that is not due yet is not sent again
+		calls := pub.calls
+		if _, err := s.Relay(context.Background(), 10); err != nil {
+			t.Fatalf("Relay: %v", err)
+		}
+		if attempt < maxAttempts && pub.calls != calls {
+			t.Fatalf("published %d times before the next attempt is due", pub.calls-calls)
+		}
+		repo.advance()
+	}
+
+	if event := repo.get(1); event.DeadAt == nil {
+		t.Fatalf("event is not dead after %d attempts", maxAttempts)
+	}
+	// a dead event no longer holds back the next one
+	if !slices.Equal(pub.published, []int64{2}) {
+		t.Errorf("published %v, want [2]", pub.published)
+	}
+}
+
+func TestRelayRetriesAfterBackoff(t *testing.T) {
+	repo := newFakeRepository(3)
+	pub := &fakePublisher{failing: map[int64]bool{2: true}}
+	s := New(repo, pub, Backoff(time.Minute, time.Hour))
+
+	if _, err := s.Relay(context.Background(), 10); err != nil {
+		t.Fatalf("Relay: %v", err)
+	}
+	if !slices.Equal(pub.published, []int64{1}) {
+		t.Fatalf("published %v, want [1]", pub.published)
+	}
+	if event := repo.get(2); event.Attempts != 1 || event.NextAttemptAt == nil {
+		t.Fatalf("event 2: attempts = %d, next attempt at %v, want a retry scheduled", event.Attempts, event.NextAttemptAt)
+	}
+
+	// the receiver is back, but the retry waits for the backoff
+	pub.setFailing(2, false)
+	if _, err := s.Relay(context.Background(), 10); err != nil {
+		t.Fatalf("Relay: %v", err)
+	}
+	if !slices.Equal(pub.published, []int64{1}) {
+		t.Fatalf("published %v before the retry is due, want [1]", pub.published)
+	}
+
+	repo.advance()
+	if _, err := s.Relay(context.Background(), 10); err != nil {
+		t.Fatalf("Relay: %v", err)
+	}
+	if !slices.Equal(pub.published, []int64{1, 2, 3}) {
+		t.Errorf("published %v, want [1 2 3]", pub.published)
+	}
+	if event := repo.get(2); event.PublishedAt == nil || event.DeadAt != nil {
+		t.Errorf("event 2: published at %v, dead at %v, want published", event.PublishedAt, event.DeadAt)
+	}
+}
@@ -0,0 +1,12 @@
+package promo_code
+
+import (
+	"context"
+
+	"github.com/4udiwe/subscription-service/internal/entity"
+)
+
+type PromoCodeRepository interface {
+	Create(ctx context.Context, code entity.PromoCode) (entity.PromoCode, error)
+	GetAll(ctx context.Context, limit int, offset int) (codes []entity.PromoCode, total int, err error)
+}
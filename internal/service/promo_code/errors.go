@@ -0,0 +1,11 @@
+package promo_code
+
+import "errors"
+
+var (
+	ErrInvalidPromoCode       = errors.New("percent discount must be within 1..100, fixed discount needs a currency, valid_until must not be before valid_from")
+	ErrPromoCodeAlreadyExists = errors.New("promo code already exists")
+	ErrOfferNotFound          = errors.New("offer not found")
+	ErrCannotCreatePromoCode  = errors.New("cannot create promo code")
+	ErrCannotFetchPromoCodes  = errors.New("cannot fetch promo codes")
+)
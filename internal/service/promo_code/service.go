@@ -0,0 +1,98 @@
+package promo_code
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/4udiwe/subscription-service/internal/entity"
+	promo_code_repo "github.com/4udiwe/subscription-service/internal/repository/promo_code"
+	"github.com/4udiwe/subscription-service/pkg/logger"
+	"github.com/4udiwe/subscription-service/pkg/tracing"
+	"github.com/4udiwe/subscription-service/pkg/transactor"
+)
+
+type PromoCodeService struct {
+	promoCodeRepository PromoCodeRepository
+	txManager           transactor.Transactor
+}
+
+func New(promoCodeRepository PromoCodeRepository, txManager transactor.Transactor) *PromoCodeService {
+	return &PromoCodeService{
+		promoCodeRepository: promoCodeRepository,
+		txManager:           txManager,
+	}
+}
+
+// CreatePromoCode stores the promo code and the offers it is restricted to in one transaction.
+// Codes are case-insensitive and stored in upper case.
+func (s *PromoCodeService) CreatePromoCode(ctx context.Context, code entity.PromoCode) (_ entity.PromoCode, err error) {
+	ctx, span := tracing.Start(ctx, "PromoCodeService.CreatePromoCode")
+	defer tracing.End(span, &err)
+	logger.FromContext(ctx).Infof("PromoCodeService.CreatePromoCode called: code=%s, discountType=%s, discountValue=%d", code.Code, code.DiscountType, code.DiscountValue)
+
+	code.Code = strings.ToUpper(code.Code)
+
+	switch code.DiscountType {
+	case entity.DiscountTypePercent:
+		if code.DiscountValue < 1 || code.DiscountValue > 100 {
+			return entity.PromoCode{}, ErrInvalidPromoCode
+		}
+		// the currency only makes sense for a fixed amount
+		code.Currency = nil
+	case entity.DiscountTypeFixed:
+		if code.DiscountValue < 1 || code.Currency == nil {
+			return entity.PromoCode{}, ErrInvalidPromoCode
+		}
+	default:
+		return entity.PromoCode{}, ErrInvalidPromoCode
+	}
+	if code.ValidFrom != nil && code.ValidUntil != nil && code.ValidUntil.Before(*code.ValidFrom) {
+		return entity.PromoCode{}, ErrInvalidPromoCode
+	}
+
+	var created entity.PromoCode
+	err = s.txManager.WithinTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+		created, err = s.promoCodeRepository.Create(txCtx, code)
+		if err != nil {
+			if errors.Is(err, promo_code_repo.ErrPromoCodeAlreadyExists) {
+				return ErrPromoCodeAlreadyExists
+			}
+			if errors.Is(err, promo_code_repo.ErrOfferNotFound) {
+				return ErrOfferNotFound
+			}
+			if errors.Is(err, promo_code_repo.ErrInvalidPromoCode) {
+				return ErrInvalidPromoCode
+			}
+			logger.FromContext(ctx).Errorf("PromoCodeService.CreatePromoCode error: %v", err)
+			return ErrCannotCreatePromoCode
+		}
+		return nil
+	})
+
+	if err != nil {
+		return entity.PromoCode{}, err
+	}
+
+	logger.FromContext(ctx).Infof("PromoCodeService.CreatePromoCode success: id=%s", created.ID)
+	return created, nil
+}
+
+func (s *PromoCodeService) GetPromoCodes(ctx context.Context, page int, pageSize int) (codes []entity.PromoCode, total int, err error) {
+	ctx, span := tracing.Start(ctx, "PromoCodeService.GetPromoCodes")
+	defer tracing.End(span, &err)
+	logger.FromContext(ctx).Info("PromoCodeService.GetPromoCodes called")
+
+	limit := pageSize
+	offset := (page - 1) * pageSize
+
+	codes, total, err = s.promoCodeRepository.GetAll(ctx, limit, offset)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("PromoCodeService.GetPromoCodes error: %v", err)
+		return nil, 0, ErrCannotFetchPromoCodes
+	}
+
+	logger.FromContext(ctx).Infof("PromoCodeService.GetPromoCodes success: count=%d", len(codes))
+	return codes, total, nil
+}
@@ -5,29 +5,140 @@ import (
 	"time"
 
 	"github.com/4udiwe/subscription-service/internal/entity"
+	"github.com/4udiwe/subscription-service/pkg/cursor"
 	"github.com/google/uuid"
 )
 
 type SubscriptionRepository interface {
-	Create(ctx context.Context, userID, offerID uuid.UUID, startDate, endDate time.Time) (entity.Subscription, error)
-	GetAll(ctx context.Context, limit int, offset int) (subs []entity.SubscriptionFullInfo, total int, err error)
-	GetAllByUserID(ctx context.Context, userID uuid.UUID, limit int, offset int) (subs []entity.SubscriptionFullInfo, total int, err error)
-	// GetById(ctx context.Context, id uuid.UUID) (entity.Subscription, error)
-	Delete(ctx context.Context, id uuid.UUID) error
+	Create(
+		ctx context.Context,
+		userID, offerID uuid.UUID,
+		startDate, endDate time.Time,
+		trialEndDate *time.Time,
+		price int,
+		priceID uuid.UUID,
+		promoCodeID *uuid.UUID,
+		autoRenew bool,
+	) (entity.Subscription, error)
+	CreateRenewal(ctx context.Context, prev entity.Subscription, startDate, endDate time.Time, price int, priceID uuid.UUID) (entity.Subscription, error)
+	GetRenewable(ctx context.Context, until time.Time, limit int) ([]entity.Subscription, error)
+	DisableAutoRenew(ctx context.Context, id uuid.UUID) error
+	GetPendingRenewal(ctx context.Context, prevID uuid.UUID, date time.Time) (entity.Subscription, error)
+	MarkRenewalFailed(ctx context.Context, id uuid.UUID, reason string) error
+	MarkExpiringNotified(ctx context.Context, from, until time.Time, limit int) ([]entity.Subscription, error)
+	GetAll(
+		ctx context.Context,
+		status *entity.SubscriptionStatus,
+		limit int,
+		offset int,
+	) (subs []entity.SubscriptionFullInfo, total int, err error)
+	GetAllByUserID(
+		ctx context.Context,
+		userID uuid.UUID,
+		status *entity.SubscriptionStatus,
+		limit int,
+		offset int,
+	) (subs []entity.SubscriptionFullInfo, total int, err error)
+	GetAllAfter(
+		ctx context.Context,
+		status *entity.SubscriptionStatus,
+		after *cursor.Cursor,
+		limit int,
+	) (subs []entity.SubscriptionFullInfo, next *cursor.Cursor, err error)
+	GetAllByUserIDAfter(
+		ctx context.Context,
+		userID uuid.UUID,
+		status *entity.SubscriptionStatus,
+		after *cursor.Cursor,
+		limit int,
+	) (subs []entity.SubscriptionFullInfo, next *cursor.Cursor, err error)
+	GetAllByUserIDAndSubscriptionNameAfter(
+		ctx context.Context,
+		userID uuid.UUID,
+		subscriptionName string,
+		status *entity.SubscriptionStatus,
+		startPeriod *time.Time,
+		endPeriod *time.Time,
+		currency string,
+		after *cursor.Cursor,
+		limit int,
+	) (subs []entity.SubscriptionFullInfo, totalPrice int, next *cursor.Cursor, err error)
+	GetById(ctx context.Context, id uuid.UUID) (entity.Subscription, error)
+	Update(
+		ctx context.Context,
+		id, offerID uuid.UUID,
+		startDate, endDate time.Time,
+		trialEndDate *time.Time,
+		price int,
+		priceID uuid.UUID,
+		autoRenew bool,
+	) (entity.Subscription, error)
+	Cancel(
+		ctx context.Context,
+		id uuid.UUID,
+		status entity.SubscriptionStatus,
+		endDate time.Time,
+		reason *string,
+	) (entity.Subscription, error)
+	ExpireEnded(ctx context.Context, date, renewableAfter time.Time) ([]entity.Subscription, error)
+	SetStatusAndEndDate(
+		ctx context.Context,
+		id uuid.UUID,
+		status entity.SubscriptionStatus,
+		endDate time.Time,
+	) (entity.Subscription, error)
+	CreatePause(ctx context.Context, subID uuid.UUID, pausedAt time.Time) (entity.SubscriptionPause, error)
+	CloseOpenPause(ctx context.Context, subID uuid.UUID, resumedAt time.Time) (entity.SubscriptionPause, error)
+	GetPauses(ctx context.Context, subID uuid.UUID) ([]entity.SubscriptionPause, error)
 	GetAllByUserIDAndSubscriptionName(
 		ctx context.Context,
 		userID uuid.UUID,
 		subscriptionName string,
+		status *entity.SubscriptionStatus,
 		startPeriod *time.Time,
 		endPeriod *time.Time,
+		currency string,
 		limit int,
 		offset int,
 	) (subs []entity.SubscriptionFullInfo, totalPrice int, totalCount int, err error)
-	HasActiveSubscriptionOnServiceForDate(ctx context.Context, userID uuid.UUID, serviceName string, date time.Time) (bool, error)
+	GetAllOverlappingPeriod(
+		ctx context.Context,
+		userID *uuid.UUID,
+		serviceNames []string,
+		from time.Time,
+		to time.Time,
+		currency string,
+	) ([]entity.SubscriptionFullInfo, error)
+	GetPausesBySubscriptionIDs(ctx context.Context, subIDs []uuid.UUID) ([]entity.SubscriptionPause, error)
+	HasActiveSubscriptionOnServiceForPeriod(
+		ctx context.Context,
+		userID uuid.UUID,
+		serviceName string,
+		start time.Time,
+		end time.Time,
+		exclude ...uuid.UUID,
+	) (bool, error)
+	HasUsedTrial(ctx context.Context, userID uuid.UUID, serviceName string) (bool, error)
+	CountActiveByService(ctx context.Context) (map[string]int, error)
 }
 
 type OfferRepository interface {
-	Create(ctx context.Context, name string, price int, durationMonths int) (entity.Offer, error)
+	Create(ctx context.Context, name string, price int, currency string, durationMonths int, trialDays int) (entity.Offer, error)
 	GetByID(ctx context.Context, id uuid.UUID) (entity.Offer, error)
-	GetByNameAndPrice(ctx context.Context, name string, price int) (entity.Offer, error)
+	GetByNameAndCurrency(ctx context.Context, name string, currency string) (entity.Offer, error)
+	GetPriceByID(ctx context.Context, id uuid.UUID) (entity.OfferPrice, error)
+	GetPriceOn(ctx context.Context, offerID uuid.UUID, date time.Time) (entity.OfferPrice, error)
+}
+
+type PromoCodeRepository interface {
+	GetByCodeForUpdate(ctx context.Context, code string) (entity.PromoCode, error)
+	Redeem(ctx context.Context, id uuid.UUID) error
+}
+
+type OutboxRepository interface {
+	Add(ctx context.Context, events ...entity.OutboxEvent) error
+}
+
+type AuditRepository interface {
+	Add(ctx context.Context, entries ...entity.AuditEntry) error
 }
@@ -0,0 +1,210 @@
+package subscription
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/4udiwe/subscription-service/internal/entity"
+	subscription_repo "github.com/4udiwe/subscription-service/internal/repository/subscription"
+	"github.com/4udiwe/subscription-service/pkg/logger"
+	"github.com/4udiwe/subscription-service/pkg/tracing"
+	"github.com/google/uuid"
+)
+
+// maxReportMonths limits the length of a cost report, the subscriptions of the whole range and
+// their pauses are loaded at once.
+const maxReportMonths = 12
+
+// period is a half-open range of dates [start, end).
+type period struct {
+	start time.Time
+	end   time.Time
+}
+
+// GetCostReport returns how much the subscriptions cost within [from, to], both dates included.
+// Each subscription is charged by the day: its price is spread evenly over the paid days of the
+// subscription itself, so later changes to the offer duration do not affect it, and only the days
+// that fall in the range are counted. Paused days are not charged,
+// since the subscription is extended by them on resume, and neither are the days of a free trial.
+// Prices are converted to currency at the rate for the start date of each subscription.
+// Empty userID and serviceNames mean all users and all services. The range covers at most
+// maxReportMonths months.
+func (s *SubscriptionService) GetCostReport(
+	ctx context.Context,
+	userID *uuid.UUID,
+	serviceNames []string,
+	from time.Time,
+	to time.Time,
+	currency string,
+) (_ entity.CostReport, err error) {
+	ctx, span := tracing.Start(ctx, "SubscriptionService.GetCostReport")
+	defer tracing.End(span, &err)
+	logger.FromContext(ctx).Infof("SubscriptionService.GetCostReport called: userID=%v, serviceNames=%v, from=%s, to=%s, currency=%s", userID, serviceNames, from, to, currency)
+
+	from = truncateToDate(from)
+	to = truncateToDate(to)
+	if to.Before(from) {
+		logger.FromContext(ctx).Errorf("SubscriptionService.GetCostReport error: to=%s is before from=%s", to, from)
+		return entity.CostReport{}, ErrInvalidReportPeriod
+	}
+	rangeEnd := to.AddDate(0, 0, 1)
+	if rangeEnd.After(from.AddDate(0, maxReportMonths, 0)) {
+		logger.FromContext(ctx).Errorf("SubscriptionService.GetCostReport error: from=%s to=%s is longer than %d months", from, to, maxReportMonths)
+		return entity.CostReport{}, ErrReportPeriodTooLong
+	}
+
+	subs, err := s.subRepository.GetAllOverlappingPeriod(ctx, userID, serviceNames, from, rangeEnd, currency)
+	if err != nil {
+		if errors.Is(err, subscription_repo.ErrExchangeRateNotFound) {
+			return entity.CostReport{}, ErrExchangeRateNotFound
+		}
+		logger.FromContext(ctx).Errorf("SubscriptionService.GetCostReport error getting subscriptions: %v", err)
+		return entity.CostReport{}, ErrCannotBuildCostReport
+	}
+
+	subIDs := make([]uuid.UUID, 0, len(subs))
+	for _, sub := range subs {
+		subIDs = append(subIDs, sub.ID)
+	}
+	pauses, err := s.subRepository.GetPausesBySubscriptionIDs(ctx, subIDs)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("SubscriptionService.GetCostReport error getting pauses: %v", err)
+		return entity.CostReport{}, ErrCannotBuildCostReport
+	}
+	pausesBySub := make(map[uuid.UUID][]entity.SubscriptionPause)
+	for _, pause := range pauses {
+		pausesBySub[pause.SubscriptionID] = append(pausesBySub[pause.SubscriptionID], pause)
+	}
+
+	var months []period
+	for monthStart := time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, time.UTC); monthStart.Before(rangeEnd); monthStart = monthStart.AddDate(0, 1, 0) {
+		months = append(months, period{
+			start: latest(monthStart, from),
+			end:   earliest(monthStart.AddDate(0, 1, 0), rangeEnd),
+		})
+	}
+
+	// costs[i][serviceName] is the cost of the service in the i-th month
+	costs := make([]map[string]int, len(months))
+	for i := range costs {
+		costs[i] = make(map[string]int)
+	}
+
+	for _, sub := range subs {
+		paidDays := paidPeriodDays(sub.Subscription, pausesBySub[sub.ID])
+		active := activePeriods(sub.Subscription, pausesBySub[sub.ID])
+
+		for i, month := range months {
+			days := 0
+			for _, p := range active {
+				days += overlapDays(p, month)
+			}
+			if days > 0 {
+				costs[i][sub.OfferName] += prorate(sub.Price, days, paidDays)
+			}
+		}
+	}
+
+	report := entity.CostReport{From: from, To: to, Currency: currency}
+	totals := make(map[string]int)
+	for i, month := range months {
+		monthly := entity.MonthlyCost{
+			Month:    time.Date(month.start.Year(), month.start.Month(), 1, 0, 0, 0, 0, time.UTC),
+			Services: toServiceCosts(costs[i]),
+		}
+		for name, cost := range costs[i] {
+			monthly.Total += cost
+			totals[name] += cost
+		}
+		report.Total += monthly.Total
+		report.Months = append(report.Months, monthly)
+	}
+	report.Services = toServiceCosts(totals)
+
+	logger.FromContext(ctx).Infof("SubscriptionService.GetCostReport success: subscriptions=%d, total=%d", len(subs), report.Total)
+	return report, nil
+}
+
+// activePeriods returns the paid days of the subscription: from the end of the trial (or the
+// start date) to the end date without the pauses. An open pause lasts until the subscription
+// is resumed, so nothing after it is counted.
+func activePeriods(sub entity.Subscription, pauses []entity.SubscriptionPause) []period {
+	periods := []period{{start: truncateToDate(paidStart(sub)), end: truncateToDate(sub.EndDate)}}
+
+	for _, pause := range pauses {
+		pausedAt := truncateToDate(pause.PausedAt)
+
+		var next []period
+		for _, p := range periods {
+			if pause.ResumedAt == nil {
+				if pausedAt.After(p.start) {
+					next = append(next, period{start: p.start, end: earliest(p.end, pausedAt)})
+				}
+				continue
+			}
+			resumedAt := truncateToDate(*pause.ResumedAt)
+			if pausedAt.After(p.start) {
+				next = append(next, period{start: p.start, end: earliest(p.end, pausedAt)})
+			}
+			if resumedAt.Before(p.end) {
+				next = append(next, period{start: latest(p.start, resumedAt), end: p.end})
+			}
+		}
+		periods = next
+	}
+
+	return periods
+}
+
+// paidPeriodDays returns the number of days the price of the subscription pays for: from the end
+// of the trial (or the start date) to the end date without the resumed pauses, since the end date
+// is extended by them. An open pause has not extended it yet.
+func paidPeriodDays(sub entity.Subscription, pauses []entity.SubscriptionPause) int {
+	paid := period{start: truncateToDate(paidStart(sub)), end: truncateToDate(sub.EndDate)}
+
+	days := overlapDays(paid, paid)
+	for _, pause := range pauses {
+		if pause.ResumedAt == nil {
+			continue
+		}
+		days -= overlapDays(period{start: truncateToDate(pause.PausedAt), end: truncateToDate(*pause.ResumedAt)}, paid)
+	}
+	return max(days, 0)
+}
+
+// overlapDays returns the number of days that a and b have in common.
+func overlapDays(a, b period) int {
+	start := latest(a.start, b.start)
+	end := earliest(a.end, b.end)
+	if !end.After(start) {
+		return 0
+	}
+	return daysBetween(start, end)
+}
+
+func toServiceCosts(costs map[string]int) []entity.ServiceCost {
+	services := make([]entity.ServiceCost, 0, len(costs))
+	for name, cost := range costs {
+		services = append(services, entity.ServiceCost{ServiceName: name, Cost: cost})
+	}
+	sort.Slice(services, func(i, j int) bool {
+		return services[i].ServiceName < services[j].ServiceName
+	})
+	return services
+}
+
+func earliest(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+func latest(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
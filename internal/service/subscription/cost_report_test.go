@@ -0,0 +1,239 @@
+package subscription
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/4udiwe/subscription-service/internal/entity"
+	"github.com/samber/lo"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestActivePeriods(t *testing.T) {
+	trialEnd := date(2025, 1, 8)
+	longTrialEnd := date(2025, 2, 10)
+	resumedAt := date(2025, 1, 15)
+
+	tests := []struct {
+		name   string
+		sub    entity.Subscription
+		pauses []entity.SubscriptionPause
+		want   []period
+	}{
+		{
+			name: "no pauses",
+			sub:  entity.Subscription{StartDate: date(2025, 1, 1), EndDate: date(2025, 2, 1)},
+			want: []period{{start: date(2025, 1, 1), end: date(2025, 2, 1)}},
+		},
+		{
+			name: "trial is not paid",
+			sub:  entity.Subscription{StartDate: date(2025, 1, 1), EndDate: date(2025, 2, 8), TrialEndDate: &trialEnd},
+			want: []period{{start: date(2025, 1, 8), end: date(2025, 2, 8)}},
+		},
+		{
+			name: "ended within the trial",
+			sub:  entity.Subscription{StartDate: date(2025, 1, 1), EndDate: date(2025, 2, 1), TrialEndDate: &longTrialEnd},
+			want: []period{{start: date(2025, 2, 1), end: date(2025, 2, 1)}},
+		},
+		{
+			name: "resumed pause",
+			sub:  entity.Subscription{StartDate: date(2025, 1, 1), EndDate: date(2025, 2, 6)},
+			pauses: []entity.SubscriptionPause{
+				{PausedAt: date(2025, 1, 10).Add(15 * time.Hour), ResumedAt: &resumedAt},
+			},
+			want: []period{
+				{start: date(2025, 1, 1), end: date(2025, 1, 10)},
+				{start: date(2025, 1, 15), end: date(2025, 2, 6)},
+			},
+		},
+		{
+			name: "open pause",
+			sub:  entity.Subscription{StartDate: date(2025, 1, 1), EndDate: date(2025, 2, 1)},
+			pauses: []entity.SubscriptionPause{
+				{PausedAt: date(2025, 1, 10)},
+			},
+			want: []period{{start: date(2025, 1, 1), end: date(2025, 1, 10)}},
+		},
+		{
+			name: "two pauses",
+			sub:  entity.Subscription{StartDate: date(2025, 1, 1), EndDate: date(2025, 2, 5)},
+			pauses: []entity.SubscriptionPause{
+				{PausedAt: date(2025, 1, 5), ResumedAt: lo.ToPtr(date(2025, 1, 7))},
+				{PausedAt: date(2025, 1, 20), ResumedAt: lo.ToPtr(date(2025, 1, 22))},
+			},
+			want: []period{
+				{start: date(2025, 1, 1), end: date(2025, 1, 5)},
+				{start: date(2025, 1, 7), end: date(2025, 1, 20)},
+				{start: date(2025, 1, 22), end: date(2025, 2, 5)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := activePeriods(tt.sub, tt.pauses); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("activePeriods() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPaidPeriodDays(t *testing.T) {
+	trialEnd := date(2025, 1, 8)
+
+	tests := []struct {
+		name   string
+		sub    entity.Subscription
+		pauses []entity.SubscriptionPause
+		want   int
+	}{
+		{
+			name: "no pauses",
+			sub:  entity.Subscription{StartDate: date(2025, 1, 1), EndDate: date(2025, 2, 1)},
+			want: 31,
+		},
+		{
+			name: "trial is not paid",
+			sub:  entity.Subscription{StartDate: date(2025, 1, 1), EndDate: date(2025, 2, 8), TrialEndDate: &trialEnd},
+			want: 31,
+		},
+		{
+			name: "extended by a resumed pause",
+			sub:  entity.Subscription{StartDate: date(2025, 1, 1), EndDate: date(2025, 2, 6)},
+			pauses: []entity.SubscriptionPause{
+				{PausedAt: date(2025, 1, 10), ResumedAt: lo.ToPtr(date(2025, 1, 15))},
+			},
+			want: 31,
+		},
+		{
+			name: "open pause",
+			sub:  entity.Subscription{StartDate: date(2025, 1, 1), EndDate: date(2025, 2, 1)},
+			pauses: []entity.SubscriptionPause{
+				{PausedAt: date(2025, 1, 10)},
+			},
+			want: 31,
+		},
+		{
+			name: "shortened by an admin",
+			sub:  entity.Subscription{StartDate: date(2025, 1, 1), EndDate: date(2025, 1, 16)},
+			want: 15,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := paidPeriodDays(tt.sub, tt.pauses); got != tt.want {
+				t.Errorf("paidPeriodDays() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOverlapDays(t *testing.T) {
+	january := period{start: date(2025, 1, 1), end: date(2025, 2, 1)}
+
+	tests := []struct {
+		name string
+		a, b period
+		want int
+	}{
+		{name: "same", a: january, b: january, want: 31},
+		{name: "inside", a: period{start: date(2025, 1, 10), end: date(2025, 1, 20)}, b: january, want: 10},
+		{name: "crosses the end", a: period{start: date(2025, 1, 20), end: date(2025, 2, 10)}, b: january, want: 12},
+		{name: "crosses the start", a: period{start: date(2024, 12, 20), end: date(2025, 1, 3)}, b: january, want: 2},
+		{name: "adjacent", a: period{start: date(2025, 2, 1), end: date(2025, 3, 1)}, b: january, want: 0},
+		{name: "disjoint", a: period{start: date(2025, 3, 1), end: date(2025, 4, 1)}, b: january, want: 0},
+		{name: "empty", a: period{start: date(2025, 1, 10), end: date(2025, 1, 10)}, b: january, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := overlapDays(tt.a, tt.b); got != tt.want {
+				t.Errorf("overlapDays(%v, %v) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+			if got := overlapDays(tt.b, tt.a); got != tt.want {
+				t.Errorf("overlapDays(%v, %v) = %d, want %d", tt.b, tt.a, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProrate(t *testing.T) {
+	tests := []struct {
+		name                   string
+		price, days, totalDays int
+		want                   int
+	}{
+		{name: "whole period", price: 999, days: 31, totalDays: 31, want: 999},
+		{name: "half", price: 100, days: 15, totalDays: 30, want: 50},
+		{name: "rounded down", price: 100, days: 1, totalDays: 3, want: 33},
+		{name: "rounded up", price: 100, days: 2, totalDays: 3, want: 67},
+		{name: "half unit rounded up", price: 100, days: 1, totalDays: 8, want: 13},
+		{name: "more days than the period", price: 100, days: 40, totalDays: 30, want: 100},
+		{name: "no days", price: 100, days: 0, totalDays: 30, want: 0},
+		{name: "empty period", price: 100, days: 10, totalDays: 0, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := prorate(tt.price, tt.days, tt.totalDays); got != tt.want {
+				t.Errorf("prorate(%d, %d, %d) = %d, want %d", tt.price, tt.days, tt.totalDays, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToServiceCosts(t *testing.T) {
+	tests := []struct {
+		name  string
+		costs map[string]int
+		want  []entity.ServiceCost
+	}{
+		{name: "empty", costs: nil, want: []entity.ServiceCost{}},
+		{
+			name:  "sorted by name",
+			costs: map[string]int{"Yandex Plus": 300, "Kinopoisk": 200, "Spotify": 0},
+			want: []entity.ServiceCost{
+				{ServiceName: "Kinopoisk", Cost: 200},
+				{ServiceName: "Spotify", Cost: 0},
+				{ServiceName: "Yandex Plus", Cost: 300},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := toServiceCosts(tt.costs); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("toServiceCosts() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetCostReportPeriod(t *testing.T) {
+	s := &SubscriptionService{}
+
+	tests := []struct {
+		name     string
+		from, to time.Time
+		want     error
+	}{
+		{name: "end before start", from: date(2025, 3, 1), to: date(2025, 2, 28), want: ErrInvalidReportPeriod},
+		{name: "longer than a year", from: date(2025, 1, 1), to: date(2026, 1, 1), want: ErrReportPeriodTooLong},
+		{name: "much longer", from: date(2000, 1, 1), to: date(2025, 1, 1), want: ErrReportPeriodTooLong},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.GetCostReport(context.Background(), nil, nil, tt.from, tt.to, entity.DefaultCurrency); !errors.Is(err, tt.want) {
+				t.Errorf("GetCostReport(%s, %s) error = %v, want %v", tt.from, tt.to, err, tt.want)
+			}
+		})
+	}
+}
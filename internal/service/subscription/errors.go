@@ -7,12 +7,40 @@ var (
 	ErrCannotFindOffer   = errors.New("cannot find offer")
 	ErrCannotCreateOffer = errors.New("cannot create offer")
 
+	ErrOfferPriceMismatch = errors.New("price differs from the current price of the offer")
+
 	ErrSubscriptionNotFound     = errors.New("subscription not found")
 	ErrCannotFindSubscription   = errors.New("cannot find subscription")
 	ErrCannotCreateSubscription = errors.New("cannot create subscription")
 	ErrCannotFetchSubscriptions = errors.New("cannot fetch subscriptions")
-	ErrCannotDeleteSubscription = errors.New("cannot delete subscription")
+	ErrCannotCountSubscriptions = errors.New("cannot count subscriptions")
+	ErrCannotUpdateSubscription = errors.New("cannot update subscription")
+	ErrInvalidSubscriptionDates = errors.New("end date must not be before start date")
+	ErrCannotCancelSubscription = errors.New("cannot cancel subscription")
+	ErrSubscriptionNotActive    = errors.New("subscription is not active")
+	ErrSubscriptionNotPaused    = errors.New("subscription is not paused")
+	ErrCannotPauseSubscription  = errors.New("cannot pause subscription")
+	ErrSubscriptionFollowed     = errors.New("subscription is followed by another subscription on the service, the pause could not extend it")
+	ErrCannotResumeSubscription = errors.New("cannot resume subscription")
+	ErrCannotChangePlan         = errors.New("cannot change plan")
+	ErrOfferOfAnotherService    = errors.New("new offer belongs to another service")
+	ErrSameOffer                = errors.New("subscription already uses the given offer")
+	ErrInvalidSwitchDate        = errors.New("switch date must be within the current subscription period")
+	ErrInvalidReportPeriod      = errors.New("end of the report period must not be before its start")
+	ErrReportPeriodTooLong      = errors.New("report period must not be longer than 12 months")
+	ErrCannotBuildCostReport    = errors.New("cannot build cost report")
+	ErrExchangeRateNotFound     = errors.New("exchange rate not found for a subscription currency")
+	ErrTrialAlreadyUsed         = errors.New("user already had a trial on the service")
+	ErrCannotCheckTrial         = errors.New("cannot check trial")
+	ErrSubscriptionInTrial      = errors.New("subscription is in its trial period")
+	ErrPromoCodeNotFound        = errors.New("promo code not found")
+	ErrPromoCodeNotValid        = errors.New("promo code is not valid on this date")
+	ErrPromoCodeNotApplicable   = errors.New("promo code does not apply to the offer")
+	ErrPromoCodeExhausted       = errors.New("promo code has no redemptions left")
+	ErrCannotApplyPromoCode     = errors.New("cannot apply promo code")
+	ErrCannotWriteEvents        = errors.New("cannot write events")
+	ErrCannotWriteAuditLog      = errors.New("cannot write audit log")
 
-	ErrUserAlreadyHasActiveSubscription = errors.New("user already has an active subscription for the given offer and date")
+	ErrUserAlreadyHasActiveSubscription = errors.New("user already has an active subscription for the service within the given period")
 	ErrCannotCheckActiveSubscription    = errors.New("cannot check active subscription")
 )
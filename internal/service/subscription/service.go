@@ -3,216 +3,1254 @@ package subscription
 import (
 	"context"
 	"errors"
+	"math"
+	"strings"
 	"time"
 
 	"github.com/4udiwe/subscription-service/internal/entity"
+	"github.com/4udiwe/subscription-service/internal/metrics"
 	offer_repo "github.com/4udiwe/subscription-service/internal/repository/offer"
+	promo_code_repo "github.com/4udiwe/subscription-service/internal/repository/promo_code"
 	subscription_repo "github.com/4udiwe/subscription-service/internal/repository/subscription"
+	"github.com/4udiwe/subscription-service/pkg/actor"
+	"github.com/4udiwe/subscription-service/pkg/cursor"
+	"github.com/4udiwe/subscription-service/pkg/logger"
+	"github.com/4udiwe/subscription-service/pkg/tracing"
 	"github.com/4udiwe/subscription-service/pkg/transactor"
 	"github.com/google/uuid"
-	"github.com/sirupsen/logrus"
+	"github.com/samber/lo"
 )
 
-const defaultDurationMonths = 1
+const (
+	defaultDurationMonths = 1
+
+	// deleteReason is the cancel reason of a subscription deleted over the API
+	deleteReason = "deleted"
+)
 
 type SubscriptionService struct {
-	subRepository   SubscriptionRepository
-	offerRepository OfferRepository
-	txManager       transactor.Transactor
+	subRepository       SubscriptionRepository
+	offerRepository     OfferRepository
+	promoCodeRepository PromoCodeRepository
+	outboxRepository    OutboxRepository
+	auditRepository     AuditRepository
+	txManager           transactor.Transactor
 }
 
-func New(subRepo SubscriptionRepository, offerRepo OfferRepository, txManager transactor.Transactor) *SubscriptionService {
+func New(
+	subRepo SubscriptionRepository,
+	offerRepo OfferRepository,
+	promoCodeRepo PromoCodeRepository,
+	outboxRepo OutboxRepository,
+	auditRepo AuditRepository,
+	txManager transactor.Transactor,
+) *SubscriptionService {
 	return &SubscriptionService{
-		subRepository:   subRepo,
-		offerRepository: offerRepo,
-		txManager:       txManager,
+		subRepository:       subRepo,
+		offerRepository:     offerRepo,
+		promoCodeRepository: promoCodeRepo,
+		outboxRepository:    outboxRepo,
+		auditRepository:     auditRepo,
+		txManager:           txManager,
 	}
 }
 
+// CreateSubscription subscribes the user to the offer of the service in the currency. When there
+// is no such offer it is created at the given price if createOffer is set, otherwise
+// ErrOfferNotFound is returned.
 func (s *SubscriptionService) CreateSubscription(
 	ctx context.Context,
 	userID uuid.UUID,
 	serviceName string,
 	price int,
+	currency string,
 	startDate time.Time,
 	endDate *time.Time,
-) (entity.SubscriptionFullInfo, error) {
-	logrus.Infof("SubscriptionService.CreateSubscription called: userID=%s, serviceName=%s, price=%d, startDate=%v, endDate=%v", userID, serviceName, price, startDate, endDate)
-	var sub entity.SubscriptionFullInfo
+	autoRenew bool,
+	skipTrial bool,
+	promoCode *string,
+	createOffer bool,
+) (_ entity.SubscriptionFullInfo, err error) {
+	ctx, span := tracing.Start(ctx, "SubscriptionService.CreateSubscription")
+	defer tracing.End(span, &err)
+	logger.FromContext(ctx).Infof("SubscriptionService.CreateSubscription called: userID=%s, serviceName=%s, price=%d, currency=%s, startDate=%v, endDate=%v, autoRenew=%t, skipTrial=%t, promoCode=%v, createOffer=%t", userID, serviceName, price, currency, startDate, endDate, autoRenew, skipTrial, promoCode, createOffer)
+	var (
+		sub          entity.SubscriptionFullInfo
+		offerCreated bool
+	)
 
-	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
-		// check if offer with given name and price exists
-		offer, err := s.offerRepository.GetByNameAndPrice(ctx, serviceName, price)
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		// the offer of the service in the currency, its current price version is bought
+		offer, err := s.offerRepository.GetByNameAndCurrency(ctx, serviceName, currency)
 		if err != nil && !errors.Is(err, offer_repo.ErrOfferNotFound) {
-			logrus.Errorf("SubscriptionService.GetByNameAndPrice error getting offer: %v", err)
+			logger.FromContext(ctx).Errorf("SubscriptionService.CreateSubscription error getting offer: %v", err)
 			return ErrCannotFindOffer
 		}
 
 		if errors.Is(err, offer_repo.ErrOfferNotFound) {
-			// if not -> create it
+			// if not -> create it, only callers that manage offers may set their price
+			if !createOffer {
+				logger.FromContext(ctx).Errorf("SubscriptionService.CreateSubscription error: offer %s in %s not found", serviceName, currency)
+				return ErrOfferNotFound
+			}
 			durationMonths := defaultDurationMonths
 			if endDate != nil {
 				durationMonths = int(endDate.Sub(startDate).Hours() / (24 * 30))
 			}
 
-			offer, err = s.offerRepository.Create(ctx, serviceName, price, durationMonths)
-			if err != nil {
-				logrus.Errorf("SubscriptionService.CreateSubscription error creating offer: %v", err)
+			offer, err = s.offerRepository.Create(ctx, serviceName, price, currency, durationMonths, 0)
+			switch {
+			case errors.Is(err, offer_repo.ErrOfferAlreadyExists):
+				// a concurrent request created it after the lookup above
+				offer, err = s.offerRepository.GetByNameAndCurrency(ctx, serviceName, currency)
+				if err != nil {
+					logger.FromContext(ctx).Errorf("SubscriptionService.CreateSubscription error getting offer: %v", err)
+					return ErrCannotFindOffer
+				}
+			case err != nil:
+				logger.FromContext(ctx).Errorf("SubscriptionService.CreateSubscription error creating offer: %v", err)
 				return ErrCannotCreateOffer
+			default:
+				offerCreated = true
+
+				if err := s.addAudit(ctx, entity.NewOfferAudit(entity.AuditActionCreate, nil, &offer)); err != nil {
+					return err
+				}
+				if err := s.addEvents(ctx, entity.NewOfferEvent(entity.EventOfferCreated, offer)); err != nil {
+					return err
+				}
 			}
 		}
 
-		// check if user has active subscription for the offer on the start date
-		hasActive, err := s.subRepository.HasActiveSubscriptionOnServiceForDate(ctx, userID, serviceName, startDate)
+		if offer.Price != price {
+			logger.FromContext(ctx).Errorf("SubscriptionService.CreateSubscription error: price=%d differs from the current price=%d of the offer", price, offer.Price)
+			return ErrOfferPriceMismatch
+		}
+
+		trialEndDate, err := s.trialEndDate(ctx, userID, offer, startDate, skipTrial)
 		if err != nil {
-			logrus.Errorf("SubscriptionService.CreateSubscription error checking active subscription: %v", err)
+			return err
+		}
+		// the paid period starts after the trial
+		subEndDate := paidEndDate(startDate, trialEndDate, offer)
+
+		// check if user has active subscription for the service within the new period
+		hasActive, err := s.subRepository.HasActiveSubscriptionOnServiceForPeriod(ctx, userID, serviceName, startDate, subEndDate)
+		if err != nil {
+			logger.FromContext(ctx).Errorf("SubscriptionService.CreateSubscription error checking active subscription: %v", err)
 			return ErrCannotCheckActiveSubscription
 		}
 		if hasActive {
-			logrus.Errorf("SubscriptionService.CreateSubscription error: user already has an active subscription for this offer on the start date")
+			logger.FromContext(ctx).Errorf("SubscriptionService.CreateSubscription error: user already has an active subscription for this service within the period")
 			return ErrUserAlreadyHasActiveSubscription
 		}
 
-		// create subscription
-		sub.Subscription, err = s.subRepository.Create(ctx, userID, offer.ID, startDate, startDate.AddDate(0, offer.DurationMonths, 0))
+		effectivePrice, promoCodeID, err := s.applyPromoCode(ctx, promoCode, offer)
+		if err != nil {
+			return err
+		}
+
+		sub.Subscription, err = s.subRepository.Create(ctx, userID, offer.ID, startDate, subEndDate, trialEndDate, effectivePrice, offer.PriceID, promoCodeID, autoRenew)
 		sub.OfferName = offer.Name
-		sub.Price = offer.Price
+		sub.ListPrice = offer.Price
+		sub.Currency = offer.Currency
+		sub.DurationMonths = offer.DurationMonths
 
 		if err != nil {
-			logrus.Errorf("SubscriptionService.CreateSubscription error creating subscription: %v", err)
+			if errors.Is(err, subscription_repo.ErrUserAlreadyHasActiveSubscription) {
+				return ErrUserAlreadyHasActiveSubscription
+			}
+			if errors.Is(err, subscription_repo.ErrTrialAlreadyUsed) {
+				return ErrTrialAlreadyUsed
+			}
+			logger.FromContext(ctx).Errorf("SubscriptionService.CreateSubscription error creating subscription: %v", err)
 			return ErrCannotCreateSubscription
 		}
-		return nil
+
+		if err := s.addAudit(ctx, entity.NewSubscriptionAudit(entity.AuditActionCreate, nil, &sub.Subscription)); err != nil {
+			return err
+		}
+		return s.addEvents(ctx, entity.NewSubscriptionEvent(entity.EventSubscriptionCreated, sub.Subscription))
 	})
 
 	if err != nil {
 		return entity.SubscriptionFullInfo{}, err
 	}
 
-	logrus.Infof("SubscriptionService.CreateSubscription success: id=%s", sub.ID)
+	if offerCreated {
+		metrics.OffersCreated.Inc()
+	}
+	metrics.SubscriptionsCreated.Inc()
+	logger.FromContext(ctx).Infof("SubscriptionService.CreateSubscription success: id=%s", sub.ID)
 	return sub, nil
 }
 
-func (s *SubscriptionService) CreateSubscriptionByOfferID(ctx context.Context, userID, offerID uuid.UUID, startDate time.Time) (entity.SubscriptionFullInfo, error) {
-	logrus.Infof("SubscriptionService.CreateSubscriptionByOfferID called: userID=%s, offerID=%s, startDate=%v", userID, offerID, startDate)
+func (s *SubscriptionService) CreateSubscriptionByOfferID(
+	ctx context.Context,
+	userID, offerID uuid.UUID,
+	startDate time.Time,
+	autoRenew bool,
+	skipTrial bool,
+	promoCode *string,
+) (_ entity.SubscriptionFullInfo, err error) {
+	ctx, span := tracing.Start(ctx, "SubscriptionService.CreateSubscriptionByOfferID")
+	defer tracing.End(span, &err)
+	logger.FromContext(ctx).Infof("SubscriptionService.CreateSubscriptionByOfferID called: userID=%s, offerID=%s, startDate=%v, autoRenew=%t, skipTrial=%t, promoCode=%v", userID, offerID, startDate, autoRenew, skipTrial, promoCode)
 	var subFullInfo entity.SubscriptionFullInfo
 
-	err := s.txManager.WithinTransaction(ctx, func(txCtx context.Context) error {
+	err = s.txManager.WithinTransaction(ctx, func(txCtx context.Context) error {
 		offer, err := s.offerRepository.GetByID(txCtx, offerID)
 		if err != nil && !errors.Is(err, offer_repo.ErrOfferNotFound) {
-			logrus.Errorf("SubscriptionService.CreateSubscriptionByOfferID error: %v", err)
+			logger.FromContext(ctx).Errorf("SubscriptionService.CreateSubscriptionByOfferID error: %v", err)
 			return ErrCannotFindOffer
 		}
 
 		if errors.Is(err, offer_repo.ErrOfferNotFound) {
-			logrus.Errorf("SubscriptionService.CreateSubscriptionByOfferID error: offer not found")
+			logger.FromContext(ctx).Errorf("SubscriptionService.CreateSubscriptionByOfferID error: offer not found")
 			return ErrOfferNotFound
 		}
 
-		// check if user has active subscription for the offer on the start date
-		hasActive, err := s.subRepository.HasActiveSubscriptionOnServiceForDate(ctx, userID, offer.Name, startDate)
+		trialEndDate, err := s.trialEndDate(txCtx, userID, offer, startDate, skipTrial)
+		if err != nil {
+			return err
+		}
+		subEndDate := paidEndDate(startDate, trialEndDate, offer)
+
+		// check if user has active subscription for the service within the new period
+		hasActive, err := s.subRepository.HasActiveSubscriptionOnServiceForPeriod(txCtx, userID, offer.Name, startDate, subEndDate)
 		if err != nil {
-			logrus.Errorf("SubscriptionService.CreateSubscription error checking active subscription: %v", err)
+			logger.FromContext(ctx).Errorf("SubscriptionService.CreateSubscriptionByOfferID error checking active subscription: %v", err)
 			return ErrCannotCheckActiveSubscription
 		}
 
 		if hasActive {
-			logrus.Errorf("SubscriptionService.CreateSubscription error: user already has an active subscription for this offer on the start date")
+			logger.FromContext(ctx).Errorf("SubscriptionService.CreateSubscriptionByOfferID error: user already has an active subscription for this service within the period")
 			return ErrUserAlreadyHasActiveSubscription
 		}
 
-		sub, err := s.subRepository.Create(txCtx, userID, offer.ID, startDate, startDate.AddDate(0, offer.DurationMonths, 0))
+		price, promoCodeID, err := s.applyPromoCode(txCtx, promoCode, offer)
 		if err != nil {
-			logrus.Errorf("SubscriptionService.CreateSubscriptionByOfferID error creating subscription: %v", err)
+			return err
+		}
+
+		sub, err := s.subRepository.Create(txCtx, userID, offer.ID, startDate, subEndDate, trialEndDate, price, offer.PriceID, promoCodeID, autoRenew)
+		if err != nil {
+			if errors.Is(err, subscription_repo.ErrUserAlreadyHasActiveSubscription) {
+				return ErrUserAlreadyHasActiveSubscription
+			}
+			if errors.Is(err, subscription_repo.ErrTrialAlreadyUsed) {
+				return ErrTrialAlreadyUsed
+			}
+			logger.FromContext(ctx).Errorf("SubscriptionService.CreateSubscriptionByOfferID error creating subscription: %v", err)
 			return ErrCannotCreateSubscription
 		}
 
 		subFullInfo = entity.SubscriptionFullInfo{
-			Subscription: sub,
-			OfferName:    offer.Name,
-			Price:        offer.Price,
+			Subscription:   sub,
+			OfferName:      offer.Name,
+			ListPrice:      offer.Price,
+			Currency:       offer.Currency,
+			DurationMonths: offer.DurationMonths,
 		}
 
-		return nil
+		if err := s.addAudit(txCtx, entity.NewSubscriptionAudit(entity.AuditActionCreate, nil, &sub)); err != nil {
+			return err
+		}
+		return s.addEvents(txCtx, entity.NewSubscriptionEvent(entity.EventSubscriptionCreated, sub))
+	})
+
+	if err != nil {
+		return entity.SubscriptionFullInfo{}, err
+	}
+
+	metrics.SubscriptionsCreated.Inc()
+	logger.FromContext(ctx).Infof("SubscriptionService.CreateSubscriptionByOfferID success: id=%s", subFullInfo.ID)
+	return subFullInfo, nil
+}
+
+// UpdateSubscription changes the start date, end date, offer and/or auto-renew flag of the subscription.
+// If the end date is not given but the start date or the offer changes, the end date is
+// recomputed from the offer duration. A trial keeps its length and moves with the start date.
+// A new offer is charged at its current list price, the promo code discount is not carried over.
+// Without an offer change the subscription keeps the price version it was bought at.
+// The overlap check is repeated without the edited row.
+func (s *SubscriptionService) UpdateSubscription(
+	ctx context.Context,
+	subID uuid.UUID,
+	startDate *time.Time,
+	endDate *time.Time,
+	offerID *uuid.UUID,
+	autoRenew *bool,
+) (_ entity.SubscriptionFullInfo, err error) {
+	ctx, span := tracing.Start(ctx, "SubscriptionService.UpdateSubscription")
+	defer tracing.End(span, &err)
+	logger.FromContext(ctx).Infof("SubscriptionService.UpdateSubscription called: subID=%s, startDate=%v, endDate=%v, offerID=%v, autoRenew=%v", subID, startDate, endDate, offerID, autoRenew)
+	var subFullInfo entity.SubscriptionFullInfo
+
+	err = s.txManager.WithinTransaction(ctx, func(txCtx context.Context) error {
+		current, err := s.subRepository.GetById(txCtx, subID)
+		if err != nil {
+			if errors.Is(err, subscription_repo.ErrSubscriptionNotFound) {
+				logger.FromContext(ctx).Errorf("SubscriptionService.UpdateSubscription error: subscription not found")
+				return ErrSubscriptionNotFound
+			}
+			logger.FromContext(ctx).Errorf("SubscriptionService.UpdateSubscription error getting subscription: %v", err)
+			return ErrCannotFindSubscription
+		}
+
+		newOfferID := current.OfferID
+		if offerID != nil {
+			newOfferID = *offerID
+		}
+
+		offer, err := s.offerRepository.GetByID(txCtx, newOfferID)
+		if err != nil {
+			if errors.Is(err, offer_repo.ErrOfferNotFound) {
+				logger.FromContext(ctx).Errorf("SubscriptionService.UpdateSubscription error: offer not found")
+				return ErrOfferNotFound
+			}
+			logger.FromContext(ctx).Errorf("SubscriptionService.UpdateSubscription error getting offer: %v", err)
+			return ErrCannotFindOffer
+		}
+
+		newStartDate := current.StartDate
+		if startDate != nil {
+			newStartDate = *startDate
+		}
+
+		newTrialEndDate := current.TrialEndDate
+		if current.TrialEndDate != nil {
+			newTrialEndDate = lo.ToPtr(newStartDate.AddDate(0, 0, daysBetween(current.StartDate, *current.TrialEndDate)))
+		}
+
+		var newEndDate time.Time
+		switch {
+		case endDate != nil:
+			newEndDate = *endDate
+		case startDate != nil || offerID != nil:
+			newEndDate = paidEndDate(newStartDate, newTrialEndDate, offer)
+		default:
+			newEndDate = current.EndDate
+		}
+
+		if newEndDate.Before(newStartDate) {
+			return ErrInvalidSubscriptionDates
+		}
+
+		// check if user has another active subscription for the service within the new period
+		hasActive, err := s.subRepository.HasActiveSubscriptionOnServiceForPeriod(txCtx, current.UserID, offer.Name, newStartDate, newEndDate, current.ID)
+		if err != nil {
+			logger.FromContext(ctx).Errorf("SubscriptionService.UpdateSubscription error checking active subscription: %v", err)
+			return ErrCannotCheckActiveSubscription
+		}
+		if hasActive {
+			logger.FromContext(ctx).Errorf("SubscriptionService.UpdateSubscription error: user already has an active subscription for this service within the period")
+			return ErrUserAlreadyHasActiveSubscription
+		}
+
+		newAutoRenew := current.AutoRenew
+		if autoRenew != nil {
+			newAutoRenew = *autoRenew
+		}
+
+		newPrice, newPriceID := current.Price, current.PriceID
+		if offer.ID != current.OfferID {
+			newPrice, newPriceID = offer.Price, offer.PriceID
+		}
+
+		sub, err := s.subRepository.Update(txCtx, current.ID, offer.ID, newStartDate, newEndDate, newTrialEndDate, newPrice, newPriceID, newAutoRenew)
+		if err != nil {
+			if errors.Is(err, subscription_repo.ErrSubscriptionNotFound) {
+				return ErrSubscriptionNotFound
+			}
+			if errors.Is(err, subscription_repo.ErrUserAlreadyHasActiveSubscription) {
+				return ErrUserAlreadyHasActiveSubscription
+			}
+			logger.FromContext(ctx).Errorf("SubscriptionService.UpdateSubscription error updating subscription: %v", err)
+			return ErrCannotUpdateSubscription
+		}
+
+		subFullInfo, err = s.fullInfo(txCtx, sub)
+		if err != nil {
+			return err
+		}
+
+		if err := s.addAudit(txCtx, entity.NewSubscriptionAudit(entity.AuditActionUpdate, &current, &sub)); err != nil {
+			return err
+		}
+		return s.addEvents(txCtx, entity.NewSubscriptionEvent(entity.EventSubscriptionUpdated, sub))
+	})
+
+	if err != nil {
+		return entity.SubscriptionFullInfo{}, err
+	}
+
+	logger.FromContext(ctx).Infof("SubscriptionService.UpdateSubscription success: id=%s", subFullInfo.ID)
+	return subFullInfo, nil
+}
+
+// RenewExpiringSubscriptions creates the next period for auto-renewable subscriptions that end
+// not later than until. Each renewal is written in its own transaction, a subscription that has
+// already been renewed is skipped, so the method is safe to call repeatedly.
+// A promo code discounts only the period it was applied to: the next period is charged the
+// offer price without the discount and carries no promo code.
+func (s *SubscriptionService) RenewExpiringSubscriptions(ctx context.Context, until time.Time, batchSize int) (_ int, err error) {
+	ctx, span := tracing.Start(ctx, "SubscriptionService.RenewExpiringSubscriptions")
+	defer tracing.End(span, &err)
+	logger.FromContext(ctx).Infof("SubscriptionService.RenewExpiringSubscriptions called: until=%v, batchSize=%d", until, batchSize)
+
+	subs, err := s.subRepository.GetRenewable(ctx, until, batchSize)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("SubscriptionService.RenewExpiringSubscriptions error fetching subscriptions: %v", err)
+		return 0, ErrCannotFetchSubscriptions
+	}
+
+	renewed := 0
+	for _, prev := range subs {
+		err := s.txManager.WithinTransaction(ctx, func(txCtx context.Context) error {
+			offer, err := s.offerRepository.GetByID(txCtx, prev.OfferID)
+			if err != nil {
+				return err
+			}
+
+			// the next period is charged the price in effect on its start, a scheduled change included
+			price, err := s.offerRepository.GetPriceOn(txCtx, prev.OfferID, prev.EndDate)
+			if err != nil {
+				return err
+			}
+
+			next, err := s.subRepository.CreateRenewal(txCtx, prev, prev.EndDate, prev.EndDate.AddDate(0, offer.DurationMonths, 0), price.Price, price.ID)
+			if err != nil {
+				return err
+			}
+
+			if err := s.addAudit(txCtx, entity.NewSubscriptionAudit(entity.AuditActionRenew, nil, &next)); err != nil {
+				return err
+			}
+			return s.addEvents(txCtx, entity.NewSubscriptionEvent(entity.EventSubscriptionRenewed, next))
+		})
+
+		switch {
+		case err == nil:
+			renewed++
+		case errors.Is(err, subscription_repo.ErrSubscriptionAlreadyRenewed):
+			logger.FromContext(ctx).Debugf("SubscriptionService.RenewExpiringSubscriptions: subscription %s already renewed", prev.ID)
+		case errors.Is(err, subscription_repo.ErrUserAlreadyHasActiveSubscription):
+			// the next period is already covered by another subscription, stop retrying it
+			logger.FromContext(ctx).Warnf("SubscriptionService.RenewExpiringSubscriptions: next period of %s overlaps, disabling auto-renew", prev.ID)
+			if err := s.subRepository.DisableAutoRenew(ctx, prev.ID); err != nil {
+				logger.FromContext(ctx).Errorf("SubscriptionService.RenewExpiringSubscriptions error disabling auto-renew for %s: %v", prev.ID, err)
+			}
+		default:
+			// record the failure, the next runs pick the subscriptions that have not failed first
+			logger.FromContext(ctx).Errorf("SubscriptionService.RenewExpiringSubscriptions error renewing %s: %v", prev.ID, err)
+			if err := s.subRepository.MarkRenewalFailed(ctx, prev.ID, err.Error()); err != nil {
+				logger.FromContext(ctx).Errorf("SubscriptionService.RenewExpiringSubscriptions error recording failed renewal of %s: %v", prev.ID, err)
+			}
+		}
+	}
+
+	logger.FromContext(ctx).Infof("SubscriptionService.RenewExpiringSubscriptions success: renewed=%d", renewed)
+	return renewed, nil
+}
+
+// NotifyExpiringSubscriptions writes a subscription.expiring_soon event for active subscriptions
+// without auto-renewal that end not later than until. Every end date is announced once, an
+// end date moved by a pause or an update is announced again.
+func (s *SubscriptionService) NotifyExpiringSubscriptions(ctx context.Context, until time.Time, batchSize int) (_ int, err error) {
+	ctx, span := tracing.Start(ctx, "SubscriptionService.NotifyExpiringSubscriptions")
+	defer tracing.End(span, &err)
+	logger.FromContext(ctx).Infof("SubscriptionService.NotifyExpiringSubscriptions called: until=%v, batchSize=%d", until, batchSize)
+	var count int
+
+	err = s.txManager.WithinTransaction(ctx, func(txCtx context.Context) error {
+		subs, err := s.subRepository.MarkExpiringNotified(txCtx, truncateToDate(time.Now()), until, batchSize)
+		if err != nil {
+			logger.FromContext(ctx).Errorf("SubscriptionService.NotifyExpiringSubscriptions error: %v", err)
+			return ErrCannotFetchSubscriptions
+		}
+		count = len(subs)
+
+		events := lo.Map(subs, func(sub entity.Subscription, _ int) entity.OutboxEvent {
+			return entity.NewSubscriptionEvent(entity.EventSubscriptionExpiringSoon, sub)
+		})
+		return s.addEvents(txCtx, events...)
 	})
 
+	if err != nil {
+		return 0, err
+	}
+
+	logger.FromContext(ctx).Infof("SubscriptionService.NotifyExpiringSubscriptions success: count=%d", count)
+	return count, nil
+}
+
+func (s *SubscriptionService) GetSubscriptionByID(ctx context.Context, subID uuid.UUID) (_ entity.SubscriptionFullInfo, err error) {
+	ctx, span := tracing.Start(ctx, "SubscriptionService.GetSubscriptionByID")
+	defer tracing.End(span, &err)
+	logger.FromContext(ctx).Infof("SubscriptionService.GetSubscriptionByID called: subID=%s", subID)
+
+	sub, err := s.subRepository.GetById(ctx, subID)
+	if err != nil {
+		if errors.Is(err, subscription_repo.ErrSubscriptionNotFound) {
+			logger.FromContext(ctx).Errorf("SubscriptionService.GetSubscriptionByID error: subscription not found")
+			return entity.SubscriptionFullInfo{}, ErrSubscriptionNotFound
+		}
+		logger.FromContext(ctx).Errorf("SubscriptionService.GetSubscriptionByID error getting subscription: %v", err)
+		return entity.SubscriptionFullInfo{}, ErrCannotFindSubscription
+	}
+
+	subFullInfo, err := s.fullInfo(ctx, sub)
 	if err != nil {
 		return entity.SubscriptionFullInfo{}, err
 	}
 
-	logrus.Infof("SubscriptionService.CreateSubscriptionByOfferID success: id=%s", subFullInfo.ID)
+	logger.FromContext(ctx).Infof("SubscriptionService.GetSubscriptionByID success: id=%s", sub.ID)
 	return subFullInfo, nil
 }
 
-func (s *SubscriptionService) GetAllSubscriptions(ctx context.Context, page int, pageSize int) ([]entity.SubscriptionFullInfo, int, error) {
-	logrus.Info("SubscriptionService.GetAllSubscriptions called")
+// CountActiveSubscriptions returns the number of active subscriptions of every service that has them.
+func (s *SubscriptionService) CountActiveSubscriptions(ctx context.Context) (_ map[string]int, err error) {
+	ctx, span := tracing.Start(ctx, "SubscriptionService.CountActiveSubscriptions")
+	defer tracing.End(span, &err)
+	counts, err := s.subRepository.CountActiveByService(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("SubscriptionService.CountActiveSubscriptions error: %v", err)
+		return nil, ErrCannotCountSubscriptions
+	}
+	return counts, nil
+}
+
+func (s *SubscriptionService) GetSubscriptionPauses(ctx context.Context, subID uuid.UUID) (_ []entity.SubscriptionPause, err error) {
+	ctx, span := tracing.Start(ctx, "SubscriptionService.GetSubscriptionPauses")
+	defer tracing.End(span, &err)
+	logger.FromContext(ctx).Infof("SubscriptionService.GetSubscriptionPauses called: subID=%s", subID)
+
+	pauses, err := s.subRepository.GetPauses(ctx, subID)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("SubscriptionService.GetSubscriptionPauses error: %v", err)
+		return nil, ErrCannotFetchSubscriptions
+	}
+
+	logger.FromContext(ctx).Infof("SubscriptionService.GetSubscriptionPauses success: count=%d", len(pauses))
+	return pauses, nil
+}
+
+func (s *SubscriptionService) GetAllSubscriptions(
+	ctx context.Context,
+	status *entity.SubscriptionStatus,
+	page int,
+	pageSize int,
+) (_ []entity.SubscriptionFullInfo, _ int, err error) {
+	ctx, span := tracing.Start(ctx, "SubscriptionService.GetAllSubscriptions")
+	defer tracing.End(span, &err)
+	logger.FromContext(ctx).Infof("SubscriptionService.GetAllSubscriptions called: status=%v", status)
 
 	limit := pageSize
 	offset := (page - 1) * pageSize
 
-	subs, total, err := s.subRepository.GetAll(ctx, limit, offset)
+	subs, total, err := s.subRepository.GetAll(ctx, status, limit, offset)
 	if err != nil {
-		logrus.Errorf("SubscriptionService.GetAllSubscriptions error: %v", err)
+		logger.FromContext(ctx).Errorf("SubscriptionService.GetAllSubscriptions error: %v", err)
 		return nil, 0, ErrCannotFetchSubscriptions
 	}
 
-	logrus.Infof("SubscriptionService.GetAllSubscriptions success: count=%d", len(subs))
+	logger.FromContext(ctx).Infof("SubscriptionService.GetAllSubscriptions success: count=%d", len(subs))
 	return subs, total, nil
 }
 
+// GetAllSubscriptionsAfter returns a page of subscriptions that come after the cursor, nil cursor means the first page.
+func (s *SubscriptionService) GetAllSubscriptionsAfter(
+	ctx context.Context,
+	status *entity.SubscriptionStatus,
+	after *cursor.Cursor,
+	pageSize int,
+) (_ []entity.SubscriptionFullInfo, _ *cursor.Cursor, err error) {
+	ctx, span := tracing.Start(ctx, "SubscriptionService.GetAllSubscriptionsAfter")
+	defer tracing.End(span, &err)
+	logger.FromContext(ctx).Infof("SubscriptionService.GetAllSubscriptionsAfter called: status=%v, after=%v", status, after)
+
+	subs, next, err := s.subRepository.GetAllAfter(ctx, status, after, pageSize)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("SubscriptionService.GetAllSubscriptionsAfter error: %v", err)
+		return nil, nil, ErrCannotFetchSubscriptions
+	}
+
+	logger.FromContext(ctx).Infof("SubscriptionService.GetAllSubscriptionsAfter success: count=%d", len(subs))
+	return subs, next, nil
+}
+
 func (s *SubscriptionService) GetAllWithPriceByUserIDAndSubscriptionName(
 	ctx context.Context,
 	userID uuid.UUID,
 	subscriptionName string,
+	status *entity.SubscriptionStatus,
 	startPeriod *time.Time,
 	endPeriod *time.Time,
+	currency string,
 	page int,
 	pageSize int,
 ) (subs []entity.SubscriptionFullInfo, price int, totalCount int, err error) {
-	logrus.Infof("SubscriptionService.GetAllWithPriceByUserIDAndSubscriptionName called: userID=%s, subscriptionName=%s, startPeriod=%v, endPeriod=%v", userID, subscriptionName, startPeriod, endPeriod)
+	ctx, span := tracing.Start(ctx, "SubscriptionService.GetAllWithPriceByUserIDAndSubscriptionName")
+	defer tracing.End(span, &err)
+	logger.FromContext(ctx).Infof("SubscriptionService.GetAllWithPriceByUserIDAndSubscriptionName called: userID=%s, subscriptionName=%s, status=%v, startPeriod=%v, endPeriod=%v, currency=%s", userID, subscriptionName, status, startPeriod, endPeriod, currency)
 
 	limit := pageSize
 	offset := (page - 1) * pageSize
 
-	subs, price, totalCount, err = s.subRepository.GetAllByUserIDAndSubscriptionName(ctx, userID, subscriptionName, startPeriod, endPeriod, limit, offset)
+	subs, price, totalCount, err = s.subRepository.GetAllByUserIDAndSubscriptionName(ctx, userID, subscriptionName, status, startPeriod, endPeriod, currency, limit, offset)
 	if err != nil {
-		logrus.Errorf("SubscriptionService.GetAllWithPriceByUserIDAndSubscriptionName error: %v", err)
+		if errors.Is(err, subscription_repo.ErrExchangeRateNotFound) {
+			return nil, 0, 0, ErrExchangeRateNotFound
+		}
+		logger.FromContext(ctx).Errorf("SubscriptionService.GetAllWithPriceByUserIDAndSubscriptionName error: %v", err)
 		return nil, 0, 0, ErrCannotFetchSubscriptions
 	}
 
-	logrus.Infof("SubscriptionService.GetAllWithPriceByUserIDAndSubscriptionName success: count=%d", len(subs))
+	logger.FromContext(ctx).Infof("SubscriptionService.GetAllWithPriceByUserIDAndSubscriptionName success: count=%d", len(subs))
 	return subs, price, totalCount, nil
 }
 
-func (s *SubscriptionService) DeleteSubscription(ctx context.Context, subID uuid.UUID) error {
-	logrus.Infof("SubscriptionService.DeleteSubscription called: subID=%s", subID)
-	err := s.subRepository.Delete(ctx, subID)
-	if err != nil && !errors.Is(err, subscription_repo.ErrSubscriptionNotFound) {
-		logrus.Errorf("SubscriptionService.DeleteSubscription error: %v", err)
-		return ErrCannotDeleteSubscription
+func (s *SubscriptionService) GetAllWithPriceByUserIDAndSubscriptionNameAfter(
+	ctx context.Context,
+	userID uuid.UUID,
+	subscriptionName string,
+	status *entity.SubscriptionStatus,
+	startPeriod *time.Time,
+	endPeriod *time.Time,
+	currency string,
+	after *cursor.Cursor,
+	pageSize int,
+) (subs []entity.SubscriptionFullInfo, price int, next *cursor.Cursor, err error) {
+	ctx, span := tracing.Start(ctx, "SubscriptionService.GetAllWithPriceByUserIDAndSubscriptionNameAfter")
+	defer tracing.End(span, &err)
+	logger.FromContext(ctx).Infof("SubscriptionService.GetAllWithPriceByUserIDAndSubscriptionNameAfter called: userID=%s, subscriptionName=%s, status=%v, startPeriod=%v, endPeriod=%v, currency=%s, after=%v", userID, subscriptionName, status, startPeriod, endPeriod, currency, after)
+
+	subs, price, next, err = s.subRepository.GetAllByUserIDAndSubscriptionNameAfter(ctx, userID, subscriptionName, status, startPeriod, endPeriod, currency, after, pageSize)
+	if err != nil {
+		if errors.Is(err, subscription_repo.ErrExchangeRateNotFound) {
+			return nil, 0, nil, ErrExchangeRateNotFound
+		}
+		logger.FromContext(ctx).Errorf("SubscriptionService.GetAllWithPriceByUserIDAndSubscriptionNameAfter error: %v", err)
+		return nil, 0, nil, ErrCannotFetchSubscriptions
 	}
 
-	if errors.Is(err, subscription_repo.ErrSubscriptionNotFound) {
-		logrus.Errorf("SubscriptionService.DeleteSubscription error: subscription not found")
-		return ErrSubscriptionNotFound
+	logger.FromContext(ctx).Infof("SubscriptionService.GetAllWithPriceByUserIDAndSubscriptionNameAfter success: count=%d", len(subs))
+	return subs, price, next, nil
+}
+
+// CancelSubscription cancels the subscription. When atPeriodEnd is false the subscription is
+// cancelled right away and its end date is moved to today, otherwise it stays active until the
+// end of the paid period. In both cases auto-renewal is turned off and a renewal the worker has
+// already created for the next period, if it has not started yet, is cancelled too.
+func (s *SubscriptionService) CancelSubscription(
+	ctx context.Context,
+	subID uuid.UUID,
+	reason *string,
+	atPeriodEnd bool,
+) (_ entity.SubscriptionFullInfo, err error) {
+	ctx, span := tracing.Start(ctx, "SubscriptionService.CancelSubscription")
+	defer tracing.End(span, &err)
+	logger.FromContext(ctx).Infof("SubscriptionService.CancelSubscription called: subID=%s, atPeriodEnd=%t", subID, atPeriodEnd)
+	var subFullInfo entity.SubscriptionFullInfo
+
+	err = s.txManager.WithinTransaction(ctx, func(txCtx context.Context) error {
+		current, err := s.subRepository.GetById(txCtx, subID)
+		if err != nil {
+			if errors.Is(err, subscription_repo.ErrSubscriptionNotFound) {
+				logger.FromContext(ctx).Errorf("SubscriptionService.CancelSubscription error: subscription not found")
+				return ErrSubscriptionNotFound
+			}
+			logger.FromContext(ctx).Errorf("SubscriptionService.CancelSubscription error getting subscription: %v", err)
+			return ErrCannotFindSubscription
+		}
+
+		if current.CancelledAt != nil ||
+			(current.Status != entity.SubscriptionStatusActive && current.Status != entity.SubscriptionStatusPaused) {
+			logger.FromContext(ctx).Errorf("SubscriptionService.CancelSubscription error: subscription is %s", current.Status)
+			return ErrSubscriptionNotActive
+		}
+
+		today := truncateToDate(time.Now())
+
+		// a paused subscription has no running period to wait for, it is cancelled right away
+		if current.Status == entity.SubscriptionStatusPaused {
+			if _, err := s.subRepository.CloseOpenPause(txCtx, current.ID, today); err != nil && !errors.Is(err, subscription_repo.ErrPauseNotFound) {
+				logger.FromContext(ctx).Errorf("SubscriptionService.CancelSubscription error closing pause: %v", err)
+				return ErrCannotCancelSubscription
+			}
+			atPeriodEnd = false
+		}
+
+		status := entity.SubscriptionStatusActive
+		endDate := current.EndDate
+		if !atPeriodEnd || !endDate.After(today) {
+			status = entity.SubscriptionStatusCancelled
+			if endDate.After(today) {
+				endDate = today
+			}
+			if endDate.Before(current.StartDate) {
+				endDate = current.StartDate
+			}
+		}
+
+		sub, err := s.subRepository.Cancel(txCtx, current.ID, status, endDate, reason)
+		if err != nil {
+			logger.FromContext(ctx).Errorf("SubscriptionService.CancelSubscription error cancelling subscription: %v", err)
+			return ErrCannotCancelSubscription
+		}
+
+		audit := []entity.AuditEntry{entity.NewSubscriptionAudit(entity.AuditActionCancel, &current, &sub)}
+		events := []entity.OutboxEvent{entity.NewSubscriptionEvent(entity.EventSubscriptionCancelled, sub)}
+
+		// the next period has not been paid for yet: its end date is moved to its start, so it has no paid days
+		renewal, err := s.subRepository.GetPendingRenewal(txCtx, current.ID, today)
+		switch {
+		case err == nil:
+			cancelled, err := s.subRepository.Cancel(txCtx, renewal.ID, entity.SubscriptionStatusCancelled, renewal.StartDate, reason)
+			if err != nil {
+				logger.FromContext(ctx).Errorf("SubscriptionService.CancelSubscription error cancelling renewal: %v", err)
+				return ErrCannotCancelSubscription
+			}
+			audit = append(audit, entity.NewSubscriptionAudit(entity.AuditActionCancel, &renewal, &cancelled))
+			events = append(events, entity.NewSubscriptionEvent(entity.EventSubscriptionCancelled, cancelled))
+		case !errors.Is(err, subscription_repo.ErrSubscriptionNotFound):
+			logger.FromContext(ctx).Errorf("SubscriptionService.CancelSubscription error getting renewal: %v", err)
+			return ErrCannotCancelSubscription
+		}
+
+		if err := s.addAudit(txCtx, audit...); err != nil {
+			return err
+		}
+		if err := s.addEvents(txCtx, events...); err != nil {
+			return err
+		}
+
+		subFullInfo, err = s.fullInfo(txCtx, sub)
+		return err
+	})
+
+	if err != nil {
+		return entity.SubscriptionFullInfo{}, err
 	}
 
-	logrus.Infof("SubscriptionService.DeleteSubscription success: subID=%s deleted", subID)
+	logger.FromContext(ctx).Infof("SubscriptionService.CancelSubscription success: id=%s, status=%s", subFullInfo.ID, subFullInfo.Status)
+	return subFullInfo, nil
+}
+
+// ChangePlan moves the user to another offer of the same service. The current subscription ends
+// on switchDate and a new one with the new offer starts on the same date. The unused part of the
+// current period is returned as a proration credit computed from the price paid for it. Trial days
+// are free, so only the unused part of the paid period is credited and the rest of a trial is
+// not carried over to the new subscription. Both rows are written in one transaction.
+func (s *SubscriptionService) ChangePlan(
+	ctx context.Context,
+	subID uuid.UUID,
+	newOfferID uuid.UUID,
+	switchDate time.Time,
+) (_ entity.PlanChange, err error) {
+	ctx, span := tracing.Start(ctx, "SubscriptionService.ChangePlan")
+	defer tracing.End(span, &err)
+	logger.FromContext(ctx).Infof("SubscriptionService.ChangePlan called: subID=%s, newOfferID=%s, switchDate=%v", subID, newOfferID, switchDate)
+	var change entity.PlanChange
+
+	err = s.txManager.WithinTransaction(ctx, func(txCtx context.Context) error {
+		current, err := s.subRepository.GetById(txCtx, subID)
+		if err != nil {
+			if errors.Is(err, subscription_repo.ErrSubscriptionNotFound) {
+				logger.FromContext(ctx).Errorf("SubscriptionService.ChangePlan error: subscription not found")
+				return ErrSubscriptionNotFound
+			}
+			logger.FromContext(ctx).Errorf("SubscriptionService.ChangePlan error getting subscription: %v", err)
+			return ErrCannotFindSubscription
+		}
+
+		if current.Status != entity.SubscriptionStatusActive || current.CancelledAt != nil {
+			logger.FromContext(ctx).Errorf("SubscriptionService.ChangePlan error: subscription is %s", current.Status)
+			return ErrSubscriptionNotActive
+		}
+		if current.OfferID == newOfferID {
+			return ErrSameOffer
+		}
+		if switchDate.Before(current.StartDate) || !switchDate.Before(current.EndDate) {
+			return ErrInvalidSwitchDate
+		}
+
+		oldOffer, err := s.offerRepository.GetByID(txCtx, current.OfferID)
+		if err != nil {
+			logger.FromContext(ctx).Errorf("SubscriptionService.ChangePlan error getting current offer: %v", err)
+			return ErrCannotFindOffer
+		}
+
+		newOffer, err := s.offerRepository.GetByID(txCtx, newOfferID)
+		if err != nil {
+			if errors.Is(err, offer_repo.ErrOfferNotFound) {
+				logger.FromContext(ctx).Errorf("SubscriptionService.ChangePlan error: offer not found")
+				return ErrOfferNotFound
+			}
+			logger.FromContext(ctx).Errorf("SubscriptionService.ChangePlan error getting new offer: %v", err)
+			return ErrCannotFindOffer
+		}
+		if newOffer.Name != oldOffer.Name {
+			return ErrOfferOfAnotherService
+		}
+
+		unusedDays, credit := prorationCredit(current, switchDate)
+
+		// end the current subscription first, so the new one does not overlap it
+		previous, err := s.subRepository.Update(txCtx, current.ID, current.OfferID, current.StartDate, switchDate, current.TrialEndDate, current.Price, current.PriceID, false)
+		if err != nil {
+			logger.FromContext(ctx).Errorf("SubscriptionService.ChangePlan error ending current subscription: %v", err)
+			return ErrCannotChangePlan
+		}
+
+		next, err := s.subRepository.Create(txCtx, current.UserID, newOffer.ID, switchDate, switchDate.AddDate(0, newOffer.DurationMonths, 0), nil, newOffer.Price, newOffer.PriceID, nil, current.AutoRenew)
+		if err != nil {
+			if errors.Is(err, subscription_repo.ErrUserAlreadyHasActiveSubscription) {
+				return ErrUserAlreadyHasActiveSubscription
+			}
+			logger.FromContext(ctx).Errorf("SubscriptionService.ChangePlan error creating new subscription: %v", err)
+			return ErrCannotChangePlan
+		}
+
+		previousInfo, err := s.fullInfo(txCtx, previous)
+		if err != nil {
+			return err
+		}
+
+		change = entity.PlanChange{
+			Previous: previousInfo,
+			Current: entity.SubscriptionFullInfo{
+				Subscription:   next,
+				OfferName:      newOffer.Name,
+				ListPrice:      newOffer.Price,
+				Currency:       newOffer.Currency,
+				DurationMonths: newOffer.DurationMonths,
+			},
+			UnusedDays:      unusedDays,
+			ProrationCredit: credit,
+		}
+
+		if err := s.addAudit(txCtx,
+			entity.NewSubscriptionAudit(entity.AuditActionChangePlan, &current, &previous),
+			entity.NewSubscriptionAudit(entity.AuditActionChangePlan, nil, &next),
+		); err != nil {
+			return err
+		}
+		return s.addEvents(txCtx, entity.NewPlanChangeEvent(change))
+	})
+
+	if err != nil {
+		return entity.PlanChange{}, err
+	}
+
+	logger.FromContext(ctx).Infof("SubscriptionService.ChangePlan success: previousID=%s, currentID=%s, credit=%d", change.Previous.ID, change.Current.ID, change.ProrationCredit)
+	return change, nil
+}
+
+// PauseSubscription freezes an active subscription starting today. The paused time is not
+// lost: on resume the end date is moved forward by the length of the pause. A subscription
+// cannot be paused during its free trial.
+func (s *SubscriptionService) PauseSubscription(ctx context.Context, subID uuid.UUID) (_ entity.SubscriptionFullInfo, err error) {
+	ctx, span := tracing.Start(ctx, "SubscriptionService.PauseSubscription")
+	defer tracing.End(span, &err)
+	logger.FromContext(ctx).Infof("SubscriptionService.PauseSubscription called: subID=%s", subID)
+	var subFullInfo entity.SubscriptionFullInfo
+
+	err = s.txManager.WithinTransaction(ctx, func(txCtx context.Context) error {
+		current, err := s.subRepository.GetById(txCtx, subID)
+		if err != nil {
+			if errors.Is(err, subscription_repo.ErrSubscriptionNotFound) {
+				logger.FromContext(ctx).Errorf("SubscriptionService.PauseSubscription error: subscription not found")
+				return ErrSubscriptionNotFound
+			}
+			logger.FromContext(ctx).Errorf("SubscriptionService.PauseSubscription error getting subscription: %v", err)
+			return ErrCannotFindSubscription
+		}
+
+		today := truncateToDate(time.Now())
+
+		// only a running period can be paused
+		if current.Status != entity.SubscriptionStatusActive || current.CancelledAt != nil ||
+			current.StartDate.After(today) || !current.EndDate.After(today) {
+			logger.FromContext(ctx).Errorf("SubscriptionService.PauseSubscription error: subscription is not running")
+			return ErrSubscriptionNotActive
+		}
+		if paidStart(current).After(today) {
+			logger.FromContext(ctx).Errorf("SubscriptionService.PauseSubscription error: subscription is in its trial period")
+			return ErrSubscriptionInTrial
+		}
+
+		if _, err := s.subRepository.CreatePause(txCtx, current.ID, today); err != nil {
+			if errors.Is(err, subscription_repo.ErrSubscriptionAlreadyPaused) {
+				return ErrSubscriptionNotActive
+			}
+			logger.FromContext(ctx).Errorf("SubscriptionService.PauseSubscription error creating pause: %v", err)
+			return ErrCannotPauseSubscription
+		}
+
+		sub, err := s.subRepository.SetStatusAndEndDate(txCtx, current.ID, entity.SubscriptionStatusPaused, current.EndDate)
+		if err != nil {
+			if errors.Is(err, subscription_repo.ErrUserAlreadyHasActiveSubscription) {
+				// a paused subscription occupies the service until it is resumed, so a renewal or
+				// another subscription that starts after the current period does not let it pause
+				logger.FromContext(ctx).Errorf("SubscriptionService.PauseSubscription error: subscription is followed by another one")
+				return ErrSubscriptionFollowed
+			}
+			logger.FromContext(ctx).Errorf("SubscriptionService.PauseSubscription error updating subscription: %v", err)
+			return ErrCannotPauseSubscription
+		}
+
+		if err := s.addAudit(txCtx, entity.NewSubscriptionAudit(entity.AuditActionPause, &current, &sub)); err != nil {
+			return err
+		}
+		if err := s.addEvents(txCtx, entity.NewSubscriptionEvent(entity.EventSubscriptionPaused, sub)); err != nil {
+			return err
+		}
+
+		subFullInfo, err = s.fullInfo(txCtx, sub)
+		return err
+	})
+
+	if err != nil {
+		return entity.SubscriptionFullInfo{}, err
+	}
+
+	logger.FromContext(ctx).Infof("SubscriptionService.PauseSubscription success: id=%s", subFullInfo.ID)
+	return subFullInfo, nil
+}
+
+// ResumeSubscription closes the open pause and extends the end date by the paused duration.
+func (s *SubscriptionService) ResumeSubscription(ctx context.Context, subID uuid.UUID) (_ entity.SubscriptionFullInfo, err error) {
+	ctx, span := tracing.Start(ctx, "SubscriptionService.ResumeSubscription")
+	defer tracing.End(span, &err)
+	logger.FromContext(ctx).Infof("SubscriptionService.ResumeSubscription called: subID=%s", subID)
+	var subFullInfo entity.SubscriptionFullInfo
+
+	err = s.txManager.WithinTransaction(ctx, func(txCtx context.Context) error {
+		current, err := s.subRepository.GetById(txCtx, subID)
+		if err != nil {
+			if errors.Is(err, subscription_repo.ErrSubscriptionNotFound) {
+				logger.FromContext(ctx).Errorf("SubscriptionService.ResumeSubscription error: subscription not found")
+				return ErrSubscriptionNotFound
+			}
+			logger.FromContext(ctx).Errorf("SubscriptionService.ResumeSubscription error getting subscription: %v", err)
+			return ErrCannotFindSubscription
+		}
+
+		if current.Status != entity.SubscriptionStatusPaused {
+			logger.FromContext(ctx).Errorf("SubscriptionService.ResumeSubscription error: subscription is %s", current.Status)
+			return ErrSubscriptionNotPaused
+		}
+
+		pause, err := s.subRepository.CloseOpenPause(txCtx, current.ID, truncateToDate(time.Now()))
+		if err != nil {
+			logger.FromContext(ctx).Errorf("SubscriptionService.ResumeSubscription error closing pause: %v", err)
+			return ErrCannotResumeSubscription
+		}
+
+		newEndDate := current.EndDate.AddDate(0, 0, daysBetween(pause.PausedAt, *pause.ResumedAt))
+
+		sub, err := s.subRepository.SetStatusAndEndDate(txCtx, current.ID, entity.SubscriptionStatusActive, newEndDate)
+		if err != nil {
+			if errors.Is(err, subscription_repo.ErrUserAlreadyHasActiveSubscription) {
+				return ErrUserAlreadyHasActiveSubscription
+			}
+			logger.FromContext(ctx).Errorf("SubscriptionService.ResumeSubscription error updating subscription: %v", err)
+			return ErrCannotResumeSubscription
+		}
+
+		if err := s.addAudit(txCtx, entity.NewSubscriptionAudit(entity.AuditActionResume, &current, &sub)); err != nil {
+			return err
+		}
+		if err := s.addEvents(txCtx, entity.NewSubscriptionEvent(entity.EventSubscriptionResumed, sub)); err != nil {
+			return err
+		}
+
+		subFullInfo, err = s.fullInfo(txCtx, sub)
+		return err
+	})
+
+	if err != nil {
+		return entity.SubscriptionFullInfo{}, err
+	}
+
+	logger.FromContext(ctx).Infof("SubscriptionService.ResumeSubscription success: id=%s, endDate=%v", subFullInfo.ID, subFullInfo.EndDate)
+	return subFullInfo, nil
+}
+
+// ExpireSubscriptions moves active subscriptions that have ended by the given date to the
+// expired status, or to cancelled if they were cancelled at the end of the period.
+// An auto-renewable subscription that has not been renewed yet is left active for renewalGrace
+// after its end date, the renewal worker can still renew it.
+// A subscription.expired event is written for every ended subscription.
+func (s *SubscriptionService) ExpireSubscriptions(ctx context.Context, date time.Time, renewalGrace time.Duration) (_ int, err error) {
+	ctx, span := tracing.Start(ctx, "SubscriptionService.ExpireSubscriptions")
+	defer tracing.End(span, &err)
+	logger.FromContext(ctx).Infof("SubscriptionService.ExpireSubscriptions called: date=%v, renewalGrace=%v", date, renewalGrace)
+	var count int
+
+	err = s.txManager.WithinTransaction(ctx, func(txCtx context.Context) error {
+		subs, err := s.subRepository.ExpireEnded(txCtx, truncateToDate(date), truncateToDate(date.Add(-renewalGrace)))
+		if err != nil {
+			logger.FromContext(ctx).Errorf("SubscriptionService.ExpireSubscriptions error: %v", err)
+			return ErrCannotUpdateSubscription
+		}
+		count = len(subs)
+
+		entries := lo.Map(subs, func(sub entity.Subscription, _ int) entity.AuditEntry {
+			// only the status changes, the subscription was active before
+			before := sub
+			before.Status = entity.SubscriptionStatusActive
+			return entity.NewSubscriptionAudit(entity.AuditActionExpire, &before, &sub)
+		})
+		if err := s.addAudit(txCtx, entries...); err != nil {
+			return err
+		}
+
+		events := lo.Map(subs, func(sub entity.Subscription, _ int) entity.OutboxEvent {
+			return entity.NewSubscriptionEvent(entity.EventSubscriptionExpired, sub)
+		})
+		return s.addEvents(txCtx, events...)
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	logger.FromContext(ctx).Infof("SubscriptionService.ExpireSubscriptions success: count=%d", count)
+	return count, nil
+}
+
+// DeleteSubscription does not remove the subscription: it is cancelled right away, so its
+// history and spend are kept. A subscription that has already ended is left as it is.
+func (s *SubscriptionService) DeleteSubscription(ctx context.Context, subID uuid.UUID) (err error) {
+	ctx, span := tracing.Start(ctx, "SubscriptionService.DeleteSubscription")
+	defer tracing.End(span, &err)
+	logger.FromContext(ctx).Infof("SubscriptionService.DeleteSubscription called: subID=%s", subID)
+
+	_, err = s.CancelSubscription(ctx, subID, lo.ToPtr(deleteReason), false)
+	if errors.Is(err, ErrSubscriptionNotActive) {
+		logger.FromContext(ctx).Infof("SubscriptionService.DeleteSubscription success: subID=%s has already ended", subID)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	metrics.SubscriptionsDeleted.Inc()
+	logger.FromContext(ctx).Infof("SubscriptionService.DeleteSubscription success: subID=%s cancelled", subID)
 	return nil
 }
 
-func (s *SubscriptionService) GetAllSubscriptionsByUserID(ctx context.Context, userID uuid.UUID, page int, pageSize int) ([]entity.SubscriptionFullInfo, int, error) {
-	logrus.Infof("SubscriptionService.GetAllSubscriptionsByUserID called: userID=%s", userID)
+func (s *SubscriptionService) GetAllSubscriptionsByUserID(
+	ctx context.Context,
+	userID uuid.UUID,
+	status *entity.SubscriptionStatus,
+	page int,
+	pageSize int,
+) (_ []entity.SubscriptionFullInfo, _ int, err error) {
+	ctx, span := tracing.Start(ctx, "SubscriptionService.GetAllSubscriptionsByUserID")
+	defer tracing.End(span, &err)
+	logger.FromContext(ctx).Infof("SubscriptionService.GetAllSubscriptionsByUserID called: userID=%s, status=%v", userID, status)
 
 	limit := pageSize
 	offset := (page - 1) * pageSize
 
-	subs, totalCount, err := s.subRepository.GetAllByUserID(ctx, userID, limit, offset)
+	subs, totalCount, err := s.subRepository.GetAllByUserID(ctx, userID, status, limit, offset)
 	if err != nil {
-		logrus.Errorf("SubscriptionService.GetAllSubscriptionsByUserID error: %v", err)
+		logger.FromContext(ctx).Errorf("SubscriptionService.GetAllSubscriptionsByUserID error: %v", err)
 		return nil, 0, ErrCannotFetchSubscriptions
 	}
 
-	logrus.Infof("SubscriptionService.GetAllSubscriptionsByUserID success: count=%d", len(subs))
+	logger.FromContext(ctx).Infof("SubscriptionService.GetAllSubscriptionsByUserID success: count=%d", len(subs))
 	return subs, totalCount, nil
 }
+
+// GetAllSubscriptionsByUserIDAfter returns a page of the user's subscriptions that come after the cursor,
+// nil cursor means the first page.
+func (s *SubscriptionService) GetAllSubscriptionsByUserIDAfter(
+	ctx context.Context,
+	userID uuid.UUID,
+	status *entity.SubscriptionStatus,
+	after *cursor.Cursor,
+	pageSize int,
+) (_ []entity.SubscriptionFullInfo, _ *cursor.Cursor, err error) {
+	ctx, span := tracing.Start(ctx, "SubscriptionService.GetAllSubscriptionsByUserIDAfter")
+	defer tracing.End(span, &err)
+	logger.FromContext(ctx).Infof("SubscriptionService.GetAllSubscriptionsByUserIDAfter called: userID=%s, status=%v, after=%v", userID, status, after)
+
+	subs, next, err := s.subRepository.GetAllByUserIDAfter(ctx, userID, status, after, pageSize)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("SubscriptionService.GetAllSubscriptionsByUserIDAfter error: %v", err)
+		return nil, nil, ErrCannotFetchSubscriptions
+	}
+
+	logger.FromContext(ctx).Infof("SubscriptionService.GetAllSubscriptionsByUserIDAfter success: count=%d", len(subs))
+	return subs, next, nil
+}
+
+// addEvents writes domain events to the outbox in the transaction of ctx.
+func (s *SubscriptionService) addEvents(ctx context.Context, events ...entity.OutboxEvent) error {
+	if err := s.outboxRepository.Add(ctx, events...); err != nil {
+		logger.FromContext(ctx).Errorf("SubscriptionService.addEvents error: %v", err)
+		return ErrCannotWriteEvents
+	}
+	return nil
+}
+
+// addAudit writes audit entries made by the actor of ctx in the transaction of ctx.
+func (s *SubscriptionService) addAudit(ctx context.Context, entries ...entity.AuditEntry) error {
+	for i := range entries {
+		entries[i].Actor = actor.FromContext(ctx)
+	}
+	if err := s.auditRepository.Add(ctx, entries...); err != nil {
+		logger.FromContext(ctx).Errorf("SubscriptionService.addAudit error: %v", err)
+		return ErrCannotWriteAuditLog
+	}
+	return nil
+}
+
+// fullInfo completes the subscription with the data of its offer and the price version it was bought at.
+func (s *SubscriptionService) fullInfo(ctx context.Context, sub entity.Subscription) (entity.SubscriptionFullInfo, error) {
+	offer, err := s.offerRepository.GetByID(ctx, sub.OfferID)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("SubscriptionService.fullInfo error getting offer: %v", err)
+		return entity.SubscriptionFullInfo{}, ErrCannotFindOffer
+	}
+
+	price, err := s.offerRepository.GetPriceByID(ctx, sub.PriceID)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("SubscriptionService.fullInfo error getting offer price: %v", err)
+		return entity.SubscriptionFullInfo{}, ErrCannotFindOffer
+	}
+
+	return entity.SubscriptionFullInfo{
+		Subscription:   sub,
+		OfferName:      offer.Name,
+		ListPrice:      price.Price,
+		Currency:       price.Currency,
+		DurationMonths: offer.DurationMonths,
+	}, nil
+}
+
+// trialEndDate returns the end of the free trial for a new subscription to the offer, nil if the
+// offer has no trial or the caller skipped it. A user gets one trial per service.
+func (s *SubscriptionService) trialEndDate(
+	ctx context.Context,
+	userID uuid.UUID,
+	offer entity.Offer,
+	startDate time.Time,
+	skipTrial bool,
+) (*time.Time, error) {
+	if offer.TrialDays == 0 || skipTrial {
+		return nil, nil
+	}
+
+	used, err := s.subRepository.HasUsedTrial(ctx, userID, offer.Name)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("SubscriptionService.trialEndDate error checking trial: %v", err)
+		return nil, ErrCannotCheckTrial
+	}
+	if used {
+		logger.FromContext(ctx).Errorf("SubscriptionService.trialEndDate error: user %s already had a trial on %s", userID, offer.Name)
+		return nil, ErrTrialAlreadyUsed
+	}
+
+	return lo.ToPtr(startDate.AddDate(0, 0, offer.TrialDays)), nil
+}
+
+// applyPromoCode returns the price of a new subscription to the offer with the promo code
+// applied and redeems the code. The code row stays locked until the end of the transaction,
+// so the redemption limit holds under concurrent requests. A nil code means the list price.
+// The discount covers the first period only, renewals are charged the list price.
+func (s *SubscriptionService) applyPromoCode(ctx context.Context, code *string, offer entity.Offer) (int, *uuid.UUID, error) {
+	if code == nil {
+		return offer.Price, nil, nil
+	}
+
+	promo, err := s.promoCodeRepository.GetByCodeForUpdate(ctx, strings.ToUpper(*code))
+	if err != nil {
+		if errors.Is(err, promo_code_repo.ErrPromoCodeNotFound) {
+			return 0, nil, ErrPromoCodeNotFound
+		}
+		logger.FromContext(ctx).Errorf("SubscriptionService.applyPromoCode error getting promo code: %v", err)
+		return 0, nil, ErrCannotApplyPromoCode
+	}
+
+	today := truncateToDate(time.Now())
+	if (promo.ValidFrom != nil && today.Before(*promo.ValidFrom)) || (promo.ValidUntil != nil && today.After(*promo.ValidUntil)) {
+		return 0, nil, ErrPromoCodeNotValid
+	}
+	if len(promo.OfferIDs) > 0 && !lo.Contains(promo.OfferIDs, offer.ID) {
+		return 0, nil, ErrPromoCodeNotApplicable
+	}
+
+	price := offer.Price
+	switch promo.DiscountType {
+	case entity.DiscountTypePercent:
+		price -= int(math.Round(float64(offer.Price) * float64(promo.DiscountValue) / 100))
+	case entity.DiscountTypeFixed:
+		if promo.Currency == nil || *promo.Currency != offer.Currency {
+			return 0, nil, ErrPromoCodeNotApplicable
+		}
+		price = max(price-promo.DiscountValue, 0)
+	}
+
+	if err := s.promoCodeRepository.Redeem(ctx, promo.ID); err != nil {
+		if errors.Is(err, promo_code_repo.ErrPromoCodeExhausted) {
+			return 0, nil, ErrPromoCodeExhausted
+		}
+		logger.FromContext(ctx).Errorf("SubscriptionService.applyPromoCode error redeeming promo code: %v", err)
+		return 0, nil, ErrCannotApplyPromoCode
+	}
+
+	return price, &promo.ID, nil
+}
+
+// paidEndDate returns the end of the subscription: the paid period of the offer follows the trial, if any.
+func paidEndDate(startDate time.Time, trialEndDate *time.Time, offer entity.Offer) time.Time {
+	if trialEndDate != nil {
+		startDate = *trialEndDate
+	}
+	return startDate.AddDate(0, offer.DurationMonths, 0)
+}
+
+// paidStart returns the first paid day of the subscription, that is the end of its trial.
+// A subscription that ended within the trial has no paid days, its end date is returned.
+func paidStart(sub entity.Subscription) time.Time {
+	if sub.TrialEndDate == nil {
+		return sub.StartDate
+	}
+	return earliest(*sub.TrialEndDate, sub.EndDate)
+}
+
+// daysBetween returns the number of whole days from start to end.
+func daysBetween(start, end time.Time) int {
+	return int(truncateToDate(end).Sub(truncateToDate(start)).Hours() / 24)
+}
+
+// prorate returns the part of price that corresponds to days out of totalDays, rounded to the nearest unit.
+func prorate(price, days, totalDays int) int {
+	if totalDays <= 0 || days <= 0 {
+		return 0
+	}
+	if days > totalDays {
+		days = totalDays
+	}
+	return int(math.Round(float64(price) * float64(days) / float64(totalDays)))
+}
+
+// prorationCredit returns the days of the paid period of sub left after switchDate and the part
+// of the paid price they are worth. Trial days are free and are not credited.
+func prorationCredit(sub entity.Subscription, switchDate time.Time) (unusedDays, credit int) {
+	paidFrom := paidStart(sub)
+	totalDays := daysBetween(paidFrom, sub.EndDate)
+	unusedDays = daysBetween(latest(switchDate, paidFrom), sub.EndDate)
+	return unusedDays, prorate(sub.Price, unusedDays, totalDays)
+}
+
+// truncateToDate drops the time part, subscription dates are stored as DATE.
+func truncateToDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
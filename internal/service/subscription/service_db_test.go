@@ -0,0 +1,280 @@
+package subscription
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/4udiwe/subscription-service/internal/entity"
+	audit_repo "github.com/4udiwe/subscription-service/internal/repository/audit"
+	offer_repo "github.com/4udiwe/subscription-service/internal/repository/offer"
+	outbox_repo "github.com/4udiwe/subscription-service/internal/repository/outbox"
+	promo_code_repo "github.com/4udiwe/subscription-service/internal/repository/promo_code"
+	subscription_repo "github.com/4udiwe/subscription-service/internal/repository/subscription"
+	"github.com/4udiwe/subscription-service/pkg/postgres"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/pressly/goose/v3"
+	"github.com/samber/lo"
+)
+
+// TEST_POSTGRES_URL points the database tests to a disposable database, the migrations are
+// applied to it. The tests are skipped without it.
+const testPostgresURLEnv = "TEST_POSTGRES_URL"
+
+const parallelRequests = 10
+
+func newDBService(t *testing.T) (*SubscriptionService, *postgres.Postgres) {
+	t.Helper()
+
+	url := os.Getenv(testPostgresURLEnv)
+	if url == "" {
+		t.Skipf("%s is not set", testPostgresURLEnv)
+	}
+
+	pg, err := postgres.New(url)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(pg.Close)
+
+	db := stdlib.OpenDBFromPool(pg.Pool)
+	defer db.Close()
+	if err := goose.SetDialect("postgres"); err != nil {
+		t.Fatalf("goose dialect: %v", err)
+	}
+	if err := goose.Up(db, "../../database/migrations"); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	return New(
+		subscription_repo.New(pg),
+		offer_repo.New(pg),
+		promo_code_repo.New(pg),
+		outbox_repo.New(pg),
+		audit_repo.New(pg),
+		pg,
+	), pg
+}
+
+func TestCreateSubscriptionConcurrent(t *testing.T) {
+	s, pg := newDBService(t)
+	ctx := context.Background()
+
+	serviceName := "concurrent-" + uuid.NewString()
+	var offer entity.Offer
+	err := pg.WithinTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+		offer, err = s.offerRepository.Create(txCtx, serviceName, 500, entity.DefaultCurrency, 1, 0)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("create offer: %v", err)
+	}
+	startDate := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		create func(userID uuid.UUID) error
+	}{
+		{
+			name: "by name",
+			create: func(userID uuid.UUID) error {
+				_, err := s.CreateSubscription(ctx, userID, serviceName, offer.Price, offer.Currency, startDate, nil, false, false, nil, false)
+				return err
+			},
+		},
+		{
+			name: "by offer id",
+			create: func(userID uuid.UUID) error {
+				_, err := s.CreateSubscriptionByOfferID(ctx, userID, offer.ID, startDate, false, false, nil)
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID := uuid.New()
+			errs := make([]error, parallelRequests)
+
+			var wg sync.WaitGroup
+			start := make(chan struct{})
+			for i := range parallelRequests {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					<-start
+					errs[i] = tt.create(userID)
+				}()
+			}
+			close(start)
+			wg.Wait()
+
+			created := 0
+			for _, err := range errs {
+				switch {
+				case err == nil:
+					created++
+				case errors.Is(err, ErrUserAlreadyHasActiveSubscription):
+				default:
+					t.Errorf("unexpected error: %v", err)
+				}
+			}
+			if created != 1 {
+				t.Errorf("created %d subscriptions, want 1", created)
+			}
+		})
+	}
+}
+
+func TestUpdateSubscriptionOverlap(t *testing.T) {
+	s, pg := newDBService(t)
+	ctx := context.Background()
+
+	serviceName := "overlap-" + uuid.NewString()
+	var offer entity.Offer
+	err := pg.WithinTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+		offer, err = s.offerRepository.Create(txCtx, serviceName, 500, entity.DefaultCurrency, 1, 0)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("create offer: %v", err)
+	}
+
+	userID := uuid.New()
+	january, err := s.CreateSubscriptionByOfferID(ctx, userID, offer.ID, time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC), false, false, nil)
+	if err != nil {
+		t.Fatalf("create january subscription: %v", err)
+	}
+	if _, err := s.CreateSubscriptionByOfferID(ctx, userID, offer.ID, time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC), false, false, nil); err != nil {
+		t.Fatalf("create march subscription: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		startDate *time.Time
+		endDate   *time.Time
+		want      error
+	}{
+		{name: "end up to the next subscription", endDate: lo.ToPtr(time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC))},
+		{name: "end within the next subscription", endDate: lo.ToPtr(time.Date(2026, time.March, 15, 0, 0, 0, 0, time.UTC)), want: ErrUserAlreadyHasActiveSubscription},
+		{name: "period around the next subscription", startDate: lo.ToPtr(time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC)), endDate: lo.ToPtr(time.Date(2026, time.May, 1, 0, 0, 0, 0, time.UTC)), want: ErrUserAlreadyHasActiveSubscription},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.UpdateSubscription(ctx, january.ID, tt.startDate, tt.endDate, nil, nil); !errors.Is(err, tt.want) {
+				t.Errorf("UpdateSubscription() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCancelSubscriptionAfterRenewal(t *testing.T) {
+	s, pg := newDBService(t)
+	ctx := context.Background()
+
+	serviceName := "renewed-" + uuid.NewString()
+	var offer entity.Offer
+	err := pg.WithinTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+		offer, err = s.offerRepository.Create(txCtx, serviceName, 500, entity.DefaultCurrency, 1, 0)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("create offer: %v", err)
+	}
+	today := truncateToDate(time.Now())
+
+	tests := []struct {
+		name        string
+		atPeriodEnd bool
+	}{
+		{name: "at period end", atPeriodEnd: true},
+		{name: "right away", atPeriodEnd: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID := uuid.New()
+			sub, err := s.CreateSubscriptionByOfferID(ctx, userID, offer.ID, today, true, false, nil)
+			if err != nil {
+				t.Fatalf("create subscription: %v", err)
+			}
+
+			// the renewal worker creates the next period ahead of the end date
+			if _, err := s.RenewExpiringSubscriptions(ctx, sub.EndDate, 1000); err != nil {
+				t.Fatalf("renew: %v", err)
+			}
+			subs, _, err := s.subRepository.GetAllByUserID(ctx, userID, nil, 10, 0)
+			if err != nil {
+				t.Fatalf("get subscriptions: %v", err)
+			}
+			renewal, found := lo.Find(subs, func(info entity.SubscriptionFullInfo) bool {
+				return info.RenewedFromID != nil && *info.RenewedFromID == sub.ID
+			})
+			if !found {
+				t.Fatalf("subscription %s was not renewed", sub.ID)
+			}
+
+			if _, err := s.CancelSubscription(ctx, sub.ID, lo.ToPtr("too expensive"), tt.atPeriodEnd); err != nil {
+				t.Fatalf("CancelSubscription: %v", err)
+			}
+
+			got, err := s.subRepository.GetById(ctx, renewal.ID)
+			if err != nil {
+				t.Fatalf("get renewal: %v", err)
+			}
+			if got.Status != entity.SubscriptionStatusCancelled || got.CancelledAt == nil || got.AutoRenew {
+				t.Errorf("renewal: status = %s, cancelled at %v, auto-renew %t, want cancelled without auto-renew", got.Status, got.CancelledAt, got.AutoRenew)
+			}
+			if !got.EndDate.Equal(got.StartDate) {
+				t.Errorf("renewal ends on %s, want its start %s: the next period must not be paid", got.EndDate, got.StartDate)
+			}
+		})
+	}
+}
+
+func TestCostReportAfterOfferDurationChange(t *testing.T) {
+	s, pg := newDBService(t)
+	ctx := context.Background()
+
+	serviceName := "duration-" + uuid.NewString()
+	var offer entity.Offer
+	err := pg.WithinTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+		offer, err = s.offerRepository.Create(txCtx, serviceName, 310, entity.DefaultCurrency, 1, 0)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("create offer: %v", err)
+	}
+
+	userID := uuid.New()
+	startDate := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := s.CreateSubscriptionByOfferID(ctx, userID, offer.ID, startDate, false, false, nil); err != nil {
+		t.Fatalf("create subscription: %v", err)
+	}
+
+	// the subscription was bought for a month, the offer is sold for three months afterwards
+	err = pg.WithinTransaction(ctx, func(txCtx context.Context) error {
+		_, err := offer_repo.New(pg).Update(txCtx, offer.ID, offer.Name, offer.Price, offer.Currency, offer.PriceID, 3, offer.TrialDays)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("update offer: %v", err)
+	}
+
+	report, err := s.GetCostReport(ctx, &userID, []string{serviceName}, startDate, startDate.AddDate(0, 0, 9), entity.DefaultCurrency)
+	if err != nil {
+		t.Fatalf("GetCostReport: %v", err)
+	}
+	if want := 100; report.Total != want {
+		t.Errorf("total for 10 days of 31 = %d, want %d", report.Total, want)
+	}
+}
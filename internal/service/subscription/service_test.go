@@ -0,0 +1,241 @@
+package subscription
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/4udiwe/subscription-service/internal/entity"
+	promo_code_repo "github.com/4udiwe/subscription-service/internal/repository/promo_code"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+func TestProrationCredit(t *testing.T) {
+	january := entity.Subscription{StartDate: date(2025, 1, 1), EndDate: date(2025, 2, 1), Price: 310}
+	february := entity.Subscription{StartDate: date(2025, 2, 1), EndDate: date(2025, 3, 1), Price: 280}
+	quarter := entity.Subscription{StartDate: date(2025, 1, 15), EndDate: date(2025, 4, 15), Price: 900}
+	withTrial := entity.Subscription{StartDate: date(2025, 1, 1), EndDate: date(2025, 2, 8), TrialEndDate: lo.ToPtr(date(2025, 1, 8)), Price: 310}
+	trialOnly := entity.Subscription{StartDate: date(2025, 1, 1), EndDate: date(2025, 1, 8), TrialEndDate: lo.ToPtr(date(2025, 1, 8)), Price: 310}
+
+	tests := []struct {
+		name       string
+		sub        entity.Subscription
+		switchDate time.Time
+		wantDays   int
+		wantCredit int
+	}{
+		{name: "switch on the start date", sub: january, switchDate: date(2025, 1, 1), wantDays: 31, wantCredit: 310},
+		{name: "switch on the last day", sub: january, switchDate: date(2025, 1, 31), wantDays: 1, wantCredit: 10},
+		{name: "switch mid month", sub: january, switchDate: date(2025, 1, 11), wantDays: 21, wantCredit: 210},
+		{name: "short month", sub: february, switchDate: date(2025, 2, 15), wantDays: 14, wantCredit: 140},
+		{name: "period across months", sub: quarter, switchDate: date(2025, 3, 1), wantDays: 45, wantCredit: 450},
+		{name: "switch during the trial", sub: withTrial, switchDate: date(2025, 1, 3), wantDays: 31, wantCredit: 310},
+		{name: "switch on the trial end", sub: withTrial, switchDate: date(2025, 1, 8), wantDays: 31, wantCredit: 310},
+		{name: "switch after the trial", sub: withTrial, switchDate: date(2025, 1, 18), wantDays: 21, wantCredit: 210},
+		{name: "trial only", sub: trialOnly, switchDate: date(2025, 1, 3), wantDays: 0, wantCredit: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			days, credit := prorationCredit(tt.sub, tt.switchDate)
+			if days != tt.wantDays || credit != tt.wantCredit {
+				t.Errorf("prorationCredit() = %d days, credit %d, want %d days, credit %d", days, credit, tt.wantDays, tt.wantCredit)
+			}
+		})
+	}
+}
+
+// fakePromoCodeRepository keeps promo codes by code and counts their redemptions.
+type fakePromoCodeRepository struct {
+	codes map[string]*entity.PromoCode
+}
+
+func (r *fakePromoCodeRepository) GetByCodeForUpdate(_ context.Context, code string) (entity.PromoCode, error) {
+	promo, ok := r.codes[code]
+	if !ok {
+		return entity.PromoCode{}, promo_code_repo.ErrPromoCodeNotFound
+	}
+	return *promo, nil
+}
+
+func (r *fakePromoCodeRepository) Redeem(_ context.Context, id uuid.UUID) error {
+	for _, promo := range r.codes {
+		if promo.ID != id {
+			continue
+		}
+		if promo.MaxRedemptions != nil && promo.Redemptions >= *promo.MaxRedemptions {
+			return promo_code_repo.ErrPromoCodeExhausted
+		}
+		promo.Redemptions++
+		return nil
+	}
+	return promo_code_repo.ErrPromoCodeNotFound
+}
+
+func TestApplyPromoCode(t *testing.T) {
+	today := truncateToDate(time.Now())
+	offer := entity.Offer{ID: uuid.New(), Name: "Yandex Plus", Price: 999, Currency: "RUB"}
+
+	tests := []struct {
+		name      string
+		code      *string
+		promo     entity.PromoCode
+		offer     entity.Offer
+		wantPrice int
+		wantErr   error
+	}{
+		{name: "no code", offer: offer, wantPrice: 999},
+		{name: "unknown code", code: lo.ToPtr("NOPE"), offer: offer, wantErr: ErrPromoCodeNotFound},
+		{
+			name:      "code in lower case",
+			code:      lo.ToPtr("sale15"),
+			promo:     entity.PromoCode{DiscountType: entity.DiscountTypePercent, DiscountValue: 15},
+			offer:     offer,
+			wantPrice: 849,
+		},
+		{
+			name:      "percent rounded to the nearest unit",
+			code:      lo.ToPtr("SALE15"),
+			promo:     entity.PromoCode{DiscountType: entity.DiscountTypePercent, DiscountValue: 15},
+			offer:     offer,
+			wantPrice: 849,
+		},
+		{
+			name:      "percent with a half unit",
+			code:      lo.ToPtr("HALF"),
+			promo:     entity.PromoCode{DiscountType: entity.DiscountTypePercent, DiscountValue: 50},
+			offer:     entity.Offer{ID: offer.ID, Price: 333, Currency: "RUB"},
+			wantPrice: 166,
+		},
+		{
+			name:      "full percent discount",
+			code:      lo.ToPtr("FREE"),
+			promo:     entity.PromoCode{DiscountType: entity.DiscountTypePercent, DiscountValue: 100},
+			offer:     offer,
+			wantPrice: 0,
+		},
+		{
+			name:      "fixed in the offer currency",
+			code:      lo.ToPtr("MINUS200"),
+			promo:     entity.PromoCode{DiscountType: entity.DiscountTypeFixed, DiscountValue: 200, Currency: lo.ToPtr("RUB")},
+			offer:     offer,
+			wantPrice: 799,
+		},
+		{
+			name:      "fixed above the price",
+			code:      lo.ToPtr("MINUS2000"),
+			promo:     entity.PromoCode{DiscountType: entity.DiscountTypeFixed, DiscountValue: 2000, Currency: lo.ToPtr("RUB")},
+			offer:     offer,
+			wantPrice: 0,
+		},
+		{
+			name:    "fixed in another currency",
+			code:    lo.ToPtr("MINUS5USD"),
+			promo:   entity.PromoCode{DiscountType: entity.DiscountTypeFixed, DiscountValue: 5, Currency: lo.ToPtr("USD")},
+			offer:   offer,
+			wantErr: ErrPromoCodeNotApplicable,
+		},
+		{
+			name:      "valid from today",
+			code:      lo.ToPtr("FROMTODAY"),
+			promo:     entity.PromoCode{DiscountType: entity.DiscountTypePercent, DiscountValue: 10, ValidFrom: &today},
+			offer:     offer,
+			wantPrice: 899,
+		},
+		{
+			name:      "valid until today",
+			code:      lo.ToPtr("UNTILTODAY"),
+			promo:     entity.PromoCode{DiscountType: entity.DiscountTypePercent, DiscountValue: 10, ValidUntil: &today},
+			offer:     offer,
+			wantPrice: 899,
+		},
+		{
+			name:    "not valid yet",
+			code:    lo.ToPtr("TOMORROW"),
+			promo:   entity.PromoCode{DiscountType: entity.DiscountTypePercent, DiscountValue: 10, ValidFrom: lo.ToPtr(today.AddDate(0, 0, 1))},
+			offer:   offer,
+			wantErr: ErrPromoCodeNotValid,
+		},
+		{
+			name:    "expired",
+			code:    lo.ToPtr("YESTERDAY"),
+			promo:   entity.PromoCode{DiscountType: entity.DiscountTypePercent, DiscountValue: 10, ValidUntil: lo.ToPtr(today.AddDate(0, 0, -1))},
+			offer:   offer,
+			wantErr: ErrPromoCodeNotValid,
+		},
+		{
+			name:      "listed offer",
+			code:      lo.ToPtr("PLUSONLY"),
+			promo:     entity.PromoCode{DiscountType: entity.DiscountTypePercent, DiscountValue: 10, OfferIDs: []uuid.UUID{uuid.New(), offer.ID}},
+			offer:     offer,
+			wantPrice: 899,
+		},
+		{
+			name:    "offer not listed",
+			code:    lo.ToPtr("OTHERONLY"),
+			promo:   entity.PromoCode{DiscountType: entity.DiscountTypePercent, DiscountValue: 10, OfferIDs: []uuid.UUID{uuid.New()}},
+			offer:   offer,
+			wantErr: ErrPromoCodeNotApplicable,
+		},
+		{
+			name:      "last redemption",
+			code:      lo.ToPtr("LASTONE"),
+			promo:     entity.PromoCode{DiscountType: entity.DiscountTypePercent, DiscountValue: 10, MaxRedemptions: lo.ToPtr(3), Redemptions: 2},
+			offer:     offer,
+			wantPrice: 899,
+		},
+		{
+			name:    "exhausted",
+			code:    lo.ToPtr("USEDUP"),
+			promo:   entity.PromoCode{DiscountType: entity.DiscountTypePercent, DiscountValue: 10, MaxRedemptions: lo.ToPtr(3), Redemptions: 3},
+			offer:   offer,
+			wantErr: ErrPromoCodeExhausted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakePromoCodeRepository{codes: make(map[string]*entity.PromoCode)}
+			promo := tt.promo
+			promo.ID = uuid.New()
+			if tt.code != nil && tt.wantErr != ErrPromoCodeNotFound {
+				promo.Code = strings.ToUpper(*tt.code)
+				repo.codes[promo.Code] = &promo
+			}
+			s := &SubscriptionService{promoCodeRepository: repo}
+
+			price, promoID, err := s.applyPromoCode(context.Background(), tt.code, tt.offer)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("applyPromoCode() error = %v, want %v", err, tt.wantErr)
+			}
+
+			// a rejected code is not redeemed
+			redeemed := promo.Redemptions - tt.promo.Redemptions
+			if tt.wantErr != nil {
+				if redeemed != 0 {
+					t.Errorf("promo code redeemed %d times, want none", redeemed)
+				}
+				return
+			}
+
+			if price != tt.wantPrice {
+				t.Errorf("price = %d, want %d", price, tt.wantPrice)
+			}
+			if tt.code == nil {
+				if promoID != nil {
+					t.Errorf("promo code ID = %s, want none", *promoID)
+				}
+				return
+			}
+			if promoID == nil || *promoID != promo.ID {
+				t.Errorf("promo code ID = %v, want %s", promoID, promo.ID)
+			}
+			if redeemed != 1 {
+				t.Errorf("promo code redeemed %d times, want once", redeemed)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"github.com/4udiwe/subscription-service/internal/entity"
+	"github.com/google/uuid"
+)
+
+type WebhookRepository interface {
+	CreateEndpoint(ctx context.Context, url, secret string, eventTypes []string) (entity.WebhookEndpoint, error)
+	GetEndpoints(ctx context.Context, limit int, offset int) (endpoints []entity.WebhookEndpoint, total int, err error)
+	GetEndpointByID(ctx context.Context, id uuid.UUID) (entity.WebhookEndpoint, error)
+	DeleteEndpoint(ctx context.Context, id uuid.UUID) error
+	CreateDeliveries(ctx context.Context, eventID int64, eventType string, payload []byte) (int64, error)
+	ClaimDue(ctx context.Context, limit int, leaseUntil time.Time) ([]entity.WebhookDeliveryTarget, error)
+	MarkDelivered(ctx context.Context, id uuid.UUID, statusCode int) error
+	MarkFailed(ctx context.Context, id uuid.UUID, statusCode *int, reason string, nextAttemptAt time.Time, dead bool) error
+	GetDeliveries(
+		ctx context.Context,
+		endpointID uuid.UUID,
+		status *entity.WebhookDeliveryStatus,
+		limit int,
+		offset int,
+	) (deliveries []entity.WebhookDelivery, total int, err error)
+	Replay(ctx context.Context, id uuid.UUID) (entity.WebhookDelivery, error)
+}
+
+type Sender interface {
+	Send(ctx context.Context, url, secret string, eventID int64, eventType string, body []byte) (int, error)
+}
@@ -0,0 +1,18 @@
+package webhook
+
+import "errors"
+
+var (
+	ErrUnknownEventType = errors.New("unknown event type")
+	ErrEndpointNotFound = errors.New("webhook endpoint not found")
+	ErrDeliveryNotFound = errors.New("webhook delivery not found")
+
+	ErrCannotCreateEndpoint   = errors.New("cannot create webhook endpoint")
+	ErrCannotFetchEndpoints   = errors.New("cannot fetch webhook endpoints")
+	ErrCannotDeleteEndpoint   = errors.New("cannot delete webhook endpoint")
+	ErrCannotFetchDeliveries  = errors.New("cannot fetch webhook deliveries")
+	ErrCannotReplayDelivery   = errors.New("cannot replay webhook delivery")
+	ErrCannotScheduleDelivery = errors.New("cannot schedule webhook delivery")
+	ErrCannotDeliverWebhooks  = errors.New("cannot deliver webhooks")
+	ErrCannotGenerateSecret   = errors.New("cannot generate webhook secret")
+)
@@ -0,0 +1,28 @@
+package webhook
+
+import "time"
+
+type Option func(*WebhookService)
+
+// MaxAttempts sets after how many failed attempts a delivery is moved to the dead status.
+func MaxAttempts(attempts int) Option {
+	return func(s *WebhookService) {
+		s.maxAttempts = attempts
+	}
+}
+
+// Backoff sets the delay before the first retry, it doubles with every failed attempt up to maxDelay.
+func Backoff(delay, maxDelay time.Duration) Option {
+	return func(s *WebhookService) {
+		s.backoff = delay
+		s.maxBackoff = maxDelay
+	}
+}
+
+// Lease sets how long a claimed delivery is hidden from other workers, it must be longer than
+// the request timeout.
+func Lease(lease time.Duration) Option {
+	return func(s *WebhookService) {
+		s.lease = lease
+	}
+}
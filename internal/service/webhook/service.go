@@ -0,0 +1,256 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/4udiwe/subscription-service/internal/entity"
+	"github.com/4udiwe/subscription-service/internal/publisher"
+	webhook_repo "github.com/4udiwe/subscription-service/internal/repository/webhook"
+	"github.com/4udiwe/subscription-service/pkg/logger"
+	"github.com/4udiwe/subscription-service/pkg/tracing"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+const (
+	defaultMaxAttempts = 8
+	defaultBackoff     = 30 * time.Second
+	defaultMaxBackoff  = time.Hour
+	defaultLease       = time.Minute
+
+	secretBytes = 32
+)
+
+type WebhookService struct {
+	webhookRepository WebhookRepository
+	sender            Sender
+
+	maxAttempts int
+	backoff     time.Duration
+	maxBackoff  time.Duration
+	lease       time.Duration
+}
+
+func New(webhookRepository WebhookRepository, sender Sender, opts ...Option) *WebhookService {
+	s := &WebhookService{
+		webhookRepository: webhookRepository,
+		sender:            sender,
+		maxAttempts:       defaultMaxAttempts,
+		backoff:           defaultBackoff,
+		maxBackoff:        defaultMaxBackoff,
+		lease:             defaultLease,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// CreateEndpoint registers a webhook endpoint for the given event types, no types means all of
+// them. A random secret is generated when none is given.
+func (s *WebhookService) CreateEndpoint(ctx context.Context, url string, secret *string, eventTypes []string) (_ entity.WebhookEndpoint, err error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.CreateEndpoint")
+	defer tracing.End(span, &err)
+	logger.FromContext(ctx).Infof("WebhookService.CreateEndpoint called: url=%s, eventTypes=%v", url, eventTypes)
+
+	for _, eventType := range eventTypes {
+		if !lo.Contains(entity.EventTypes, eventType) {
+			return entity.WebhookEndpoint{}, fmt.Errorf("%w: %s", ErrUnknownEventType, eventType)
+		}
+	}
+
+	var key string
+	if secret != nil {
+		key = *secret
+	} else {
+		generated, err := generateSecret()
+		if err != nil {
+			logger.FromContext(ctx).Errorf("WebhookService.CreateEndpoint error generating secret: %v", err)
+			return entity.WebhookEndpoint{}, ErrCannotGenerateSecret
+		}
+		key = generated
+	}
+
+	endpoint, err := s.webhookRepository.CreateEndpoint(ctx, url, key, lo.Uniq(eventTypes))
+	if err != nil {
+		logger.FromContext(ctx).Errorf("WebhookService.CreateEndpoint error: %v", err)
+		return entity.WebhookEndpoint{}, ErrCannotCreateEndpoint
+	}
+
+	logger.FromContext(ctx).Infof("WebhookService.CreateEndpoint success: id=%s", endpoint.ID)
+	return endpoint, nil
+}
+
+func (s *WebhookService) GetEndpoints(ctx context.Context, page int, pageSize int) (endpoints []entity.WebhookEndpoint, total int, err error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.GetEndpoints")
+	defer tracing.End(span, &err)
+	logger.FromContext(ctx).Info("WebhookService.GetEndpoints called")
+
+	limit := pageSize
+	offset := (page - 1) * pageSize
+
+	endpoints, total, err = s.webhookRepository.GetEndpoints(ctx, limit, offset)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("WebhookService.GetEndpoints error: %v", err)
+		return nil, 0, ErrCannotFetchEndpoints
+	}
+
+	logger.FromContext(ctx).Infof("WebhookService.GetEndpoints success: count=%d", len(endpoints))
+	return endpoints, total, nil
+}
+
+func (s *WebhookService) DeleteEndpoint(ctx context.Context, id uuid.UUID) (err error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.DeleteEndpoint")
+	defer tracing.End(span, &err)
+	logger.FromContext(ctx).Infof("WebhookService.DeleteEndpoint called: id=%s", id)
+
+	if err := s.webhookRepository.DeleteEndpoint(ctx, id); err != nil {
+		if errors.Is(err, webhook_repo.ErrEndpointNotFound) {
+			return ErrEndpointNotFound
+		}
+		logger.FromContext(ctx).Errorf("WebhookService.DeleteEndpoint error: %v", err)
+		return ErrCannotDeleteEndpoint
+	}
+
+	logger.FromContext(ctx).Infof("WebhookService.DeleteEndpoint success: id=%s", id)
+	return nil
+}
+
+// GetDeliveries returns the deliveries of the endpoint, newest first, optionally filtered by status.
+func (s *WebhookService) GetDeliveries(
+	ctx context.Context,
+	endpointID uuid.UUID,
+	status *entity.WebhookDeliveryStatus,
+	page int,
+	pageSize int,
+) (_ []entity.WebhookDelivery, _ int, err error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.GetDeliveries")
+	defer tracing.End(span, &err)
+	logger.FromContext(ctx).Infof("WebhookService.GetDeliveries called: endpointID=%s, status=%v", endpointID, status)
+
+	if _, err := s.webhookRepository.GetEndpointByID(ctx, endpointID); err != nil {
+		if errors.Is(err, webhook_repo.ErrEndpointNotFound) {
+			return nil, 0, ErrEndpointNotFound
+		}
+		logger.FromContext(ctx).Errorf("WebhookService.GetDeliveries error getting endpoint: %v", err)
+		return nil, 0, ErrCannotFetchDeliveries
+	}
+
+	limit := pageSize
+	offset := (page - 1) * pageSize
+
+	deliveries, total, err := s.webhookRepository.GetDeliveries(ctx, endpointID, status, limit, offset)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("WebhookService.GetDeliveries error: %v", err)
+		return nil, 0, ErrCannotFetchDeliveries
+	}
+
+	logger.FromContext(ctx).Infof("WebhookService.GetDeliveries success: count=%d", len(deliveries))
+	return deliveries, total, nil
+}
+
+// ReplayDelivery sends the delivery again on the next worker run, including delivered and dead ones.
+func (s *WebhookService) ReplayDelivery(ctx context.Context, id uuid.UUID) (_ entity.WebhookDelivery, err error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.ReplayDelivery")
+	defer tracing.End(span, &err)
+	logger.FromContext(ctx).Infof("WebhookService.ReplayDelivery called: id=%s", id)
+
+	delivery, err := s.webhookRepository.Replay(ctx, id)
+	if err != nil {
+		if errors.Is(err, webhook_repo.ErrDeliveryNotFound) {
+			return entity.WebhookDelivery{}, ErrDeliveryNotFound
+		}
+		logger.FromContext(ctx).Errorf("WebhookService.ReplayDelivery error: %v", err)
+		return entity.WebhookDelivery{}, ErrCannotReplayDelivery
+	}
+
+	logger.FromContext(ctx).Infof("WebhookService.ReplayDelivery success: id=%s", delivery.ID)
+	return delivery, nil
+}
+
+// Publish schedules a delivery of the event to every subscribed endpoint. The outbox relay can
+// pass an event again, an endpoint that already has a delivery of it does not get another one.
+func (s *WebhookService) Publish(ctx context.Context, event entity.OutboxEvent) (err error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.Publish")
+	defer tracing.End(span, &err)
+	body, err := publisher.Marshal(event)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("WebhookService.Publish error marshalling event %d: %v", event.ID, err)
+		return ErrCannotScheduleDelivery
+	}
+
+	if _, err := s.webhookRepository.CreateDeliveries(ctx, event.ID, event.EventType, body); err != nil {
+		logger.FromContext(ctx).Errorf("WebhookService.Publish error scheduling event %d: %v", event.ID, err)
+		return ErrCannotScheduleDelivery
+	}
+	return nil
+}
+
+// Deliver sends up to batchSize due deliveries and returns how many were attempted. A failed
+// delivery is retried with exponential backoff and moved to the dead status after maxAttempts.
+// Deliveries are independent, so a failing endpoint does not hold back the others.
+func (s *WebhookService) Deliver(ctx context.Context, batchSize int) (_ int, err error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.Deliver")
+	defer tracing.End(span, &err)
+	logger.FromContext(ctx).Debugf("WebhookService.Deliver called: batchSize=%d", batchSize)
+
+	targets, err := s.webhookRepository.ClaimDue(ctx, batchSize, time.Now().Add(s.lease))
+	if err != nil {
+		logger.FromContext(ctx).Errorf("WebhookService.Deliver error claiming deliveries: %v", err)
+		return 0, ErrCannotDeliverWebhooks
+	}
+
+	for _, target := range targets {
+		statusCode, sendErr := s.sender.Send(ctx, target.URL, target.Secret, target.EventID, target.EventType, target.Payload)
+		if sendErr == nil {
+			if err := s.webhookRepository.MarkDelivered(ctx, target.ID, statusCode); err != nil {
+				logger.FromContext(ctx).Errorf("WebhookService.Deliver error marking delivery %s delivered: %v", target.ID, err)
+			}
+			continue
+		}
+
+		attempts := target.Attempts + 1
+		dead := attempts >= s.maxAttempts
+		if dead {
+			logger.FromContext(ctx).Warnf("WebhookService.Deliver: delivery %s to %s is dead after %d attempts: %v", target.ID, target.URL, attempts, sendErr)
+		} else {
+			logger.FromContext(ctx).Warnf("WebhookService.Deliver: delivery %s to %s failed, attempt %d: %v", target.ID, target.URL, attempts, sendErr)
+		}
+
+		var code *int
+		if statusCode != 0 {
+			code = &statusCode
+		}
+		if err := s.webhookRepository.MarkFailed(ctx, target.ID, code, sendErr.Error(), time.Now().Add(s.retryDelay(attempts)), dead); err != nil {
+			logger.FromContext(ctx).Errorf("WebhookService.Deliver error marking delivery %s failed: %v", target.ID, err)
+		}
+	}
+
+	logger.FromContext(ctx).Debugf("WebhookService.Deliver success: attempted=%d", len(targets))
+	return len(targets), nil
+}
+
+// retryDelay returns the delay after the given number of failed attempts: backoff, 2*backoff,
+// 4*backoff and so on, capped at maxBackoff.
+func (s *WebhookService) retryDelay(attempts int) time.Duration {
+	delay := s.backoff
+	for i := 1; i < attempts && delay < s.maxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, s.maxBackoff)
+}
+
+func generateSecret() (string, error) {
+	buf := make([]byte, secretBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
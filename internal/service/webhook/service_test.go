@@ -0,0 +1,312 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/4udiwe/subscription-service/internal/entity"
+	webhook_publisher "github.com/4udiwe/subscription-service/internal/publisher/webhook"
+	webhook_repo "github.com/4udiwe/subscription-service/internal/repository/webhook"
+	"github.com/google/uuid"
+)
+
+const testSecret = "test-secret"
+
+// fakeRepository keeps the deliveries of one endpoint in memory. ClaimDue returns the pending
+// deliveries due at now, the test moves now forward instead of sleeping.
+type fakeRepository struct {
+	WebhookRepository
+
+	mu         sync.Mutex
+	endpoint   entity.WebhookEndpoint
+	deliveries map[uuid.UUID]*entity.WebhookDelivery
+	now        time.Time
+}
+
+func newFakeRepository(url string) *fakeRepository {
+	return &fakeRepository{
+		endpoint:   entity.WebhookEndpoint{ID: uuid.New(), URL: url, Secret: testSecret},
+		deliveries: make(map[uuid.UUID]*entity.WebhookDelivery),
+		now:        time.Now(),
+	}
+}
+
+func (r *fakeRepository) add(eventID int64, payload string) uuid.UUID {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	d := &entity.WebhookDelivery{
+		ID:            uuid.New(),
+		EndpointID:    r.endpoint.ID,
+		EventID:       eventID,
+		EventType:     entity.EventSubscriptionCreated,
+		Payload:       []byte(payload),
+		Status:        entity.WebhookDeliveryStatusPending,
+		NextAttemptAt: r.now,
+	}
+	r.deliveries[d.ID] = d
+	return d.ID
+}
+
+func (r *fakeRepository) get(id uuid.UUID) entity.WebhookDelivery {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return *r.deliveries[id]
+}
+
+// advance moves the clock past the next attempt of every delivery.
+func (r *fakeRepository) advance() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, d := range r.deliveries {
+		if d.NextAttemptAt.After(r.now) {
+			r.now = d.NextAttemptAt
+		}
+	}
+}
+
+func (r *fakeRepository) ClaimDue(_ context.Context, limit int, leaseUntil time.Time) ([]entity.WebhookDeliveryTarget, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var targets []entity.WebhookDeliveryTarget
+	for _, d := range r.deliveries {
+		if len(targets) == limit {
+			break
+		}
+		if d.Status != entity.WebhookDeliveryStatusPending || d.NextAttemptAt.After(r.now) {
+			continue
+		}
+		d.NextAttemptAt = leaseUntil
+		targets = append(targets, entity.WebhookDeliveryTarget{WebhookDelivery: *d, URL: r.endpoint.URL, Secret: r.endpoint.Secret})
+	}
+	return targets, nil
+}
+
+func (r *fakeRepository) MarkDelivered(_ context.Context, id uuid.UUID, statusCode int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	d := r.deliveries[id]
+	d.Status = entity.WebhookDeliveryStatusDelivered
+	d.Attempts++
+	d.LastStatusCode = &statusCode
+	d.LastError = nil
+	d.DeliveredAt = &r.now
+	return nil
+}
+
+func (r *fakeRepository) MarkFailed(_ context.Context, id uuid.UUID, statusCode *int, reason string, nextAttemptAt time.Time, dead bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	d := r.deliveries[id]
+	if dead {
+		d.Status = entity.WebhookDeliveryStatusDead
+	}
+	d.Attempts++
+	d.NextAttemptAt = nextAttemptAt
+	d.LastStatusCode = statusCode
+	d.LastError = &reason
+	return nil
+}
+
+func (r *fakeRepository) Replay(_ context.Context, id uuid.UUID) (entity.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	d, ok := r.deliveries[id]
+	if !ok {
+		return entity.WebhookDelivery{}, webhook_repo.ErrDeliveryNotFound
+	}
+	d.Status = entity.WebhookDeliveryStatusPending
+	d.Attempts = 0
+	d.NextAttemptAt = r.now
+	d.DeliveredAt = nil
+	return *d, nil
+}
+
+// receiver is a webhook endpoint that answers with status and checks the signature of every request.
+type receiver struct {
+	t *testing.T
+
+	mu       sync.Mutex
+	status   int
+	requests int
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		rc.t.Errorf("read body: %v", err)
+	}
+	timestamp, err := strconv.ParseInt(r.Header.Get(webhook_publisher.HeaderTimestamp), 10, 64)
+	if err != nil {
+		rc.t.Errorf("timestamp header: %v", err)
+	}
+	if !webhook_publisher.Verify(testSecret, timestamp, body, r.Header.Get(webhook_publisher.HeaderSignature)) {
+		rc.t.Errorf("signature %q does not match the body", r.Header.Get(webhook_publisher.HeaderSignature))
+	}
+	if got := r.Header.Get(webhook_publisher.HeaderEventType); got != entity.EventSubscriptionCreated {
+		rc.t.Errorf("event type header = %q, want %q", got, entity.EventSubscriptionCreated)
+	}
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.requests++
+	w.WriteHeader(rc.status)
+}
+
+func (rc *receiver) received() int {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return rc.requests
+}
+
+func (rc *receiver) respond(status int) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.status = status
+}
+
+func newTestService(t *testing.T, status int, opts ...Option) (*WebhookService, *fakeRepository, *receiver) {
+	t.Helper()
+	rc := &receiver{t: t, status: status}
+	server := httptest.NewServer(rc)
+	t.Cleanup(server.Close)
+
+	repo := newFakeRepository(server.URL)
+	return New(repo, webhook_publisher.NewSender(time.Second), opts...), repo, rc
+}
+
+func TestDeliverSigned(t *testing.T) {
+	s, repo, rc := newTestService(t, http.StatusNoContent)
+	id := repo.add(1, `{"id":1}`)
+
+	attempted, err := s.Deliver(context.Background(), 10)
+	if err != nil {
+		t.Fatalf("Deliver: %v", err)
+	}
+	if attempted != 1 || rc.received() != 1 {
+		t.Fatalf("attempted %d, received %d, want 1", attempted, rc.received())
+	}
+
+	d := repo.get(id)
+	if d.Status != entity.WebhookDeliveryStatusDelivered {
+		t.Errorf("status = %s, want delivered", d.Status)
+	}
+	if d.LastStatusCode == nil || *d.LastStatusCode != http.StatusNoContent {
+		t.Errorf("last status code = %v, want %d", d.LastStatusCode, http.StatusNoContent)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	s := New(nil, nil, Backoff(time.Second, 10*time.Second))
+
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 1, want: time.Second},
+		{attempts: 2, want: 2 * time.Second},
+		{attempts: 3, want: 4 * time.Second},
+		{attempts: 4, want: 8 * time.Second},
+		{attempts: 5, want: 10 * time.Second},
+		{attempts: 50, want: 10 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.attempts), func(t *testing.T) {
+			if got := s.retryDelay(tt.attempts); got != tt.want {
+				t.Errorf("retryDelay(%d) = %v, want %v", tt.attempts, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeliverRetriesUntilDead(t *testing.T) {
+	const maxAttempts = 4
+	s, repo, rc := newTestService(t, http.StatusInternalServerError, MaxAttempts(maxAttempts), Backoff(time.Minute, time.Hour))
+	id := repo.add(1, `{"id":1}`)
+
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		before := time.Now()
+		if _, err := s.Deliver(context.Background(), 10); err != nil {
+			t.Fatalf("Deliver: %v", err)
+		}
+
+		d := repo.get(id)
+		if d.Attempts != attempt {
+			t.Fatalf("attempts = %d, want %d", d.Attempts, attempt)
+		}
+		if d.LastStatusCode == nil || *d.LastStatusCode != http.StatusInternalServerError {
+			t.Errorf("last status code = %v, want %d", d.LastStatusCode, http.StatusInternalServerError)
+		}
+		if attempt < maxAttempts {
+			if d.Status != entity.WebhookDeliveryStatusPending {
+				t.Fatalf("status after attempt %d = %s, want pending", attempt, d.Status)
+			}
+			// the next attempt waits for the backoff of this one
+			if wait := d.NextAttemptAt.Sub(before); wait < s.retryDelay(attempt) {
+				t.Errorf("next attempt in %v, want at least %v", wait, s.retryDelay(attempt))
+			}
+		}
+
+		// a delivery that is not due yet is not sent again
+		if _, err := s.Deliver(context.Background(), 10); err != nil {
+			t.Fatalf("Deliver: %v", err)
+		}
+		if rc.received() != attempt {
+			t.Fatalf("received %d requests, want %d", rc.received(), attempt)
+		}
+		repo.advance()
+	}
+
+	if d := repo.get(id); d.Status != entity.WebhookDeliveryStatusDead {
+		t.Fatalf("status = %s, want dead", d.Status)
+	}
+	if _, err := s.Deliver(context.Background(), 10); err != nil {
+		t.Fatalf("Deliver: %v", err)
+	}
+	if rc.received() != maxAttempts {
+		t.Errorf("a dead delivery was sent again: received %d requests", rc.received())
+	}
+}
+
+func TestReplayDelivery(t *testing.T) {
+	s, repo, rc := newTestService(t, http.StatusBadRequest, MaxAttempts(1))
+	id := repo.add(1, `{"id":1}`)
+
+	if _, err := s.Deliver(context.Background(), 10); err != nil {
+		t.Fatalf("Deliver: %v", err)
+	}
+	if d := repo.get(id); d.Status != entity.WebhookDeliveryStatusDead {
+		t.Fatalf("status = %s, want dead", d.Status)
+	}
+
+	rc.respond(http.StatusOK)
+	replayed, err := s.ReplayDelivery(context.Background(), id)
+	if err != nil {
+		t.Fatalf("ReplayDelivery: %v", err)
+	}
+	if replayed.Status != entity.WebhookDeliveryStatusPending || replayed.Attempts != 0 {
+		t.Errorf("replayed delivery: status = %s, attempts = %d, want pending and 0", replayed.Status, replayed.Attempts)
+	}
+
+	if _, err := s.Deliver(context.Background(), 10); err != nil {
+		t.Fatalf("Deliver: %v", err)
+	}
+	d := repo.get(id)
+	if d.Status != entity.WebhookDeliveryStatusDelivered || d.Attempts != 1 {
+		t.Errorf("status = %s, attempts = %d, want delivered and 1", d.Status, d.Attempts)
+	}
+	if rc.received() != 2 {
+		t.Errorf("received %d requests, want 2", rc.received())
+	}
+
+	if _, err := s.ReplayDelivery(context.Background(), uuid.New()); !errors.Is(err, ErrDeliveryNotFound) {
+		t.Errorf("ReplayDelivery of an unknown delivery: err = %v, want %v", err, ErrDeliveryNotFound)
+	}
+}
@@ -0,0 +1,7 @@
+package delivery
+
+import "context"
+
+type WebhookService interface {
+	Deliver(ctx context.Context, batchSize int) (int, error)
+}
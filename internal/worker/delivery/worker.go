@@ -0,0 +1,88 @@
+package delivery
+
+import (
+	"context"
+	"time"
+
+	"github.com/4udiwe/subscription-service/pkg/logger"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultInterval  = 5 * time.Second
+	defaultBatchSize = 100
+)
+
+// Worker periodically sends due webhook deliveries.
+type Worker struct {
+	s         WebhookService
+	interval  time.Duration
+	batchSize int
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func New(s WebhookService, opts ...Option) *Worker {
+	w := &Worker{
+		s:         s,
+		interval:  defaultInterval,
+		batchSize: defaultBatchSize,
+	}
+
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	return w
+}
+
+// Start runs the worker in a background goroutine. The first run happens immediately.
+func (w *Worker) Start() {
+	ctx, cancel := context.WithCancel(logger.WithFields(context.Background(), logrus.Fields{"worker": "delivery"}))
+	w.cancel = cancel
+	w.done = make(chan struct{})
+
+	go func() {
+		defer close(w.done)
+
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			w.run(ctx)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop cancels the current run and waits for the worker to exit.
+func (w *Worker) Stop() {
+	if w.cancel == nil {
+		return
+	}
+	w.cancel()
+	<-w.done
+}
+
+// run sends due deliveries batch by batch until a short batch shows the queue is drained.
+func (w *Worker) run(ctx context.Context) {
+	for ctx.Err() == nil {
+		attempted, err := w.s.Deliver(ctx, w.batchSize)
+		if err != nil {
+			logger.FromContext(ctx).Errorf("DeliveryWorker.run error: %v", err)
+			return
+		}
+		if attempted > 0 {
+			logger.FromContext(ctx).Infof("DeliveryWorker.run: attempted %d deliveries", attempted)
+		}
+		if attempted < w.batchSize {
+			return
+		}
+	}
+}
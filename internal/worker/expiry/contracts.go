@@ -0,0 +1,10 @@
+package expiry
+
+import (
+	"context"
+	"time"
+)
+
+type SubscriptionService interface {
+	ExpireSubscriptions(ctx context.Context, date time.Time, renewalGrace time.Duration) (int, error)
+}
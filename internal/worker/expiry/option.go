@@ -0,0 +1,22 @@
+package expiry
+
+import "time"
+
+type Option func(*Worker)
+
+// Interval sets how often the worker looks for ended subscriptions.
+func Interval(interval time.Duration) Option {
+	return func(w *Worker) {
+		if interval > 0 {
+			w.interval = interval
+		}
+	}
+}
+
+// RenewalGrace sets how long an auto-renewable subscription that has not been renewed yet stays
+// active after its end date, so the renewal worker can still renew it.
+func RenewalGrace(grace time.Duration) Option {
+	return func(w *Worker) {
+		w.renewalGrace = grace
+	}
+}
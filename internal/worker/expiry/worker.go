@@ -0,0 +1,83 @@
+package expiry
+
+import (
+	"context"
+	"time"
+
+	"github.com/4udiwe/subscription-service/pkg/logger"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultInterval     = time.Hour
+	defaultRenewalGrace = 24 * time.Hour
+)
+
+// Worker periodically moves subscriptions that have ended to their final status. It runs on its
+// own, so subscriptions expire whether or not the renewal worker is enabled or succeeds.
+type Worker struct {
+	s            SubscriptionService
+	interval     time.Duration
+	renewalGrace time.Duration
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func New(s SubscriptionService, opts ...Option) *Worker {
+	w := &Worker{
+		s:            s,
+		interval:     defaultInterval,
+		renewalGrace: defaultRenewalGrace,
+	}
+
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	return w
+}
+
+// Start runs the worker in a background goroutine. The first run happens immediately.
+func (w *Worker) Start() {
+	ctx, cancel := context.WithCancel(logger.WithFields(context.Background(), logrus.Fields{"worker": "expiry"}))
+	w.cancel = cancel
+	w.done = make(chan struct{})
+
+	go func() {
+		defer close(w.done)
+
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			w.run(ctx)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop cancels the current run and waits for the worker to exit.
+func (w *Worker) Stop() {
+	if w.cancel == nil {
+		return
+	}
+	w.cancel()
+	<-w.done
+}
+
+func (w *Worker) run(ctx context.Context) {
+	expired, err := w.s.ExpireSubscriptions(ctx, time.Now(), w.renewalGrace)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("ExpiryWorker.run error: %v", err)
+		return
+	}
+	if expired > 0 {
+		logger.FromContext(ctx).Infof("ExpiryWorker.run: expired %d subscriptions", expired)
+	}
+}
@@ -0,0 +1,10 @@
+package pricing
+
+import (
+	"context"
+	"time"
+)
+
+type OfferService interface {
+	ApplyDuePriceChanges(ctx context.Context, date time.Time, batchSize int) (int, error)
+}
@@ -0,0 +1,82 @@
+package pricing
+
+import (
+	"context"
+	"time"
+
+	"github.com/4udiwe/subscription-service/pkg/logger"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultInterval  = time.Hour
+	defaultBatchSize = 100
+)
+
+// Worker periodically applies scheduled price changes that have come due to their offers.
+type Worker struct {
+	s         OfferService
+	interval  time.Duration
+	batchSize int
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func New(s OfferService, opts ...Option) *Worker {
+	w := &Worker{
+		s:         s,
+		interval:  defaultInterval,
+		batchSize: defaultBatchSize,
+	}
+
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	return w
+}
+
+// Start runs the worker in a background goroutine. The first run happens immediately.
+func (w *Worker) Start() {
+	ctx, cancel := context.WithCancel(logger.WithFields(context.Background(), logrus.Fields{"worker": "pricing"}))
+	w.cancel = cancel
+	w.done = make(chan struct{})
+
+	go func() {
+		defer close(w.done)
+
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			w.run(ctx)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop cancels the current run and waits for the worker to exit.
+func (w *Worker) Stop() {
+	if w.cancel == nil {
+		return
+	}
+	w.cancel()
+	<-w.done
+}
+
+func (w *Worker) run(ctx context.Context) {
+	applied, err := w.s.ApplyDuePriceChanges(ctx, time.Now(), w.batchSize)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("PricingWorker.run error: %v", err)
+		return
+	}
+	if applied > 0 {
+		logger.FromContext(ctx).Infof("PricingWorker.run: applied new prices to %d offers", applied)
+	}
+}
@@ -0,0 +1,7 @@
+package relay
+
+import "context"
+
+type OutboxService interface {
+	Relay(ctx context.Context, batchSize int) (int, error)
+}
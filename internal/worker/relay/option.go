@@ -0,0 +1,21 @@
+package relay
+
+import "time"
+
+type Option func(*Worker)
+
+// Interval sets how often the worker looks for pending events.
+func Interval(interval time.Duration) Option {
+	return func(w *Worker) {
+		if interval > 0 {
+			w.interval = interval
+		}
+	}
+}
+
+// BatchSize sets the maximum number of events published in one transaction.
+func BatchSize(size int) Option {
+	return func(w *Worker) {
+		w.batchSize = size
+	}
+}
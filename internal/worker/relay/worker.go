@@ -0,0 +1,89 @@
+package relay
+
+import (
+	"context"
+	"time"
+
+	"github.com/4udiwe/subscription-service/pkg/logger"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultInterval  = 5 * time.Second
+	defaultBatchSize = 100
+)
+
+// Worker periodically publishes pending outbox events.
+type Worker struct {
+	s         OutboxService
+	interval  time.Duration
+	batchSize int
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func New(s OutboxService, opts ...Option) *Worker {
+	w := &Worker{
+		s:         s,
+		interval:  defaultInterval,
+		batchSize: defaultBatchSize,
+	}
+
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	return w
+}
+
+// Start runs the worker in a background goroutine. The first run happens immediately.
+func (w *Worker) Start() {
+	ctx, cancel := context.WithCancel(logger.WithFields(context.Background(), logrus.Fields{"worker": "relay"}))
+	w.cancel = cancel
+	w.done = make(chan struct{})
+
+	go func() {
+		defer close(w.done)
+
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			w.run(ctx)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop cancels the current run and waits for the worker to exit.
+func (w *Worker) Stop() {
+	if w.cancel == nil {
+		return
+	}
+	w.cancel()
+	<-w.done
+}
+
+// run drains the outbox batch by batch, a short batch means there is nothing left to publish
+// or a delivery failed and should wait for the next tick.
+func (w *Worker) run(ctx context.Context) {
+	for ctx.Err() == nil {
+		published, err := w.s.Relay(ctx, w.batchSize)
+		if err != nil {
+			logger.FromContext(ctx).Errorf("RelayWorker.run error: %v", err)
+			return
+		}
+		if published > 0 {
+			logger.FromContext(ctx).Infof("RelayWorker.run: published %d events", published)
+		}
+		if published < w.batchSize {
+			return
+		}
+	}
+}
@@ -0,0 +1,11 @@
+package renewal
+
+import (
+	"context"
+	"time"
+)
+
+type SubscriptionService interface {
+	RenewExpiringSubscriptions(ctx context.Context, until time.Time, batchSize int) (int, error)
+	NotifyExpiringSubscriptions(ctx context.Context, until time.Time, batchSize int) (int, error)
+}
@@ -0,0 +1,36 @@
+package renewal
+
+import "time"
+
+type Option func(*Worker)
+
+// Interval sets how often the worker looks for expiring subscriptions.
+func Interval(interval time.Duration) Option {
+	return func(w *Worker) {
+		if interval > 0 {
+			w.interval = interval
+		}
+	}
+}
+
+// Window sets how long before the end date a subscription is renewed.
+func Window(window time.Duration) Option {
+	return func(w *Worker) {
+		w.window = window
+	}
+}
+
+// NoticeWindow sets how long before the end date a subscription that is not renewed is
+// announced as expiring soon.
+func NoticeWindow(window time.Duration) Option {
+	return func(w *Worker) {
+		w.noticeWindow = window
+	}
+}
+
+// BatchSize sets the maximum number of subscriptions renewed per tick.
+func BatchSize(size int) Option {
+	return func(w *Worker) {
+		w.batchSize = size
+	}
+}
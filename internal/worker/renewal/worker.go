@@ -0,0 +1,97 @@
+package renewal
+
+import (
+	"context"
+	"time"
+
+	"github.com/4udiwe/subscription-service/pkg/logger"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultInterval     = time.Hour
+	defaultWindow       = 24 * time.Hour
+	defaultNoticeWindow = 72 * time.Hour
+	defaultBatchSize    = 100
+)
+
+// Worker periodically renews auto-renewable subscriptions that end within the window and announces
+// subscriptions that end within the notice window without renewal.
+type Worker struct {
+	s            SubscriptionService
+	interval     time.Duration
+	window       time.Duration
+	noticeWindow time.Duration
+	batchSize    int
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func New(s SubscriptionService, opts ...Option) *Worker {
+	w := &Worker{
+		s:            s,
+		interval:     defaultInterval,
+		window:       defaultWindow,
+		noticeWindow: defaultNoticeWindow,
+		batchSize:    defaultBatchSize,
+	}
+
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	return w
+}
+
+// Start runs the worker in a background goroutine. The first run happens immediately.
+func (w *Worker) Start() {
+	ctx, cancel := context.WithCancel(logger.WithFields(context.Background(), logrus.Fields{"worker": "renewal"}))
+	w.cancel = cancel
+	w.done = make(chan struct{})
+
+	go func() {
+		defer close(w.done)
+
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			w.run(ctx)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop cancels the current run and waits for the worker to exit.
+func (w *Worker) Stop() {
+	if w.cancel == nil {
+		return
+	}
+	w.cancel()
+	<-w.done
+}
+
+func (w *Worker) run(ctx context.Context) {
+	renewed, err := w.s.RenewExpiringSubscriptions(ctx, time.Now().Add(w.window), w.batchSize)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("RenewalWorker.run error: %v", err)
+		return
+	}
+	if renewed > 0 {
+		logger.FromContext(ctx).Infof("RenewalWorker.run: renewed %d subscriptions", renewed)
+	}
+
+	notified, err := w.s.NotifyExpiringSubscriptions(ctx, time.Now().Add(w.noticeWindow), w.batchSize)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("RenewalWorker.run notify error: %v", err)
+	}
+	if notified > 0 {
+		logger.FromContext(ctx).Infof("RenewalWorker.run: %d subscriptions expiring soon", notified)
+	}
+}
@@ -0,0 +1,11 @@
+package stats
+
+import "context"
+
+type SubscriptionService interface {
+	CountActiveSubscriptions(ctx context.Context) (map[string]int, error)
+}
+
+type OfferService interface {
+	CountOffers(ctx context.Context) (int, error)
+}
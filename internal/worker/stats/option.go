@@ -0,0 +1,14 @@
+package stats
+
+import "time"
+
+type Option func(*Worker)
+
+// Interval sets how often the worker refreshes the business metrics.
+func Interval(interval time.Duration) Option {
+	return func(w *Worker) {
+		if interval > 0 {
+			w.interval = interval
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package stats
+
+import (
+	"context"
+	"time"
+
+	"github.com/4udiwe/subscription-service/internal/metrics"
+	"github.com/4udiwe/subscription-service/pkg/logger"
+	"github.com/sirupsen/logrus"
+)
+
+const defaultInterval = 30 * time.Second
+
+// Worker periodically refreshes the business gauges: active subscriptions per service and the number of offers.
+type Worker struct {
+	subs     SubscriptionService
+	offers   OfferService
+	interval time.Duration
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func New(subs SubscriptionService, offers OfferService, opts ...Option) *Worker {
+	w := &Worker{
+		subs:     subs,
+		offers:   offers,
+		interval: defaultInterval,
+	}
+
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	return w
+}
+
+// Start runs the worker in a background goroutine. The first run happens immediately.
+func (w *Worker) Start() {
+	ctx, cancel := context.WithCancel(logger.WithFields(context.Background(), logrus.Fields{"worker": "stats"}))
+	w.cancel = cancel
+	w.done = make(chan struct{})
+
+	go func() {
+		defer close(w.done)
+
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			w.run(ctx)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop cancels the current run and waits for the worker to exit.
+func (w *Worker) Stop() {
+	if w.cancel == nil {
+		return
+	}
+	w.cancel()
+	<-w.done
+}
+
+// run keeps the previous values of the gauges when a count fails.
+func (w *Worker) run(ctx context.Context) {
+	active, err := w.subs.CountActiveSubscriptions(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("StatsWorker.run error counting subscriptions: %v", err)
+	} else {
+		metrics.SetActiveSubscriptions(active)
+	}
+
+	offers, err := w.offers.CountOffers(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("StatsWorker.run error counting offers: %v", err)
+	} else {
+		metrics.Offers.Set(float64(offers))
+	}
+}
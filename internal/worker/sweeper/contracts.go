@@ -0,0 +1,10 @@
+package sweeper
+
+import (
+	"context"
+	"time"
+)
+
+type IdempotencyService interface {
+	SweepExpired(ctx context.Context, now time.Time, batchSize int) (int64, error)
+}
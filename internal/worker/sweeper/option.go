@@ -0,0 +1,21 @@
+package sweeper
+
+import "time"
+
+type Option func(*Worker)
+
+// Interval sets how often the worker deletes expired idempotency keys.
+func Interval(interval time.Duration) Option {
+	return func(w *Worker) {
+		if interval > 0 {
+			w.interval = interval
+		}
+	}
+}
+
+// BatchSize sets the maximum number of keys deleted by one statement.
+func BatchSize(size int) Option {
+	return func(w *Worker) {
+		w.batchSize = size
+	}
+}
@@ -0,0 +1,82 @@
+package sweeper
+
+import (
+	"context"
+	"time"
+
+	"github.com/4udiwe/subscription-service/pkg/logger"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultInterval  = time.Hour
+	defaultBatchSize = 1000
+)
+
+// Worker periodically deletes the idempotency keys that have expired.
+type Worker struct {
+	s         IdempotencyService
+	interval  time.Duration
+	batchSize int
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func New(s IdempotencyService, opts ...Option) *Worker {
+	w := &Worker{
+		s:         s,
+		interval:  defaultInterval,
+		batchSize: defaultBatchSize,
+	}
+
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	return w
+}
+
+// Start runs the worker in a background goroutine. The first run happens immediately.
+func (w *Worker) Start() {
+	ctx, cancel := context.WithCancel(logger.WithFields(context.Background(), logrus.Fields{"worker": "sweeper"}))
+	w.cancel = cancel
+	w.done = make(chan struct{})
+
+	go func() {
+		defer close(w.done)
+
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			w.run(ctx)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop cancels the current run and waits for the worker to exit.
+func (w *Worker) Stop() {
+	if w.cancel == nil {
+		return
+	}
+	w.cancel()
+	<-w.done
+}
+
+func (w *Worker) run(ctx context.Context) {
+	deleted, err := w.s.SweepExpired(ctx, time.Now(), w.batchSize)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("SweeperWorker.run error: %v", err)
+		return
+	}
+	if deleted > 0 {
+		logger.FromContext(ctx).Infof("SweeperWorker.run: deleted %d expired idempotency keys", deleted)
+	}
+}
@@ -0,0 +1,25 @@
+package actor
+
+import "context"
+
+const (
+	// System is the actor of changes made by background workers.
+	System = "system"
+	// Anonymous is the actor of requests that do not name one.
+	Anonymous = "anonymous"
+)
+
+type ctxKey struct{}
+
+// WithActor returns a copy of ctx that carries the actor, the one who makes the change.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, actor)
+}
+
+// FromContext returns the actor stored in ctx, System if there is none.
+func FromContext(ctx context.Context) string {
+	if actor, ok := ctx.Value(ctxKey{}).(string); ok && actor != "" {
+		return actor
+	}
+	return System
+}
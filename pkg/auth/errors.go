@@ -0,0 +1,10 @@
+package auth
+
+import "errors"
+
+var (
+	ErrNoKeys         = errors.New("no signing keys configured")
+	ErrInvalidToken   = errors.New("invalid token")
+	ErrUnknownKey     = errors.New("unknown signing key")
+	ErrMissingSubject = errors.New("token has no subject")
+)
@@ -0,0 +1,73 @@
+package auth
+
+import (
+	"context"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// RoleAdmin is the role of callers that manage offers and see the data of every user.
+const RoleAdmin = "admin"
+
+// Identity is the authenticated caller: the subject of its token and the roles granted to it,
+// or, for backends calling with an API key, the key name and its scopes.
+type Identity struct {
+	Subject string
+	Roles   []string
+	Scopes  []string
+	APIKey  bool
+}
+
+// HasRole reports whether the identity was granted the role.
+func (i Identity) HasRole(role string) bool {
+	return slices.Contains(i.Roles, role)
+}
+
+// HasScope reports whether the identity was granted the scope or ScopeAdmin.
+func (i Identity) HasScope(scope string) bool {
+	return slices.Contains(i.Scopes, scope) || slices.Contains(i.Scopes, ScopeAdmin)
+}
+
+type ctxKey struct{}
+
+// WithIdentity returns a copy of ctx that carries the identity of the caller.
+func WithIdentity(ctx context.Context, identity Identity) context.Context {
+	return context.WithValue(ctx, ctxKey{}, identity)
+}
+
+// FromContext returns the identity stored in ctx, false if the request was not authenticated.
+func FromContext(ctx context.Context) (Identity, bool) {
+	identity, ok := ctx.Value(ctxKey{}).(Identity)
+	return identity, ok
+}
+
+// HasFullAccess reports whether the caller may act on behalf of any user: it is an admin, a backend
+// calling with an API key, whose scopes are checked per route, or authentication is turned off
+// and ctx carries no identity.
+func HasFullAccess(ctx context.Context) bool {
+	identity, ok := FromContext(ctx)
+	return !ok || identity.APIKey || identity.HasRole(RoleAdmin)
+}
+
+// CanManageOffers reports whether the caller may create and change offers: an admin, a backend
+// calling with an API key granted ScopeOffersWrite, or any caller when authentication is turned off.
+func CanManageOffers(ctx context.Context) bool {
+	identity, ok := FromContext(ctx)
+	if !ok {
+		return true
+	}
+	if identity.APIKey {
+		return identity.HasScope(ScopeOffersWrite)
+	}
+	return identity.HasRole(RoleAdmin)
+}
+
+// CanAccessUser reports whether the caller may see and change the data of the user.
+func CanAccessUser(ctx context.Context, userID uuid.UUID) bool {
+	if HasFullAccess(ctx) {
+		return true
+	}
+	identity, _ := FromContext(ctx)
+	return identity.Subject == userID.String()
+}
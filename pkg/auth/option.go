@@ -0,0 +1,43 @@
+package auth
+
+// Option -.
+type Option func(*Verifier)
+
+// HS256Secret accepts tokens signed with HS256 and the secret.
+func HS256Secret(secret string) Option {
+	return func(v *Verifier) {
+		if secret != "" {
+			v.hmacSecret = []byte(secret)
+		}
+	}
+}
+
+// JWKSFile accepts tokens signed with RS256 and one of the RSA keys of the JWK set in the file.
+func JWKSFile(path string) Option {
+	return func(v *Verifier) {
+		v.jwksFile = path
+	}
+}
+
+// Issuer requires the iss claim of tokens to be equal to issuer.
+func Issuer(issuer string) Option {
+	return func(v *Verifier) {
+		v.issuer = issuer
+	}
+}
+
+// Audience requires the aud claim of tokens to contain audience.
+func Audience(audience string) Option {
+	return func(v *Verifier) {
+		v.audience = audience
+	}
+}
+
+// RolesClaim sets the claim the roles of the caller are read from.
+func RolesClaim(claim string) Option {
+	return func(v *Verifier) {
+		if claim != "" {
+			v.rolesClaim = claim
+		}
+	}
+}
@@ -0,0 +1,19 @@
+package auth
+
+// Scopes of API keys. ScopeAdmin grants all the others and the admin endpoints.
+const (
+	ScopeSubscriptionsRead  = "subscriptions:read"
+	ScopeSubscriptionsWrite = "subscriptions:write"
+	ScopeOffersRead         = "offers:read"
+	ScopeOffersWrite        = "offers:write"
+	ScopeAdmin              = "admin"
+)
+
+// Scopes lists all the scopes an API key can be granted.
+var Scopes = []string{
+	ScopeSubscriptionsRead,
+	ScopeSubscriptionsWrite,
+	ScopeOffersRead,
+	ScopeOffersWrite,
+	ScopeAdmin,
+}
@@ -0,0 +1,112 @@
+package auth
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const defaultRolesClaim = "roles"
+
+// Verifier checks the signature and the claims of bearer tokens and tells who made the request.
+type Verifier struct {
+	hmacSecret []byte
+	jwksFile   string
+	rsaKeys    jwks
+	issuer     string
+	audience   string
+	rolesClaim string
+	parser     *jwt.Parser
+}
+
+// NewVerifier returns a verifier of tokens signed with HS256, RS256 or both,
+// depending on the keys passed in options.
+func NewVerifier(options ...Option) (*Verifier, error) {
+	v := &Verifier{rolesClaim: defaultRolesClaim}
+
+	for _, op := range options {
+		op(v)
+	}
+
+	if v.jwksFile != "" {
+		keys, err := loadJWKS(v.jwksFile)
+		if err != nil {
+			return nil, fmt.Errorf("auth - NewVerifier - loadJWKS: %w", err)
+		}
+		v.rsaKeys = keys
+	}
+
+	var methods []string
+	if v.hmacSecret != nil {
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+	if len(v.rsaKeys) > 0 {
+		methods = append(methods, jwt.SigningMethodRS256.Alg())
+	}
+	if len(methods) == 0 {
+		return nil, fmt.Errorf("auth - NewVerifier: %w", ErrNoKeys)
+	}
+
+	parserOptions := []jwt.ParserOption{
+		jwt.WithValidMethods(methods),
+		jwt.WithExpirationRequired(),
+	}
+	if v.issuer != "" {
+		parserOptions = append(parserOptions, jwt.WithIssuer(v.issuer))
+	}
+	if v.audience != "" {
+		parserOptions = append(parserOptions, jwt.WithAudience(v.audience))
+	}
+	v.parser = jwt.NewParser(parserOptions...)
+
+	return v, nil
+}
+
+// Verify parses the token and returns the identity it was issued to.
+func (v *Verifier) Verify(token string) (Identity, error) {
+	claims := jwt.MapClaims{}
+	if _, err := v.parser.ParseWithClaims(token, claims, v.key); err != nil {
+		return Identity{}, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+
+	subject, err := claims.GetSubject()
+	if err != nil || subject == "" {
+		return Identity{}, ErrMissingSubject
+	}
+
+	return Identity{
+		Subject: subject,
+		Roles:   roles(claims[v.rolesClaim]),
+	}, nil
+}
+
+func (v *Verifier) key(token *jwt.Token) (any, error) {
+	switch token.Method.Alg() {
+	case jwt.SigningMethodHS256.Alg():
+		return v.hmacSecret, nil
+	case jwt.SigningMethodRS256.Alg():
+		kid, _ := token.Header["kid"].(string)
+		return v.rsaKeys.lookup(kid)
+	default:
+		return nil, ErrUnknownKey
+	}
+}
+
+// roles accepts the claim both as a list of strings and as a space separated string.
+func roles(claim any) []string {
+	switch value := claim.(type) {
+	case []any:
+		result := make([]string, 0, len(value))
+		for _, role := range value {
+			if s, ok := role.(string); ok && s != "" {
+				result = append(result, s)
+			}
+		}
+		return result
+	case string:
+		return strings.Fields(value)
+	default:
+		return nil
+	}
+}
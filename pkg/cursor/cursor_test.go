@@ -0,0 +1,71 @@
+package cursor
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	tests := []struct {
+		name      string
+		createdAt time.Time
+	}{
+		{name: "utc", createdAt: time.Date(2025, 9, 21, 10, 42, 22, 0, time.UTC)},
+		{name: "nanoseconds", createdAt: time.Date(2025, 9, 21, 10, 42, 22, 123456789, time.UTC)},
+		{name: "other zone", createdAt: time.Date(2025, 9, 21, 13, 42, 22, 0, time.FixedZone("MSK", 3*60*60))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New(tt.createdAt, uuid.New())
+
+			encoded := c.Encode()
+			if strings.ContainsAny(encoded, "+/=") {
+				t.Errorf("encoded cursor %q is not URL safe", encoded)
+			}
+
+			got, err := Decode(encoded)
+			if err != nil {
+				t.Fatalf("Decode(%q): %v", encoded, err)
+			}
+			if !got.CreatedAt.Equal(c.CreatedAt) || got.ID != c.ID {
+				t.Errorf("Decode(Encode()) = %+v, want %+v", got, *c)
+			}
+		})
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	valid := New(time.Date(2025, 9, 21, 10, 42, 22, 0, time.UTC), uuid.New()).Encode()
+	encode := func(s string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(s))
+	}
+
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{name: "empty", cursor: ""},
+		{name: "not base64", cursor: "not a cursor!"},
+		{name: "truncated", cursor: valid[:len(valid)/2]},
+		{name: "not json", cursor: encode("created_at=2025-09-21")},
+		{name: "tampered id", cursor: encode(`{"created_at":"2025-09-21T10:42:22Z","id":"not-a-uuid"}`)},
+		{name: "tampered time", cursor: encode(`{"created_at":"yesterday","id":"` + uuid.NewString() + `"}`)},
+		{name: "no id", cursor: encode(`{"created_at":"2025-09-21T10:42:22Z"}`)},
+		{name: "no time", cursor: encode(`{"id":"` + uuid.NewString() + `"}`)},
+		{name: "empty object", cursor: encode(`{}`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Decode(tt.cursor); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("Decode(%q) error = %v, want %v", tt.cursor, err, ErrInvalidCursor)
+			}
+		})
+	}
+}
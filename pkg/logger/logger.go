@@ -0,0 +1,38 @@
+package logger
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type ctxKey struct{}
+
+// WithLogger returns a copy of ctx that carries the logger, lines logged through it
+// carry the fields of the request or the worker the context belongs to.
+func WithLogger(ctx context.Context, entry *logrus.Entry) context.Context {
+	return context.WithValue(ctx, ctxKey{}, entry)
+}
+
+// WithFields returns a copy of ctx whose logger carries the fields in addition to its own.
+func WithFields(ctx context.Context, fields logrus.Fields) context.Context {
+	return WithLogger(ctx, FromContext(ctx).WithFields(fields))
+}
+
+// FromContext returns the logger stored in ctx, the standard logger without fields if there is none.
+// When ctx carries a span, the lines also carry its trace_id and span_id.
+func FromContext(ctx context.Context) *logrus.Entry {
+	entry, ok := ctx.Value(ctxKey{}).(*logrus.Entry)
+	if !ok {
+		entry = logrus.NewEntry(logrus.StandardLogger())
+	}
+
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		entry = entry.WithFields(logrus.Fields{
+			"trace_id": sc.TraceID().String(),
+			"span_id":  sc.SpanID().String(),
+		})
+	}
+	return entry
+}
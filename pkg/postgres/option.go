@@ -1,6 +1,10 @@
 package postgres
 
-import "time"
+import (
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
 
 type Option func(*Postgres)
 
@@ -15,3 +19,10 @@ func TimeOut(t time.Duration) Option {
 		p.connTimeout = t
 	}
 }
+
+// Tracer sets the tracer of the queries, see QueryTracer.
+func Tracer(t pgx.QueryTracer) Option {
+	return func(p *Postgres) {
+		p.tracer = t
+	}
+}
@@ -20,6 +20,7 @@ const (
 type Postgres struct {
 	connTimeout  time.Duration
 	connAttempts int
+	tracer       pgx.QueryTracer
 
 	Pool    *pgxpool.Pool
 	Builder squirrel.StatementBuilderType
@@ -42,6 +43,7 @@ func New(url string, opts ...Option) (*Postgres, error) {
 	if err != nil {
 		return nil, fmt.Errorf("postgres - NewPostgres - pgxpool.ParseConfig: %w", err)
 	}
+	poolConfig.ConnConfig.Tracer = pg.tracer
 
 	for pg.connAttempts > 0 {
 		pg.Pool, err = pgxpool.NewWithConfig(context.Background(), poolConfig)
@@ -0,0 +1,46 @@
+package tracing
+
+type Option func(*config)
+
+// ServiceName sets the service.name resource attribute of the spans.
+func ServiceName(name string) Option {
+	return func(c *config) {
+		c.serviceName = name
+	}
+}
+
+// ServiceVersion sets the service.version resource attribute of the spans.
+func ServiceVersion(version string) Option {
+	return func(c *config) {
+		c.serviceVersion = version
+	}
+}
+
+// Exporter sets where the spans are sent: ExporterOTLP, ExporterStdout or ExporterNone.
+func Exporter(exporter string) Option {
+	return func(c *config) {
+		c.exporter = exporter
+	}
+}
+
+// Endpoint sets the host:port of the OTLP HTTP collector. When it is empty the exporter
+// reads OTEL_EXPORTER_OTLP_ENDPOINT and falls back to localhost:4318.
+func Endpoint(endpoint string) Option {
+	return func(c *config) {
+		c.endpoint = endpoint
+	}
+}
+
+// Insecure makes the OTLP exporter use plain HTTP.
+func Insecure(insecure bool) Option {
+	return func(c *config) {
+		c.insecure = insecure
+	}
+}
+
+// SampleRatio sets the share of traces that are recorded, from 0 to 1.
+func SampleRatio(ratio float64) Option {
+	return func(c *config) {
+		c.sampleRatio = ratio
+	}
+}
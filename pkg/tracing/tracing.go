@@ -0,0 +1,121 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+	ExporterNone   = "none"
+
+	instrumentationName = "github.com/4udiwe/subscription-service"
+)
+
+type config struct {
+	serviceName    string
+	serviceVersion string
+	exporter       string
+	endpoint       string
+	insecure       bool
+	sampleRatio    float64
+}
+
+// Provider sends the spans of the service to the configured exporter.
+type Provider struct {
+	tp *sdktrace.TracerProvider
+}
+
+// New installs the global tracer provider and the W3C trace context propagator.
+// With ExporterNone spans are still created, so trace IDs reach the logs, but they are not exported.
+func New(ctx context.Context, opts ...Option) (*Provider, error) {
+	cfg := &config{
+		exporter:    ExporterNone,
+		sampleRatio: 1,
+	}
+
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(cfg.serviceName),
+		semconv.ServiceVersion(cfg.serviceVersion),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("tracing - New - resource.Merge: %w", err)
+	}
+
+	tpOpts := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.sampleRatio))),
+	}
+
+	switch cfg.exporter {
+	case ExporterOTLP:
+		exporterOpts := []otlptracehttp.Option{}
+		if cfg.endpoint != "" {
+			exporterOpts = append(exporterOpts, otlptracehttp.WithEndpoint(cfg.endpoint))
+		}
+		if cfg.insecure {
+			exporterOpts = append(exporterOpts, otlptracehttp.WithInsecure())
+		}
+		exporter, err := otlptracehttp.New(ctx, exporterOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("tracing - New - otlptracehttp.New: %w", err)
+		}
+		tpOpts = append(tpOpts, sdktrace.WithBatcher(exporter))
+	case ExporterStdout:
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+		if err != nil {
+			return nil, fmt.Errorf("tracing - New - stdouttrace.New: %w", err)
+		}
+		tpOpts = append(tpOpts, sdktrace.WithBatcher(exporter))
+	case ExporterNone:
+	default:
+		return nil, fmt.Errorf("tracing - New - unknown exporter %q", cfg.exporter)
+	}
+
+	tp := sdktrace.NewTracerProvider(tpOpts...)
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return &Provider{tp: tp}, nil
+}
+
+// Shutdown sends the buffered spans and stops the exporter.
+func (p *Provider) Shutdown(ctx context.Context) error {
+	return p.tp.Shutdown(ctx)
+}
+
+// Start starts a span named after the method, it is a child of the span in ctx:
+//
+//	ctx, span := tracing.Start(ctx, "OfferService.CreateOffer")
+//	defer tracing.End(span, &err)
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, opts...)
+}
+
+// End records the error that err points to, if any, on the span and ends it. It is deferred
+// with the address of the named error result of the method, so the error the method returns
+// marks the span as failed. Methods that do not return an error call span.End.
+func End(span trace.Span, err *error) {
+	if err != nil && *err != nil {
+		span.RecordError(*err)
+		span.SetStatus(codes.Error, (*err).Error())
+	}
+	span.End()
+}
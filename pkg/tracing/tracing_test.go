@@ -0,0 +1,60 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestEnd(t *testing.T) {
+	failure := errors.New("failure")
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus codes.Code
+	}{
+		{name: "success", err: nil, wantStatus: codes.Unset},
+		{name: "error", err: failure, wantStatus: codes.Error},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := tracetest.NewSpanRecorder()
+			tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+			method := func() (err error) {
+				_, span := tp.Tracer(instrumentationName).Start(context.Background(), "Service.Method")
+				defer End(span, &err)
+				return tt.err
+			}
+			if err := method(); !errors.Is(err, tt.err) {
+				t.Fatalf("method() = %v, want %v", err, tt.err)
+			}
+
+			spans := recorder.Ended()
+			if len(spans) != 1 {
+				t.Fatalf("%d spans ended, want 1", len(spans))
+			}
+			span := spans[0]
+			if span.Status().Code != tt.wantStatus {
+				t.Errorf("status = %v, want %v", span.Status().Code, tt.wantStatus)
+			}
+
+			var recorded []string
+			for _, event := range span.Events() {
+				recorded = append(recorded, event.Name)
+			}
+			if tt.err != nil && (len(recorded) != 1 || recorded[0] != "exception") {
+				t.Errorf("events = %v, want one exception", recorded)
+			}
+			if tt.err == nil && len(recorded) != 0 {
+				t.Errorf("events = %v, want none", recorded)
+			}
+		})
+	}
+}
@@ -13,6 +13,26 @@ type CustomValidator struct {
 	v *validator.Validate
 }
 
+// FieldError describes a field that failed validation: its name in the request, the failed rule and a message.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// ValidationError lists every invalid field of a request.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Fields))
+	for _, field := range e.Fields {
+		messages = append(messages, field.Message)
+	}
+	return strings.Join(messages, "; ")
+}
+
 func NewCustomValidator() *CustomValidator {
 	v := validator.New()
 	cv := &CustomValidator{v: v}
@@ -28,19 +48,28 @@ func NewCustomValidator() *CustomValidator {
 	return cv
 }
 
+// Validate checks the struct and returns a *ValidationError with all the invalid fields.
 func (cv *CustomValidator) Validate(i any) error {
 	err := cv.v.Struct(i)
 	if err != nil {
 		var validationErr validator.ValidationErrors
 		if errors.As(err, &validationErr) {
-			return cv.newValidationError(validationErr[0].Field(), validationErr[0].Tag(), validationErr[0].Param())
+			fields := make([]FieldError, 0, len(validationErr))
+			for _, fe := range validationErr {
+				fields = append(fields, FieldError{
+					Field:   fe.Field(),
+					Rule:    fe.Tag(),
+					Message: cv.newValidationError(fe.Field(), fe.Tag(), fe.Param(), fe.Kind()).Error(),
+				})
+			}
+			return &ValidationError{Fields: fields}
 		}
 		return err
 	}
 	return nil
 }
 
-func (cv *CustomValidator) newValidationError(field string, tag string, param string) error {
+func (cv *CustomValidator) newValidationError(field string, tag string, param string, kind reflect.Kind) error {
 	switch tag {
 	case "required":
 		return fmt.Errorf("field %s is required", field)
@@ -48,13 +77,39 @@ func (cv *CustomValidator) newValidationError(field string, tag string, param st
 		return fmt.Errorf("field %s must be %s characters length", field, param)
 	case "uri":
 		return fmt.Errorf("field %s must be a valid URI", field)
+	case "http_url":
+		return fmt.Errorf("field %s must be a valid HTTP URL", field)
 	case "email":
 		return fmt.Errorf("field %s must be a valid email address", field)
+	case "uuid":
+		return fmt.Errorf("field %s must be a valid UUID", field)
+	case "iso4217":
+		return fmt.Errorf("field %s must be an ISO 4217 currency code", field)
+	case "datetime":
+		return fmt.Errorf("field %s must be a date in format %s", field, param)
+	case "oneof":
+		return fmt.Errorf("field %s must be one of: %s", field, strings.Join(strings.Fields(param), ", "))
+	case "nefield":
+		return fmt.Errorf("field %s must differ from %s", field, param)
+	case "gt":
+		return fmt.Errorf("field %s must be greater than %s", field, param)
 	case "min":
-		return fmt.Errorf("field %s must be at least %s characters", field, param)
+		return fmt.Errorf("field %s must be at least %s%s", field, param, unit(kind))
 	case "max":
-		return fmt.Errorf("field %s must be at most %s characters", field, param)
+		return fmt.Errorf("field %s must be at most %s%s", field, param, unit(kind))
 	default:
 		return fmt.Errorf("field %s is invalid", field)
 	}
 }
+
+// unit is what min and max count for fields of the kind.
+func unit(kind reflect.Kind) string {
+	switch kind {
+	case reflect.String:
+		return " characters"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return " items"
+	default:
+		return ""
+	}
+}